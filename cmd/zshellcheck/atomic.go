@@ -0,0 +1,81 @@
+// SPDX-License-Identifier: MIT
+// Copyright the ZShellCheck contributors.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// rewriteFile replaces the contents of filename, which held orig when it
+// was read, with data. It writes a temp file beside the real target —
+// following symlinks, so a stow- or chezmoi-managed dotfile keeps its link
+// — copies the target's mode and ownership onto it, and renames it over
+// the target, so an interrupted run leaves either the old file or the new
+// one, never a truncated mix. With a non-empty backupSuffix the original
+// contents are saved to the target's path plus the suffix first. If the
+// file no longer holds orig, it was edited since it was read and is left
+// alone.
+func rewriteFile(filename string, orig, data []byte, backupSuffix string) error {
+	target, err := filepath.EvalSymlinks(filename)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(target)
+	if err != nil {
+		return err
+	}
+	current, err := os.ReadFile(target)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(current, orig) {
+		return fmt.Errorf("%s changed on disk since it was read; not overwriting it", filename)
+	}
+	if backupSuffix != "" {
+		if err := writeReplacing(target+backupSuffix, orig, info); err != nil {
+			return fmt.Errorf("backup: %w", err)
+		}
+	}
+	return writeReplacing(target, data, info)
+}
+
+// writeReplacing atomically writes data to path via a temp file in the
+// same directory, giving it the mode and owner in info.
+func writeReplacing(path string, data []byte, info os.FileInfo) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".zshellcheck-*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	committed := false
+	defer func() {
+		if !committed {
+			_ = os.Remove(tmpName)
+		}
+	}()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpName, info.Mode().Perm()); err != nil {
+		return err
+	}
+	if err := copyOwner(tmpName, info); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpName, path); err != nil {
+		return err
+	}
+	committed = true
+	return nil
+}
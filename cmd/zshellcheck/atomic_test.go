@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: MIT
+// Copyright the ZShellCheck contributors.
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRewriteFile_KeepsModeAndLeavesNoTemp(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "s.zsh")
+	if err := os.WriteFile(path, []byte("old\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(path, 0o751); err != nil {
+		t.Fatal(err)
+	}
+	if err := rewriteFile(path, []byte("old\n"), []byte("new\n"), ""); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "new\n" {
+		t.Errorf("content = %q", data)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0o751 {
+		t.Errorf("mode = %v, want 0751", info.Mode().Perm())
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("directory holds %d entries, want only the script", len(entries))
+	}
+}
+
+func TestRewriteFile_FollowsSymlink(t *testing.T) {
+	dir := t.TempDir()
+	real := filepath.Join(dir, "dotfiles", "zshrc")
+	if err := os.MkdirAll(filepath.Dir(real), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(real, []byte("old\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(dir, ".zshrc")
+	if err := os.Symlink(real, link); err != nil {
+		t.Skipf("symlinks unavailable: %v", err)
+	}
+	if err := rewriteFile(link, []byte("old\n"), []byte("new\n"), ".orig"); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Lstat(link); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Fatalf("%s is no longer a symlink", link)
+	}
+	if data, _ := os.ReadFile(real); string(data) != "new\n" {
+		t.Errorf("target content = %q", data)
+	}
+	if data, err := os.ReadFile(real + ".orig"); err != nil || string(data) != "old\n" {
+		t.Errorf("backup beside the target = %q, %v", data, err)
+	}
+}
+
+func TestRewriteFile_RefusesWhenChangedOnDisk(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "s.zsh")
+	if err := os.WriteFile(path, []byte("edited meanwhile\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	err := rewriteFile(path, []byte("old\n"), []byte("new\n"), ".orig")
+	if err == nil || !strings.Contains(err.Error(), "changed on disk") {
+		t.Fatalf("err = %v, want a changed-on-disk error", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "edited meanwhile\n" {
+		t.Errorf("file was overwritten: %q", data)
+	}
+	if _, err := os.Stat(path + ".orig"); !os.IsNotExist(err) {
+		t.Errorf("backup written for a refused rewrite: %v", err)
+	}
+}
+
+func TestRunFixModeBackupSuffix(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "x.zsh")
+	orig := "result=`date`\n"
+	if err := os.WriteFile(src, []byte(orig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	resetFlags()
+	old := os.Args
+	defer func() { os.Args = old }()
+	os.Args = []string{"zshellcheck", "-fix", "-backup-suffix", ".orig", "-no-banner", src}
+	_ = run()
+	if data, _ := os.ReadFile(src); string(data) == orig {
+		t.Errorf("file not fixed: %q", data)
+	}
+	if data, _ := os.ReadFile(src + ".orig"); string(data) != orig {
+		t.Errorf("backup = %q, want the original", data)
+	}
+}
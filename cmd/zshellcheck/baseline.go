@@ -4,10 +4,12 @@ package main
 
 import (
 	"os"
+	"slices"
 	"sort"
 	"strings"
 
 	"github.com/afadesigns/zshellcheck/pkg/katas"
+	"github.com/afadesigns/zshellcheck/pkg/reporter"
 )
 
 // baselineState drives the `-baseline` / `-baseline-write` ratchet. In
@@ -18,6 +20,11 @@ type baselineState struct {
 	write   bool
 	known   map[string]bool
 	collect []string
+	// path is the snapshot file a filtering state was loaded from.
+	path string
+	// byFile lists the snapshot's fingerprints by the file they name,
+	// repeated as often as the snapshot repeats them.
+	byFile map[string][]string
 }
 
 // loadBaseline reads a snapshot file into a filtering baselineState.
@@ -27,23 +34,23 @@ func loadBaseline(path string) (*baselineState, error) {
 		return nil, err
 	}
 	known := map[string]bool{}
+	byFile := map[string][]string{}
 	for _, line := range strings.Split(string(data), "\n") {
 		if line != "" {
 			known[line] = true
+			if _, file, ok := strings.Cut(line, "\t"); ok {
+				file, _, _ = strings.Cut(file, "\t")
+				byFile[file] = append(byFile[file], line)
+			}
 		}
 	}
-	return &baselineState{known: known}, nil
+	return &baselineState{known: known, path: path, byFile: byFile}, nil
 }
 
-// baselineFingerprint identifies a finding by kata, file, and the trimmed
-// source line — not the line number — so inserting or removing unrelated
-// lines elsewhere in the file does not invalidate the snapshot.
+// baselineFingerprint identifies a finding in the snapshot by its
+// reporter.FindingKey, which survives unrelated lines moving.
 func baselineFingerprint(file string, lines []string, v katas.Violation) string {
-	content := ""
-	if v.Line >= 1 && v.Line <= len(lines) {
-		content = strings.TrimSpace(lines[v.Line-1])
-	}
-	return v.KataID + "\t" + file + "\t" + content
+	return reporter.FindingKey(file, lines, v)
 }
 
 // applyBaseline records or filters this file's findings against the
@@ -51,35 +58,71 @@ func baselineFingerprint(file string, lines []string, v katas.Violation) string
 // collecting them; otherwise it returns only the findings absent from the
 // snapshot.
 func (b *baselineState) applyBaseline(filename string, data []byte, violations []katas.Violation) []katas.Violation {
+	kept, _ := b.split(filename, data, violations)
+	return kept
+}
+
+// split is applyBaseline that also returns the findings the snapshot
+// suppressed. In write mode nothing is suppressed.
+func (b *baselineState) split(filename string, data []byte, violations []katas.Violation) (kept, known []katas.Violation) {
 	lines := strings.Split(string(data), "\n")
 	if b.write {
 		for _, v := range violations {
 			b.collect = append(b.collect, baselineFingerprint(filename, lines, v))
 		}
-		return violations
+		return violations, nil
 	}
-	kept := violations[:0]
+	kept = violations[:0]
 	for _, v := range violations {
-		if !b.known[baselineFingerprint(filename, lines, v)] {
+		if b.known[baselineFingerprint(filename, lines, v)] {
+			known = append(known, v)
+		} else {
 			kept = append(kept, v)
 		}
 	}
-	return kept
+	return kept, known
 }
 
-// writeBaseline saves the collected fingerprints as a sorted, de-duplicated
-// snapshot.
-func (b *baselineState) writeBaseline(path string) error {
-	seen := map[string]bool{}
-	uniq := make([]string, 0, len(b.collect))
-	for _, fp := range b.collect {
-		if !seen[fp] {
-			seen[fp] = true
-			uniq = append(uniq, fp)
+// absent returns the snapshot's findings for filename that the findings
+// no longer account for, skipping those of disabled katas, which were not
+// run. When the snapshot holds a fingerprint n times and m findings match
+// it, the entries numbered m to n-1 are absent.
+func (b *baselineState) absent(filename string, data []byte, violations []katas.Violation, disabled []string) []reporter.AbsentFinding {
+	lines := strings.Split(string(data), "\n")
+	found := map[string]int{}
+	for _, v := range violations {
+		found[baselineFingerprint(filename, lines, v)]++
+	}
+	var out []reporter.AbsentFinding
+	recorded := map[string]int{}
+	for _, fp := range b.byFile[filename] {
+		n := recorded[fp]
+		recorded[fp]++
+		kataID, _, _ := strings.Cut(fp, "\t")
+		if n >= found[fp] && !slices.Contains(disabled, kataID) {
+			out = append(out, reporter.AbsentFinding{Key: fp, Ordinal: n})
 		}
 	}
-	sort.Strings(uniq)
-	out := strings.Join(uniq, "\n")
+	return out
+}
+
+// markKnown flags the suppressed findings the snapshot records.
+func (b *baselineState) markKnown(filename string, data []byte, suppressed []reporter.Suppressed) {
+	lines := strings.Split(string(data), "\n")
+	for i := range suppressed {
+		if b.known[baselineFingerprint(filename, lines, suppressed[i].Violation)] {
+			suppressed[i].InBaseline = true
+		}
+	}
+}
+
+// writeBaseline saves the collected fingerprints as a sorted snapshot.
+// Findings on identical lines share a fingerprint and each keep their own
+// entry, so the snapshot counts them.
+func (b *baselineState) writeBaseline(path string) error {
+	sorted := slices.Clone(b.collect)
+	sort.Strings(sorted)
+	out := strings.Join(sorted, "\n")
 	if out != "" {
 		out += "\n"
 	}
@@ -5,10 +5,12 @@ package main
 import (
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 
 	"github.com/afadesigns/zshellcheck/pkg/katas"
+	"github.com/afadesigns/zshellcheck/pkg/reporter"
 )
 
 func TestBaselineFingerprint(t *testing.T) {
@@ -56,15 +58,15 @@ func TestWriteAndLoadBaseline(t *testing.T) {
 	dir := t.TempDir()
 	path := filepath.Join(dir, "base.txt")
 	b := &baselineState{write: true, collect: []string{
-		"ZC2\tf\tline", "ZC1\tf\tline", "ZC2\tf\tline", // duplicate + unsorted
+		"ZC2\tf\tline", "ZC1\tf\tline", "ZC2\tf\tline", // identical lines + unsorted
 	}}
 	if err := b.writeBaseline(path); err != nil {
 		t.Fatalf("writeBaseline: %v", err)
 	}
 	data, _ := os.ReadFile(path)
 	got := strings.TrimSpace(string(data))
-	// De-duplicated and sorted.
-	if got != "ZC1\tf\tline\nZC2\tf\tline" {
+	// Sorted, with each finding on an identical line kept.
+	if got != "ZC1\tf\tline\nZC2\tf\tline\nZC2\tf\tline" {
 		t.Errorf("baseline content = %q", got)
 	}
 	loaded, err := loadBaseline(path)
@@ -76,6 +78,36 @@ func TestWriteAndLoadBaseline(t *testing.T) {
 	}
 }
 
+func TestBaselineAbsentAndMarkKnown(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "base.txt")
+	snapshot := "ZC1\tf.zsh\tcmd\nZC1\tf.zsh\tcmd\nZC2\tf.zsh\told\nZC3\tf.zsh\told\nZC1\tg.zsh\tcmd\n"
+	if err := os.WriteFile(path, []byte(snapshot), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	b, err := loadBaseline(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := []byte("cmd\n")
+	vs := []katas.Violation{{KataID: "ZC1", Line: 1}}
+	// One of the two ZC1 findings on `cmd` remains, so the second is
+	// absent. ZC3 is disabled, so its entry is not known to be gone.
+	want := []reporter.AbsentFinding{{Key: "ZC1\tf.zsh\tcmd", Ordinal: 1}, {Key: "ZC2\tf.zsh\told"}}
+	if got := b.absent("f.zsh", data, vs, []string{"ZC3"}); !slices.Equal(got, want) {
+		t.Errorf("absent = %+v, want %+v", got, want)
+	}
+
+	suppressed := []reporter.Suppressed{
+		{Violation: katas.Violation{KataID: "ZC1", Line: 1}, Kind: reporter.SuppressedByNoka},
+		{Violation: katas.Violation{KataID: "ZC4", Line: 1}, Kind: reporter.SuppressedByNoka},
+	}
+	b.markKnown("f.zsh", data, suppressed)
+	if !suppressed[0].InBaseline || suppressed[1].InBaseline {
+		t.Errorf("markKnown = %+v", suppressed)
+	}
+}
+
 func TestLoadBaselineMissing(t *testing.T) {
 	if _, err := loadBaseline("/nonexistent/baseline.txt"); err == nil {
 		t.Error("expected error for missing baseline file")
@@ -5,7 +5,6 @@ package main
 import (
 	"fmt"
 	"io"
-	"os"
 	"sort"
 	"strings"
 
@@ -94,9 +93,9 @@ func reportStaleNoka(out io.Writer, filename string, raw []katas.Violation, dire
 
 // addNokaDirectives appends a `# noka: ZC####` directive to every line that
 // carries a finding and does not already have a `# noka`, then writes the
-// file back. It is the bulk "silence what exists" companion to the baseline
-// ratchet.
-func addNokaDirectives(filename string, data []byte, violations []katas.Violation) error {
+// file back atomically (see rewriteFile). It is the bulk "silence what
+// exists" companion to the baseline ratchet.
+func addNokaDirectives(filename string, data []byte, violations []katas.Violation, backupSuffix string) error {
 	byLine := map[int]map[string]bool{}
 	for _, v := range violations {
 		if byLine[v.Line] == nil {
@@ -125,9 +124,5 @@ func addNokaDirectives(filename string, data []byte, violations []katas.Violatio
 	if !changed {
 		return nil
 	}
-	mode := os.FileMode(0o600)
-	if info, err := os.Stat(filename); err == nil {
-		mode = info.Mode().Perm()
-	}
-	return os.WriteFile(filename, []byte(strings.Join(lines, "\n")), mode)
+	return rewriteFile(filename, data, []byte(strings.Join(lines, "\n")), backupSuffix)
 }
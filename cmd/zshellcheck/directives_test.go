@@ -85,7 +85,7 @@ func TestAddNokaDirectives(t *testing.T) {
 		{KataID: "ZC1037", Line: 1}, // two on line 1 -> grouped
 		{KataID: "ZC9", Line: 2},    // line already has a noka -> skipped
 	}
-	if err := addNokaDirectives(path, []byte(src), vs); err != nil {
+	if err := addNokaDirectives(path, []byte(src), vs, ""); err != nil {
 		t.Fatalf("addNokaDirectives: %v", err)
 	}
 	out, _ := os.ReadFile(path)
@@ -98,7 +98,7 @@ func TestAddNokaDirectives(t *testing.T) {
 	}
 
 	// No findings -> no write, no error.
-	if err := addNokaDirectives(path, []byte(src), nil); err != nil {
+	if err := addNokaDirectives(path, []byte(src), nil, ""); err != nil {
 		t.Errorf("empty add-noka should be a no-op: %v", err)
 	}
 }
@@ -120,7 +120,7 @@ func TestAddNokaDirectivesOutOfRange(t *testing.T) {
 		t.Fatal(err)
 	}
 	// A finding on a line past EOF is skipped; the file is left unchanged.
-	if err := addNokaDirectives(path, []byte(src), []katas.Violation{{KataID: "ZC1", Line: 99}}); err != nil {
+	if err := addNokaDirectives(path, []byte(src), []katas.Violation{{KataID: "ZC1", Line: 99}}, ""); err != nil {
 		t.Fatal(err)
 	}
 	if b, _ := os.ReadFile(path); string(b) != src {
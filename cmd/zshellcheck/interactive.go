@@ -0,0 +1,227 @@
+// SPDX-License-Identifier: MIT
+// Copyright the ZShellCheck contributors.
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/afadesigns/zshellcheck/pkg/fix"
+	"github.com/afadesigns/zshellcheck/pkg/katas"
+)
+
+// fixReviewer backs `-fix -interactive`: it shows each group of edits one
+// kata produced on one line as a coloured diff hunk and asks whether to
+// apply it. Answers are read line by line from in, so a script can drive
+// the loop through stdin.
+type fixReviewer struct {
+	in       *bufio.Reader
+	out      io.Writer
+	color    palette
+	registry *katas.KatasRegistry
+	// filename and violations describe the file under review; violations
+	// supply the message shown above each hunk.
+	filename   string
+	violations []katas.Violation
+	// always holds the katas answered `a`: their later edits are applied
+	// without asking. declined remembers rejected groups so a later fixer
+	// pass does not ask about them again.
+	always   map[string]bool
+	declined map[string]bool
+	// quit is set by `q` and ends the review for the rest of the run.
+	quit bool
+}
+
+const reviewHelp = `y - apply this fix
+n - skip this fix
+a - apply this fix and every later fix from the same kata
+q - skip this fix and every remaining one
+e - edit the replacement text, then apply
+`
+
+func newFixReviewer(in io.Reader, out io.Writer, color palette, registry *katas.KatasRegistry) *fixReviewer {
+	return &fixReviewer{
+		in:       bufio.NewReader(in),
+		out:      out,
+		color:    color,
+		registry: registry,
+		always:   map[string]bool{},
+		declined: map[string]bool{},
+	}
+}
+
+// review asks about every edit group in edits against src and returns the
+// edits accepted. It is called once per fixer pass, so fixes exposed by an
+// earlier rewrite are reviewed too.
+func (r *fixReviewer) review(src string, edits []katas.FixEdit) []katas.FixEdit {
+	var accepted []katas.FixEdit
+	seen := map[string]bool{}
+	for _, group := range groupEdits(edits) {
+		if r.quit {
+			break
+		}
+		// Katas that share a rewrite (ZC1002 and ZC1015 both turn
+		// backticks into `$(...)`) propose identical edits; ask once.
+		span := spanKey(group)
+		if seen[span] {
+			continue
+		}
+		seen[span] = true
+		id := group[0].KataID
+		if r.always[id] {
+			accepted = append(accepted, group...)
+			continue
+		}
+		key := r.groupKey(src, group)
+		if r.declined[key] {
+			continue
+		}
+		group = r.ask(src, group)
+		if group == nil {
+			r.declined[key] = true
+			continue
+		}
+		accepted = append(accepted, group...)
+	}
+	return accepted
+}
+
+// ask shows one edit group and prompts until it gets a valid answer. It
+// returns the edits to apply, or nil when the group is skipped. End of
+// input counts as `q`.
+func (r *fixReviewer) ask(src string, group []katas.FixEdit) []katas.FixEdit {
+	first := group[0]
+	fmt.Fprintf(r.out, "%s:%d:%d: [%s] %s\n", r.filename, first.Line, first.Column, first.KataID, r.message(first))
+	if diff, err := fix.Diff(r.filename, src, group); err == nil {
+		fmt.Fprint(r.out, r.colorDiff(diff))
+	}
+	for {
+		fmt.Fprint(r.out, r.color.bold("Apply this fix [y,n,a,q,e,?]? "))
+		line, err := r.in.ReadString('\n')
+		if err != nil && line == "" {
+			r.quit = true
+			fmt.Fprintln(r.out)
+			return nil
+		}
+		switch strings.ToLower(strings.TrimSpace(line)) {
+		case "y":
+			return group
+		case "n":
+			return nil
+		case "a":
+			r.always[first.KataID] = true
+			return group
+		case "q":
+			r.quit = true
+			return nil
+		case "e":
+			return r.edit(src, group)
+		default:
+			fmt.Fprint(r.out, reviewHelp)
+		}
+	}
+}
+
+// edit prompts for a new replacement for each edit in group; an empty
+// answer keeps the proposed text.
+func (r *fixReviewer) edit(src string, group []katas.FixEdit) []katas.FixEdit {
+	edited := append([]katas.FixEdit(nil), group...)
+	for i, e := range edited {
+		fmt.Fprintf(r.out, "Replace %q with [%s]: ", spanText(src, e), e.Replace)
+		line, _ := r.in.ReadString('\n')
+		if text := strings.TrimRight(line, "\r\n"); text != "" {
+			edited[i].Replace = text
+		}
+	}
+	return edited
+}
+
+// message returns the finding message for an edit: the message of the
+// violation its kata reported on the edit's line, or the kata title.
+func (r *fixReviewer) message(e katas.FixEdit) string {
+	for _, v := range r.violations {
+		if v.KataID == e.KataID && v.Line == e.Line {
+			return v.Message
+		}
+	}
+	if k, ok := r.registry.GetKata(e.KataID); ok {
+		return k.Title
+	}
+	return ""
+}
+
+// groupKey identifies an edit group by its kata, line, the text it
+// replaces and the replacement — which all survive an accepted rewrite
+// elsewhere on the line that shifts its column.
+func (r *fixReviewer) groupKey(src string, group []katas.FixEdit) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s:%d", group[0].KataID, group[0].Line)
+	for _, e := range group {
+		b.WriteString("\x00" + spanText(src, e) + "\x00" + e.Replace)
+	}
+	return b.String()
+}
+
+// spanKey identifies an edit group by what it does, ignoring which kata
+// proposed it.
+func spanKey(group []katas.FixEdit) string {
+	var b strings.Builder
+	for _, e := range group {
+		fmt.Fprintf(&b, "%d:%d:%d:%s\x00", e.Line, e.Column, e.Length, e.Replace)
+	}
+	return b.String()
+}
+
+// colorDiff paints removed lines red, added lines green and hunk headers
+// cyan.
+func (r *fixReviewer) colorDiff(diff string) string {
+	var b strings.Builder
+	for _, line := range strings.SplitAfter(diff, "\n") {
+		text := strings.TrimSuffix(line, "\n")
+		nl := line[len(text):]
+		switch {
+		case strings.HasPrefix(text, "---"), strings.HasPrefix(text, "+++"):
+			b.WriteString(r.color.bold(text))
+		case strings.HasPrefix(text, "@@"):
+			b.WriteString(r.color.wrap("36", text))
+		case strings.HasPrefix(text, "-"):
+			b.WriteString(r.color.wrap("31", text))
+		case strings.HasPrefix(text, "+"):
+			b.WriteString(r.color.wrap("32", text))
+		default:
+			b.WriteString(text)
+		}
+		b.WriteString(nl)
+	}
+	return b.String()
+}
+
+// groupEdits splits edits into the groups reviewed together: consecutive
+// edits from the same kata on the same line, which is how a kata's
+// multi-part rewrite of one finding arrives.
+func groupEdits(edits []katas.FixEdit) [][]katas.FixEdit {
+	var groups [][]katas.FixEdit
+	for i, e := range edits {
+		if i > 0 {
+			prev := edits[i-1]
+			if prev.KataID == e.KataID && prev.Line == e.Line {
+				groups[len(groups)-1] = append(groups[len(groups)-1], e)
+				continue
+			}
+		}
+		groups = append(groups, []katas.FixEdit{e})
+	}
+	return groups
+}
+
+// spanText returns the source text an edit replaces, or "" when the edit
+// does not resolve against src.
+func spanText(src string, e katas.FixEdit) string {
+	off := katas.LineColToByteOffset([]byte(src), e.Line, e.Column)
+	if off < 0 || off+e.Length > len(src) {
+		return ""
+	}
+	return src[off : off+e.Length]
+}
@@ -0,0 +1,88 @@
+// SPDX-License-Identifier: MIT
+// Copyright the ZShellCheck contributors.
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/afadesigns/zshellcheck/pkg/config"
+	"github.com/afadesigns/zshellcheck/pkg/katas"
+)
+
+// reviewFixes runs the interactive fixer over src with answers as stdin and
+// returns the fixed source and the prompt transcript.
+func reviewFixes(t *testing.T, src, answers string) (string, string) {
+	t.Helper()
+	katas.Registry.SetUnsafeFixes(true)
+	t.Cleanup(func() { katas.Registry.SetUnsafeFixes(false) })
+	var out bytes.Buffer
+	r := newFixReviewer(strings.NewReader(answers), &out, palette{}, katas.Registry)
+	r.filename = "s.zsh"
+	cfg := config.DefaultConfig()
+	edits := collectEdits(src, katas.Registry, nil, cfg, nil, true)
+	outcome, err := applyReviewedFixes(src, edits, r.review, katas.Registry, nil, cfg, nil, 5, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return outcome.source, out.String()
+}
+
+func TestFixReviewerAnswers(t *testing.T) {
+	src := "x=`date`\necho -e \"a\"\ny=`id`\n"
+	for _, tc := range []struct {
+		name, answers, want string
+	}{
+		{"yes and no", "n\ny\nn\n", "x=`date`\nprint \"a\"\ny=`id`\n"},
+		{"all for kata", "a\nn\n", "x=$(date)\necho -e \"a\"\ny=$(id)\n"},
+		{"quit", "y\nq\ny\n", "x=$(date)\necho -e \"a\"\ny=`id`\n"},
+		{"end of input quits", "y\n", "x=$(date)\necho -e \"a\"\ny=`id`\n"},
+		{"edit", "e\n$( date )\nn\nn\n", "x=$( date )\necho -e \"a\"\ny=`id`\n"},
+		{"help then answer", "?\nn\nn\ny\n", "x=`date`\necho -e \"a\"\ny=$(id)\n"},
+	} {
+		got, transcript := reviewFixes(t, src, tc.answers)
+		if got != tc.want {
+			t.Errorf("%s: got %q, want %q\n%s", tc.name, got, tc.want, transcript)
+		}
+	}
+}
+
+func TestFixReviewerTranscript(t *testing.T) {
+	_, transcript := reviewFixes(t, "x=`date`\n", "n\n")
+	for _, want := range []string{
+		"s.zsh:1:3: [ZC1002] ",
+		"-x=`date`\n+x=$(date)\n",
+		"Apply this fix [y,n,a,q,e,?]? ",
+	} {
+		if !strings.Contains(transcript, want) {
+			t.Errorf("transcript missing %q:\n%s", want, transcript)
+		}
+	}
+	// ZC1015 proposes the same rewrite as ZC1002; it is asked once.
+	if n := strings.Count(transcript, "Apply this fix"); n != 1 {
+		t.Errorf("asked %d times, want once:\n%s", n, transcript)
+	}
+}
+
+// A fix exposed by an earlier rewrite is reviewed in the next pass, and a
+// declined fix is not asked about again.
+func TestFixReviewerLaterPasses(t *testing.T) {
+	got, transcript := reviewFixes(t, "x=`which git`\n", "y\nn\nn\n")
+	if got != "x=$(which git)\n" {
+		t.Errorf("got %q", got)
+	}
+	if n := strings.Count(transcript, "Apply this fix"); n != 3 {
+		t.Errorf("asked %d times, want 3:\n%s", n, transcript)
+	}
+}
+
+func TestGroupEdits(t *testing.T) {
+	groups := groupEdits([]katas.FixEdit{
+		{KataID: "ZC1", Line: 1, Column: 1}, {KataID: "ZC1", Line: 1, Column: 5},
+		{KataID: "ZC2", Line: 1, Column: 7}, {KataID: "ZC1", Line: 2, Column: 1},
+	})
+	if len(groups) != 3 || len(groups[0]) != 2 {
+		t.Errorf("groups = %+v", groups)
+	}
+}
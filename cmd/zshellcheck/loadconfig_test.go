@@ -5,6 +5,7 @@ package main
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -37,6 +38,18 @@ func TestLoadConfigBranches_ValidYAML(t *testing.T) {
 	}
 }
 
+func TestLoadConfigBranches_InvalidColor(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "cfg.yml")
+	if err := os.WriteFile(path, []byte("warning_color: \"#ff8800\"\nerror_color: crimson\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	_, err := loadConfig(path)
+	if err == nil || !strings.Contains(err.Error(), "error_color") {
+		t.Errorf("loadConfig() = %v, want an error_color error", err)
+	}
+}
+
 func TestLoadConfigBranches_InvalidYAML(t *testing.T) {
 	dir := t.TempDir()
 	path := filepath.Join(dir, "cfg.yml")
@@ -8,8 +8,10 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"regexp"
 	"runtime/pprof"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/afadesigns/zshellcheck/pkg/ast"
@@ -27,25 +29,36 @@ func main() {
 }
 
 type runFlags struct {
-	format         *string
-	cpuprofile     *string
-	showVersion    *bool
-	verbose        *bool
-	noColor        *bool
-	noBanner       *bool
-	severityFilter *string
-	fixMode        *bool
-	diffMode       *bool
-	dryRun         *bool
-	unsafeFixes    *bool
-	listRules      *bool
-	explain        *string
-	statistics     *bool
-	baseline       *string
-	baselineWrite  *string
-	ruleSeverity   *string
-	addNoka        *bool
-	detectStale    *bool
+	format          *string
+	output          *string
+	ndjsonEvents    *bool
+	sarifSuppressed *bool
+	cpuprofile      *string
+	showVersion     *bool
+	verbose         *bool
+	compact         *bool
+	context         *int
+	tabWidth        *int
+	noColor         *bool
+	noBanner        *bool
+	severityFilter  *string
+	fixMode         *bool
+	diffMode        *bool
+	dryRun          *bool
+	unsafeFixes     *bool
+	fixChoose       *string
+	interactive     *bool
+	fixPatch        *string
+	verifyFixes     *bool
+	backupSuffix    *string
+	listRules       *bool
+	explain         *string
+	statistics      *bool
+	baseline        *string
+	baselineWrite   *string
+	ruleSeverity    *string
+	addNoka         *bool
+	detectStale     *bool
 }
 
 func run() int {
@@ -81,7 +94,29 @@ func run() int {
 		fmt.Fprintf(os.Stderr, "Error loading config: %s\n", err)
 		return 1
 	}
-	cfg = applyFlagOverrides(cfg, *flags.noColor, *flags.verbose)
+	cfg = applyFlagOverrides(cfg, *flags.noColor || *flags.output != "" || !newPalette(os.Stdout).enabled, *flags.verbose)
+	if *flags.compact {
+		cfg.Compact = true
+	}
+	if *flags.context > 0 {
+		cfg.Context = *flags.context
+	}
+	if *flags.tabWidth > 0 {
+		cfg.TabWidth = *flags.tabWidth
+	}
+	if err := applyRuleOptions(katas.Registry, cfg.Rules); err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %s\n", err)
+		return 1
+	}
+	if err := applyFixChoices(katas.Registry, *flags.fixChoose); err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err)
+		return 1
+	}
+	// -verify-fixes checks every fix, unsafe ones included.
+	katas.Registry.SetUnsafeFixes(*flags.unsafeFixes || *flags.verifyFixes)
+	if *flags.verifyFixes {
+		return runVerifyFixes(os.Stdout, os.Stderr, flag.Args(), cfg, katas.Registry)
+	}
 
 	allowedSeverities, code := parseSeverityFilter(*flags.severityFilter)
 	if code != 0 {
@@ -91,8 +126,33 @@ func run() int {
 	if code := configureModes(flags, &fixOpts); code != 0 {
 		return code
 	}
-	total := scanArgs(cfg, allowedSeverities, *flags.format, fixOpts)
+	if code := setupInteractive(flags, cfg, &fixOpts); code != 0 {
+		return code
+	}
+	if code := setupFixPatch(flags, &fixOpts); code != 0 {
+		return code
+	}
+	out, closeOut, err := openOutput(*flags.output)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening output: %s\n", err)
+		return 1
+	}
+	if *flags.format == "ndjson" {
+		fixOpts.stream = reporter.NewNDJSONStream(out, *flags.ndjsonEvents)
+	}
+	fixOpts.includeSuppressed = *flags.sarifSuppressed
+	total := scanArgs(out, cfg, allowedSeverities, *flags.format, fixOpts)
+	if err := closeOut(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing output: %s\n", err)
+		return 1
+	}
 	emitFixSummary(fixOpts.stats)
+	if fixOpts.patch != nil {
+		if err := fixOpts.patch.write(os.Stderr); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing fix patch: %s\n", err)
+			return 1
+		}
+	}
 	return runResult(flags, total, fixOpts)
 }
 
@@ -101,6 +161,11 @@ func run() int {
 func configureModes(flags runFlags, fixOpts *fixOptions) int {
 	if *flags.statistics {
 		fixOpts.statistics = map[string]int{}
+	} else if *flags.format == "text" {
+		// Only the text report shows the `[*]` marker and the fixable
+		// footer, so only it counts findings by their graded fix.
+		fixOpts.fixable = new(int)
+		fixOpts.unsafeFixable = new(int)
 	}
 	regrade, err := parseRuleSeverity(*flags.ruleSeverity)
 	if err != nil {
@@ -109,6 +174,7 @@ func configureModes(flags runFlags, fixOpts *fixOptions) int {
 	}
 	fixOpts.ruleSeverity = regrade
 	fixOpts.addNoka = *flags.addNoka
+	fixOpts.backupSuffix = *flags.backupSuffix
 	if *flags.detectStale {
 		fixOpts.detectStale = true
 		fixOpts.staleCount = new(int)
@@ -116,6 +182,43 @@ func configureModes(flags runFlags, fixOpts *fixOptions) int {
 	return setupBaseline(fixOpts, *flags.baseline, *flags.baselineWrite)
 }
 
+// setupInteractive attaches a fix reviewer reading answers from stdin
+// when -interactive is set. Prompts go to stderr so stdout keeps only the
+// report.
+func setupInteractive(flags runFlags, cfg config.Config, fixOpts *fixOptions) int {
+	if !*flags.interactive {
+		return 0
+	}
+	if !*flags.fixMode {
+		fmt.Fprintln(os.Stderr, "-interactive requires -fix")
+		return 1
+	}
+	color := newPalette(os.Stderr)
+	if cfg.NoColor {
+		color = palette{}
+	}
+	fixOpts.review = newFixReviewer(os.Stdin, os.Stderr, color, katas.Registry)
+	return 0
+}
+
+// setupFixPatch switches the run to -fix-patch: fixes are computed as for
+// -fix but collected into a patch instead of written back.
+func setupFixPatch(flags runFlags, fixOpts *fixOptions) int {
+	if *flags.fixPatch == "" {
+		return 0
+	}
+	if *flags.fixMode || *flags.diffMode || *flags.interactive {
+		fmt.Fprintln(os.Stderr, "-fix-patch cannot be combined with -fix, -diff or -interactive")
+		return 1
+	}
+	fixOpts.patch = &patchState{path: *flags.fixPatch}
+	fixOpts.enabled = true
+	if fixOpts.stats == nil {
+		fixOpts.stats = &fixStats{}
+	}
+	return 0
+}
+
 // runResult turns the scan total and run mode into the process exit code,
 // emitting any deferred output (baseline file, statistics table).
 func runResult(flags runFlags, total int, fixOpts fixOptions) int {
@@ -193,25 +296,36 @@ func setupBaseline(fixOpts *fixOptions, baseline, baselineWrite string) int {
 
 func registerRunFlags() runFlags {
 	return runFlags{
-		format:         flag.String("format", "text", "Output format. One of text, json, sarif."),
-		cpuprofile:     flag.String("cpuprofile", "", "Write a Go pprof CPU profile to this path."),
-		showVersion:    flag.Bool("version", false, "Print the version and exit."),
-		verbose:        flag.Bool("verbose", false, "Include the full kata description under each violation."),
-		noColor:        flag.Bool("no-color", false, "Disable ANSI colours in the report."),
-		noBanner:       flag.Bool("no-banner", false, "Suppress the startup banner — useful for CI and scripted runs."),
-		severityFilter: flag.String("severity", "", "Comma-separated minimum severities to surface (error, warning, info, style)."),
-		fixMode:        flag.Bool("fix", false, "Apply auto-fixes in place for katas that ship a deterministic rewrite."),
-		diffMode:       flag.Bool("diff", false, "Print a unified diff of the fixes instead of writing them."),
-		dryRun:         flag.Bool("dry-run", false, "With -fix, report what would change without modifying files."),
-		unsafeFixes:    flag.Bool("unsafe-fixes", false, "Also apply fixes that may change runtime behavior (off by default)."),
-		listRules:      flag.Bool("list-rules", false, "Print every kata (ID, severity, title) and exit."),
-		explain:        flag.String("explain", "", "Print the full description of a kata by ID (e.g. ZC1001) and exit."),
-		statistics:     flag.Bool("statistics", false, "Print a per-kata count of findings instead of individual reports."),
-		baseline:       flag.String("baseline", "", "Suppress findings recorded in this baseline file; report only new ones."),
-		baselineWrite:  flag.String("baseline-write", "", "Write a baseline snapshot of current findings to this path and exit 0."),
-		ruleSeverity:   flag.String("rule-severity", "", "Re-grade katas: comma-separated ZC####:level (error, warning, info, style)."),
-		addNoka:        flag.Bool("add-noka", false, "Append a `# noka: ZC####` directive to every line with a finding, then exit."),
-		detectStale:    flag.Bool("detect-stale-noka", false, "Report `# noka` directives that suppress no actual finding."),
+		format:          flag.String("format", "text", "Output format. One of text, json, json-v2, ndjson, sarif, checkstyle, junit, gitlab, github, rdjson, rdjsonl, shellcheck-json1, gcc, html."),
+		output:          flag.String("o", "", "Write the report to this file instead of stdout; colour is off."),
+		ndjsonEvents:    flag.Bool("ndjson-events", false, "With -format ndjson, also write a record per parse error and one as each file finishes."),
+		sarifSuppressed: flag.Bool("sarif-include-suppressed", false, "With -format sarif, also list findings silenced by # noka or the baseline, marked as suppressed."),
+		cpuprofile:      flag.String("cpuprofile", "", "Write a Go pprof CPU profile to this path."),
+		showVersion:     flag.Bool("version", false, "Print the version and exit."),
+		verbose:         flag.Bool("verbose", false, "Include the full kata description under each violation."),
+		compact:         flag.Bool("compact", false, "Print each finding on one line, without the source snippet."),
+		context:         flag.Int("context", 0, "Show this many source lines before and after each finding."),
+		tabWidth:        flag.Int("tab-width", 0, "Lay out tabs in source snippets with stops every this many columns (default 8)."),
+		noColor:         flag.Bool("no-color", false, "Disable ANSI colours in the report (also off when stdout is not a terminal or NO_COLOR is set)."),
+		noBanner:        flag.Bool("no-banner", false, "Suppress the startup banner — useful for CI and scripted runs."),
+		severityFilter:  flag.String("severity", "", "Comma-separated minimum severities to surface (error, warning, info, style)."),
+		fixMode:         flag.Bool("fix", false, "Apply auto-fixes in place for katas that ship a deterministic rewrite."),
+		diffMode:        flag.Bool("diff", false, "Print a unified diff of the fixes instead of writing them."),
+		dryRun:          flag.Bool("dry-run", false, "With -fix, report what would change without modifying files."),
+		unsafeFixes:     flag.Bool("unsafe-fixes", false, "Also apply fixes that may change runtime behavior (off by default)."),
+		interactive:     flag.Bool("interactive", false, "With -fix, review each fix as a diff and choose whether to apply it."),
+		fixPatch:        flag.String("fix-patch", "", "Write every applicable fix as one git patch to this path, plus a <path>.json hunk manifest; files are left untouched."),
+		backupSuffix:    flag.String("backup-suffix", "", "With -fix or -add-noka, keep each rewritten file's original at its path plus this suffix (e.g. .orig)."),
+		verifyFixes:     flag.Bool("verify-fixes", false, "Check every auto-fix on the given scripts: it must remove its finding, add none, converge and keep the rest of the parse."),
+		fixChoose:       flag.String("fix-choose", "", "Apply a kata's n-th suggested fix instead of its first: comma-separated ZC####=n."),
+		listRules:       flag.Bool("list-rules", false, "Print every kata (ID, severity, title) and exit."),
+		explain:         flag.String("explain", "", "Print the full description of a kata by ID (e.g. ZC1001) and exit."),
+		statistics:      flag.Bool("statistics", false, "Print a per-kata count of findings instead of individual reports."),
+		baseline:        flag.String("baseline", "", "Suppress findings recorded in this baseline file; report only new ones."),
+		baselineWrite:   flag.String("baseline-write", "", "Write a baseline snapshot of current findings to this path and exit 0."),
+		ruleSeverity:    flag.String("rule-severity", "", "Re-grade katas: comma-separated ZC####:level (error, warning, info, style)."),
+		addNoka:         flag.Bool("add-noka", false, "Append a `# noka: ZC####` directive to every line with a finding, then exit."),
+		detectStale:     flag.Bool("detect-stale-noka", false, "Report `# noka` directives that suppress no actual finding."),
 	}
 }
 
@@ -245,7 +359,7 @@ func printRunUsage(noBanner bool) {
 }
 
 func maybeEmitBanner(format string, noColor, noBanner bool) {
-	if format == "json" || format == "sarif" || noColor || noBanner {
+	if isAggregateFormat(format) || format == "ndjson" || noColor || noBanner {
 		return
 	}
 	fmt.Fprint(os.Stderr, config.Banner)
@@ -341,48 +455,111 @@ func buildFixOpts(fixMode, diffMode, dryRun, unsafeFixes bool) fixOptions {
 	if opts.enabled {
 		opts.stats = &fixStats{}
 	}
-	opts.fixable = new(int)
-	opts.unsafeFixable = new(int)
 	return opts
 }
 
-func scanArgs(cfg config.Config, allowed []katas.Severity, format string, fixOpts fixOptions) int {
+// openOutput returns where the report goes: the file path names, or
+// stdout when path is empty. closeOut closes the file; for stdout it does
+// nothing.
+func openOutput(path string) (out io.Writer, closeOut func() error, err error) {
+	if path == "" {
+		return os.Stdout, func() error { return nil }, nil
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return f, f.Close, nil
+}
+
+func scanArgs(out io.Writer, cfg config.Config, allowed []katas.Severity, format string, fixOpts fixOptions) int {
 	// The machine-readable formats accumulate findings across every file
 	// and are emitted once as a single document; without this each file
 	// printed its own array, so multi-file output was not valid JSON or
 	// SARIF. Text streams per file.
 	var collector *[]reporter.FileViolations
-	if format == "json" || format == "sarif" {
+	if isAggregateFormat(format) {
 		collector = &[]reporter.FileViolations{}
 		fixOpts.collector = collector
+		// SARIF needs the silenced findings to list them as suppressed,
+		// and, against a baseline, to tell unchanged and absent results.
+		filtering := fixOpts.baseline != nil && !fixOpts.baseline.write
+		fixOpts.recordAll = format == "json-v2" || (format == "sarif" && (fixOpts.includeSuppressed || filtering))
 	}
 	total := 0
 	for _, filename := range flag.Args() {
-		total += processPath(filename, os.Stdout, os.Stderr, cfg, katas.Registry, format, allowed, fixOpts)
+		total += processPath(filename, out, os.Stderr, cfg, katas.Registry, format, allowed, fixOpts)
 	}
 	if collector != nil {
-		emitAggregate(os.Stdout, os.Stderr, format, *collector)
+		emitAggregate(out, os.Stderr, format, *collector, runInfo(cfg, allowed, fixOpts))
 	}
 	return total
 }
 
+// runInfo describes this run's version and configuration for the formats
+// that record them.
+func runInfo(cfg config.Config, allowed []katas.Severity, fixOpts fixOptions) reporter.RunInfo {
+	run := reporter.RunInfo{
+		ToolVersion:       version.Version,
+		DisabledKatas:     cfg.DisabledKatas,
+		Severities:        allowed,
+		UnsafeFixes:       fixOpts.unsafe,
+		IncludeSuppressed: fixOpts.includeSuppressed,
+	}
+	if fixOpts.baseline != nil {
+		run.Baseline = fixOpts.baseline.path
+	}
+	return run
+}
+
+// isAggregateFormat reports whether format is one of the machine-readable
+// formats collected across every file and emitted as one document.
+func isAggregateFormat(format string) bool {
+	switch format {
+	case "json", "json-v2", "sarif", "checkstyle", "junit", "gitlab", "github", "rdjson", "rdjsonl", "shellcheck-json1", "gcc", "html":
+		return true
+	}
+	return false
+}
+
 // emitAggregate writes the collected findings for the machine-readable
 // formats as a single document.
-func emitAggregate(out, errOut io.Writer, format string, files []reporter.FileViolations) {
+func emitAggregate(out, errOut io.Writer, format string, files []reporter.FileViolations, run reporter.RunInfo) {
 	var err error
 	switch format {
 	case "json":
 		err = reporter.ReportJSON(out, files)
+	case "json-v2":
+		err = reporter.ReportJSONV2(out, files, run, sarifRuleMeta)
 	case "sarif":
-		err = reporter.ReportSARIF(out, files, version.Version, sarifRuleMeta)
+		err = reporter.ReportSARIFWith(out, files, run, sarifRuleMeta)
+	case "checkstyle":
+		err = reporter.ReportCheckstyle(out, files)
+	case "junit":
+		err = reporter.ReportJUnit(out, files)
+	case "gitlab":
+		err = reporter.ReportGitLab(out, files)
+	case "github":
+		err = reporter.ReportGitHub(out, files)
+	case "rdjson":
+		err = reporter.ReportRDJSON(out, files, sarifRuleMeta)
+	case "rdjsonl":
+		err = reporter.ReportRDJSONL(out, files, sarifRuleMeta)
+	case "shellcheck-json1":
+		err = reporter.ReportShellCheckJSON1(out, files)
+	case "gcc":
+		err = reporter.ReportGCC(out, files)
+	case "html":
+		err = reporter.ReportHTML(out, files, version.Version, sarifRuleMeta)
 	}
 	if err != nil {
 		fmt.Fprintf(errOut, "Error reporting violations: %s\n", err)
 	}
 }
 
-// sarifRuleMeta supplies SARIF rule metadata for a kata ID from the
-// registry: its title, full description, and a link to the kata catalog.
+// sarifRuleMeta supplies rule metadata for a kata ID from the registry —
+// its title, full description, tags, and a link to the kata catalog — to
+// the SARIF, json-v2, reviewdog and HTML reports.
 func sarifRuleMeta(id string) reporter.RuleMeta {
 	k, ok := katas.Registry.GetKata(id)
 	if !ok {
@@ -393,6 +570,8 @@ func sarifRuleMeta(id string) reporter.RuleMeta {
 		Title:       k.Title,
 		Description: k.Description,
 		HelpURI:     "https://github.com/afadesigns/zshellcheck/blob/main/KATAS.md",
+		Tags:        k.Tags,
+		Severity:    k.Severity,
 	}
 }
 
@@ -402,6 +581,9 @@ func emitFixSummary(stats *fixStats) {
 	}
 	fmt.Fprintf(os.Stderr, "\nfix summary: %d edit(s) across %d file(s) (scanned %d)\n",
 		stats.totalEdits, stats.filesModified, stats.filesScanned)
+	if stats.deferredEdits > 0 {
+		fmt.Fprintf(os.Stderr, "%d edit(s) deferred; re-run -fix to apply them\n", stats.deferredEdits)
+	}
 }
 
 func finalExitCode(total int, format string, fixOpts fixOptions) int {
@@ -448,7 +630,7 @@ func loadConfig(paths ...string) (config.Config, error) {
 		cfg = config.MergeConfig(cfg, fileConfig)
 	}
 
-	return cfg, nil
+	return cfg, cfg.ValidateColors()
 }
 
 type fixOptions struct {
@@ -461,9 +643,22 @@ type fixOptions struct {
 	// directory tree. nil when -fix is disabled.
 	stats *fixStats
 	// collector accumulates per-file findings for the machine-readable
-	// formats so they are emitted once as a single JSON / SARIF document.
+	// formats so they are emitted once as a single document. Clean files
+	// are collected too, for the formats that list every file checked.
 	// nil for the text format, which streams per file.
 	collector *[]reporter.FileViolations
+	// stream, when non-nil, writes each file's findings as NDJSON records
+	// as soon as the file is done (-format ndjson).
+	stream *reporter.NDJSONStream
+	// recordAll also collects files that failed to parse and findings a
+	// `# noka` directive or the baseline silenced, for the formats that
+	// report them (-format json-v2, and -format sarif with a baseline or
+	// -sarif-include-suppressed), and the baseline findings no longer
+	// found.
+	recordAll bool
+	// includeSuppressed lists the silenced findings in the SARIF report
+	// (-sarif-include-suppressed).
+	includeSuppressed bool
 	// unsafe applies fixes that may change runtime behavior. When false,
 	// only value-preserving (safe) fixes are applied.
 	unsafe bool
@@ -487,6 +682,14 @@ type fixOptions struct {
 	// staleCount tallies them for the exit code.
 	detectStale bool
 	staleCount  *int
+	// review, when non-nil, asks before applying each fix (-interactive).
+	review *fixReviewer
+	// patch, when non-nil, collects the fixed sources into one patch
+	// file instead of rewriting them (-fix-patch).
+	patch *patchState
+	// backupSuffix, when set, saves each file's original contents to its
+	// path plus this suffix before -fix or -add-noka rewrites it.
+	backupSuffix string
 }
 
 // fixStats accumulates fix activity across all files visited in one
@@ -495,6 +698,7 @@ type fixStats struct {
 	filesScanned  int
 	filesModified int
 	totalEdits    int
+	deferredEdits int
 }
 
 // applyFixesUntilStable runs fix.Apply repeatedly, re-parsing and
@@ -507,79 +711,97 @@ type fixStats struct {
 // which a second pass then rewrites to `result=$(whence git)`
 // (ZC1005). A single pass would leave the inner stale.
 func applyFixesUntilStable(src string, initialEdits []katas.FixEdit, registry *katas.KatasRegistry, disabled []string, cfg config.Config, allowedSeverities []katas.Severity, maxPasses int, unsafe bool) (string, int, error) {
+	out, err := applyReviewedFixes(src, initialEdits, nil, registry, disabled, cfg, allowedSeverities, maxPasses, unsafe)
+	return out.source, out.applied, err
+}
+
+// editReview picks the edits of one fixer pass to apply; -interactive
+// asks the user.
+type editReview func(src string, edits []katas.FixEdit) []katas.FixEdit
+
+// fixOutcome is the result of a multi-pass fix run.
+type fixOutcome struct {
+	source  string
+	applied int
+	// deferred lists the edits still pending when the run stopped —
+	// dropped for overlapping an applied edit, or past the pass cap —
+	// which a re-run would apply. rejected lists the edits left out
+	// because applying them would break the parse.
+	deferred []katas.FixEdit
+	rejected []katas.FixEdit
+	// trace credits every applied edit to a line of the original source,
+	// so -fix-patch can name the katas behind each hunk.
+	trace *fix.Trace
+}
+
+// applyReviewedFixes is applyFixesUntilStable with a review step: when
+// review is non-nil, each pass applies only the edits it returns. It also
+// reports the edits left unapplied.
+func applyReviewedFixes(src string, initialEdits []katas.FixEdit, review editReview, registry *katas.KatasRegistry, disabled []string, cfg config.Config, allowedSeverities []katas.Severity, maxPasses int, unsafe bool) (fixOutcome, error) {
 	if maxPasses < 1 {
 		maxPasses = 5
 	}
-	current := src
-	totalEdits := 0
+	out := fixOutcome{source: src, trace: fix.NewTrace(src)}
 	edits := initialEdits
 	for pass := 0; pass < maxPasses; pass++ {
-		if len(edits) == 0 {
-			break
-		}
-		next, err := fix.Apply(current, edits)
-		if err != nil {
-			return current, totalEdits, err
+		if review != nil && len(edits) > 0 {
+			edits = review(out.source, edits)
 		}
-		if next == current {
+		if len(edits) == 0 {
 			break
 		}
-		applied := len(edits)
 		// Safety net: never accept a pass that introduces parse errors.
 		// Two fixes can collide on adjacent spans (for example ZC1073
 		// deleting the `$` while another rewrites the same token) and
 		// produce broken source. Rather than write it, keep only the
 		// edits that are individually safe.
-		if parseErrorCount(next) > parseErrorCount(current) {
-			next, applied = applySafeEdits(current, edits)
-			if next == current {
-				break
-			}
+		res, err := fix.ApplyParseSafe(out.source, edits)
+		if err != nil {
+			return out, err
 		}
-		totalEdits += applied
-		current = next
+		if res.Source == out.source {
+			break
+		}
+		out.applied += len(res.Applied)
+		out.trace.Pass(out.source, res.Source, res.Applied)
+		out.source = res.Source
 		// Re-collect edits from the new source.
-		edits = collectEdits(current, registry, disabled, cfg, allowedSeverities, unsafe)
-	}
-	return current, totalEdits, nil
-}
-
-// parseErrorCount reports how many parser errors src produces. Used by
-// the auto-fix safety net to reject any rewrite that breaks the parse.
-func parseErrorCount(src string) int {
-	p := parser.New(lexer.New(src))
-	p.ParseProgram()
-	return len(p.Errors())
-}
-
-// applySafeEdits is the fallback when the full edit batch breaks the
-// parse. It applies edits one at a time, highest source offset first, and
-// keeps an edit only when it does not raise the parser-error count of the
-// accumulated result. Highest-offset-first ordering means accepting an
-// edit never shifts the line/column of the edits not yet tried, so each
-// stays valid against the growing source. A failed apply or a fix that
-// would break the parse is simply skipped, so a broken file is never
-// written.
-func applySafeEdits(base string, edits []katas.FixEdit) (string, int) {
-	baseErrs := parseErrorCount(base)
-	ordered := append([]katas.FixEdit(nil), edits...)
-	sort.SliceStable(ordered, func(i, j int) bool {
-		if ordered[i].Line != ordered[j].Line {
-			return ordered[i].Line > ordered[j].Line
-		}
-		return ordered[i].Column > ordered[j].Column
-	})
-	acc := base
-	applied := 0
-	for _, e := range ordered {
-		trial, err := fix.Apply(acc, []katas.FixEdit{e})
-		if err != nil || parseErrorCount(trial) > baseErrs {
-			continue
+		edits = collectEdits(out.source, registry, disabled, cfg, allowedSeverities, unsafe)
+	}
+	// Sort whatever the last pass left over into edits a re-run would
+	// apply and edits that would break the parse. Edits that leave the
+	// source as it is are neither: no re-run ever applies them.
+	edits = changing(out.source, edits)
+	if len(edits) > 0 {
+		if res, err := fix.ApplyParseSafe(out.source, edits); err == nil {
+			out.deferred = append(res.Applied, res.Conflicts...)
+			out.rejected = res.Rejected
+		}
+	}
+	return out, nil
+}
+
+// safelyFixable reports whether -fix applies v's fix without
+// -unsafe-fixes.
+func safelyFixable(v katas.Violation) bool {
+	return katas.WeakestSafety(v.Fix) == katas.FixSafe
+}
+
+// fixableAtAll reports whether -fix -unsafe-fixes applies v's fix.
+func fixableAtAll(v katas.Violation) bool {
+	level := katas.WeakestSafety(v.Fix)
+	return level == katas.FixSafe || level == katas.FixUnsafe
+}
+
+// changing returns the edits that alter src when applied on their own.
+func changing(src string, edits []katas.FixEdit) []katas.FixEdit {
+	var out []katas.FixEdit
+	for _, e := range edits {
+		if got, err := fix.Apply(src, []katas.FixEdit{e}); err == nil && got != src {
+			out = append(out, e)
 		}
-		acc = trial
-		applied++
 	}
-	return acc, applied
+	return out
 }
 
 // collectEdits parses src and returns the auto-fix edits the registry
@@ -597,14 +819,7 @@ func collectEdits(src string, registry *katas.KatasRegistry, disabled []string,
 	if len(directives.File) > 0 {
 		allDisabled = append(append([]string(nil), disabled...), directives.File...)
 	}
-	var violations []katas.Violation
-	var edits []katas.FixEdit
-	ast.Walk(program, func(node ast.Node) bool {
-		vs, es := registry.CheckAndFix(node, allDisabled, []byte(src))
-		violations = append(violations, vs...)
-		edits = append(edits, es...)
-		return true
-	})
+	violations, edits := registry.CheckProgram("", []byte(src), program, allDisabled, true)
 	if len(directives.PerLine) > 0 {
 		keptV := violations[:0]
 		keptE := edits[:0]
@@ -640,45 +855,52 @@ func collectEdits(src string, registry *katas.KatasRegistry, disabled []string,
 }
 
 func processPath(path string, out, errOut io.Writer, cfg config.Config, registry *katas.KatasRegistry, format string, allowedSeverities []katas.Severity, fixOpts fixOptions) int {
+	count := 0
+	walkScripts(path, errOut, func(p string) {
+		count += processFile(p, out, errOut, cfg, registry, format, allowedSeverities, fixOpts)
+	})
+	return count
+}
+
+// walkScripts calls fn for path, or for every script under it when path
+// is a directory, reporting stat and walk errors to errOut.
+func walkScripts(path string, errOut io.Writer, fn func(string)) {
 	info, err := os.Stat(path)
 	if err != nil {
 		fmt.Fprintf(errOut, "Error stating path %s: %s\n", path, err)
-		return 0
+		return
 	}
-
-	count := 0
-	if info.IsDir() {
-		err := filepath.WalkDir(path, func(p string, d os.DirEntry, err error) error {
-			if err != nil {
-				return err
-			}
-			if d.IsDir() {
-				if strings.HasPrefix(d.Name(), ".") && d.Name() != "." && d.Name() != ".." {
-					return filepath.SkipDir // Skip hidden directories like .git
-				}
-				return nil
-			}
-
-			// Skip non-shell files to avoid parsing errors on Go source code etc.
-			ext := filepath.Ext(d.Name())
-			if ext == ".go" || ext == ".md" || ext == ".json" || ext == ".yml" || ext == ".yaml" || ext == ".txt" {
-				return nil
+	if !info.IsDir() {
+		fn(path)
+		return
+	}
+	err = filepath.WalkDir(path, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if strings.HasPrefix(d.Name(), ".") && d.Name() != "." && d.Name() != ".." {
+				return filepath.SkipDir // Skip hidden directories like .git
 			}
+			return nil
+		}
 
-			// Process only files that look like shell scripts?
-			// For now, let's try to parse everything, or maybe filter by extension/shebang if it gets too noisy.
-			// Shellcheck defaults to checking all files passed, but for recursive it might filter.
-			// Let's assume user wants to check all files in the dir if they passed the dir.
-			count += processFile(p, out, errOut, cfg, registry, format, allowedSeverities, fixOpts)
+		// Skip non-shell files to avoid parsing errors on Go source code etc.
+		ext := filepath.Ext(d.Name())
+		if ext == ".go" || ext == ".md" || ext == ".json" || ext == ".yml" || ext == ".yaml" || ext == ".txt" {
 			return nil
-		})
-		if err != nil {
-			fmt.Fprintf(errOut, "Error walking directory %s: %s\n", path, err)
 		}
-	} else {
-		count += processFile(path, out, errOut, cfg, registry, format, allowedSeverities, fixOpts)
+
+		// Process only files that look like shell scripts?
+		// For now, let's try to parse everything, or maybe filter by extension/shebang if it gets too noisy.
+		// Shellcheck defaults to checking all files passed, but for recursive it might filter.
+		// Let's assume user wants to check all files in the dir if they passed the dir.
+		fn(p)
+		return nil
+	})
+	if err != nil {
+		fmt.Fprintf(errOut, "Error walking directory %s: %s\n", path, err)
 	}
-	return count
 }
 
 func processFile(filename string, out, errOut io.Writer, cfg config.Config, registry *katas.KatasRegistry, format string, allowedSeverities []katas.Severity, fixOpts fixOptions) int {
@@ -692,43 +914,77 @@ func processFile(filename string, out, errOut io.Writer, cfg config.Config, regi
 		for _, msg := range errs {
 			fmt.Fprintf(errOut, "Parser Error in %s: %s\n", filename, msg)
 		}
+		if fixOpts.stream != nil {
+			if err := fixOpts.stream.ParseErrors(filename, errs); err != nil {
+				fmt.Fprintf(errOut, "Error reporting violations: %s\n", err)
+			}
+		}
+		if fixOpts.collector != nil && fixOpts.recordAll {
+			*fixOpts.collector = append(*fixOpts.collector, reporter.FileViolations{Filename: filename, Source: data, ParseErrors: parseErrorsOf(errs)})
+		}
 		return 1
 	}
 	directives := config.ParseDirectives(string(data))
 	disabled := mergeDisabled(cfg.DisabledKatas, directives.File)
 
-	violations, edits := collectViolations(program, registry, disabled, data, fixOpts.enabled)
+	// The machine-readable formats carry each finding's fix, and the text
+	// report marks findings by their graded fix, so they run the katas'
+	// fixes even when nothing will be applied. A text -statistics run needs
+	// neither, so it skips them.
+	withFix := fixOpts.enabled || fixOpts.collector != nil || fixOpts.stream != nil || fixOpts.fixable != nil
+	violations, edits := registry.CheckProgram(filename, data, program, disabled, withFix)
 	regradeSeverity(violations, fixOpts.ruleSeverity)
+	// Every finding, silenced or filtered out or not, still counts as
+	// present against the baseline; only a disabled kata's are unknown.
+	var absent []reporter.AbsentFinding
+	if fixOpts.recordAll && fixOpts.baseline != nil && !fixOpts.baseline.write {
+		absent = fixOpts.baseline.absent(filename, data, violations, disabled)
+	}
 	// Stale-suppression detection compares the raw findings against the
 	// `# noka` directives before any are silenced.
 	if fixOpts.detectStale {
 		reportStaleNoka(out, filename, violations, directives, fixOpts.staleCount)
 	}
+	var silenced []katas.Violation
+	if fixOpts.recordAll {
+		silenced = directiveSilenced(violations, directives)
+	}
 	violations, edits = applyDirectiveSilences(violations, edits, directives)
 
 	// add-noka rewrites the file in place, silencing every current finding,
 	// and stops short of severity filtering, fixing, or reporting.
 	if fixOpts.addNoka {
-		if err := addNokaDirectives(filename, data, violations); err != nil {
+		if err := addNokaDirectives(filename, data, violations, fixOpts.backupSuffix); err != nil {
 			fmt.Fprintf(errOut, "add-noka: %s\n", err)
 		}
 		return len(violations)
 	}
 
 	violations, edits = applySeverityFilter(violations, edits, allowedSeverities)
+	silenced, _ = applySeverityFilter(silenced, nil, allowedSeverities)
+	suppressed := suppressedAs(silenced, reporter.SuppressedByNoka, func(v katas.Violation) string {
+		return directives.Reasons[v.Line]
+	})
 
 	// The baseline ratchet records or suppresses findings against a saved
 	// snapshot. Write mode collects them and stops short of fixing or
 	// reporting; filter mode leaves only findings new since the baseline.
 	if fixOpts.baseline != nil {
-		violations = fixOpts.baseline.applyBaseline(filename, data, violations)
+		var known []katas.Violation
+		violations, known = fixOpts.baseline.split(filename, data, violations)
 		if fixOpts.baseline.write {
 			return len(violations)
 		}
+		if fixOpts.recordAll {
+			suppressed = append(suppressed, suppressedAs(known, reporter.SuppressedByBaseline, func(katas.Violation) string {
+				return "recorded in the baseline " + fixOpts.baseline.path
+			})...)
+			fixOpts.baseline.markKnown(filename, data, suppressed)
+		}
 	}
 
 	applyFixIfEnabled(filename, data, registry, disabled, cfg, allowedSeverities, edits, violations, fixOpts, out, errOut)
-	emitReport(filename, out, errOut, format, cfg, violations, data, registry, fixOpts)
+	emitReport(filename, out, errOut, format, cfg, violations, suppressed, absent, data, registry, fixOpts)
 	return len(violations)
 }
 
@@ -746,22 +1002,6 @@ func mergeDisabled(base, extra []string) []string {
 	return append(append([]string(nil), base...), extra...)
 }
 
-func collectViolations(program *ast.Program, registry *katas.KatasRegistry, disabled []string, data []byte, withFix bool) ([]katas.Violation, []katas.FixEdit) {
-	violations := []katas.Violation{}
-	var edits []katas.FixEdit
-	ast.Walk(program, func(node ast.Node) bool {
-		if withFix {
-			vs, es := registry.CheckAndFix(node, disabled, data)
-			violations = append(violations, vs...)
-			edits = append(edits, es...)
-		} else {
-			violations = append(violations, registry.Check(node, disabled)...)
-		}
-		return true
-	})
-	return violations, edits
-}
-
 func applyDirectiveSilences(violations []katas.Violation, edits []katas.FixEdit, directives config.Directives) ([]katas.Violation, []katas.FixEdit) {
 	if len(directives.PerLine) == 0 {
 		return violations, edits
@@ -776,6 +1016,50 @@ func applyDirectiveSilences(violations []katas.Violation, edits []katas.FixEdit,
 	return kept, edits
 }
 
+// directiveSilenced returns the findings applyDirectiveSilences drops.
+func directiveSilenced(violations []katas.Violation, directives config.Directives) []katas.Violation {
+	if len(directives.PerLine) == 0 {
+		return nil
+	}
+	var silenced []katas.Violation
+	for _, v := range violations {
+		if directives.IsDisabledOn(v.KataID, v.Line) {
+			silenced = append(silenced, v)
+		}
+	}
+	return silenced
+}
+
+// suppressedAs marks each finding as suppressed by kind, for the reason
+// justify gives.
+func suppressedAs(violations []katas.Violation, kind reporter.SuppressionKind, justify func(katas.Violation) string) []reporter.Suppressed {
+	var out []reporter.Suppressed
+	for _, v := range violations {
+		out = append(out, reporter.Suppressed{Violation: v, Kind: kind, Justification: justify(v)})
+	}
+	return out
+}
+
+// parseErrorPos matches the `line L:C: ` prefix the parser puts on its
+// errors.
+var parseErrorPos = regexp.MustCompile(`^line (\d+):(\d+): `)
+
+// parseErrorsOf splits the parser's error strings into positions and
+// messages. An error without a position keeps its whole text at 0:0.
+func parseErrorsOf(errs []string) []reporter.ParseError {
+	out := make([]reporter.ParseError, 0, len(errs))
+	for _, msg := range errs {
+		pe := reporter.ParseError{Message: msg}
+		if m := parseErrorPos.FindStringSubmatch(msg); m != nil {
+			pe.Line, _ = strconv.Atoi(m[1])
+			pe.Column, _ = strconv.Atoi(m[2])
+			pe.Message = msg[len(m[0]):]
+		}
+		out = append(out, pe)
+	}
+	return out
+}
+
 func applySeverityFilter(violations []katas.Violation, edits []katas.FixEdit, allowed []katas.Severity) ([]katas.Violation, []katas.FixEdit) {
 	if len(allowed) == 0 {
 		return violations, edits
@@ -793,15 +1077,19 @@ func applySeverityFilter(violations []katas.Violation, edits []katas.FixEdit, al
 }
 
 // applicableEdits drops behavior-changing (unsafe) fixes unless the run
-// opted into them with -unsafe-fixes.
+// opted into them with -unsafe-fixes, and suggestion-only fixes always.
+// Each edit is graded on its own, so a kata's per-edit safety and config
+// overrides both apply.
 func applicableEdits(edits []katas.FixEdit, registry *katas.KatasRegistry, unsafe bool) []katas.FixEdit {
-	if unsafe {
-		return edits
-	}
 	kept := edits[:0]
 	for _, e := range edits {
-		if registry.IsSafeFix(e.KataID) {
+		switch registry.EditSafety(e) {
+		case katas.FixSafe:
 			kept = append(kept, e)
+		case katas.FixUnsafe:
+			if unsafe {
+				kept = append(kept, e)
+			}
 		}
 	}
 	return kept
@@ -812,16 +1100,35 @@ func applyFixIfEnabled(filename string, data []byte, registry *katas.KatasRegist
 	if !fixOpts.enabled || len(edits) == 0 || len(violations) == 0 {
 		return
 	}
-	if fixOpts.diff {
+	switch {
+	case fixOpts.patch != nil:
+		collectFixPatch(filename, data, registry, disabled, cfg, allowed, edits, fixOpts, errOut)
+	case fixOpts.diff:
 		emitFixDiff(filename, data, edits, out, errOut)
-	} else if !fixOpts.dryRun {
-		applyFixInPlace(filename, data, registry, disabled, cfg, allowed, edits, fixOpts, errOut)
+	case !fixOpts.dryRun:
+		applyFixInPlace(filename, data, registry, disabled, cfg, allowed, edits, violations, fixOpts, errOut)
 	}
 	if fixOpts.stats != nil {
 		fixOpts.stats.filesScanned++
 	}
 }
 
+// collectFixPatch runs the multi-pass fixer over one file in memory and
+// adds the result to the -fix-patch output.
+func collectFixPatch(filename string, data []byte, registry *katas.KatasRegistry, disabled []string, cfg config.Config, allowed []katas.Severity, edits []katas.FixEdit, fixOpts fixOptions, errOut io.Writer) {
+	outcome, err := applyReviewedFixes(string(data), edits, nil, registry, disabled, cfg, allowed, fixOpts.maxPasses, fixOpts.unsafe)
+	if err != nil {
+		fmt.Fprintf(errOut, "fix: apply failed for %s: %s\n", filename, err)
+		return
+	}
+	fixOpts.patch.add(filename, data, outcome)
+	if fixOpts.stats != nil && outcome.source != string(data) {
+		fixOpts.stats.filesModified++
+		fixOpts.stats.totalEdits += outcome.applied
+	}
+	reportUnapplied(errOut, filename, outcome, fixOpts.stats)
+}
+
 func emitFixDiff(filename string, data []byte, edits []katas.FixEdit, out, errOut io.Writer) {
 	diff, derr := fix.Diff(filename, string(data), edits)
 	if derr != nil {
@@ -833,20 +1140,23 @@ func emitFixDiff(filename string, data []byte, edits []katas.FixEdit, out, errOu
 	}
 }
 
-func applyFixInPlace(filename string, data []byte, registry *katas.KatasRegistry, disabled []string, cfg config.Config, allowed []katas.Severity, edits []katas.FixEdit, fixOpts fixOptions, errOut io.Writer) {
-	fixed, totalEdits, perr := applyFixesUntilStable(string(data), edits, registry, disabled, cfg, allowed, fixOpts.maxPasses, fixOpts.unsafe)
+func applyFixInPlace(filename string, data []byte, registry *katas.KatasRegistry, disabled []string, cfg config.Config, allowed []katas.Severity, edits []katas.FixEdit, violations []katas.Violation, fixOpts fixOptions, errOut io.Writer) {
+	var review editReview
+	if fixOpts.review != nil {
+		fixOpts.review.filename, fixOpts.review.violations = filename, violations
+		review = fixOpts.review.review
+	}
+	outcome, perr := applyReviewedFixes(string(data), edits, review, registry, disabled, cfg, allowed, fixOpts.maxPasses, fixOpts.unsafe)
 	if perr != nil {
 		fmt.Fprintf(errOut, "fix: apply failed for %s: %s\n", filename, perr)
 		return
 	}
+	defer reportUnapplied(errOut, filename, outcome, fixOpts.stats)
+	fixed, totalEdits := outcome.source, outcome.applied
 	if fixed == string(data) {
 		return
 	}
-	mode := os.FileMode(0o600)
-	if info, statErr := os.Stat(filename); statErr == nil {
-		mode = info.Mode().Perm()
-	}
-	if werr := os.WriteFile(filename, []byte(fixed), mode); werr != nil {
+	if werr := rewriteFile(filename, data, []byte(fixed), fixOpts.backupSuffix); werr != nil {
 		fmt.Fprintf(errOut, "fix: write failed for %s: %s\n", filename, werr)
 		return
 	}
@@ -857,8 +1167,24 @@ func applyFixInPlace(filename string, data []byte, registry *katas.KatasRegistry
 	}
 }
 
-func emitReport(filename string, out, errOut io.Writer, format string, cfg config.Config, violations []katas.Violation, data []byte, registry *katas.KatasRegistry, fixOpts fixOptions) {
-	if len(violations) == 0 {
+// reportUnapplied tells the user about the edits a fix run left out, by
+// kata: deferred ones a re-run applies, and rejected ones it never will.
+func reportUnapplied(errOut io.Writer, filename string, outcome fixOutcome, stats *fixStats) {
+	if n := len(outcome.deferred); n > 0 {
+		fmt.Fprintf(errOut, "%d edit(s) deferred in %s (%s); re-run -fix to apply them\n",
+			n, filename, strings.Join(fix.KataIDs(outcome.deferred), ", "))
+	}
+	if n := len(outcome.rejected); n > 0 {
+		fmt.Fprintf(errOut, "%d edit(s) rejected in %s (%s); applying them would break the parse\n",
+			n, filename, strings.Join(fix.KataIDs(outcome.rejected), ", "))
+	}
+	if stats != nil {
+		stats.deferredEdits += len(outcome.deferred)
+	}
+}
+
+func emitReport(filename string, out, errOut io.Writer, format string, cfg config.Config, violations []katas.Violation, suppressed []reporter.Suppressed, absent []reporter.AbsentFinding, data []byte, registry *katas.KatasRegistry, fixOpts fixOptions) {
+	if len(violations) == 0 && ((fixOpts.collector == nil && fixOpts.stream == nil) || fixOpts.statistics != nil) {
 		return
 	}
 	// Statistics mode tallies findings per kata and suppresses the
@@ -872,28 +1198,38 @@ func emitReport(filename string, out, errOut io.Writer, format string, cfg confi
 	// The machine-readable formats are aggregated and emitted once by the
 	// caller; collect this file's findings and return. Text reports inline.
 	if fixOpts.collector != nil {
-		*fixOpts.collector = append(*fixOpts.collector, reporter.FileViolations{Filename: filename, Violations: violations})
+		*fixOpts.collector = append(*fixOpts.collector, reporter.FileViolations{Filename: filename, Violations: violations, Source: data, Suppressed: suppressed, Absent: absent})
+		return
+	}
+	// NDJSON streams this file's findings now, keeping nothing.
+	if fixOpts.stream != nil {
+		if err := fixOpts.stream.File(reporter.FileViolations{Filename: filename, Violations: violations, Source: data}); err != nil {
+			fmt.Fprintf(errOut, "Error reporting violations: %s\n", err)
+		}
 		return
 	}
 	// `[*]` marks the fixes the current command would apply: safe-only, or
-	// every fix under -unsafe-fixes. Findings whose only fix is unsafe are
-	// counted separately so the footer can point at -unsafe-fixes.
-	marked := registry.IsSafeFix
+	// every fix under -unsafe-fixes. A fix is graded by its riskiest edit,
+	// so a kata that is unsafe in general is marked where its edits are
+	// safe. Findings whose fix is unsafe are counted separately so the
+	// footer can point at -unsafe-fixes.
+	marked := safelyFixable
 	if fixOpts.unsafe {
-		marked = registry.IsFixable
+		marked = fixableAtAll
 	}
 	if fixOpts.fixable != nil {
 		for _, v := range violations {
 			switch {
-			case marked(v.KataID):
+			case marked(v):
 				*fixOpts.fixable++
-			case registry.IsFixable(v.KataID) && fixOpts.unsafeFixable != nil:
+			case fixableAtAll(v) && fixOpts.unsafeFixable != nil:
 				*fixOpts.unsafeFixable++
 			}
 		}
 	}
 	r := reporter.NewTextReporter(out, filename, string(data), cfg)
 	r.MarkFixable(marked)
+	r.DescribeWith(sarifRuleMeta)
 	if err := r.Report(violations); err != nil {
 		fmt.Fprintf(errOut, "Error reporting violations: %s\n", err)
 	}
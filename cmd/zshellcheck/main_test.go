@@ -4,15 +4,19 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
 	"flag"
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 
 	"github.com/afadesigns/zshellcheck/pkg/config"
 	"github.com/afadesigns/zshellcheck/pkg/katas"
+	"github.com/afadesigns/zshellcheck/pkg/lexer"
+	"github.com/afadesigns/zshellcheck/pkg/parser"
 	"github.com/afadesigns/zshellcheck/pkg/reporter"
 )
 
@@ -27,18 +31,68 @@ func TestEmitAggregate(t *testing.T) {
 		}},
 	}
 	var buf bytes.Buffer
-	emitAggregate(&buf, &buf, "json", files)
+	emitAggregate(&buf, &buf, "json", files, reporter.RunInfo{})
 	if !strings.Contains(buf.String(), "ZC1") {
 		t.Error("json aggregate missing finding")
 	}
 	buf.Reset()
-	emitAggregate(&buf, &buf, "sarif", files)
+	emitAggregate(&buf, &buf, "sarif", files, reporter.RunInfo{})
 	if !strings.Contains(buf.String(), "2.1.0") {
 		t.Error("sarif aggregate missing version")
 	}
+	buf.Reset()
+	emitAggregate(&buf, &buf, "checkstyle", files, reporter.RunInfo{})
+	if !strings.Contains(buf.String(), `source="ZC1"`) {
+		t.Error("checkstyle aggregate missing finding")
+	}
+	buf.Reset()
+	emitAggregate(&buf, &buf, "junit", files, reporter.RunInfo{})
+	if !strings.Contains(buf.String(), "<testsuites") {
+		t.Error("junit aggregate missing testsuites")
+	}
+	buf.Reset()
+	emitAggregate(&buf, &buf, "gitlab", files, reporter.RunInfo{})
+	if !strings.Contains(buf.String(), `"check_name": "ZC1"`) {
+		t.Error("gitlab aggregate missing finding")
+	}
+	buf.Reset()
+	emitAggregate(&buf, &buf, "github", files, reporter.RunInfo{})
+	if !strings.HasPrefix(buf.String(), "::error file=x.zsh,") {
+		t.Errorf("github aggregate = %q", buf.String())
+	}
+	buf.Reset()
+	emitAggregate(&buf, &buf, "rdjson", files, reporter.RunInfo{})
+	if !strings.Contains(buf.String(), `"diagnostics"`) {
+		t.Error("rdjson aggregate missing diagnostics")
+	}
+	buf.Reset()
+	emitAggregate(&buf, &buf, "rdjsonl", files, reporter.RunInfo{})
+	if !strings.HasPrefix(buf.String(), `{"message":"m"`) {
+		t.Errorf("rdjsonl aggregate = %q", buf.String())
+	}
+	buf.Reset()
+	emitAggregate(&buf, &buf, "shellcheck-json1", files, reporter.RunInfo{})
+	if !strings.HasPrefix(buf.String(), `{"comments":[{"file":"x.zsh"`) {
+		t.Errorf("shellcheck-json1 aggregate = %q", buf.String())
+	}
+	buf.Reset()
+	emitAggregate(&buf, &buf, "html", files, reporter.RunInfo{})
+	if !strings.Contains(buf.String(), "<!DOCTYPE html>") {
+		t.Error("html aggregate missing document")
+	}
+	buf.Reset()
+	emitAggregate(&buf, &buf, "gcc", files, reporter.RunInfo{})
+	if got := buf.String(); got != "x.zsh:1:1: error: m [ZC1]\n" {
+		t.Errorf("gcc aggregate = %q", got)
+	}
+	buf.Reset()
+	emitAggregate(&buf, &buf, "json-v2", files, reporter.RunInfo{ToolVersion: "1.2.3"})
+	if !strings.Contains(buf.String(), `"version": "1.2.3"`) || !strings.Contains(buf.String(), `"kataId": "ZC1"`) {
+		t.Errorf("json-v2 aggregate = %q", buf.String())
+	}
 	// Error branch: a writer that always fails.
 	var errBuf bytes.Buffer
-	emitAggregate(failingWriter{}, &errBuf, "json", files)
+	emitAggregate(failingWriter{}, &errBuf, "json", files, reporter.RunInfo{})
 	if !strings.Contains(errBuf.String(), "Error reporting") {
 		t.Errorf("expected error reported, got %q", errBuf.String())
 	}
@@ -121,6 +175,48 @@ func TestRun_SarifFormat(t *testing.T) {
 	_ = code
 }
 
+func TestRun_OutputFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "test.zsh")
+	if err := os.WriteFile(path, []byte("x=`date`\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	report := filepath.Join(dir, "report.html")
+
+	resetFlags()
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	os.Args = []string{"zshellcheck", "-format", "html", "-o", report, path}
+
+	if code := run(); code != 1 {
+		t.Errorf("exit code = %d, want 1 for findings", code)
+	}
+	data, err := os.ReadFile(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "<!DOCTYPE html>") || !strings.Contains(string(data), "ZC1002") {
+		t.Errorf("report file does not hold the HTML report:\n%.200s", data)
+	}
+}
+
+func TestRun_OutputFileUnwritable(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "test.zsh")
+	if err := os.WriteFile(path, []byte("echo hi\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	resetFlags()
+	oldArgs := os.Args
+	defer func() { os.Args = oldArgs }()
+	os.Args = []string{"zshellcheck", "-o", filepath.Join(dir, "missing", "out.txt"), path}
+
+	if code := run(); code != 1 {
+		t.Errorf("exit code = %d, want 1", code)
+	}
+}
+
 func TestRun_WithSeverityFilter(t *testing.T) {
 	dir := t.TempDir()
 	path := filepath.Join(dir, "test.zsh")
@@ -436,6 +532,171 @@ func TestProcessFile_SarifFormat(t *testing.T) {
 	_ = count
 }
 
+func TestProcessFile_CollectsFixes(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "test.zsh")
+	if err := os.WriteFile(path, []byte("x=`date`\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var out, errOut bytes.Buffer
+	files := []reporter.FileViolations{}
+	processFile(path, &out, &errOut, config.DefaultConfig(), katas.Registry, "json", nil, fixOptions{collector: &files})
+	if len(files) != 1 {
+		t.Fatalf("collected %d files, want 1", len(files))
+	}
+	// The report carries fixes although none are applied.
+	for _, v := range files[0].Violations {
+		if v.KataID == "ZC1002" {
+			if len(v.Fix) == 0 || v.Fix[0].Safety == "" {
+				t.Errorf("ZC1002 fix = %+v, want graded edits", v.Fix)
+			}
+			return
+		}
+	}
+	t.Errorf("no ZC1002 finding in %+v", files[0].Violations)
+}
+
+func TestProcessFile_CollectsCleanFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "clean.zsh")
+	if err := os.WriteFile(path, []byte(""), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var out, errOut bytes.Buffer
+	files := []reporter.FileViolations{}
+	processFile(path, &out, &errOut, config.DefaultConfig(), katas.Registry, "junit", nil, fixOptions{collector: &files})
+	if len(files) != 1 || files[0].Filename != path || len(files[0].Violations) != 0 {
+		t.Errorf("collected %+v, want the clean file with no findings", files)
+	}
+}
+
+func TestProcessFile_StreamsNDJSON(t *testing.T) {
+	dir := t.TempDir()
+	good := filepath.Join(dir, "good.zsh")
+	bad := filepath.Join(dir, "bad.zsh")
+	if err := os.WriteFile(good, []byte("x=`date`\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(bad, []byte("if true; then echo \"unterminated\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var out, errOut bytes.Buffer
+	opts := fixOptions{stream: reporter.NewNDJSONStream(&out, true)}
+	processFile(good, &out, &errOut, config.DefaultConfig(), katas.Registry, "ndjson", nil, opts)
+	// The good file's records are out before the next file is read.
+	if !strings.Contains(out.String(), `"Type":"file","File":"`+good) {
+		t.Fatalf("no file-done record after the first file:\n%s", out.String())
+	}
+	processFile(bad, &out, &errOut, config.DefaultConfig(), katas.Registry, "ndjson", nil, opts)
+
+	var types []string
+	for _, line := range strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n") {
+		var rec struct{ Type, File, KataID string }
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("line is not JSON: %q: %v", line, err)
+		}
+		types = append(types, rec.Type)
+	}
+	if types[0] != "finding" || types[len(types)-1] != "file" || !slices.Contains(types, "parse_error") {
+		t.Errorf("record types = %v, want findings, then parse errors, each file closed", types)
+	}
+}
+
+// The text report's `[*]` follows each finding's graded edits: ZC1015 is
+// unsafe in general, but its fix of a plain backtick body is safe, so
+// -fix applies it and it is marked without -unsafe-fixes.
+func TestProcessFile_MarksFixByEditSafety(t *testing.T) {
+	dir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.NoColor = true
+	cfg.Compact = true
+	for _, tc := range []struct {
+		src    string
+		marked bool
+	}{
+		{"x=`date`\n", true},
+		{"x=`echo \\$HOME`\n", false},
+	} {
+		path := filepath.Join(dir, "s.zsh")
+		if err := os.WriteFile(path, []byte(tc.src), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		var out, errOut bytes.Buffer
+		opts := fixOptions{fixable: new(int), unsafeFixable: new(int)}
+		processFile(path, &out, &errOut, cfg, katas.Registry, "text", nil, opts)
+		var line string
+		for _, l := range strings.Split(out.String(), "\n") {
+			if strings.Contains(l, "[ZC1015]") {
+				line = l
+			}
+		}
+		if line == "" {
+			t.Fatalf("%q: no ZC1015 finding:\n%s", tc.src, out.String())
+		}
+		if got := strings.HasSuffix(line, "[*]"); got != tc.marked {
+			t.Errorf("%q: marked = %v, want %v: %q", tc.src, got, tc.marked, line)
+		}
+		if !tc.marked && *opts.unsafeFixable == 0 {
+			t.Errorf("%q: unsafe fix not counted for the footer", tc.src)
+		}
+	}
+}
+
+func TestProcessFile_RecordsAllForJSONV2(t *testing.T) {
+	dir := t.TempDir()
+	good := filepath.Join(dir, "good.zsh")
+	bad := filepath.Join(dir, "bad.zsh")
+	if err := os.WriteFile(good, []byte("x=`date`  # noka: ZC1002 -- legacy\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(bad, []byte("if true; then echo \"unterminated\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var collector []reporter.FileViolations
+	var out, errOut bytes.Buffer
+	opts := fixOptions{collector: &collector, recordAll: true}
+	processFile(good, &out, &errOut, config.DefaultConfig(), katas.Registry, "json-v2", nil, opts)
+	processFile(bad, &out, &errOut, config.DefaultConfig(), katas.Registry, "json-v2", nil, opts)
+	if len(collector) != 2 {
+		t.Fatalf("collected %d files, want 2", len(collector))
+	}
+	if got := collector[0].Suppressed; len(got) != 1 || got[0].Violation.KataID != "ZC1002" || got[0].Kind != reporter.SuppressedByNoka || got[0].Justification != "legacy" {
+		t.Errorf("suppressed = %+v, want ZC1002 silenced by noka for legacy", got)
+	}
+	for _, v := range collector[0].Violations {
+		if v.KataID == "ZC1002" {
+			t.Error("silenced ZC1002 still reported as a finding")
+		}
+	}
+	if pe := collector[1].ParseErrors; len(pe) == 0 || pe[0].Line == 0 || strings.HasPrefix(pe[0].Message, "line ") {
+		t.Errorf("parse errors = %+v, want positions split from messages", pe)
+	}
+
+	out.Reset()
+	emitAggregate(&out, &errOut, "json-v2", collector, reporter.RunInfo{})
+	var doc struct {
+		Files []struct{ Status string }
+	}
+	if err := json.Unmarshal(out.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.Files) != 2 || doc.Files[0].Status != "findings" || doc.Files[1].Status != "parse-error" {
+		t.Errorf("file statuses = %+v", doc.Files)
+	}
+}
+
+func TestParseErrorsOf(t *testing.T) {
+	got := parseErrorsOf([]string{"line 3:7: expected }", "unexpected EOF"})
+	want := []reporter.ParseError{{Line: 3, Column: 7, Message: "expected }"}, {Message: "unexpected EOF"}}
+	if !slices.Equal(got, want) {
+		t.Errorf("parseErrorsOf = %+v, want %+v", got, want)
+	}
+}
+
 func TestCollectEdits_ParseError(t *testing.T) {
 	// Unbalanced brace forces the parser into an error state.
 	src := "if true; then echo \"unterminated\n"
@@ -550,30 +811,11 @@ func TestApplyFixesUntilStable_NullglobIdempotent(t *testing.T) {
 	}
 }
 
-func TestApplySafeEdits_Bailouts(t *testing.T) {
-	base := "echo hi\n"
-	// Every candidate edit breaks the parse alone -> nothing is kept and
-	// base is returned unchanged.
-	stray := katas.FixEdit{Line: 1, Column: 1, Length: 0, Replace: "fi\n"}
-	if out, n := applySafeEdits(base, []katas.FixEdit{stray}); out != base || n != 0 {
-		t.Errorf("all-break: want base/0, got %q/%d", out, n)
-	}
-	// A safe edit is kept while the breaking one is dropped.
-	safe := katas.FixEdit{Line: 1, Column: 1, Length: 4, Replace: "print -r --"}
-	out, n := applySafeEdits(base, []katas.FixEdit{stray, safe})
-	if n != 1 || !strings.Contains(out, "print -r --") {
-		t.Errorf("mixed: want 1 kept with rewrite, got %q/%d", out, n)
-	}
-
-	// Two safe edits on different lines exercise the highest-offset-first
-	// ordering: the line-two edit applies before the line-one edit.
-	multi := "echo a\necho b\n"
-	e1 := katas.FixEdit{Line: 1, Column: 1, Length: 4, Replace: "print"}
-	e2 := katas.FixEdit{Line: 2, Column: 1, Length: 4, Replace: "print"}
-	mout, mn := applySafeEdits(multi, []katas.FixEdit{e1, e2})
-	if mn != 2 || strings.Contains(mout, "echo") {
-		t.Errorf("multiline: want both applied, got %q/%d", mout, mn)
-	}
+// parseErrorCount reports how many parser errors src produces.
+func parseErrorCount(src string) int {
+	p := parser.New(lexer.New(src))
+	p.ParseProgram()
+	return len(p.Errors())
 }
 
 func TestXdgConfigSearch(t *testing.T) {
@@ -1093,3 +1335,45 @@ func TestProcessFile_ViolationsWithVerbose(t *testing.T) {
 	count := processFile(path, &out, &errOut, cfg, registry, "text", nil, fixOptions{})
 	_ = count
 }
+
+// An edit that rewrites text to itself is not reported as deferred: a
+// re-run would never apply it either.
+func TestApplyReviewedFixes_NoOpEditNotDeferred(t *testing.T) {
+	cfg := config.DefaultConfig()
+	src := "echo hi\n"
+	noop := katas.FixEdit{Line: 1, Column: 1, Length: 4, Replace: "echo", KataID: "ZC1"}
+	outcome, err := applyReviewedFixes(src, []katas.FixEdit{noop}, nil, katas.Registry, nil, cfg, nil, 5, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if outcome.source != src || len(outcome.deferred) != 0 || len(outcome.rejected) != 0 {
+		t.Errorf("outcome = %+v, want nothing applied or deferred", outcome)
+	}
+}
+
+// Edits still pending when the pass cap stops the fixer are reported as
+// deferred, by kata, so the user knows to re-run.
+func TestApplyReviewedFixes_ReportsDeferred(t *testing.T) {
+	cfg := config.DefaultConfig()
+	registry := katas.Registry
+	src := "x=`which git`\n"
+	outcome, err := applyReviewedFixes(src, collectEdits(src, registry, nil, cfg, nil, true), nil, registry, nil, cfg, nil, 1, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if outcome.source != "x=$(which git)\n" || len(outcome.deferred) == 0 || len(outcome.rejected) != 0 {
+		t.Fatalf("outcome = %+v", outcome)
+	}
+	var errOut bytes.Buffer
+	stats := &fixStats{}
+	reportUnapplied(&errOut, "s.zsh", outcome, stats)
+	if !strings.Contains(errOut.String(), "edit(s) deferred in s.zsh (ZC1005") || stats.deferredEdits != len(outcome.deferred) {
+		t.Errorf("deferred report = %q, stats = %+v", errOut.String(), stats)
+	}
+
+	errOut.Reset()
+	reportUnapplied(&errOut, "s.zsh", fixOutcome{rejected: []katas.FixEdit{{KataID: "ZC1073"}}}, nil)
+	if errOut.String() != "1 edit(s) rejected in s.zsh (ZC1073); applying them would break the parse\n" {
+		t.Errorf("rejected report = %q", errOut.String())
+	}
+}
@@ -0,0 +1,13 @@
+// SPDX-License-Identifier: MIT
+// Copyright the ZShellCheck contributors.
+
+//go:build !unix
+
+package main
+
+import "os"
+
+// copyOwner is a no-op where files carry no Unix owner.
+func copyOwner(string, os.FileInfo) error {
+	return nil
+}
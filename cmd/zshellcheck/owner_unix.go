@@ -0,0 +1,26 @@
+// SPDX-License-Identifier: MIT
+// Copyright the ZShellCheck contributors.
+
+//go:build unix
+
+package main
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// copyOwner gives path the owner and group recorded in info. Only root
+// may hand a file to another user, so a permission error is ignored: the
+// rewrite then belongs to whoever ran the fixer, as a plain write would.
+func copyOwner(path string, info os.FileInfo) error {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return nil
+	}
+	if err := os.Lchown(path, int(st.Uid), int(st.Gid)); err != nil && !errors.Is(err, os.ErrPermission) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+// SPDX-License-Identifier: MIT
+// Copyright the ZShellCheck contributors.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/afadesigns/zshellcheck/pkg/fix"
+)
+
+// patchState drives `-fix-patch`: each file's fixes run to convergence in
+// memory and are collected here, then written once as a single multi-file
+// patch plus a JSON manifest of the katas behind every hunk. No scanned
+// file is modified.
+type patchState struct {
+	path  string
+	files []fix.FilePatch
+}
+
+// manifestPath returns where the manifest for a patch written to path
+// goes: alongside it, with `.json` appended.
+func manifestPath(path string) string {
+	return path + ".json"
+}
+
+// add records the fixed source of filename for the patch.
+func (p *patchState) add(filename string, data []byte, outcome fixOutcome) {
+	if outcome.source == string(data) {
+		return
+	}
+	mode := os.FileMode(0o644)
+	if info, err := os.Stat(filename); err == nil {
+		mode = info.Mode().Perm()
+	}
+	p.files = append(p.files, fix.FilePatch{
+		Path:  patchRelPath(filename),
+		Mode:  mode,
+		Old:   string(data),
+		New:   outcome.source,
+		Edits: outcome.trace.Edits(),
+	})
+}
+
+// write saves the patch and its manifest, reporting what it wrote to
+// errOut. An empty patch is still written, so a CI step can tell "no
+// fixes" from "not run".
+func (p *patchState) write(errOut io.Writer) error {
+	f, err := os.Create(p.path)
+	if err != nil {
+		return err
+	}
+	manifest, err := fix.WritePatch(f, p.files)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	if manifest.Files == nil {
+		manifest.Files = []fix.ManifestFile{}
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	mf, err := os.Create(manifestPath(p.path))
+	if err != nil {
+		return err
+	}
+	_, err = mf.Write(append(data, '\n'))
+	if cerr := mf.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	hunks := 0
+	for _, file := range manifest.Files {
+		hunks += len(file.Hunks)
+	}
+	fmt.Fprintf(errOut, "wrote %d hunk(s) across %d file(s) to %s\n", hunks, len(manifest.Files), p.path)
+	return nil
+}
+
+// patchRelPath names filename in the patch relative to the working
+// directory when it lies under it, so `git apply` run from the repository
+// root finds it.
+func patchRelPath(filename string) string {
+	if !filepath.IsAbs(filename) {
+		return filename
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return filename
+	}
+	rel, err := filepath.Rel(wd, filename)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return filename
+	}
+	return rel
+}
@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: MIT
+// Copyright the ZShellCheck contributors.
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/afadesigns/zshellcheck/pkg/fix"
+)
+
+func TestRunFixPatch(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.zsh")
+	b := filepath.Join(dir, "b.zsh")
+	clean := filepath.Join(dir, "clean.zsh")
+	src := "result=`which git`\n"
+	for path, body := range map[string]string{a: src, b: src, clean: "print -r -- ok\n"} {
+		if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	out := filepath.Join(dir, "out.patch")
+	resetFlags()
+	old := os.Args
+	defer func() { os.Args = old }()
+	os.Args = []string{"zshellcheck", "-fix-patch", out, "-unsafe-fixes", "-no-banner", a, b, clean}
+	_ = run()
+
+	for _, path := range []string{a, b} {
+		if data, _ := os.ReadFile(path); string(data) != src {
+			t.Errorf("-fix-patch modified %s: %q", path, data)
+		}
+	}
+	patch, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(patch), "diff --git "); n != 2 {
+		t.Errorf("patch has %d file sections, want 2:\n%s", n, patch)
+	}
+	if !strings.Contains(string(patch), "+result=$(whence git)\n") {
+		t.Errorf("patch is missing the converged rewrite:\n%s", patch)
+	}
+	data, err := os.ReadFile(manifestPath(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m fix.Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatalf("manifest is not JSON: %v\n%s", err, data)
+	}
+	if len(m.Files) != 2 || len(m.Files[0].Hunks) != 1 {
+		t.Fatalf("manifest = %+v", m)
+	}
+	if got := strings.Join(m.Files[0].Hunks[0].Katas, ","); !strings.Contains(got, "ZC1002") || !strings.Contains(got, "ZC1005") {
+		t.Errorf("hunk katas = %s, want both passes credited", got)
+	}
+}
+
+func TestRunFixPatchRejectsFix(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "x.zsh")
+	if err := os.WriteFile(src, []byte("echo hi\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	resetFlags()
+	old := os.Args
+	defer func() { os.Args = old }()
+	os.Args = []string{"zshellcheck", "-fix", "-fix-patch", filepath.Join(dir, "p"), "-no-banner", src}
+	if got := run(); got != 1 {
+		t.Errorf("expected exit 1 for -fix with -fix-patch, got %d", got)
+	}
+}
+
+func TestPatchRelPath(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := patchRelPath(filepath.Join(wd, "sub", "x.zsh")); got != filepath.Join("sub", "x.zsh") {
+		t.Errorf("path under cwd = %q", got)
+	}
+	outside := filepath.Join(filepath.Dir(wd), "elsewhere.zsh")
+	if got := patchRelPath(outside); got != outside {
+		t.Errorf("path outside cwd = %q", got)
+	}
+	if got := patchRelPath("rel.zsh"); got != "rel.zsh" {
+		t.Errorf("relative path = %q", got)
+	}
+}
@@ -5,6 +5,9 @@ package main
 import (
 	"fmt"
 	"io"
+	"maps"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/afadesigns/zshellcheck/pkg/katas"
@@ -43,8 +46,80 @@ func printRuleExplain(out, errOut io.Writer, registry *katas.KatasRegistry, id s
 	}
 	fmt.Fprintf(out, "%s — %s\n", kata.ID, kata.Title)
 	fmt.Fprintf(out, "Severity: %s\n", titleSeverity(kata.Severity))
+	if level := registry.FixSafetyOf(kata.ID); level != "" {
+		fmt.Fprintf(out, "Auto-fix: %s\n", level)
+	}
+	if registry.IsFileKata(kata.ID) {
+		fmt.Fprintln(out, "Scope: file (checked once over the whole script)")
+	}
 	if kata.Description != "" {
 		fmt.Fprintf(out, "\n%s\n", kata.Description)
 	}
+	if len(kata.Options) > 0 {
+		fmt.Fprintf(out, "\nOptions (set under `rules: %s:` in .zshellcheckrc):\n", kata.ID)
+		for _, opt := range kata.Options {
+			fmt.Fprintf(out, "  %s (%s, default: %s)\n", opt.Name, opt.Type, katas.FormatOptionDefault(opt))
+			if opt.Description != "" {
+				fmt.Fprintf(out, "      %s\n", opt.Description)
+			}
+		}
+	}
 	return 0
 }
+
+// fixSafetyRuleKey is the reserved `rules:` entry that promotes or demotes
+// a kata's auto-fix (`rules: {ZC1037: {fix_safety: safe}}`) rather than
+// setting one of its options.
+const fixSafetyRuleKey = "fix_safety"
+
+// applyRuleOptions hands the config's `rules:` section to the registry,
+// kata by kata in ID order so the first bad entry reported is stable. An
+// unknown kata, unknown option, mistyped value, or a `fix_safety` on a
+// kata without a fix is an error.
+func applyRuleOptions(registry *katas.KatasRegistry, rules map[string]map[string]string) error {
+	ids := make([]string, 0, len(rules))
+	for id := range rules {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		opts := rules[id]
+		if raw, ok := opts[fixSafetyRuleKey]; ok {
+			level, err := katas.ParseFixSafety(raw)
+			if err != nil {
+				return fmt.Errorf("%s: %w", id, err)
+			}
+			if err := registry.SetFixSafety(id, level); err != nil {
+				return err
+			}
+			opts = maps.Clone(opts)
+			delete(opts, fixSafetyRuleKey)
+		}
+		if err := registry.SetOptions(id, opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyFixChoices parses a `-fix-choose` spec — comma-separated
+// `ZC####=n` pairs — and makes the fixer apply suggestion n of each named
+// kata instead of its preferred one.
+func applyFixChoices(registry *katas.KatasRegistry, spec string) error {
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		id, raw, ok := strings.Cut(pair, "=")
+		id = strings.ToUpper(strings.TrimSpace(id))
+		n, err := strconv.Atoi(strings.TrimSpace(raw))
+		if !ok || id == "" || err != nil {
+			return fmt.Errorf("fix-choose: expected ZC####=n, got %q", pair)
+		}
+		if err := registry.ChooseSuggestion(id, n); err != nil {
+			return fmt.Errorf("fix-choose: %w", err)
+		}
+	}
+	return nil
+}
@@ -81,3 +81,135 @@ func TestTitleSeverity(t *testing.T) {
 		}
 	}
 }
+
+func TestPrintRuleExplainOptions(t *testing.T) {
+	kr := katas.NewKatasRegistry()
+	kr.RegisterKata(&ast.SimpleCommand{}, katas.Kata{
+		ID: "ZC1003", Title: "Charlie title",
+		Options: []katas.Option{{
+			Name: "min_bytes", Type: katas.OptionInt, Default: 16, Description: "Smallest size.",
+		}},
+		CheckContext: func(*katas.Context, ast.Node) []katas.Violation { return nil },
+	})
+	var out, errOut bytes.Buffer
+	if code := printRuleExplain(&out, &errOut, kr, "ZC1003"); code != 0 {
+		t.Fatalf("explain code = %d, want 0", code)
+	}
+	for _, want := range []string{"Options", "rules: ZC1003:", "min_bytes (int, default: 16)", "Smallest size."} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("explain output missing %q\n%s", want, out.String())
+		}
+	}
+}
+
+func TestApplyRuleOptions(t *testing.T) {
+	kr := katas.NewKatasRegistry()
+	kr.RegisterKata(&ast.SimpleCommand{}, katas.Kata{
+		ID:      "ZC1003",
+		Options: []katas.Option{{Name: "min_bytes", Type: katas.OptionInt, Default: 16}},
+	})
+	if err := applyRuleOptions(kr, map[string]map[string]string{"ZC1003": {"min_bytes": "32"}}); err != nil {
+		t.Fatalf("applyRuleOptions: %v", err)
+	}
+	if got := kr.OptionValuesFor("ZC1003").Int("min_bytes"); got != 32 {
+		t.Errorf("min_bytes = %d, want 32", got)
+	}
+	if err := applyRuleOptions(kr, map[string]map[string]string{"ZC9999": {"x": "1"}}); err == nil {
+		t.Error("want error for unknown kata")
+	}
+}
+
+func TestPrintRuleExplainFileScope(t *testing.T) {
+	kr := katas.NewKatasRegistry()
+	kr.RegisterFileKata(katas.Kata{
+		ID: "ZC1004", Title: "Delta title",
+		CheckFile: func(*katas.Context, *ast.Program) []katas.Violation { return nil },
+	})
+	var out, errOut bytes.Buffer
+	printRuleExplain(&out, &errOut, kr, "ZC1004")
+	if !strings.Contains(out.String(), "Scope: file") {
+		t.Errorf("explain of a file kata missing scope line:\n%s", out.String())
+	}
+	out.Reset()
+	printRuleExplain(&out, &errOut, testRulesRegistry(), "ZC1001")
+	if strings.Contains(out.String(), "Scope:") {
+		t.Errorf("explain of a node kata printed a scope line:\n%s", out.String())
+	}
+}
+
+func fixSafetyRulesRegistry() *katas.KatasRegistry {
+	kr := katas.NewKatasRegistry()
+	noop := func(ast.Node, katas.Violation, []byte) []katas.FixEdit { return nil }
+	kr.RegisterKata(&ast.SimpleCommand{}, katas.Kata{ID: "ZC1005", Title: "Echo title", Fix: noop})
+	kr.RegisterKata(&ast.SimpleCommand{}, katas.Kata{ID: "ZC1006", Title: "Foxtrot title"})
+	return kr
+}
+
+func TestApplyRuleOptionsFixSafety(t *testing.T) {
+	kr := fixSafetyRulesRegistry()
+	if err := applyRuleOptions(kr, map[string]map[string]string{"ZC1005": {"fix_safety": "safe"}}); err != nil {
+		t.Fatalf("applyRuleOptions: %v", err)
+	}
+	if !kr.IsSafeFix("ZC1005") {
+		t.Error("fix_safety: safe did not promote ZC1005")
+	}
+	var out, errOut bytes.Buffer
+	printRuleExplain(&out, &errOut, kr, "ZC1005")
+	if !strings.Contains(out.String(), "Auto-fix: safe") {
+		t.Errorf("explain missing promoted safety:\n%s", out.String())
+	}
+
+	for name, rules := range map[string]map[string]map[string]string{
+		"bad level":  {"ZC1005": {"fix_safety": "sometimes"}},
+		"no fix":     {"ZC1006": {"fix_safety": "safe"}},
+		"unknown ID": {"ZC9999": {"fix_safety": "safe"}},
+	} {
+		if err := applyRuleOptions(fixSafetyRulesRegistry(), rules); err == nil {
+			t.Errorf("%s: want error", name)
+		}
+	}
+}
+
+func TestApplicableEditsSafety(t *testing.T) {
+	kr := fixSafetyRulesRegistry()
+	edits := func() []katas.FixEdit {
+		return []katas.FixEdit{
+			{KataID: "ZC1005", Replace: "unsafe"},
+			{KataID: "ZC1005", Replace: "safe", Safety: katas.FixSafe},
+			{KataID: "ZC1005", Replace: "suggestion", Safety: katas.FixSuggestion},
+		}
+	}
+	replaces := func(es []katas.FixEdit) string {
+		var parts []string
+		for _, e := range es {
+			parts = append(parts, e.Replace)
+		}
+		return strings.Join(parts, ",")
+	}
+	if got := replaces(applicableEdits(edits(), kr, false)); got != "safe" {
+		t.Errorf("default run kept %q, want safe", got)
+	}
+	if got := replaces(applicableEdits(edits(), kr, true)); got != "unsafe,safe" {
+		t.Errorf("-unsafe-fixes kept %q, want unsafe,safe", got)
+	}
+}
+
+func TestApplyFixChoices(t *testing.T) {
+	suggest := func(*katas.Context, ast.Node, katas.Violation) []katas.Suggestion { return nil }
+	registry := func() *katas.KatasRegistry {
+		kr := fixSafetyRulesRegistry()
+		kr.RegisterKata(&ast.SimpleCommand{}, katas.Kata{ID: "ZC1007", Title: "Golf title", Suggest: suggest})
+		return kr
+	}
+	if err := applyFixChoices(registry(), " zc1007=2 ,"); err != nil {
+		t.Errorf("valid spec rejected: %v", err)
+	}
+	if err := applyFixChoices(registry(), ""); err != nil {
+		t.Errorf("empty spec rejected: %v", err)
+	}
+	for _, spec := range []string{"ZC1007", "ZC1007=x", "=2", "ZC1007=0", "ZC1005=2", "ZC9999=1"} {
+		if err := applyFixChoices(registry(), spec); err == nil {
+			t.Errorf("%q: want error", spec)
+		}
+	}
+}
@@ -37,7 +37,7 @@ func printUsage(out io.Writer, fset *flag.FlagSet, showBanner bool) {
 	groups := []flagGroup{
 		{
 			title: "OUTPUT",
-			names: []string{"format", "statistics", "no-color", "no-banner", "verbose"},
+			names: []string{"format", "o", "ndjson-events", "sarif-include-suppressed", "statistics", "compact", "context", "tab-width", "no-color", "no-banner", "verbose"},
 			blurb: "Shape what lands on stdout / stderr.",
 		},
 		{
@@ -52,7 +52,7 @@ func printUsage(out io.Writer, fset *flag.FlagSet, showBanner bool) {
 		},
 		{
 			title: "AUTO-FIX",
-			names: []string{"fix", "unsafe-fixes", "diff", "dry-run"},
+			names: []string{"fix", "unsafe-fixes", "interactive", "fix-choose", "diff", "fix-patch", "dry-run", "backup-suffix", "verify-fixes"},
 			blurb: "Apply or preview deterministic rewrites. -fix is safe-only by default.",
 		},
 		{
@@ -90,6 +90,8 @@ func printUsage(out io.Writer, fset *flag.FlagSet, showBanner bool) {
 		{"Preview every available auto-fix as a diff", "zshellcheck -diff path/to/script.zsh"},
 		{"Apply auto-fixes in place (safe only)", "zshellcheck -fix path/to/script.zsh"},
 		{"Also apply behavior-changing fixes", "zshellcheck -fix -unsafe-fixes path/to/script.zsh"},
+		{"Collect every fix into one git patch for review", "zshellcheck -fix-patch fixes.patch ./scripts"},
+		{"Review each fix before applying it", "zshellcheck -fix -unsafe-fixes -interactive path/to/script.zsh"},
 		{"CI-friendly run (no banner, errors only)", "zshellcheck -no-banner -severity error ./scripts"},
 	}
 	for _, ex := range examples {
@@ -0,0 +1,49 @@
+// SPDX-License-Identifier: MIT
+// Copyright the ZShellCheck contributors.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/afadesigns/zshellcheck/pkg/config"
+	"github.com/afadesigns/zshellcheck/pkg/fix"
+	"github.com/afadesigns/zshellcheck/pkg/katas"
+)
+
+// runVerifyFixes backs `-verify-fixes`: it runs the fix verifier over
+// every script under paths, prints one line per fix that fails, and
+// returns 1 when any does. Scripts that do not parse are reported and
+// skipped.
+func runVerifyFixes(out, errOut io.Writer, paths []string, cfg config.Config, registry *katas.KatasRegistry) int {
+	checked, failed, files := 0, 0, 0
+	for _, path := range paths {
+		walkScripts(path, errOut, func(filename string) {
+			data, err := os.ReadFile(filename)
+			if err != nil {
+				fmt.Fprintf(errOut, "Error reading file %s: %s\n", filename, err)
+				return
+			}
+			directives := config.ParseDirectives(string(data))
+			verifier := fix.Verifier{Registry: registry, Disabled: mergeDisabled(cfg.DisabledKatas, directives.File)}
+			report, err := verifier.Verify(filename, data)
+			if err != nil {
+				fmt.Fprintf(errOut, "verify-fixes: %s\n", err)
+				return
+			}
+			files++
+			checked += report.Checked
+			for _, f := range report.Failures {
+				failed++
+				v := f.Violation
+				fmt.Fprintf(out, "%s:%d:%d: [%s] %s: %s\n", filename, v.Line, v.Column, v.KataID, f.Problem, f.Detail)
+			}
+		})
+	}
+	fmt.Fprintf(out, "verified %d fix(es) across %d file(s): %d failed\n", checked, files, failed)
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,52 @@
+// SPDX-License-Identifier: MIT
+// Copyright the ZShellCheck contributors.
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/afadesigns/zshellcheck/pkg/config"
+	"github.com/afadesigns/zshellcheck/pkg/katas"
+)
+
+func TestRunVerifyFixes(t *testing.T) {
+	dir := t.TempDir()
+	good := filepath.Join(dir, "good.zsh")
+	if err := os.WriteFile(good, []byte("result=`date`\nprint -r -- $result\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	// `[ ... ]` becomes `[[ ... ]]`, which ZC1073 then flags: the fix
+	// trades one finding for another.
+	chain := filepath.Join(dir, "chain.zsh")
+	if err := os.WriteFile(chain, []byte("[ $x -eq 1 ]\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	broken := filepath.Join(dir, "broken.zsh")
+	if err := os.WriteFile(broken, []byte("if true; then\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var out, errOut bytes.Buffer
+	if code := runVerifyFixes(&out, &errOut, []string{good}, config.DefaultConfig(), katas.Registry); code != 0 {
+		t.Errorf("clean fixes: exit %d, output:\n%s", code, out.String())
+	}
+
+	out.Reset()
+	code := runVerifyFixes(&out, &errOut, []string{dir}, config.DefaultConfig(), katas.Registry)
+	if code != 1 {
+		t.Errorf("failing fix: exit %d, want 1", code)
+	}
+	if !strings.Contains(out.String(), "chain.zsh:1:1: [ZC1003] new-findings: ZC1073: +1") {
+		t.Errorf("missing failure line:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "across 2 file(s): 1 failed") {
+		t.Errorf("missing summary:\n%s", out.String())
+	}
+	if !strings.Contains(errOut.String(), "broken.zsh does not parse") {
+		t.Errorf("unparsable file not reported: %q", errOut.String())
+	}
+}
@@ -68,8 +68,8 @@ func main() {
 	fmt.Fprintf(w, "| **total** | **%d** |\n", count)
 	fmt.Fprintf(w, "| **with auto-fix** | **%d** |\n\n", fixCount)
 
-	fmt.Fprintf(w, "Auto-fix availability is marked per-entry below as **Auto-fix:** `yes` or `no`. ")
-	fmt.Fprintf(w, "Run `zshellcheck -fix path/...` to apply every available rewrite, or `-diff` to preview without writing.\n\n")
+	fmt.Fprintf(w, "Auto-fix availability is marked per-entry below as **Auto-fix:** `safe`, `unsafe`, `suggestion` or `no`. ")
+	fmt.Fprintf(w, "Run `zshellcheck -fix path/...` to apply every safe rewrite, add `-unsafe-fixes` for the unsafe ones, or `-diff` to preview without writing.\n\n")
 
 	fmt.Fprintf(w, "## Table of Contents\n\n")
 	for _, id := range ids {
@@ -89,7 +89,7 @@ func main() {
 		fmt.Fprintf(w, "**Severity:** `%s`  \n", k.Severity)
 		fix := "no"
 		if k.Fix != nil {
-			fix = "yes"
+			fix = string(k.FixSafety)
 		}
 		fmt.Fprintf(w, "**Auto-fix:** `%s`\n\n", fix)
 		fmt.Fprintf(w, "%s\n\n", k.Description)
@@ -14,11 +14,14 @@ import (
 // when n is nil. Every Node.String() that embeds a child-node's output
 // routes through here so a partially-parsed tree (where the parser
 // recovered from an error and left a child field unset) can stringify
-// without panicking.
+// without panicking. A typed-nil child counts as unset, as in Walk.
 func nodeString(n Node) string {
 	if n == nil {
 		return ""
 	}
+	if v := reflect.ValueOf(n); v.Kind() == reflect.Pointer && v.IsNil() {
+		return ""
+	}
 	return n.String()
 }
 
@@ -677,6 +680,52 @@ func Walk(node Node, f WalkFn) {
 	walkChildren(node, f)
 }
 
+// WalkWithParentsFn is called for each node with its ancestor chain,
+// outermost first (parents[len(parents)-1] is the direct parent). The
+// slice is reused across calls; copy it to keep it. It returns true if
+// the children of the node should be visited.
+type WalkWithParentsFn func(node Node, parents []Node) bool
+
+// WalkWithParents traverses the AST like Walk, additionally handing f the
+// chain of ancestors of every visited node.
+func WalkWithParents(node Node, f WalkWithParentsFn) {
+	var stack []Node
+	var visit WalkFn
+	visit = func(n Node) bool {
+		if !f(n, stack) {
+			return false
+		}
+		stack = append(stack, n)
+		walkChildren(n, visit)
+		stack = stack[:len(stack)-1]
+		// Children were visited above with the stack in place.
+		return false
+	}
+	Walk(node, visit)
+}
+
+// End returns the position just past the last token of node: the line
+// and the exclusive column of its final byte, as stamped by the lexer.
+// Tokens without a recorded end count as one line-local run of their
+// literal. It returns zeros when no token under node has a position.
+func End(node Node) (line, column int) {
+	Walk(node, func(n Node) bool {
+		tok := n.TokenLiteralNode()
+		l, c := tok.EndLine, tok.EndColumn
+		if c == 0 {
+			if tok.Line == 0 {
+				return true
+			}
+			l, c = tok.Line, tok.Column+len(tok.Literal)
+		}
+		if l > line || (l == line && c > column) {
+			line, column = l, c
+		}
+		return true
+	})
+	return line, column
+}
+
 // walkChildren dispatches by concrete node type and recurses into the
 // children of node. Split out from Walk so the per-type case lists
 // stay below the gocyclo > 15 threshold.
@@ -334,6 +334,63 @@ func TestWalk_AllNodeTypes(t *testing.T) {
 	}
 }
 
+func TestWalkWithParents(t *testing.T) {
+	tok := token.Token{Type: token.IDENT, Literal: "t"}
+	name := &Identifier{Token: tok, Value: "ls"}
+	cmd := &SimpleCommand{Token: tok, Name: name}
+	body := &BlockStatement{Token: tok, Statements: []Statement{cmd}}
+	fn := &FunctionDefinition{Token: tok, Name: &Identifier{Token: tok, Value: "f"}, Body: body}
+	prog := &Program{Statements: []Statement{fn}}
+
+	depth := map[Node]int{}
+	var nameParents []Node
+	WalkWithParents(prog, func(node Node, parents []Node) bool {
+		depth[node] = len(parents)
+		if node == name {
+			nameParents = append([]Node(nil), parents...)
+		}
+		return true
+	})
+	if depth[prog] != 0 || depth[fn] != 1 || depth[body] != 2 || depth[cmd] != 3 {
+		t.Errorf("unexpected depths: %v", depth)
+	}
+	want := []Node{prog, fn, body, cmd}
+	if len(nameParents) != len(want) {
+		t.Fatalf("parents of name = %d nodes, want %d", len(nameParents), len(want))
+	}
+	for i := range want {
+		if nameParents[i] != want[i] {
+			t.Errorf("parents[%d] = %T, want %T", i, nameParents[i], want[i])
+		}
+	}
+
+	visited := 0
+	WalkWithParents(prog, func(node Node, parents []Node) bool {
+		visited++
+		return node != fn
+	})
+	if visited != 2 {
+		t.Errorf("pruned walk visited %d nodes, want 2", visited)
+	}
+}
+
+func TestEnd(t *testing.T) {
+	name := &Identifier{Token: token.Token{Literal: "ls", Line: 1, Column: 1, EndLine: 1, EndColumn: 3}}
+	str := &StringLiteral{Token: token.Token{Literal: "\"a\nb\"", Line: 1, Column: 4, EndLine: 2, EndColumn: 3}}
+	bare := &Identifier{Token: token.Token{Literal: "-l", Line: 1, Column: 10}}
+	cmd := &SimpleCommand{Token: name.Token, Name: name, Arguments: []Expression{bare, str}}
+
+	if l, c := End(cmd); l != 2 || c != 3 {
+		t.Errorf("End(cmd) = %d:%d, want 2:3", l, c)
+	}
+	if l, c := End(bare); l != 1 || c != 12 {
+		t.Errorf("End of a token without an end = %d:%d, want 1:12", l, c)
+	}
+	if l, c := End(&Program{}); l != 0 || c != 0 {
+		t.Errorf("End(empty program) = %d:%d, want 0:0", l, c)
+	}
+}
+
 func TestCaseStatement_String(t *testing.T) {
 	tok := token.Token{Type: token.IDENT, Literal: "case"}
 	ident := &Identifier{Token: tok, Value: "x"}
@@ -474,3 +531,12 @@ func TestDeclarationAssignment_String(t *testing.T) {
 		t.Errorf("expected just name, got %q", s)
 	}
 }
+
+func TestString_TypedNilChild(t *testing.T) {
+	// The parser can leave a child field holding a typed-nil pointer
+	// after recovering from an error; stringifying must not panic.
+	ls := &LetStatement{Token: token.Token{Literal: "let"}, Name: (*Identifier)(nil)}
+	if got := ls.String(); got != "let  = ;" {
+		t.Errorf("String() = %q", got)
+	}
+}
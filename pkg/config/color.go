@@ -0,0 +1,106 @@
+// SPDX-License-Identifier: MIT
+// Copyright the ZShellCheck contributors.
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// colorNames maps the colour and attribute names a colour setting may use
+// to their SGR parameters.
+var colorNames = map[string]string{
+	"reset":     "0",
+	"default":   "39",
+	"bold":      "1",
+	"dim":       "2",
+	"italic":    "3",
+	"underline": "4",
+	"black":     "30",
+	"red":       "31",
+	"green":     "32",
+	"yellow":    "33",
+	"blue":      "34",
+	"magenta":   "35",
+	"cyan":      "36",
+	"white":     "37",
+	"gray":      "90",
+	"grey":      "90",
+
+	"bright-black":   "90",
+	"bright-red":     "91",
+	"bright-green":   "92",
+	"bright-yellow":  "93",
+	"bright-blue":    "94",
+	"bright-magenta": "95",
+	"bright-cyan":    "96",
+	"bright-white":   "97",
+}
+
+// ColorCode converts a colour setting into the ANSI escape sequence that
+// renders it. A setting is one or more space-separated parts, each a name
+// (`red`, `bright-blue`, `bold`), a 256-colour index (`208`), or a
+// `#rrggbb` hex colour, so `bold #ff8800` is bold orange. A raw escape
+// sequence, as the defaults are, passes through unchanged, and an empty
+// setting yields "".
+func ColorCode(spec string) (string, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" || strings.HasPrefix(spec, "\033[") {
+		return spec, nil
+	}
+	var params []string
+	for _, part := range strings.Fields(strings.ToLower(spec)) {
+		p, err := sgrParams(part)
+		if err != nil {
+			return "", err
+		}
+		params = append(params, p)
+	}
+	return "\033[" + strings.Join(params, ";") + "m", nil
+}
+
+// sgrParams returns the SGR parameters of one part of a colour setting.
+func sgrParams(part string) (string, error) {
+	if p, ok := colorNames[part]; ok {
+		return p, nil
+	}
+	if hex, ok := strings.CutPrefix(part, "#"); ok {
+		if len(hex) != 6 {
+			return "", fmt.Errorf("unknown colour %q (want #rrggbb)", part)
+		}
+		rgb, err := strconv.ParseUint(hex, 16, 32)
+		if err != nil {
+			return "", fmt.Errorf("unknown colour %q (want #rrggbb)", part)
+		}
+		return fmt.Sprintf("38;2;%d;%d;%d", rgb>>16, rgb>>8&0xff, rgb&0xff), nil
+	}
+	if n, err := strconv.Atoi(part); err == nil {
+		if n < 0 || n > 255 {
+			return "", fmt.Errorf("colour index %d out of range 0-255", n)
+		}
+		return "38;5;" + part, nil
+	}
+	return "", fmt.Errorf("unknown colour %q (want a name such as red, a 0-255 index, or #rrggbb)", part)
+}
+
+// ValidateColors checks that every colour setting parses, naming the first
+// setting that does not.
+func (c Config) ValidateColors() error {
+	for _, s := range []struct{ key, spec string }{
+		{"error_color", c.ErrorColor},
+		{"warning_color", c.WarningColor},
+		{"info_color", c.InfoColor},
+		{"style_color", c.StyleColor},
+		{"id_color", c.IDColor},
+		{"title_color", c.TitleColor},
+		{"message_color", c.MessageColor},
+		{"line_color", c.LineColor},
+		{"column_color", c.ColumnColor},
+	} {
+		if _, err := ColorCode(s.spec); err != nil {
+			return fmt.Errorf("%s: %w", s.key, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+// SPDX-License-Identifier: MIT
+// Copyright the ZShellCheck contributors.
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestColorCode(t *testing.T) {
+	tests := []struct {
+		spec, want string
+	}{
+		{"", ""},
+		{ColorRed, ColorRed},
+		{"red", "\033[31m"},
+		{"Bright-Blue", "\033[94m"},
+		{"bold magenta", "\033[1;35m"},
+		{"208", "\033[38;5;208m"},
+		{"#ff8800", "\033[38;2;255;136;0m"},
+		{"underline #00FF7f", "\033[4;38;2;0;255;127m"},
+	}
+	for _, tt := range tests {
+		got, err := ColorCode(tt.spec)
+		if err != nil {
+			t.Errorf("ColorCode(%q) error: %v", tt.spec, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ColorCode(%q) = %q, want %q", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestColorCodeRejects(t *testing.T) {
+	for _, spec := range []string{"purple", "256", "-1", "#fff", "#gggggg", "red chartreuse"} {
+		if _, err := ColorCode(spec); err == nil {
+			t.Errorf("ColorCode(%q) = nil error, want one", spec)
+		}
+	}
+}
+
+func TestValidateColors(t *testing.T) {
+	if err := DefaultConfig().ValidateColors(); err != nil {
+		t.Fatalf("default config: %v", err)
+	}
+	cfg := DefaultConfig()
+	cfg.LineColor = "mauve"
+	err := cfg.ValidateColors()
+	if err == nil || !strings.HasPrefix(err.Error(), "line_color: ") {
+		t.Errorf("ValidateColors() = %v, want a line_color error", err)
+	}
+}
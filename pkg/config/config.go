@@ -14,6 +14,7 @@ type Config struct {
 	ErrorColor   string `yaml:"error_color"`
 	WarningColor string `yaml:"warning_color"`
 	InfoColor    string `yaml:"info_color"`
+	StyleColor   string `yaml:"style_color"`
 	IDColor      string `yaml:"id_color"`
 	TitleColor   string `yaml:"title_color"`
 	MessageColor string `yaml:"message_color"`
@@ -21,6 +22,20 @@ type Config struct {
 	ColumnColor  string `yaml:"column_color"`
 	NoColor      bool   `yaml:"no_color"`
 	Verbose      bool   `yaml:"verbose"`
+	// Compact prints each finding on one line, without the source
+	// snippet, notes or suggestions.
+	Compact bool `yaml:"compact"`
+	// Context is the number of source lines shown before and after each
+	// finding's own lines.
+	Context int `yaml:"context"`
+	// TabWidth is the tab stop interval used to lay out source lines;
+	// zero means the reporter's default of 8.
+	TabWidth int `yaml:"tab_width"`
+
+	// Rules carries per-kata option overrides from the `rules:` section,
+	// keyed by kata ID then option name. Values stay raw strings; the
+	// kata registry checks them against each kata's option schema.
+	Rules map[string]map[string]string `yaml:"rules"`
 }
 
 // Default colors
@@ -28,6 +43,7 @@ const (
 	ColorReset  = "\033[0m"
 	ColorRed    = "\033[31m"
 	ColorYellow = "\033[33m"
+	ColorBlue   = "\033[34m"
 	ColorCyan   = "\033[36m"
 	ColorBold   = "\033[1m"
 )
@@ -48,7 +64,8 @@ func DefaultConfig() Config {
 	return Config{
 		ErrorColor:   ColorRed,
 		WarningColor: ColorYellow,
-		InfoColor:    ColorCyan,
+		InfoColor:    ColorBlue,
+		StyleColor:   ColorCyan,
 		IDColor:      ColorRed,
 		TitleColor:   ColorCyan,
 		MessageColor: ColorReset,
@@ -74,6 +91,9 @@ func MergeConfig(base, override Config) Config {
 	if override.InfoColor != "" {
 		base.InfoColor = override.InfoColor
 	}
+	if override.StyleColor != "" {
+		base.StyleColor = override.StyleColor
+	}
 	if override.IDColor != "" {
 		base.IDColor = override.IDColor
 	}
@@ -89,13 +109,44 @@ func MergeConfig(base, override Config) Config {
 	if override.ColumnColor != "" {
 		base.ColumnColor = override.ColumnColor
 	}
+	if len(override.Rules) > 0 {
+		base.Rules = mergeRules(base.Rules, override.Rules)
+	}
 	// These are boolean flags, direct assignment is fine
 	base.NoColor = override.NoColor
 	base.Verbose = override.Verbose
+	base.Compact = override.Compact
+	if override.Context > 0 {
+		base.Context = override.Context
+	}
+	if override.TabWidth > 0 {
+		base.TabWidth = override.TabWidth
+	}
 
 	return base
 }
 
+// mergeRules overlays override's per-kata options onto base option by
+// option, so a project config can tune one option of a kata without
+// dropping the others set globally. Neither input is modified.
+func mergeRules(base, override map[string]map[string]string) map[string]map[string]string {
+	out := make(map[string]map[string]string, len(base)+len(override))
+	for id, opts := range base {
+		out[id] = opts
+	}
+	for id, opts := range override {
+		merged := make(map[string]string, len(out[id])+len(opts))
+		for k, v := range out[id] {
+			merged[k] = v
+		}
+		for k, v := range opts {
+			merged[k] = v
+		}
+		out[id] = merged
+	}
+	return out
+}
+
 // NewConfigFromYAML loads configuration from a config file.
 func NewConfigFromYAML(path string) (Config, error) {
 	cfg := DefaultConfig()
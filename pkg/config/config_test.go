@@ -17,8 +17,11 @@ func TestDefaultConfig(t *testing.T) {
 	if cfg.WarningColor != ColorYellow {
 		t.Errorf("expected WarningColor=%q, got %q", ColorYellow, cfg.WarningColor)
 	}
-	if cfg.InfoColor != ColorCyan {
-		t.Errorf("expected InfoColor=%q, got %q", ColorCyan, cfg.InfoColor)
+	if cfg.InfoColor != ColorBlue {
+		t.Errorf("expected InfoColor=%q, got %q", ColorBlue, cfg.InfoColor)
+	}
+	if cfg.StyleColor != ColorCyan {
+		t.Errorf("expected StyleColor=%q, got %q", ColorCyan, cfg.StyleColor)
 	}
 	if cfg.IDColor != ColorRed {
 		t.Errorf("expected IDColor=%q, got %q", ColorRed, cfg.IDColor)
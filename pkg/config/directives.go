@@ -24,7 +24,8 @@ import (
 //     code line silences for the whole file.
 //
 // `# noka` (no IDs) means "silence everything in scope". Listed IDs limit
-// the suppression to those katas only.
+// the suppression to those katas only. Text after ` -- ` is the reason for
+// the suppression: `cmd  # noka: ZC1234 -- vendored code`.
 type Directives struct {
 	// File contains kata IDs disabled file-wide. Populated by trailing
 	// directives at file end with no code after them.
@@ -38,6 +39,9 @@ type Directives struct {
 	// PerLineAll marks lines that carried a bare `# noka` (no IDs)
 	// directive, suppressing every kata on the line.
 	PerLineAll map[int]bool
+	// Reasons maps a line silenced by a trailing or preceding directive
+	// to the justification written after its `--`.
+	Reasons map[int]string
 }
 
 // HasAny returns true if the directive set disables any kata, anywhere.
@@ -57,10 +61,11 @@ var directiveRe = regexp.MustCompile(`\bnoka\b\s*(?::\s*([A-Za-z0-9_,\s]+))?`)
 // ParseDirectives scans source text for `# noka` annotations and returns
 // the file-wide and per-line sets.
 type directiveScan struct {
-	d           Directives
-	pendingIDs  []string
-	pendingAll  bool
-	pendingFrom int
+	d             Directives
+	pendingIDs    []string
+	pendingAll    bool
+	pendingReason string
+	pendingFrom   int
 }
 
 func ParseDirectives(source string) Directives {
@@ -68,6 +73,7 @@ func ParseDirectives(source string) Directives {
 		d: Directives{
 			PerLine:    make(map[int][]string),
 			PerLineAll: make(map[int]bool),
+			Reasons:    make(map[int]string),
 		},
 	}
 	scanner := bufio.NewScanner(strings.NewReader(source))
@@ -87,17 +93,34 @@ func (s *directiveScan) consumeLine(lineNo int, raw string) {
 		s.consumeCodeLine(lineNo, strings.TrimSpace(raw) != "")
 		return
 	}
-	match := directiveRe.FindStringSubmatch(raw[hashIdx+1:])
-	if match == nil {
+	comment := raw[hashIdx+1:]
+	loc := directiveRe.FindStringSubmatchIndex(comment)
+	if loc == nil {
 		s.consumeCodeLine(lineNo, strings.TrimSpace(raw[:hashIdx]) != "")
 		return
 	}
-	ids, all := parseDirectiveIDs(match[1])
+	tail := ""
+	if loc[2] >= 0 {
+		tail = comment[loc[2]:loc[3]]
+	}
+	ids, all := parseDirectiveIDs(tail)
+	reason := directiveReason(comment[loc[1]:])
 	if strings.TrimSpace(raw[:hashIdx]) != "" {
-		s.recordTrailing(lineNo, ids, all)
+		s.recordTrailing(lineNo, ids, all, reason)
 		return
 	}
-	s.recordPending(lineNo, ids, all)
+	s.recordPending(lineNo, ids, all, reason)
+}
+
+// directiveReason returns the justification after the `--` that follows
+// a directive's IDs, or "" when there is none.
+func directiveReason(rest string) string {
+	rest = strings.TrimSpace(rest)
+	reason, ok := strings.CutPrefix(rest, "--")
+	if !ok {
+		return ""
+	}
+	return strings.TrimSpace(reason)
 }
 
 func (s *directiveScan) consumeCodeLine(lineNo int, hasContent bool) {
@@ -114,27 +137,37 @@ func (s *directiveScan) flushPending(targetLine int) {
 	if len(s.pendingIDs) > 0 {
 		s.d.PerLine[targetLine] = append(s.d.PerLine[targetLine], s.pendingIDs...)
 	}
+	if s.pendingReason != "" {
+		s.d.Reasons[targetLine] = s.pendingReason
+	}
 	s.pendingIDs = nil
 	s.pendingAll = false
+	s.pendingReason = ""
 	s.pendingFrom = 0
 }
 
-func (s *directiveScan) recordTrailing(lineNo int, ids []string, all bool) {
+func (s *directiveScan) recordTrailing(lineNo int, ids []string, all bool, reason string) {
 	if all {
 		s.d.PerLineAll[lineNo] = true
 	}
 	if len(ids) > 0 {
 		s.d.PerLine[lineNo] = append(s.d.PerLine[lineNo], ids...)
 	}
+	if reason != "" {
+		s.d.Reasons[lineNo] = reason
+	}
 }
 
-func (s *directiveScan) recordPending(lineNo int, ids []string, all bool) {
+func (s *directiveScan) recordPending(lineNo int, ids []string, all bool, reason string) {
 	if all {
 		s.pendingAll = true
 	}
 	if len(ids) > 0 {
 		s.pendingIDs = append(s.pendingIDs, ids...)
 	}
+	if reason != "" {
+		s.pendingReason = reason
+	}
 	s.pendingFrom = lineNo
 }
 
@@ -126,3 +126,19 @@ func TestParseDirectives_LegacyZshellcheckFormDoesNothing(t *testing.T) {
 		t.Errorf("legacy directive should not register, got %+v", d)
 	}
 }
+
+func TestParseDirectives_Reasons(t *testing.T) {
+	src := `echo hi  # noka: ZC1075 -- vendored code
+# noka -- generated
+rm -rf /tmp/noisy
+echo plain  # noka: ZC1075
+`
+	d := ParseDirectives(src)
+	if !d.IsDisabledOn("ZC1075", 1) || d.IsDisabledOn("ZC1136", 1) {
+		t.Errorf("reason changed the IDs silenced on line 1: %v", d.PerLine[1])
+	}
+	want := map[int]string{1: "vendored code", 3: "generated"}
+	if !reflect.DeepEqual(d.Reasons, want) {
+		t.Errorf("Reasons = %v, want %v", d.Reasons, want)
+	}
+}
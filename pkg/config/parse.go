@@ -9,8 +9,10 @@ import (
 )
 
 // Parse reads a ZShellCheck configuration from its YAML-subset format.
-// The schema is flat: scalar `key: value` pairs plus the `disabled_katas`
-// sequence (a block list of `- ZC####` items or an inline `[ZC####, …]`).
+// The schema is mostly flat: scalar `key: value` pairs, the
+// `disabled_katas` sequence (a block list of `- ZC####` items or an inline
+// `[ZC####, …]`), and the `rules` mapping of per-kata options (a nested
+// `ZC####:` block of `option: value` lines or an inline `{option: value}`).
 // It is implemented without a third-party YAML dependency to keep the
 // binary dependency-free, and accepts the documented format: `#` comments,
 // single/double quotes, and standard escapes inside double quotes.
@@ -21,6 +23,7 @@ import (
 func Parse(data []byte) (Config, error) {
 	var cfg Config
 	inList := false
+	var rules *rulesState
 	for n, raw := range strings.Split(string(data), "\n") {
 		line := stripComment(raw)
 		trimmed := strings.TrimSpace(line)
@@ -43,9 +46,24 @@ func Parse(data []byte) (Config, error) {
 			return cfg, fmt.Errorf("config: line %d: expected `key: value`", n+1)
 		}
 		inList = false
-		if key == "disabled_katas" {
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		if rules != nil && indent > 0 {
+			if err := rules.parseLine(&cfg, key, val, indent); err != nil {
+				return cfg, fmt.Errorf("config: line %d: %w", n+1, err)
+			}
+			continue
+		}
+		rules = nil
+		switch key {
+		case "disabled_katas":
 			inList = parseListValue(&cfg, val)
 			continue
+		case "rules":
+			if val != "" && val != "{}" {
+				return cfg, fmt.Errorf("config: line %d: rules must be a nested `ZC####:` mapping", n+1)
+			}
+			rules = &rulesState{}
+			continue
 		}
 		if err := assignScalar(&cfg, key, unquote(val)); err != nil {
 			return cfg, fmt.Errorf("config: line %d: %w", n+1, err)
@@ -54,6 +72,93 @@ func Parse(data []byte) (Config, error) {
 	return cfg, nil
 }
 
+// rulesState tracks the open `rules:` mapping: the kata whose block of
+// option lines is being read and the indentation of its `ZC####:` key.
+type rulesState struct {
+	kata   string
+	indent int
+}
+
+// parseLine handles one indented line under `rules:`. A line at or left
+// of the current kata key starts a new kata — either a block (`ZC####:`)
+// or an inline flow mapping (`ZC####: {opt: val}`); a deeper line is an
+// `option: value` pair of the open kata.
+func (r *rulesState) parseLine(cfg *Config, key, val string, indent int) error {
+	if r.kata == "" || indent <= r.indent {
+		r.kata = ""
+		switch {
+		case val == "":
+			r.kata, r.indent = key, indent
+			ruleOptions(cfg, key)
+			return nil
+		case strings.HasPrefix(val, "{") && strings.HasSuffix(val, "}"):
+			return parseFlowMapping(ruleOptions(cfg, key), val[1:len(val)-1])
+		}
+		return fmt.Errorf("rules: %s must map option names to values", key)
+	}
+	if val == "" {
+		return fmt.Errorf("rules: %s.%s has no value", r.kata, key)
+	}
+	ruleOptions(cfg, r.kata)[key] = unquote(val)
+	return nil
+}
+
+// ruleOptions returns the option map of kata id, creating it on first use.
+func ruleOptions(cfg *Config, id string) map[string]string {
+	if cfg.Rules == nil {
+		cfg.Rules = map[string]map[string]string{}
+	}
+	if cfg.Rules[id] == nil {
+		cfg.Rules[id] = map[string]string{}
+	}
+	return cfg.Rules[id]
+}
+
+// parseFlowMapping reads the body of an inline `{a: 1, b: [x, y]}` mapping
+// into dst. Commas inside brackets or quotes do not split entries.
+func parseFlowMapping(dst map[string]string, body string) error {
+	for _, entry := range splitFlow(body) {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		key, val, ok := strings.Cut(entry, ":")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return fmt.Errorf("rules: expected `option: value`, got %q", entry)
+		}
+		dst[key] = unquote(strings.TrimSpace(val))
+	}
+	return nil
+}
+
+// splitFlow splits s on the commas that sit outside `[…]` and quotes.
+func splitFlow(s string) []string {
+	var parts []string
+	depth, start := 0, 0
+	inSingle, inDouble := false, false
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case inSingle:
+			inSingle = c != '\''
+		case inDouble:
+			inDouble = c != '"'
+		case c == '\'':
+			inSingle = true
+		case c == '"':
+			inDouble = true
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+		case c == ',' && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
 // parseListValue handles the value after `disabled_katas:`. An empty
 // value opens a block sequence (reported by the true return); `[]` is the
 // empty inline list; `[a, b]` is a populated inline list; anything else is
@@ -77,7 +182,7 @@ func parseListValue(cfg *Config, val string) (blockOpen bool) {
 }
 
 // assignScalar sets the field named by key. Unknown keys are ignored for
-// forward compatibility; only a malformed boolean is an error.
+// forward compatibility; only a malformed boolean or number is an error.
 func assignScalar(cfg *Config, key, val string) error {
 	switch key {
 	case "error_color":
@@ -86,6 +191,8 @@ func assignScalar(cfg *Config, key, val string) error {
 		cfg.WarningColor = val
 	case "info_color":
 		cfg.InfoColor = val
+	case "style_color":
+		cfg.StyleColor = val
 	case "id_color":
 		cfg.IDColor = val
 	case "title_color":
@@ -108,6 +215,24 @@ func assignScalar(cfg *Config, key, val string) error {
 			return fmt.Errorf("invalid boolean for verbose: %q", val)
 		}
 		cfg.Verbose = b
+	case "compact":
+		b, err := strconv.ParseBool(val)
+		if err != nil {
+			return fmt.Errorf("invalid boolean for compact: %q", val)
+		}
+		cfg.Compact = b
+	case "context":
+		n, err := strconv.Atoi(val)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid line count for context: %q", val)
+		}
+		cfg.Context = n
+	case "tab_width":
+		n, err := strconv.Atoi(val)
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid width for tab_width: %q", val)
+		}
+		cfg.TabWidth = n
 	}
 	return nil
 }
@@ -73,6 +73,21 @@ func TestParseAllScalars(t *testing.T) {
 	}
 }
 
+func TestParseLayoutNumbers(t *testing.T) {
+	cfg, err := Parse([]byte("context: 3\ntab_width: 4\ncompact: true\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Context != 3 || cfg.TabWidth != 4 || !cfg.Compact {
+		t.Errorf("Context, TabWidth, Compact = %d, %d, %v; want 3, 4, true", cfg.Context, cfg.TabWidth, cfg.Compact)
+	}
+	for _, src := range []string{"context: -1\n", "context: some\n", "tab_width: 0\n", "tab_width: wide\n"} {
+		if _, err := Parse([]byte(src)); err == nil {
+			t.Errorf("Parse(%q) = nil error, want one", src)
+		}
+	}
+}
+
 func TestParseQuotesAndEscapes(t *testing.T) {
 	cfg, err := Parse([]byte("error_color: \"\\e[31m\"\nwarning_color: '\\eliteral'\n" +
 		"info_color: \"\\x1b[1m\"\nid_color: \"\\x1b[0m\"\ntitle_color: \"tab\\there\"\n"))
@@ -164,3 +179,62 @@ func TestParseEmptyAndBlankLines(t *testing.T) {
 		t.Errorf("blank config should be zero value, got %+v", cfg)
 	}
 }
+
+func TestParseRulesBlock(t *testing.T) {
+	src := "rules:\n" +
+		"  ZC1760:\n" +
+		"    min_bytes: 32  # stricter than the default\n" +
+		"  ZC1141: {allowed_hosts: [sh.rustup.rs, 'get.example.com'], strict: true}\n" +
+		"  ZC1136:\n" +
+		"    commands: \"[rm, srm]\"\n" +
+		"no_color: true\n"
+	cfg, err := Parse([]byte(src))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]map[string]string{
+		"ZC1760": {"min_bytes": "32"},
+		"ZC1141": {"allowed_hosts": "[sh.rustup.rs, 'get.example.com']", "strict": "true"},
+		"ZC1136": {"commands": "[rm, srm]"},
+	}
+	if !reflect.DeepEqual(cfg.Rules, want) {
+		t.Errorf("Rules = %#v, want %#v", cfg.Rules, want)
+	}
+	if !cfg.NoColor {
+		t.Error("a top-level key after rules should close the mapping")
+	}
+}
+
+func TestParseRulesErrors(t *testing.T) {
+	for _, src := range []string{
+		"rules: ZC1760\n",                 // scalar instead of a mapping
+		"rules:\n  ZC1760: 32\n",          // kata with a scalar value
+		"rules:\n  ZC1760:\n    min:\n",   // option without a value
+		"rules:\n  ZC1760: {min_bytes}\n", // flow entry without a separator
+	} {
+		if _, err := Parse([]byte(src)); err == nil {
+			t.Errorf("expected error for %q", src)
+		}
+	}
+}
+
+func TestMergeConfigRulesPerOption(t *testing.T) {
+	base := Config{Rules: map[string]map[string]string{
+		"ZC1760": {"min_bytes": "24"},
+		"ZC1136": {"commands": "[rm]"},
+	}}
+	override := Config{Rules: map[string]map[string]string{
+		"ZC1136": {"commands": "[rm, srm]"},
+	}}
+	got := MergeConfig(base, override).Rules
+	want := map[string]map[string]string{
+		"ZC1760": {"min_bytes": "24"},
+		"ZC1136": {"commands": "[rm, srm]"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("merged Rules = %#v, want %#v", got, want)
+	}
+	if base.Rules["ZC1136"]["commands"] != "[rm]" {
+		t.Error("MergeConfig modified the base rules")
+	}
+}
@@ -0,0 +1,269 @@
+// SPDX-License-Identifier: MIT
+// Copyright the ZShellCheck contributors.
+package fix
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around each change.
+// Two changes separated by at most twice this many lines share a hunk.
+const diffContext = 3
+
+// noNewline marks a diff line that has no line terminator in the file.
+const noNewline = "\\ No newline at end of file\n"
+
+// diffOp is one line of an edit script: an unchanged line present in
+// both sides, a line deleted from the old side, or a line inserted from
+// the new side. a and b are the 0-based indices of the line in the old
+// and new sides; for an insert a is the old-side position it lands at,
+// and for a delete b is the new-side position.
+type diffOp struct {
+	kind byte
+	a, b int
+}
+
+// unifiedDiff renders the difference between oldSrc and newSrc as a
+// git-style unified diff with `a/` and `b/` path prefixes. It returns ""
+// when the two are identical.
+func unifiedDiff(filename, oldSrc, newSrc string) string {
+	if oldSrc == newSrc {
+		return ""
+	}
+	path := diffPath(filename)
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- a/%s\n+++ b/%s\n", path, path)
+	for _, h := range diffHunks(oldSrc, newSrc) {
+		out.WriteString(h.text)
+	}
+	return out.String()
+}
+
+// hunk is one rendered hunk of a diff. aStart and bStart are the 0-based
+// first line of each side; for an empty side they are the line the hunk
+// sits before.
+type hunk struct {
+	aStart, aLen int
+	bStart, bLen int
+	text         string
+}
+
+// header returns the hunk's `@@ -a +b @@` line without its newline.
+func (h hunk) header() string {
+	return fmt.Sprintf("@@ -%s +%s @@", hunkRange(h.aStart, h.aLen), hunkRange(h.bStart, h.bLen))
+}
+
+// diffHunks returns the hunks of the diff from oldSrc to newSrc.
+func diffHunks(oldSrc, newSrc string) []hunk {
+	a, b := diffLines(oldSrc), diffLines(newSrc)
+	ops := editScript(a, b)
+	spans := hunkSpans(ops)
+	hunks := make([]hunk, 0, len(spans))
+	for _, span := range spans {
+		hunks = append(hunks, renderHunk(a, b, ops[span[0]:span[1]]))
+	}
+	return hunks
+}
+
+// diffLines splits src into lines that keep their terminator, so a final
+// line without a newline compares unequal to the same text with one.
+func diffLines(src string) []string {
+	lines := strings.SplitAfter(src, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffPath normalises filename for a diff header: slash-separated, with
+// no leading `./` or `/` to collide with the `a/` and `b/` prefixes.
+func diffPath(filename string) string {
+	p := filepath.ToSlash(filename)
+	for strings.HasPrefix(p, "./") {
+		p = p[2:]
+	}
+	return strings.TrimLeft(p, "/")
+}
+
+// editScript returns the shortest edit script turning a into b, with the
+// deletions of each change block ahead of its insertions.
+func editScript(a, b []string) []diffOp {
+	d := &differ{a: a, b: b, delA: make([]bool, len(a)), insB: make([]bool, len(b))}
+	d.compare(0, len(a), 0, len(b))
+
+	ops := make([]diffOp, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && d.delA[i]:
+			ops = append(ops, diffOp{kind: '-', a: i, b: j})
+			i++
+		case j < len(b) && d.insB[j]:
+			ops = append(ops, diffOp{kind: '+', a: i, b: j})
+			j++
+		default:
+			ops = append(ops, diffOp{kind: ' ', a: i, b: j})
+			i++
+			j++
+		}
+	}
+	return ops
+}
+
+// differ runs Myers' linear-space diff: it finds the middle snake of an
+// optimal edit path, recurses on the halves either side of it, and marks
+// the lines deleted from a and inserted from b. Memory stays linear in
+// the input size, unlike a full LCS table.
+type differ struct {
+	a, b       []string
+	delA, insB []bool
+}
+
+func (d *differ) compare(aLo, aHi, bLo, bHi int) {
+	for aLo < aHi && bLo < bHi && d.a[aLo] == d.b[bLo] {
+		aLo++
+		bLo++
+	}
+	for aLo < aHi && bLo < bHi && d.a[aHi-1] == d.b[bHi-1] {
+		aHi--
+		bHi--
+	}
+	switch {
+	case aLo == aHi:
+		for j := bLo; j < bHi; j++ {
+			d.insB[j] = true
+		}
+	case bLo == bHi:
+		for i := aLo; i < aHi; i++ {
+			d.delA[i] = true
+		}
+	default:
+		x, y, u, v := d.middleSnake(aLo, aHi, bLo, bHi)
+		d.compare(aLo, x, bLo, y)
+		d.compare(u, aHi, v, bHi)
+	}
+}
+
+// middleSnake runs the forward and backward searches over a[aLo:aHi] and
+// b[bLo:bHi] until they meet and returns the snake where they do, as
+// start (x, y) and end (u, v) in absolute indices. Both ranges are
+// non-empty and differ in their first and last lines, so the edit
+// distance is at least two and both halves around the snake are strictly
+// smaller problems.
+func (d *differ) middleSnake(aLo, aHi, bLo, bHi int) (x, y, u, v int) {
+	n, m := aHi-aLo, bHi-bLo
+	delta := n - m
+	odd := delta%2 != 0
+	limit := (n + m + 1) / 2
+	off := limit + 1
+	// fwd[off+k] is the furthest x reached on forward diagonal k = x-y;
+	// bwd[off+k] the same for the search run from the ends backwards.
+	fwd := make([]int, 2*limit+3)
+	bwd := make([]int, 2*limit+3)
+	for depth := 0; depth <= limit; depth++ {
+		for k := -depth; k <= depth; k += 2 {
+			px := furthest(fwd, off, k, depth)
+			py := px - k
+			ex, ey := px, py
+			for ex < n && ey < m && d.a[aLo+ex] == d.b[bLo+ey] {
+				ex++
+				ey++
+			}
+			fwd[off+k] = ex
+			if c := delta - k; odd && c >= -(depth-1) && c <= depth-1 && ex+bwd[off+c] >= n {
+				return aLo + px, bLo + py, aLo + ex, bLo + ey
+			}
+		}
+		for k := -depth; k <= depth; k += 2 {
+			px := furthest(bwd, off, k, depth)
+			py := px - k
+			ex, ey := px, py
+			for ex < n && ey < m && d.a[aHi-1-ex] == d.b[bHi-1-ey] {
+				ex++
+				ey++
+			}
+			bwd[off+k] = ex
+			if c := delta - k; !odd && c >= -depth && c <= depth && ex+fwd[off+c] >= n {
+				return aHi - ex, bHi - ey, aHi - px, bHi - py
+			}
+		}
+	}
+	// Unreachable: the searches always meet by depth ceil((n+m)/2).
+	panic("fix: diff searches did not meet")
+}
+
+// furthest picks the starting x on diagonal k for the next search step:
+// a move down from diagonal k+1 or a move right from diagonal k-1,
+// whichever has got further.
+func furthest(vec []int, off, k, depth int) int {
+	if k == -depth || (k != depth && vec[off+k-1] < vec[off+k+1]) {
+		return vec[off+k+1]
+	}
+	return vec[off+k-1] + 1
+}
+
+// hunkSpans groups ops into hunks as [start, end) index pairs: each
+// change with up to diffContext unchanged lines either side, merging
+// changes whose context would touch or overlap.
+func hunkSpans(ops []diffOp) [][2]int {
+	var hunks [][2]int
+	for i := 0; i < len(ops); i++ {
+		if ops[i].kind == ' ' {
+			continue
+		}
+		start := max(i-diffContext, 0)
+		end := i + 1
+		if n := len(hunks); n > 0 && start <= hunks[n-1][1] {
+			hunks[n-1][1] = min(end+diffContext, len(ops))
+			continue
+		}
+		hunks = append(hunks, [2]int{start, min(end+diffContext, len(ops))})
+	}
+	return hunks
+}
+
+// renderHunk renders one hunk: its `@@` header, then each line with its
+// ` `, `-` or `+` prefix and a no-newline marker after a line that ends
+// the file without a terminator.
+func renderHunk(a, b []string, ops []diffOp) hunk {
+	h := hunk{aStart: ops[0].a, bStart: ops[0].b}
+	for _, op := range ops {
+		if op.kind != '+' {
+			h.aLen++
+		}
+		if op.kind != '-' {
+			h.bLen++
+		}
+	}
+	var out strings.Builder
+	out.WriteString(h.header() + "\n")
+	for _, op := range ops {
+		var line string
+		if op.kind == '+' {
+			line = b[op.b]
+		} else {
+			line = a[op.a]
+		}
+		out.WriteByte(op.kind)
+		out.WriteString(line)
+		if !strings.HasSuffix(line, "\n") {
+			out.WriteString("\n" + noNewline)
+		}
+	}
+	h.text = out.String()
+	return h
+}
+
+// hunkRange formats one side of a hunk header. The count is omitted when
+// it is one; an empty range names the line before it, as diff(1) does.
+func hunkRange(start, length int) string {
+	switch length {
+	case 0:
+		return fmt.Sprintf("%d,0", start)
+	case 1:
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, length)
+}
@@ -4,15 +4,19 @@
 // Package fix applies FixEdit sets produced by kata Fix functions to
 // source files. It handles offset math (1-based Line/Column to absolute
 // byte offsets), sorts edits bottom-up so earlier offsets stay valid,
-// and renders either a rewritten source string or a unified diff.
+// reports which edits were applied, dropped as conflicts or rejected for
+// breaking the parse, and renders either a rewritten source string or a
+// unified diff.
 package fix
 
 import (
 	"fmt"
 	"sort"
-	"strings"
 
+	"github.com/afadesigns/zshellcheck/pkg/ast"
 	"github.com/afadesigns/zshellcheck/pkg/katas"
+	"github.com/afadesigns/zshellcheck/pkg/lexer"
+	"github.com/afadesigns/zshellcheck/pkg/parser"
 )
 
 // Apply returns the source rewritten with every edit in edits applied.
@@ -27,92 +31,196 @@ import (
 // Non-overlapping edits are applied in descending start-offset order
 // so that earlier splices do not invalidate the offsets of later ones.
 func Apply(source string, edits []katas.FixEdit) (string, error) {
-	if len(edits) == 0 {
-		return source, nil
+	res, err := ApplyWithResult(source, edits)
+	if err != nil {
+		return "", err
+	}
+	return res.Source, nil
+}
+
+// ApplyResult reports what became of each edit handed to the fixer.
+// Every edit lands in exactly one list, except an exact duplicate of an
+// applied edit, which is folded into it.
+type ApplyResult struct {
+	// Source is the rewritten source.
+	Source string
+	// Applied lists the edits spliced into Source.
+	Applied []katas.FixEdit
+	// Conflicts lists edits dropped because they overlap an applied
+	// edit. A later fixer pass, or the next run, can apply them.
+	Conflicts []katas.FixEdit
+	// Rejected lists edits dropped because applying them would add a
+	// parse error. Only ApplyParseSafe rejects edits.
+	Rejected []katas.FixEdit
+}
+
+// KataIDs returns the sorted, de-duplicated kata IDs of edits.
+func KataIDs(edits []katas.FixEdit) []string {
+	seen := map[string]bool{}
+	var ids []string
+	for _, e := range edits {
+		if e.KataID != "" && !seen[e.KataID] {
+			seen[e.KataID] = true
+			ids = append(ids, e.KataID)
+		}
 	}
+	sort.Strings(ids)
+	return ids
+}
 
+// ApplyWithResult is Apply reporting which edits were applied and which
+// were dropped as conflicts.
+func ApplyWithResult(source string, edits []katas.FixEdit) (ApplyResult, error) {
+	if len(edits) == 0 {
+		return ApplyResult{Source: source}, nil
+	}
 	resolved, err := resolveOffsets(source, edits)
 	if err != nil {
-		return "", err
+		return ApplyResult{}, err
 	}
+	kept, dropped := resolveConflicts(resolved)
+	res := ApplyResult{Source: splice(source, kept)}
+	for _, e := range kept {
+		res.Applied = append(res.Applied, e.edit)
+	}
+	for _, e := range dropped {
+		res.Conflicts = append(res.Conflicts, e.edit)
+	}
+	return res, nil
+}
 
-	resolved = resolveConflicts(resolved)
-
-	// Sort by start offset descending so we splice from the end
-	// backwards.
-	sort.SliceStable(resolved, func(i, j int) bool {
-		return resolved[i].start > resolved[j].start
+// ApplyParseSafe is ApplyWithResult with a safety net: it never returns
+// source that parses worse than the input. When the whole batch adds a
+// parse error, the non-conflicting edits are retried one at a time,
+// highest offset first, and each one that adds an error is rejected.
+// Highest-offset-first ordering means accepting an edit never shifts the
+// position of the edits not yet tried.
+func ApplyParseSafe(source string, edits []katas.FixEdit) (ApplyResult, error) {
+	res, err := ApplyWithResult(source, edits)
+	if err != nil {
+		return ApplyResult{}, err
+	}
+	baseErrs := parseErrors(source)
+	if res.Source == source || parseErrors(res.Source) <= baseErrs {
+		return res, nil
+	}
+	ordered := append([]katas.FixEdit(nil), res.Applied...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if ordered[i].Line != ordered[j].Line {
+			return ordered[i].Line > ordered[j].Line
+		}
+		return ordered[i].Column > ordered[j].Column
 	})
+	safe := ApplyResult{Source: source, Conflicts: res.Conflicts}
+	for _, e := range ordered {
+		trial, err := Apply(safe.Source, []katas.FixEdit{e})
+		if err != nil || parseErrors(trial) > baseErrs {
+			safe.Rejected = append(safe.Rejected, e)
+			continue
+		}
+		safe.Source = trial
+		safe.Applied = append(safe.Applied, e)
+	}
+	return safe, nil
+}
 
+// parseErrors reports how many parser errors src produces.
+func parseErrors(src string) int {
+	_, errs := parse(src)
+	return len(errs)
+}
+
+// parse lexes and parses source, returning the program and any parser
+// errors.
+func parse(source string) (*ast.Program, []string) {
+	p := parser.New(lexer.New(source))
+	program := p.ParseProgram()
+	return program, p.Errors()
+}
+
+// splice applies pairwise-disjoint edits to source, from the end
+// backwards so earlier splices do not invalidate later offsets.
+func splice(source string, edits []resolvedEdit) string {
+	ordered := append([]resolvedEdit(nil), edits...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].start > ordered[j].start
+	})
 	out := source
-	for _, e := range resolved {
+	for _, e := range ordered {
 		out = out[:e.start] + e.replace + out[e.start+e.length:]
 	}
-	return out, nil
+	return out
 }
 
-// resolveConflicts drops edits that overlap the span of a surviving
-// edit. The policy: prefer the edit with the earlier start; when two
-// edits share a start offset, prefer the longer. Returned edits are
-// guaranteed pairwise-disjoint and preserve the input order for
-// disjoint edits so Apply stays deterministic.
-func resolveConflicts(edits []resolvedEdit) []resolvedEdit {
-	if len(edits) < 2 {
-		return edits
-	}
+// resolveConflicts splits edits into a pairwise-disjoint set to apply and
+// the edits dropped because they overlap one of those. The policy: prefer
+// the edit with the earlier start; when two edits share a start offset,
+// prefer the longer. An exact duplicate of a kept edit — several
+// detectors sharing one Fix function (e.g. ZC1293 wired to ZC1006 /
+// ZC1020 / ZC1036) each fire their own copy — is folded into it rather
+// than reported as a conflict. Both lists come back in ascending start
+// order so Apply stays deterministic.
+func resolveConflicts(edits []resolvedEdit) (kept, dropped []resolvedEdit) {
+	ordered := append([]resolvedEdit(nil), edits...)
 	// Sort ascending by start; break ties by descending length so the
 	// longer span wins the single-pass overlap check.
-	sort.SliceStable(edits, func(i, j int) bool {
-		if edits[i].start != edits[j].start {
-			return edits[i].start < edits[j].start
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if ordered[i].start != ordered[j].start {
+			return ordered[i].start < ordered[j].start
 		}
-		return edits[i].length > edits[j].length
+		return ordered[i].length > ordered[j].length
 	})
-
-	kept := edits[:0]
-	var lastEnd int
-	for _, e := range edits {
-		if len(kept) > 0 && e.start < lastEnd {
-			// Overlaps with an already-kept edit; drop it.
-			continue
-		}
-		// Dedupe identical zero-length insertions at the same offset:
-		// when several detectors share a Fix function (e.g. ZC1293
-		// wired to ZC1006 / ZC1020 / ZC1036), each fires its own copy
-		// of the trailing ` ]]` insertion. The overlap test above
-		// can't drop them because zero-width spans don't overlap.
+	for _, e := range ordered {
 		if len(kept) > 0 {
+			// Kept edits are disjoint and sorted, so only the last one
+			// can overlap e.
 			prev := kept[len(kept)-1]
-			if prev.start == e.start && prev.length == 0 && e.length == 0 && prev.replace == e.replace {
+			if prev.start == e.start && prev.length == e.length && prev.replace == e.replace {
+				continue
+			}
+			if prev.overlaps(e) {
+				dropped = append(dropped, e)
 				continue
 			}
 		}
 		kept = append(kept, e)
-		lastEnd = e.start + e.length
 	}
-	// Return a copy so callers cannot depend on the in-place shuffle.
-	out := make([]resolvedEdit, len(kept))
-	copy(out, kept)
-	return out
+	return kept, dropped
+}
+
+// overlaps reports whether two resolved edits touch the same source
+// bytes. An insertion strictly inside another edit's span overlaps it,
+// as does an insertion at the start of a non-empty edit, since the two
+// splices could land in either order. Two insertions at one offset, or
+// an insertion at the end of a span, do not.
+func (a resolvedEdit) overlaps(b resolvedEdit) bool {
+	if a.start > b.start {
+		a, b = b, a
+	}
+	if a.start == b.start {
+		return a.length > 0 || b.length > 0
+	}
+	return b.start < a.start+a.length
 }
 
-// Overlap returns true when two edits share any source bytes. Exposed
-// for callers that want to pre-filter fix candidates before calling
-// Apply (for example a CLI that wants to report how many edits were
-// suppressed by nesting).
-func Overlap(a, b katas.FixEdit) bool {
-	// Same line, overlapping column ranges.
-	if a.Line == b.Line {
-		return a.Column < b.Column+b.Length && b.Column < a.Column+a.Length
+// Overlap reports whether two edits share any source bytes, resolving
+// their Line/Column against source so an edit whose Length crosses a
+// newline is compared across lines too. Edits that do not resolve
+// against source never overlap. Exposed for callers that want to
+// pre-filter fix candidates before calling Apply (for example a CLI
+// that wants to report how many edits were suppressed by nesting).
+func Overlap(source string, a, b katas.FixEdit) bool {
+	resolved, err := resolveOffsets(source, []katas.FixEdit{a, b})
+	if err != nil {
+		return false
 	}
-	// Different lines: non-overlapping by definition (edits don't
-	// span lines via the 1-D column metric alone).
-	return false
+	return resolved[0].overlaps(resolved[1])
 }
 
 // Diff returns a unified-format diff between the original source and
-// the source after applying edits. The diff uses the filename on both
-// the "---" and "+++" lines with a "(fixed)" suffix on the new side.
+// the source after applying edits, naming the file `a/<filename>` on the
+// "---" line and `b/<filename>` on the "+++" line as git does, so the
+// output feeds straight into `git apply` or `patch -p1`.
 func Diff(filename, source string, edits []katas.FixEdit) (string, error) {
 	fixed, err := Apply(source, edits)
 	if err != nil {
@@ -128,6 +236,7 @@ type resolvedEdit struct {
 	start   int
 	length  int
 	replace string
+	edit    katas.FixEdit
 }
 
 func resolveOffsets(source string, edits []katas.FixEdit) ([]resolvedEdit, error) {
@@ -166,153 +275,8 @@ func resolveOffsets(source string, edits []katas.FixEdit) ([]resolvedEdit, error
 			start:   start,
 			length:  e.Length,
 			replace: e.Replace,
+			edit:    e,
 		})
 	}
 	return out, nil
 }
-
-// unifiedDiff produces a minimal unified diff. It is not a full
-// implementation of diff3 — it emits a single hunk per contiguous
-// block of changes with three lines of context on each side. Good
-// enough for CLI display of auto-fix previews.
-func unifiedDiff(filename, a, b string) string {
-	al := splitLines(a)
-	bl := splitLines(b)
-	lcs := lcsTable(al, bl)
-	hunks := buildHunks(al, bl, lcs)
-
-	var out strings.Builder
-	fmt.Fprintf(&out, "--- %s\n", filename)
-	fmt.Fprintf(&out, "+++ %s (fixed)\n", filename)
-	for _, h := range hunks {
-		writeHunk(&out, al, bl, h)
-	}
-	return out.String()
-}
-
-// buildHunks walks the two line slices side by side and emits a hunk
-// for every divergent run, using lcs to decide which side moves on
-// the unequal step.
-func buildHunks(al, bl []string, lcs [][]int) []hunk {
-	var hunks []hunk
-	i, j := 0, 0
-	for i < len(al) || j < len(bl) {
-		for i < len(al) && j < len(bl) && al[i] == bl[j] {
-			i++
-			j++
-		}
-		if i >= len(al) && j >= len(bl) {
-			break
-		}
-		h := hunk{aStart: i, bStart: j}
-		i, j = collectHunkEdits(al, bl, lcs, &h, i, j)
-		h.aEnd = i
-		h.bEnd = j
-		hunks = append(hunks, h)
-	}
-	return hunks
-}
-
-func collectHunkEdits(al, bl []string, lcs [][]int, h *hunk, i, j int) (int, int) {
-	for i < len(al) || j < len(bl) {
-		if i < len(al) && j < len(bl) && al[i] == bl[j] {
-			return i, j
-		}
-		if hunkPicksDeletion(al, bl, lcs, i, j) {
-			h.edits = append(h.edits, diffEdit{op: '-', text: al[i]})
-			i++
-			continue
-		}
-		h.edits = append(h.edits, diffEdit{op: '+', text: bl[j]})
-		j++
-	}
-	return i, j
-}
-
-func hunkPicksDeletion(al, bl []string, lcs [][]int, i, j int) bool {
-	if j >= len(bl) {
-		return true
-	}
-	return i < len(al) && lcs[i+1][j] >= lcs[i][j+1]
-}
-
-func writeHunk(out *strings.Builder, al, bl []string, h hunk) {
-	aContextStart := maxInt(0, h.aStart-3)
-	aContextEnd := minInt(len(al), h.aEnd+3)
-	bContextStart := maxInt(0, h.bStart-3)
-	bContextEnd := minInt(len(bl), h.bEnd+3)
-
-	fmt.Fprintf(out, "@@ -%d,%d +%d,%d @@\n",
-		aContextStart+1, aContextEnd-aContextStart,
-		bContextStart+1, bContextEnd-bContextStart,
-	)
-	for k := aContextStart; k < h.aStart; k++ {
-		fmt.Fprintf(out, " %s\n", al[k])
-	}
-	for _, e := range h.edits {
-		fmt.Fprintf(out, "%c%s\n", e.op, e.text)
-	}
-	for k := h.aEnd; k < aContextEnd; k++ {
-		fmt.Fprintf(out, " %s\n", al[k])
-	}
-}
-
-type hunk struct {
-	aStart, aEnd int
-	bStart, bEnd int
-	edits        []diffEdit
-}
-
-type diffEdit struct {
-	op   byte
-	text string
-}
-
-func splitLines(s string) []string {
-	if s == "" {
-		return nil
-	}
-	lines := strings.Split(s, "\n")
-	// Drop the trailing empty element produced by a trailing newline —
-	// callers round-trip through Apply which preserves the newline.
-	if lines[len(lines)-1] == "" {
-		lines = lines[:len(lines)-1]
-	}
-	return lines
-}
-
-func lcsTable(a, b []string) [][]int {
-	// lcs[i][j] is the length of the longest common subsequence of
-	// a[i:] and b[j:].
-	t := make([][]int, len(a)+1)
-	for i := range t {
-		t[i] = make([]int, len(b)+1)
-	}
-	for i := len(a) - 1; i >= 0; i-- {
-		for j := len(b) - 1; j >= 0; j-- {
-			switch {
-			case a[i] == b[j]:
-				t[i][j] = t[i+1][j+1] + 1
-			case t[i+1][j] >= t[i][j+1]:
-				t[i][j] = t[i+1][j]
-			default:
-				t[i][j] = t[i][j+1]
-			}
-		}
-	}
-	return t
-}
-
-func maxInt(a, b int) int {
-	if a > b {
-		return a
-	}
-	return b
-}
-
-func minInt(a, b int) int {
-	if a < b {
-		return a
-	}
-	return b
-}
@@ -154,7 +154,7 @@ func TestDiff_SimpleReplacement(t *testing.T) {
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	wantHeaders := []string{"--- f.zsh", "+++ f.zsh (fixed)"}
+	wantHeaders := []string{"--- a/f.zsh\n", "+++ b/f.zsh\n"}
 	for _, h := range wantHeaders {
 		if !strings.Contains(got, h) {
 			t.Errorf("diff missing header %q:\n%s", h, got)
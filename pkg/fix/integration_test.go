@@ -13,11 +13,14 @@ import (
 )
 
 // runFix walks source through lex + parse, collects fix edits from the
-// shipped registry, applies them, and returns the rewritten source.
+// shipped registry, unsafe ones included, applies them, and returns the
+// rewritten source.
 // It is used by each kata-level integration test below; keeping it
 // centralised means the tests stay small and identical in shape.
 func runFix(t *testing.T, source string) string {
 	t.Helper()
+	katas.Registry.SetUnsafeFixes(true)
+	t.Cleanup(func() { katas.Registry.SetUnsafeFixes(false) })
 	l := lexer.New(source)
 	p := parser.New(l)
 	program := p.ParseProgram()
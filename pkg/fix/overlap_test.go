@@ -8,10 +8,13 @@ import (
 	"github.com/afadesigns/zshellcheck/pkg/katas"
 )
 
+// overlapSource is two ten-byte lines the overlap tests resolve against.
+const overlapSource = "0123456789\nabcdefghij\n"
+
 func TestOverlapSameLineOverlapping(t *testing.T) {
 	a := katas.FixEdit{Line: 1, Column: 1, Length: 5, Replace: ""}
 	b := katas.FixEdit{Line: 1, Column: 3, Length: 4, Replace: ""}
-	if !Overlap(a, b) {
+	if !Overlap(overlapSource, a, b) {
 		t.Errorf("expected overlap on same line")
 	}
 }
@@ -19,7 +22,7 @@ func TestOverlapSameLineOverlapping(t *testing.T) {
 func TestOverlapSameLineDisjoint(t *testing.T) {
 	a := katas.FixEdit{Line: 1, Column: 1, Length: 2, Replace: ""}
 	b := katas.FixEdit{Line: 1, Column: 5, Length: 2, Replace: ""}
-	if Overlap(a, b) {
+	if Overlap(overlapSource, a, b) {
 		t.Errorf("disjoint edits reported as overlapping")
 	}
 }
@@ -27,11 +30,103 @@ func TestOverlapSameLineDisjoint(t *testing.T) {
 func TestOverlapDifferentLines(t *testing.T) {
 	a := katas.FixEdit{Line: 1, Column: 1, Length: 5, Replace: ""}
 	b := katas.FixEdit{Line: 2, Column: 1, Length: 5, Replace: ""}
-	if Overlap(a, b) {
+	if Overlap(overlapSource, a, b) {
 		t.Errorf("edits on different lines reported as overlapping")
 	}
 }
 
+// An edit whose Length crosses the newline overlaps an edit on the next
+// line that it reaches.
+func TestOverlapAcrossLines(t *testing.T) {
+	a := katas.FixEdit{Line: 1, Column: 9, Length: 5, Replace: ""}
+	b := katas.FixEdit{Line: 2, Column: 2, Length: 1, Replace: ""}
+	if !Overlap(overlapSource, a, b) || !Overlap(overlapSource, b, a) {
+		t.Errorf("multi-line edit not reported as overlapping the next line")
+	}
+	c := katas.FixEdit{Line: 2, Column: 3, Length: 1, Replace: ""}
+	if Overlap(overlapSource, a, c) {
+		t.Errorf("edit past the multi-line span reported as overlapping")
+	}
+}
+
+func TestOverlapInsertions(t *testing.T) {
+	span := katas.FixEdit{Line: 1, Column: 3, Length: 4}
+	for _, tc := range []struct {
+		col  int
+		want bool
+	}{{3, true}, {5, true}, {7, false}, {2, false}} {
+		ins := katas.FixEdit{Line: 1, Column: tc.col, Replace: "x"}
+		if got := Overlap(overlapSource, span, ins); got != tc.want {
+			t.Errorf("insertion at column %d: Overlap = %v, want %v", tc.col, got, tc.want)
+		}
+	}
+	a := katas.FixEdit{Line: 1, Column: 3, Replace: "x"}
+	b := katas.FixEdit{Line: 1, Column: 3, Replace: "y"}
+	if Overlap(overlapSource, a, b) {
+		t.Errorf("two insertions at one offset reported as overlapping")
+	}
+}
+
+func TestOverlapUnresolvable(t *testing.T) {
+	a := katas.FixEdit{Line: 9, Column: 1, Length: 5}
+	if Overlap(overlapSource, a, a) {
+		t.Errorf("edit outside the source reported as overlapping")
+	}
+}
+
+func TestApplyWithResult(t *testing.T) {
+	outer := katas.FixEdit{Line: 1, Column: 1, Length: 5, Replace: "ABCDE", KataID: "ZC1"}
+	inner := katas.FixEdit{Line: 1, Column: 2, Length: 1, Replace: "z", KataID: "ZC2"}
+	dup := outer
+	dup.KataID = "ZC3"
+	other := katas.FixEdit{Line: 2, Column: 1, Length: 1, Replace: "A", KataID: "ZC4"}
+	res, err := ApplyWithResult(overlapSource, []katas.FixEdit{inner, outer, dup, other})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Source != "ABCDE56789\nAbcdefghij\n" {
+		t.Errorf("Source = %q", res.Source)
+	}
+	if len(res.Applied) != 2 || res.Applied[0].KataID != "ZC1" || res.Applied[1].KataID != "ZC4" {
+		t.Errorf("Applied = %+v", res.Applied)
+	}
+	// The inner edit is a conflict; the exact duplicate is folded away.
+	if len(res.Conflicts) != 1 || res.Conflicts[0].KataID != "ZC2" || len(res.Rejected) != 0 {
+		t.Errorf("Conflicts = %+v, Rejected = %+v", res.Conflicts, res.Rejected)
+	}
+	if got := KataIDs(res.Conflicts); len(got) != 1 || got[0] != "ZC2" {
+		t.Errorf("conflicting katas = %v", got)
+	}
+}
+
+func TestApplyParseSafe(t *testing.T) {
+	base := "echo hi\n"
+	// Every candidate edit breaks the parse alone -> nothing is kept and
+	// base is returned unchanged.
+	stray := katas.FixEdit{Line: 1, Column: 1, Length: 0, Replace: "fi\n", KataID: "ZC1"}
+	res, err := ApplyParseSafe(base, []katas.FixEdit{stray})
+	if err != nil || res.Source != base || len(res.Applied) != 0 || len(res.Rejected) != 1 {
+		t.Errorf("all-break: got %+v, %v", res, err)
+	}
+	// A safe edit is kept while the breaking one is rejected.
+	safe := katas.FixEdit{Line: 1, Column: 6, Length: 2, Replace: "there", KataID: "ZC2"}
+	res, _ = ApplyParseSafe(base, []katas.FixEdit{stray, safe})
+	if res.Source != "echo there\n" || len(res.Applied) != 1 || len(res.Rejected) != 1 || res.Rejected[0].KataID != "ZC1" {
+		t.Errorf("mixed: got %+v", res)
+	}
+
+	// Two safe edits on different lines exercise the highest-offset-first
+	// ordering: the line-two edit applies before the line-one edit.
+	multi := "echo a\necho b\n"
+	e1 := katas.FixEdit{Line: 1, Column: 1, Length: 4, Replace: "print"}
+	e2 := katas.FixEdit{Line: 2, Column: 1, Length: 4, Replace: "print"}
+	strayFi := katas.FixEdit{Line: 2, Column: 7, Replace: "\nfi"}
+	res, _ = ApplyParseSafe(multi, []katas.FixEdit{e1, e2, strayFi})
+	if len(res.Applied) != 2 || len(res.Rejected) != 1 || res.Source != "print a\nprint b\n" {
+		t.Errorf("multiline: got %+v", res)
+	}
+}
+
 func TestDiff_EmptyEditsReturnsBlank(t *testing.T) {
 	out, err := Diff("file.zsh", "echo hi\n", nil)
 	if err != nil {
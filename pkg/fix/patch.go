@@ -0,0 +1,183 @@
+// SPDX-License-Identifier: MIT
+// Copyright the ZShellCheck contributors.
+package fix
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/afadesigns/zshellcheck/pkg/katas"
+)
+
+// Trace follows the lines of a source through the passes of a multi-pass
+// fix, so an edit applied to the rewritten source of a later pass can
+// still be credited to a line of the original.
+type Trace struct {
+	// origin maps each line of the current source to the 0-based line of
+	// the original it came from. A line a fix inserted maps to the line
+	// its change starts at.
+	origin []int
+	size   int
+	edits  []katas.FixEdit
+}
+
+// NewTrace starts a trace over the original source src.
+func NewTrace(src string) *Trace {
+	n := len(diffLines(src))
+	t := &Trace{origin: make([]int, n), size: n}
+	for i := range t.origin {
+		t.origin[i] = i
+	}
+	return t
+}
+
+// Pass records one fix pass that turned before into after by applying
+// edits, whose lines refer to before.
+func (t *Trace) Pass(before, after string, applied []katas.FixEdit) {
+	for _, e := range applied {
+		e.Line = t.originOf(e.Line-1) + 1
+		t.edits = append(t.edits, e)
+	}
+	ops := editScript(diffLines(before), diffLines(after))
+	next := make([]int, 0, len(t.origin))
+	anchor := 0
+	for i, op := range ops {
+		if op.kind != ' ' && (i == 0 || ops[i-1].kind == ' ') {
+			anchor = t.originOf(op.a)
+		}
+		switch op.kind {
+		case ' ':
+			next = append(next, t.originOf(op.a))
+		case '+':
+			next = append(next, anchor)
+		}
+	}
+	t.origin = next
+}
+
+// Edits returns every edit recorded so far, with Line rewritten to the
+// original source's line. Columns still refer to the pass that applied
+// the edit.
+func (t *Trace) Edits() []katas.FixEdit {
+	return t.edits
+}
+
+// originOf returns the original line of current line i, or the line past
+// the end of the original for a position at the end of the source.
+func (t *Trace) originOf(i int) int {
+	if i >= 0 && i < len(t.origin) {
+		return t.origin[i]
+	}
+	return t.size
+}
+
+// FilePatch is one file's entry in a multi-file patch: its path, its
+// permission bits, the source before and after fixing, and the applied
+// edits with lines in the original source (see Trace).
+type FilePatch struct {
+	Path  string
+	Mode  os.FileMode
+	Old   string
+	New   string
+	Edits []katas.FixEdit
+}
+
+// Manifest maps every hunk of a patch written by WritePatch to the katas
+// whose edits it contains.
+type Manifest struct {
+	Files []ManifestFile `json:"Files"`
+}
+
+// ManifestFile lists the hunks of one file in the patch.
+type ManifestFile struct {
+	Path  string         `json:"Path"`
+	Hunks []ManifestHunk `json:"Hunks"`
+}
+
+// ManifestHunk describes one hunk by its header and 1-based line ranges,
+// and names the katas that produced it.
+type ManifestHunk struct {
+	Header   string   `json:"Header"`
+	OldStart int      `json:"OldStart"`
+	OldLines int      `json:"OldLines"`
+	NewStart int      `json:"NewStart"`
+	NewLines int      `json:"NewLines"`
+	Katas    []string `json:"Katas"`
+}
+
+// WritePatch writes files as a single git-format patch — a `diff --git`
+// header, an `index` line carrying the blob IDs and file mode, then the
+// hunks — that `git apply` or `git am` can take. Files whose source did
+// not change are skipped. It returns the manifest of the hunks written.
+func WritePatch(w io.Writer, files []FilePatch) (Manifest, error) {
+	var m Manifest
+	for _, f := range files {
+		if f.Old == f.New {
+			continue
+		}
+		path := diffPath(f.Path)
+		if _, err := fmt.Fprintf(w, "diff --git a/%s b/%s\nindex %s..%s %s\n--- a/%s\n+++ b/%s\n",
+			path, path, blobID(f.Old), blobID(f.New), gitMode(f.Mode), path, path); err != nil {
+			return m, err
+		}
+		mf := ManifestFile{Path: path}
+		for _, h := range diffHunks(f.Old, f.New) {
+			if _, err := io.WriteString(w, h.text); err != nil {
+				return m, err
+			}
+			mf.Hunks = append(mf.Hunks, ManifestHunk{
+				Header:   h.header(),
+				OldStart: headerStart(h.aStart, h.aLen),
+				OldLines: h.aLen,
+				NewStart: headerStart(h.bStart, h.bLen),
+				NewLines: h.bLen,
+				Katas:    hunkKatas(h, f.Edits),
+			})
+		}
+		m.Files = append(m.Files, mf)
+	}
+	return m, nil
+}
+
+// hunkKatas returns the katas of the edits whose original line falls in
+// the old-side range of h; a pure insertion claims the edits at the line
+// it is inserted before.
+func hunkKatas(h hunk, edits []katas.FixEdit) []string {
+	var in []katas.FixEdit
+	for _, e := range edits {
+		line := e.Line - 1
+		if line >= h.aStart && line < h.aStart+max(h.aLen, 1) {
+			in = append(in, e)
+		}
+	}
+	if ids := KataIDs(in); ids != nil {
+		return ids
+	}
+	return []string{}
+}
+
+// headerStart returns the 1-based start line a hunk header shows for a
+// side; an empty side names the line before it.
+func headerStart(start, length int) int {
+	if length == 0 {
+		return start
+	}
+	return start + 1
+}
+
+// blobID returns the abbreviated git object ID of content as a blob.
+func blobID(content string) string {
+	sum := sha1.Sum([]byte(fmt.Sprintf("blob %d\x00%s", len(content), content)))
+	return fmt.Sprintf("%x", sum[:])[:12]
+}
+
+// gitMode renders permission bits as the file mode git records: 100755
+// for an executable, 100644 otherwise.
+func gitMode(mode os.FileMode) string {
+	if mode&0o111 != 0 {
+		return "100755"
+	}
+	return "100644"
+}
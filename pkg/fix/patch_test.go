@@ -0,0 +1,284 @@
+// SPDX-License-Identifier: MIT
+// Copyright the ZShellCheck contributors.
+package fix
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/afadesigns/zshellcheck/pkg/katas"
+)
+
+// applyPatch is a strict pure-Go unified-diff applier: every context and
+// deleted line must match the source exactly, at exactly the position the
+// hunk header names, and `\ No newline at end of file` strips the
+// terminator from the line before it. It stands in for `git apply` so the
+// round-trip tests need no external tools.
+func applyPatch(src, patch string) (string, error) {
+	if patch == "" {
+		return src, nil
+	}
+	old := diffLines(src)
+	lines := strings.SplitAfter(patch, "\n")
+	if len(lines) < 2 || !strings.HasPrefix(lines[0], "--- a/") || !strings.HasPrefix(lines[1], "+++ b/") {
+		return "", fmt.Errorf("missing a/ b/ file headers")
+	}
+	var out []string
+	pos := 0 // next unconsumed line of old
+	for i := 2; i < len(lines) && lines[i] != ""; {
+		aStart, aLen, bLen, err := parseHunkHeader(lines[i])
+		if err != nil {
+			return "", err
+		}
+		i++
+		// An empty old range names the line before the insertion point.
+		at := aStart - 1
+		if aLen == 0 {
+			at = aStart
+		}
+		if at < pos || at > len(old) {
+			return "", fmt.Errorf("hunk at line %d out of order", aStart)
+		}
+		out = append(out, old[pos:at]...)
+		pos = at
+		var gotA, gotB int
+		for i < len(lines) && lines[i] != "" && !strings.HasPrefix(lines[i], "@@") {
+			body := lines[i]
+			i++
+			if i < len(lines) && lines[i] == noNewline {
+				body = strings.TrimSuffix(body, "\n")
+				i++
+			}
+			switch body[0] {
+			case ' ', '-':
+				if pos >= len(old) || old[pos] != body[1:] {
+					return "", fmt.Errorf("line %d does not match %q", pos+1, body)
+				}
+				pos++
+				gotA++
+				if body[0] == ' ' {
+					out = append(out, body[1:])
+					gotB++
+				}
+			case '+':
+				out = append(out, body[1:])
+				gotB++
+			default:
+				return "", fmt.Errorf("bad hunk line %q", body)
+			}
+		}
+		if gotA != aLen || gotB != bLen {
+			return "", fmt.Errorf("hunk -%d,%d counts %d/%d lines", aLen, bLen, gotA, gotB)
+		}
+	}
+	out = append(out, old[pos:]...)
+	return strings.Join(out, ""), nil
+}
+
+// parseHunkHeader reads `@@ -s[,l] +s[,l] @@`, where an omitted count is one.
+func parseHunkHeader(line string) (aStart, aLen, bLen int, err error) {
+	var oldR, newR string
+	if _, err = fmt.Sscanf(line, "@@ %s %s @@", &oldR, &newR); err != nil {
+		return 0, 0, 0, fmt.Errorf("bad hunk header %q: %w", line, err)
+	}
+	aStart, aLen, err = parseHunkRange(strings.TrimPrefix(oldR, "-"))
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	_, bLen, err = parseHunkRange(strings.TrimPrefix(newR, "+"))
+	return aStart, aLen, bLen, err
+}
+
+func parseHunkRange(r string) (start, length int, err error) {
+	s, l, ok := strings.Cut(r, ",")
+	if start, err = strconv.Atoi(s); err != nil {
+		return 0, 0, err
+	}
+	if !ok {
+		return start, 1, nil
+	}
+	length, err = strconv.Atoi(l)
+	return start, length, err
+}
+
+// checkRoundTrip fails t unless the diff from oldSrc to newSrc applies
+// cleanly to oldSrc and reproduces newSrc.
+func checkRoundTrip(t *testing.T, oldSrc, newSrc string) {
+	t.Helper()
+	patch := unifiedDiff("dir/f.zsh", oldSrc, newSrc)
+	got, err := applyPatch(oldSrc, patch)
+	if err != nil {
+		t.Errorf("patch does not apply: %v\nold: %q\nnew: %q\npatch:\n%s", err, oldSrc, newSrc, patch)
+		return
+	}
+	if got != newSrc {
+		t.Errorf("round trip mismatch\nold:  %q\nnew:  %q\ngot:  %q\npatch:\n%s", oldSrc, newSrc, got, patch)
+	}
+}
+
+func TestUnifiedDiff_RoundTrip(t *testing.T) {
+	cases := []struct{ old, new string }{
+		{"", "a\n"},
+		{"a\n", ""},
+		{"a", "a\n"},
+		{"a\n", "a"},
+		{"a\nb", "a\nc"},
+		{"a\nb\nc\n", "x\nb\ny\n"},
+		{"a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\n", "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nK\nl\n"},
+		{"same\nsame\nsame\n", "same\nsame\n"},
+		{"x\ny\n", "y\nx\n"},
+		{"one\ntwo\nthree", "zero\none\ntwo\nthree\nfour"},
+	}
+	for _, c := range cases {
+		checkRoundTrip(t, c.old, c.new)
+	}
+}
+
+func TestUnifiedDiff_RoundTripRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(35))
+	// A small alphabet makes repeated lines, and so ambiguous alignments,
+	// common.
+	gen := func() string {
+		var b strings.Builder
+		for n := rng.Intn(30); n > 0; n-- {
+			b.WriteString(string(rune('a' + rng.Intn(4))))
+			b.WriteByte('\n')
+		}
+		s := b.String()
+		if s != "" && rng.Intn(4) == 0 {
+			s = strings.TrimSuffix(s, "\n")
+		}
+		return s
+	}
+	for range 2000 {
+		checkRoundTrip(t, gen(), gen())
+	}
+}
+
+func TestUnifiedDiff_RoundTripFixCorpus(t *testing.T) {
+	for _, src := range roundTripCorpus {
+		fixed, _, err := fixOnce(src)
+		if err != nil {
+			t.Fatalf("fixOnce(%q): %v", src, err)
+		}
+		checkRoundTrip(t, src, fixed)
+	}
+}
+
+func TestUnifiedDiff_Headers(t *testing.T) {
+	for name, want := range map[string]string{
+		"f.zsh":          "f.zsh",
+		"./dir/f.zsh":    "dir/f.zsh",
+		"/abs/path.zsh":  "abs/path.zsh",
+		"nested/x/y.zsh": "nested/x/y.zsh",
+	} {
+		out := unifiedDiff(name, "a\n", "b\n")
+		if !strings.HasPrefix(out, "--- a/"+want+"\n+++ b/"+want+"\n") {
+			t.Errorf("unifiedDiff(%q) headers:\n%s", name, out)
+		}
+	}
+}
+
+func TestUnifiedDiff_NoNewlineMarker(t *testing.T) {
+	out := unifiedDiff("f", "a\nb", "a\nc")
+	want := "--- a/f\n+++ b/f\n@@ -1,2 +1,2 @@\n a\n-b\n" + noNewline + "+c\n" + noNewline
+	if out != want {
+		t.Errorf("got:\n%s\nwant:\n%s", out, want)
+	}
+}
+
+func TestUnifiedDiff_CoalescesHunks(t *testing.T) {
+	var base []string
+	for i := range 20 {
+		base = append(base, strconv.Itoa(i)+"\n")
+	}
+	edit := func(lines ...int) string {
+		out := append([]string(nil), base...)
+		for _, l := range lines {
+			out[l] = "x\n"
+		}
+		return strings.Join(out, "")
+	}
+	src := strings.Join(base, "")
+	// Changes six lines apart share their context and form one hunk;
+	// seven apart leave a gap and stay separate.
+	if got := strings.Count(unifiedDiff("f", src, edit(5, 12)), "@@ -"); got != 1 {
+		t.Errorf("changes 6 lines apart: got %d hunks, want 1", got)
+	}
+	if got := strings.Count(unifiedDiff("f", src, edit(5, 13)), "@@ -"); got != 2 {
+		t.Errorf("changes 7 lines apart: got %d hunks, want 2", got)
+	}
+}
+
+func TestUnifiedDiff_LargeInput(t *testing.T) {
+	var b strings.Builder
+	for i := range 50000 {
+		fmt.Fprintf(&b, "line %d\n", i)
+	}
+	src := b.String()
+	fixed := strings.Replace(src, "line 25000\n", "changed\n", 1)
+	out := unifiedDiff("f", src, fixed)
+	if !strings.Contains(out, "@@ -24998,7 +24998,7 @@\n") {
+		t.Errorf("unexpected hunk header:\n%s", out)
+	}
+	checkRoundTrip(t, src, fixed)
+}
+
+func TestTrace_CreditsLaterPassesToOriginalLines(t *testing.T) {
+	src := "a\nb\nc\n"
+	tr := NewTrace(src)
+	// Pass 1 inserts two lines ahead of `c`; pass 2 rewrites `c`, now on
+	// line 5, which must be credited to original line 3.
+	pass1 := "a\nb\nx\ny\nc\n"
+	tr.Pass(src, pass1, []katas.FixEdit{{Line: 2, Column: 2, Replace: "\nx\ny", KataID: "ZC0001"}})
+	tr.Pass(pass1, "a\nb\nx\ny\nC\n", []katas.FixEdit{{Line: 5, Column: 1, Length: 1, Replace: "C", KataID: "ZC0002"}})
+	got := tr.Edits()
+	if len(got) != 2 || got[0].Line != 2 || got[1].Line != 3 {
+		t.Fatalf("Edits() = %+v, want lines 2 and 3", got)
+	}
+}
+
+func TestWritePatch(t *testing.T) {
+	var lines []string
+	for i := range 20 {
+		lines = append(lines, fmt.Sprintf("echo %d\n", i))
+	}
+	old := strings.Join(lines, "")
+	fixed := strings.Replace(strings.Replace(old, "echo 1\n", "print 1\n", 1), "echo 15\n", "print 15\n", 1)
+	files := []FilePatch{
+		{Path: "./a.zsh", Mode: 0o755, Old: old, New: fixed, Edits: []katas.FixEdit{
+			{Line: 2, Column: 1, Length: 4, Replace: "print", KataID: "ZC1037"},
+			{Line: 16, Column: 1, Length: 4, Replace: "print", KataID: "ZC1092"},
+		}},
+		{Path: "unchanged.zsh", Mode: 0o644, Old: "x\n", New: "x\n"},
+	}
+	var b strings.Builder
+	m, err := WritePatch(&b, files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	patch := b.String()
+	if !strings.HasPrefix(patch, "diff --git a/a.zsh b/a.zsh\nindex ") || !strings.Contains(patch, " 100755\n--- a/a.zsh\n+++ b/a.zsh\n") {
+		t.Errorf("unexpected file header:\n%s", patch)
+	}
+	// `git hash-object` of "echo 1\n".
+	if got := blobID("echo 1\n"); got != "c3e9e049287b" {
+		t.Errorf("blobID = %s", got)
+	}
+	if strings.Contains(patch, "unchanged.zsh") {
+		t.Errorf("unchanged file in patch:\n%s", patch)
+	}
+	if len(m.Files) != 1 || len(m.Files[0].Hunks) != 2 {
+		t.Fatalf("manifest = %+v, want one file with two hunks", m)
+	}
+	h0, h1 := m.Files[0].Hunks[0], m.Files[0].Hunks[1]
+	if h0.Header != "@@ -1,5 +1,5 @@" || strings.Join(h0.Katas, ",") != "ZC1037" {
+		t.Errorf("hunk 0 = %+v", h0)
+	}
+	if h1.OldStart != 13 || strings.Join(h1.Katas, ",") != "ZC1092" {
+		t.Errorf("hunk 1 = %+v", h1)
+	}
+}
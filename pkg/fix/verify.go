@@ -0,0 +1,254 @@
+// SPDX-License-Identifier: MIT
+// Copyright the ZShellCheck contributors.
+package fix
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/afadesigns/zshellcheck/pkg/ast"
+	"github.com/afadesigns/zshellcheck/pkg/katas"
+)
+
+// Problem names one way a fix can fail verification.
+type Problem string
+
+const (
+	// ProblemBroken: the fix's edits do not apply, or the fixed source no
+	// longer parses.
+	ProblemBroken Problem = "broken"
+	// ProblemStillReported: the kata still reports as many findings after
+	// the fix as before it, so the fix did not remove its own.
+	ProblemStillReported Problem = "still-reported"
+	// ProblemNewFindings: the fix made other katas report findings the
+	// source did not have.
+	ProblemNewFindings Problem = "new-findings"
+	// ProblemNoConverge: re-running the kata's fix over the lines it
+	// rewrote keeps producing changes after the pass cap.
+	ProblemNoConverge Problem = "no-converge"
+	// ProblemParseChanged: a statement the edits do not touch parses
+	// differently after the fix.
+	ProblemParseChanged Problem = "parse-changed"
+)
+
+// Verifier checks that auto-fixes do what they claim: for every fixable
+// violation in a source it applies that violation's fix alone, re-lints,
+// and reports the fix if it leaves the violation, introduces findings
+// from other katas, does not converge, or changes the parse outside the
+// edited span.
+type Verifier struct {
+	Registry *katas.KatasRegistry
+	// Disabled lists katas to leave out, both as fixes to verify and as
+	// findings to compare.
+	Disabled []string
+	// MaxPasses caps the convergence check; zero means five, the same
+	// cap `-fix` uses.
+	MaxPasses int
+}
+
+// VerifyFailure is one fix that failed verification.
+type VerifyFailure struct {
+	Violation katas.Violation
+	Edits     []katas.FixEdit
+	Problem   Problem
+	Detail    string
+}
+
+// VerifyReport is the outcome of verifying every fix in one source.
+type VerifyReport struct {
+	// Checked counts the violations whose fix was verified.
+	Checked  int
+	Failures []VerifyFailure
+}
+
+// Verify checks the fix of every fixable violation in source. It returns
+// an error only when source does not parse to begin with.
+func (vr Verifier) Verify(filename string, source []byte) (VerifyReport, error) {
+	var report VerifyReport
+	program, errs := parse(string(source))
+	if len(errs) > 0 {
+		return report, fmt.Errorf("%s does not parse: %s", filename, errs[0])
+	}
+	fixes := vr.Registry.ViolationFixes(filename, source, program, vr.Disabled)
+	before := vr.counts(filename, source, program)
+	for _, vf := range fixes {
+		if len(vf.Edits) == 0 {
+			continue
+		}
+		report.Checked++
+		problem, detail := vr.verifyOne(filename, string(source), program, before, vf)
+		if problem != "" {
+			report.Failures = append(report.Failures, VerifyFailure{Violation: vf.Violation, Edits: vf.Edits, Problem: problem, Detail: detail})
+		}
+	}
+	return report, nil
+}
+
+// verifyOne applies one violation's edits and runs each check in turn,
+// returning the first problem found.
+func (vr Verifier) verifyOne(filename, source string, program *ast.Program, before map[string]int, vf katas.ViolationFix) (Problem, string) {
+	id := vf.Violation.KataID
+	res, err := ApplyWithResult(source, vf.Edits)
+	if err != nil {
+		return ProblemBroken, err.Error()
+	}
+	if len(res.Conflicts) > 0 {
+		return ProblemBroken, fmt.Sprintf("%d of its own edits overlap", len(res.Conflicts))
+	}
+	fixed := res.Source
+	fixedProgram, errs := parse(fixed)
+	if len(errs) > 0 {
+		return ProblemBroken, "fixed source does not parse: " + errs[0]
+	}
+	after := vr.counts(filename, []byte(fixed), fixedProgram)
+	if after[id] >= before[id] {
+		return ProblemStillReported, fmt.Sprintf("%s reports %d finding(s) before and %d after", id, before[id], after[id])
+	}
+	if added := newFindings(before, after, id); len(added) > 0 {
+		return ProblemNewFindings, strings.Join(added, ", ")
+	}
+	if !vr.converges(filename, source, fixed, id) {
+		return ProblemNoConverge, fmt.Sprintf("%s still rewrites its output after %d passes", id, vr.maxPasses())
+	}
+	if stmt := changedStatement(source, program, fixedProgram, vf.Edits); stmt != "" {
+		return ProblemParseChanged, fmt.Sprintf("untouched statement %q no longer parses the same", stmt)
+	}
+	return "", ""
+}
+
+// counts tallies the findings in source per kata.
+func (vr Verifier) counts(filename string, source []byte, program *ast.Program) map[string]int {
+	violations, _ := vr.Registry.CheckProgram(filename, source, program, vr.Disabled, false)
+	out := map[string]int{}
+	for _, v := range violations {
+		out[v.KataID]++
+	}
+	return out
+}
+
+// newFindings lists, in ID order, the katas other than id that report
+// more findings after the fix than before, as `ZC####: +n`.
+func newFindings(before, after map[string]int, id string) []string {
+	var added []string
+	for k, n := range after {
+		if k != id && n > before[k] {
+			added = append(added, fmt.Sprintf("%s: +%d", k, n-before[k]))
+		}
+	}
+	sort.Strings(added)
+	return added
+}
+
+// converges re-applies kata id's fixes to the lines the fix changed
+// until they stop changing them, reporting whether that happens within
+// the pass cap. Findings of id elsewhere in the file are left alone, so
+// another finding's fix cannot be blamed on this one.
+func (vr Verifier) converges(filename, original, fixed, id string) bool {
+	source := fixed
+	for pass := 0; pass < vr.maxPasses(); pass++ {
+		program, errs := parse(source)
+		if len(errs) > 0 {
+			return false
+		}
+		changed := changedLines(original, source)
+		var edits []katas.FixEdit
+		for _, vf := range vr.Registry.ViolationFixes(filename, []byte(source), program, vr.Disabled) {
+			if vf.Violation.KataID == id && changed[vf.Violation.Line] {
+				edits = append(edits, vf.Edits...)
+			}
+		}
+		next, err := Apply(source, edits)
+		if err != nil {
+			return false
+		}
+		if next == source {
+			return true
+		}
+		source = next
+	}
+	return false
+}
+
+// changedLines returns the 1-based lines of newSrc that differ from
+// oldSrc.
+func changedLines(oldSrc, newSrc string) map[int]bool {
+	out := map[int]bool{}
+	for _, op := range editScript(diffLines(oldSrc), diffLines(newSrc)) {
+		if op.kind == '+' {
+			out[op.b+1] = true
+		}
+	}
+	return out
+}
+
+func (vr Verifier) maxPasses() int {
+	if vr.MaxPasses > 0 {
+		return vr.MaxPasses
+	}
+	return 5
+}
+
+// changedStatement returns the first statement of the original program
+// that lies wholly outside the edited spans yet has no identical
+// counterpart in the fixed program, or "" when every such statement
+// survives.
+func changedStatement(source string, program, fixedProgram *ast.Program, edits []katas.FixEdit) string {
+	src := []byte(source)
+	var spans [][2]int
+	for _, e := range edits {
+		start := katas.LineColToByteOffset(src, e.Line, e.Column)
+		spans = append(spans, [2]int{start, start + e.Length})
+	}
+	survivors := map[string]int{}
+	ast.Walk(fixedProgram, func(n ast.Node) bool {
+		if _, ok := n.(ast.Statement); ok {
+			survivors[n.String()]++
+		}
+		return true
+	})
+	changed := ""
+	ast.Walk(program, func(n ast.Node) bool {
+		if changed != "" {
+			return false
+		}
+		if _, ok := n.(ast.Statement); !ok || touches(src, n, spans) {
+			return true
+		}
+		if survivors[n.String()] == 0 {
+			changed = n.String()
+			return false
+		}
+		survivors[n.String()]--
+		return false
+	})
+	return changed
+}
+
+// touches reports whether node's source range meets any of spans. An
+// insertion touches a node when it lands inside it or on either edge.
+func touches(src []byte, node ast.Node, spans [][2]int) bool {
+	startLine, startCol := 0, 0
+	ast.Walk(node, func(n ast.Node) bool {
+		tok := n.TokenLiteralNode()
+		if tok.Line > 0 && (startLine == 0 || tok.Line < startLine || tok.Line == startLine && tok.Column < startCol) {
+			startLine, startCol = tok.Line, tok.Column
+		}
+		return true
+	})
+	endLine, endCol := ast.End(node)
+	if startLine == 0 || endLine == 0 {
+		return true
+	}
+	start := katas.LineColToByteOffset(src, startLine, startCol)
+	end := katas.LineColToByteOffset(src, endLine, endCol)
+	if start < 0 || end < 0 {
+		return true
+	}
+	for _, s := range spans {
+		if s[0] <= end && s[1] >= start {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+// SPDX-License-Identifier: MIT
+// Copyright the ZShellCheck contributors.
+package fix
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/afadesigns/zshellcheck/pkg/ast"
+	"github.com/afadesigns/zshellcheck/pkg/katas"
+)
+
+// commandKata flags every simple command whose name starts with name and
+// that has at most maxArgs arguments. When replace is non-nil its fix
+// rewrites the command name to what replace returns.
+func commandKata(id, name string, maxArgs int, replace func(string) string) katas.Kata {
+	k := katas.Kata{
+		ID: id,
+		Check: func(node ast.Node) []katas.Violation {
+			cmd, ok := node.(*ast.SimpleCommand)
+			if !ok || !strings.HasPrefix(cmd.Name.String(), name) || len(cmd.Arguments) > maxArgs {
+				return nil
+			}
+			tok := cmd.TokenLiteralNode()
+			return []katas.Violation{{KataID: id, Message: "flagged", Line: tok.Line, Column: tok.Column}}
+		},
+	}
+	if replace != nil {
+		k.Fix = func(node ast.Node, v katas.Violation, _ []byte) []katas.FixEdit {
+			cmd := node.(*ast.SimpleCommand)
+			old := cmd.Name.String()
+			return []katas.FixEdit{{Line: v.Line, Column: v.Column, Length: len(old), Replace: replace(old)}}
+		}
+	}
+	return k
+}
+
+func fixedText(s string) func(string) string { return func(string) string { return s } }
+
+// verifyWith verifies src against a registry holding only ks. A bare word
+// alone on the last line parses as an identifier rather than a command, so
+// the sources give their commands an argument or a following line.
+func verifyWith(t *testing.T, src string, ks ...katas.Kata) VerifyReport {
+	t.Helper()
+	kr := katas.NewKatasRegistry()
+	for _, k := range ks {
+		kr.RegisterKata(ast.SimpleCommandNode, k)
+	}
+	report, err := Verifier{Registry: kr}.Verify("t.zsh", []byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return report
+}
+
+func TestVerify_GoodFix(t *testing.T) {
+	report := verifyWith(t, "good\nother\n", commandKata("ZC9001", "good", 0, fixedText("fine")))
+	if report.Checked != 1 || len(report.Failures) != 0 {
+		t.Errorf("report = %+v, want one clean check", report)
+	}
+}
+
+func TestVerify_Problems(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		ks   []katas.Kata
+		want Problem
+	}{
+		{"fix leaves its finding", "noop x\n", []katas.Kata{commandKata("ZC9001", "noop", 1, fixedText("noop"))}, ProblemStillReported},
+		{
+			"fix trades one finding for another", "old x\n",
+			[]katas.Kata{commandKata("ZC9001", "old", 1, fixedText("new")), commandKata("ZC9002", "new", 1, nil)},
+			ProblemNewFindings,
+		},
+		// The trailing backslash joins the next line's command onto this
+		// one, so `next` — never edited — stops being a statement.
+		{"fix swallows the next statement", "join\nnext\n", []katas.Kata{commandKata("ZC9001", "join", 0, fixedText("join \\"))}, ProblemParseChanged},
+		{"fix breaks the parse", "brk x\n", []katas.Kata{commandKata("ZC9001", "brk", 1, fixedText("brk )"))}, ProblemBroken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := verifyWith(t, tt.src, tt.ks...)
+			if len(report.Failures) != 1 || report.Failures[0].Problem != tt.want {
+				t.Fatalf("failures = %+v, want one %s", report.Failures, tt.want)
+			}
+			if report.Failures[0].Detail == "" {
+				t.Error("failure has no detail")
+			}
+		})
+	}
+}
+
+func TestVerify_NoConverge(t *testing.T) {
+	kr := katas.NewKatasRegistry()
+	// Every `c...` command is flagged and grows by one `c`, forever.
+	kr.RegisterKata(ast.SimpleCommandNode, commandKata("ZC9001", "c", 1, func(old string) string { return old + "c" }))
+	vr := Verifier{Registry: kr, MaxPasses: 3}
+	if vr.converges("t.zsh", "c x\n", "cc x\n", "ZC9001") {
+		t.Error("a fix that keeps rewriting its output converged")
+	}
+	// A finding on a line the fix did not touch is not this fix's problem.
+	if !vr.converges("t.zsh", "a x\nc x\n", "b x\nc x\n", "ZC9001") {
+		t.Error("an untouched finding elsewhere blocked convergence")
+	}
+}
+
+func TestVerify_UnparsableInput(t *testing.T) {
+	if _, err := (Verifier{Registry: katas.NewKatasRegistry()}).Verify("t.zsh", []byte("if true; then\n")); err == nil {
+		t.Error("expected an error for source that does not parse")
+	}
+}
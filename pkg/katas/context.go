@@ -0,0 +1,133 @@
+// SPDX-License-Identifier: MIT
+// Copyright the ZShellCheck contributors.
+package katas
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/afadesigns/zshellcheck/pkg/ast"
+	"github.com/afadesigns/zshellcheck/pkg/katas/fixbuild"
+)
+
+// Context is what a context-aware check (Kata.CheckContext) or fix
+// (Kata.FixContext) sees beyond the node under inspection: the file being
+// linted, its raw source, the kata's resolved options, the chain of
+// ancestors of the node, and file-wide analysis results. The registry
+// reuses one Context per file, updating Options and the ancestor chain
+// before each call, so a check must not retain it.
+type Context struct {
+	// File is the path of the file being linted, as given to the CLI.
+	// Empty when the source did not come from a file.
+	File string
+	// Source is the raw file content the AST was parsed from.
+	Source []byte
+	// Program is the root of the parsed file.
+	Program *ast.Program
+	// Options holds the running kata's option values.
+	Options OptionValues
+
+	parents  []ast.Node
+	analysis *Analysis
+	lines    *fixbuild.LineIndex
+}
+
+// NewContext returns a Context for one parsed file.
+func NewContext(file string, source []byte, program *ast.Program) *Context {
+	return &Context{File: file, Source: source, Program: program}
+}
+
+// Parent returns the direct parent of the node under inspection, or nil
+// at the root.
+func (c *Context) Parent() ast.Node {
+	if len(c.parents) == 0 {
+		return nil
+	}
+	return c.parents[len(c.parents)-1]
+}
+
+// Ancestors returns the ancestors of the node under inspection, innermost
+// first. The slice is a copy the caller may keep.
+func (c *Context) Ancestors() []ast.Node {
+	out := make([]ast.Node, len(c.parents))
+	for i, p := range c.parents {
+		out[len(c.parents)-1-i] = p
+	}
+	return out
+}
+
+// EnclosingFunction returns the innermost function definition containing
+// the node under inspection — an *ast.FunctionDefinition or
+// *ast.FunctionLiteral — or nil at file scope.
+func (c *Context) EnclosingFunction() ast.Node {
+	for i := len(c.parents) - 1; i >= 0; i-- {
+		switch c.parents[i].(type) {
+		case *ast.FunctionDefinition, *ast.FunctionLiteral:
+			return c.parents[i]
+		}
+	}
+	return nil
+}
+
+// Shebang returns the file's `#!` line without the leading `#!`, or ""
+// when the file has none.
+func (c *Context) Shebang() string {
+	return c.Analysis().Shebang
+}
+
+// Analysis returns the file-wide facts for the context's file, computing
+// them on first use.
+func (c *Context) Analysis() *Analysis {
+	if c.analysis == nil {
+		c.analysis = analyze(c.Program, c.Source)
+	}
+	return c.analysis
+}
+
+// NewBuilder returns a fixbuild.Builder over the context's source. Every
+// builder for the file shares one line index, built on first use.
+func (c *Context) NewBuilder() *fixbuild.Builder {
+	if c.lines == nil {
+		c.lines = fixbuild.NewLineIndex(c.Source)
+	}
+	return fixbuild.NewBuilder(c.Source, c.lines)
+}
+
+// Analysis holds facts about a whole file that individual checks would
+// otherwise recompute with their own walks.
+type Analysis struct {
+	// Shebang is the interpreter line without its leading `#!`.
+	Shebang string
+	// Functions maps each function name to its definitions in source
+	// order — an *ast.FunctionDefinition or *ast.FunctionLiteral each.
+	Functions map[string][]ast.Node
+}
+
+func analyze(program *ast.Program, source []byte) *Analysis {
+	a := &Analysis{Functions: map[string][]ast.Node{}}
+	if bytes.HasPrefix(source, []byte("#!")) {
+		line, _, _ := bytes.Cut(source[2:], []byte("\n"))
+		a.Shebang = string(bytes.TrimSpace(line))
+	}
+	if program == nil {
+		return a
+	}
+	ast.Walk(program, func(n ast.Node) bool {
+		switch fn := n.(type) {
+		case *ast.FunctionDefinition:
+			if fn.Name != nil {
+				a.Functions[fn.Name.Value] = append(a.Functions[fn.Name.Value], fn)
+			}
+		case *ast.FunctionLiteral:
+			if fn.Name != nil {
+				a.Functions[fn.Name.Value] = append(a.Functions[fn.Name.Value], fn)
+			}
+		case *ast.Shebang:
+			if a.Shebang == "" {
+				a.Shebang = strings.TrimSpace(strings.TrimPrefix(fn.Path, "#!"))
+			}
+		}
+		return true
+	})
+	return a
+}
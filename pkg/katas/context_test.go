@@ -0,0 +1,173 @@
+// SPDX-License-Identifier: MIT
+// Copyright the ZShellCheck contributors.
+package katas
+
+import (
+	"testing"
+
+	"github.com/afadesigns/zshellcheck/pkg/ast"
+	"github.com/afadesigns/zshellcheck/pkg/lexer"
+	"github.com/afadesigns/zshellcheck/pkg/parser"
+)
+
+func TestCheckProgramContext(t *testing.T) {
+	src := "#!/usr/bin/env zsh\nf() {\n  ls -l\n}\nls -l\nf() { : x; }\n"
+	prog := parser.New(lexer.New(src)).ParseProgram()
+
+	type seen struct {
+		file, shebang string
+		parent        ast.Node
+		fn            ast.Node
+		depth         int
+	}
+	var got []seen
+	kr := NewKatasRegistry()
+	kr.RegisterKata(ast.SimpleCommandNode, Kata{
+		ID: "ZC_CTX",
+		CheckContext: func(ctx *Context, node ast.Node) []Violation {
+			if ctx.Parent() == nil || len(ctx.Source) != len(src) {
+				t.Errorf("context missing parent or source for %s", node)
+			}
+			if cmd := node.(*ast.SimpleCommand); cmd.Name.String() == "ls" {
+				got = append(got, seen{ctx.File, ctx.Shebang(), ctx.Parent(), ctx.EnclosingFunction(), len(ctx.Ancestors())})
+			}
+			return nil
+		},
+	})
+	kr.CheckProgram("dot.zsh", []byte(src), prog, nil, false)
+
+	if len(got) != 2 {
+		t.Fatalf("saw %d `ls` commands, want 2", len(got))
+	}
+	inner, outer := got[0], got[1]
+	if inner.file != "dot.zsh" || inner.shebang != "/usr/bin/env zsh" {
+		t.Errorf("file/shebang = %q/%q", inner.file, inner.shebang)
+	}
+	if _, ok := inner.fn.(*ast.FunctionDefinition); !ok {
+		t.Errorf("inner ls enclosing function = %T, want *ast.FunctionDefinition", inner.fn)
+	}
+	if outer.fn != nil {
+		t.Errorf("top-level ls enclosing function = %T, want nil", outer.fn)
+	}
+	if inner.depth <= outer.depth {
+		t.Errorf("inner depth %d should exceed top-level depth %d", inner.depth, outer.depth)
+	}
+
+	ctx := NewContext("dot.zsh", []byte(src), prog)
+	if defs := ctx.Analysis().Functions["f"]; len(defs) != 2 {
+		t.Errorf("Analysis.Functions[f] = %d definitions, want 2", len(defs))
+	}
+}
+
+func TestContextAncestorsInnermostFirst(t *testing.T) {
+	a, b := &ast.Program{}, &ast.BlockStatement{}
+	ctx := &Context{parents: []ast.Node{a, b}}
+	anc := ctx.Ancestors()
+	if len(anc) != 2 || anc[0] != b || anc[1] != a {
+		t.Errorf("Ancestors = %v, want innermost first", anc)
+	}
+	if ctx.Parent() != b {
+		t.Errorf("Parent = %v, want the innermost ancestor", ctx.Parent())
+	}
+	if (&Context{}).Parent() != nil {
+		t.Error("Parent of a root context should be nil")
+	}
+}
+
+func TestCheckContextAdapter(t *testing.T) {
+	kr := NewKatasRegistry()
+	kr.RegisterKata(ast.IdentifierNode, Kata{
+		ID:      "ZC_CTX",
+		Options: []Option{{Name: "word", Type: OptionString, Default: "hi"}},
+		CheckContext: func(ctx *Context, node ast.Node) []Violation {
+			return []Violation{{KataID: "ZC_CTX", Message: ctx.Options.String("word")}}
+		},
+	})
+	kata, _ := kr.GetKata("ZC_CTX")
+	if kata.Check == nil {
+		t.Fatal("RegisterKata did not derive Check from CheckContext")
+	}
+	if vs := kata.Check(&ast.Identifier{}); len(vs) != 1 || vs[0].Message != "hi" {
+		t.Errorf("adapted Check = %+v", vs)
+	}
+	if vs, _ := kr.CheckAndFix(&ast.Identifier{}, nil, nil); len(vs) != 1 || vs[0].Level != SeverityWarning {
+		t.Errorf("CheckAndFix through CheckContext = %+v", vs)
+	}
+}
+
+func TestRegisterFileKataRunsOncePerFile(t *testing.T) {
+	src := "f() { ls -l; }\nls -l\n"
+	prog := parser.New(lexer.New(src)).ParseProgram()
+
+	calls := 0
+	kr := NewKatasRegistry()
+	kr.RegisterFileKata(Kata{
+		ID: "ZC_FILE",
+		CheckFile: func(ctx *Context, program *ast.Program) []Violation {
+			calls++
+			if ctx.File != "dot.zsh" || program != prog {
+				t.Errorf("CheckFile got file %q, program %p", ctx.File, program)
+			}
+			return []Violation{{KataID: "ZC_FILE", Line: 2, Column: 1}}
+		},
+	})
+	vs, _ := kr.CheckProgram("dot.zsh", []byte(src), prog, nil, false)
+	if calls != 1 || len(vs) != 1 || vs[0].Level != SeverityWarning {
+		t.Errorf("calls = %d, violations = %+v; want one call and one warning", calls, vs)
+	}
+	if !kr.IsFileKata("ZC_FILE") || kr.IsFileKata("ZC_NOPE") {
+		t.Error("IsFileKata misreports program-scope katas")
+	}
+	if vs, _ := kr.CheckProgram("", nil, prog, []string{"ZC_FILE"}, false); len(vs) != 0 {
+		t.Errorf("disabled file kata still reported %+v", vs)
+	}
+}
+
+func TestCheckProgramStampsViolationEnds(t *testing.T) {
+	src := "rm -rf $dir\n"
+	prog := parser.New(lexer.New(src)).ParseProgram()
+	kr := NewKatasRegistry()
+	kr.RegisterKata(ast.SimpleCommandNode, Kata{
+		ID: "ZC_END",
+		Check: func(node ast.Node) []Violation {
+			cmd := node.(*ast.SimpleCommand)
+			arg := cmd.Arguments[len(cmd.Arguments)-1].TokenLiteralNode()
+			return []Violation{
+				{KataID: "ZC_END", Line: cmd.Token.Line, Column: cmd.Token.Column},
+				{KataID: "ZC_END", Line: arg.Line, Column: arg.Column},
+				{KataID: "ZC_END", Line: 1, Column: 2},
+				{KataID: "ZC_END", Line: 1, Column: 1, EndLine: 1, EndColumn: 2},
+			}
+		},
+	})
+	vs, _ := kr.CheckProgram("", []byte(src), prog, nil, false)
+	want := [][2]int{{1, 12}, {1, 12}, {0, 0}, {1, 2}}
+	if len(vs) != len(want) {
+		t.Fatalf("got %d violations, want %d", len(vs), len(want))
+	}
+	for i, w := range want {
+		if vs[i].EndLine != w[0] || vs[i].EndColumn != w[1] {
+			t.Errorf("violation %d at %d:%d ends %d:%d, want %d:%d",
+				i, vs[i].Line, vs[i].Column, vs[i].EndLine, vs[i].EndColumn, w[0], w[1])
+		}
+	}
+}
+
+func TestFileKatasRelatedLocations(t *testing.T) {
+	cases := []struct {
+		id, src string
+		want    Location
+	}{
+		{"ZC2004", "f() { :; }\nf() { :; }\n", Location{Line: 1, Column: 1, Message: "`f` first defined here"}},
+		{"ZC2005", "compinit -i\nfpath+=(x)\n", Location{Line: 1, Column: 1, Message: "`compinit` runs here"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.id, func(t *testing.T) {
+			kata, _ := Registry.GetKata(tc.id)
+			vs := kata.Check(parser.New(lexer.New(tc.src)).ParseProgram())
+			if len(vs) != 1 || len(vs[0].Related) != 1 || vs[0].Related[0] != tc.want {
+				t.Errorf("violations = %+v, want one related to %+v", vs, tc.want)
+			}
+		})
+	}
+}
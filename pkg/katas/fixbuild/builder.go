@@ -0,0 +1,296 @@
+// SPDX-License-Identifier: MIT
+// Copyright the ZShellCheck contributors.
+//
+// Package fixbuild builds the edits a kata's Fix function returns. A
+// Builder resolves AST nodes to byte spans of the source through a line
+// index built once per file, so a fix names what it rewrites — a command
+// name, an argument, a whole node — and the offset arithmetic lives here
+// instead of in every kata. Package katas converts the result to its
+// FixEdit; applying edits to a file is the job of the engine in pkg/fix.
+package fixbuild
+
+import (
+	"bytes"
+
+	"github.com/afadesigns/zshellcheck/pkg/ast"
+	"github.com/afadesigns/zshellcheck/pkg/token"
+)
+
+// Edit is one replacement: Length bytes of source starting at the
+// 1-based Line:Column become Replace.
+type Edit struct {
+	Line    int
+	Column  int
+	Length  int
+	Replace string
+}
+
+// Builder collects the edits of one fix. An operation whose node or
+// range cannot be located in the source fails the whole builder and
+// Edits then returns nil, so a fix never applies half of its rewrite.
+type Builder struct {
+	src    []byte
+	index  *LineIndex
+	edits  []Edit
+	failed bool
+}
+
+// NewBuilder returns a Builder over src, the source the nodes it is
+// handed were parsed from, and index, src's NewLineIndex. The index is
+// built once per file and shared by every Builder over it.
+func NewBuilder(src []byte, index *LineIndex) *Builder {
+	return &Builder{src: src, index: index}
+}
+
+// Source returns the source the builder edits.
+func (b *Builder) Source() []byte {
+	return b.src
+}
+
+// Offset returns the byte offset of the 1-based line:col, or -1 when it
+// lies outside the source.
+func (b *Builder) Offset(line, col int) int {
+	return b.index.Offset(line, col)
+}
+
+// TokenOffset returns the byte offset where tok starts, or -1 when its
+// literal is not there. The lexer stamps two-byte operators such as
+// `[[`, `((` and `<(` at their last byte; TokenOffset steps back to the
+// first.
+func (b *Builder) TokenOffset(tok token.Token) int {
+	off := b.index.Offset(tok.Line, tok.Column)
+	if off < 0 || tok.Literal == "" {
+		return off
+	}
+	lit := []byte(tok.Literal)
+	if bytes.HasPrefix(b.src[off:], lit) {
+		return off
+	}
+	if back := off - len(lit) + 1; back >= 0 && bytes.HasPrefix(b.src[back:], lit) {
+		return back
+	}
+	return -1
+}
+
+// Span returns the byte range [start, end) of the source that node
+// covers. It runs from the node's earliest token to the end of its last
+// one, then on past the closers of any `(`, `{` or backquote left open
+// there: the parser keeps no token for the `)` of `$(cmd)`, the `}` of
+// `${x}` or a closing backquote. A `[[ … ]]` test also takes in its
+// `]]`. Constructs closed by a keyword (`fi`, `done`, `esac`) end at
+// their last inner token.
+func (b *Builder) Span(node ast.Node) (start, end int, ok bool) {
+	var first token.Token
+	tokens := 0
+	ast.Walk(node, func(n ast.Node) bool {
+		tok := n.TokenLiteralNode()
+		tokens++
+		if tok.Line > 0 && (first.Line == 0 || tok.Line < first.Line || tok.Line == first.Line && tok.Column < first.Column) {
+			first = tok
+		}
+		return true
+	})
+	if first.Line == 0 {
+		return 0, 0, false
+	}
+	start = b.TokenOffset(first)
+	end = b.index.Offset(ast.End(node))
+	if start < 0 || end < start {
+		return 0, 0, false
+	}
+	if tokens > 1 {
+		end = closeGroups(b.src, start, end)
+	}
+	if _, ok := node.(*ast.DoubleBracketExpression); ok {
+		end = closeDoubleBracket(b.src, end)
+	}
+	return start, end, true
+}
+
+// Text returns the source text of node, or "" when it cannot be
+// located.
+func (b *Builder) Text(node ast.Node) string {
+	start, end, ok := b.Span(node)
+	if !ok {
+		return ""
+	}
+	return string(b.src[start:end])
+}
+
+// Replace replaces the source bytes [start, end) with text.
+func (b *Builder) Replace(start, end int, text string) {
+	if b.failed {
+		return
+	}
+	if start < 0 || end < start || end > len(b.src) {
+		b.failed = true
+		return
+	}
+	line, col := b.index.Position(start)
+	b.edits = append(b.edits, Edit{Line: line, Column: col, Length: end - start, Replace: text})
+}
+
+// Insert inserts text at byte offset off.
+func (b *Builder) Insert(off int, text string) {
+	b.Replace(off, off, text)
+}
+
+// ReplaceNode replaces the source of node with text.
+func (b *Builder) ReplaceNode(node ast.Node, text string) {
+	start, end, ok := b.Span(node)
+	if !ok {
+		b.Fail()
+		return
+	}
+	b.Replace(start, end, text)
+}
+
+// ReplaceCommandName replaces the name of cmd with name, leaving its
+// arguments as written.
+func (b *Builder) ReplaceCommandName(cmd *ast.SimpleCommand, name string) {
+	if cmd == nil {
+		b.Fail()
+		return
+	}
+	b.ReplaceNode(cmd.Name, name)
+}
+
+// InsertArgBefore inserts text as a new argument of cmd ahead of
+// argument i: one space and text go right after the word before it, the
+// command name when i is 0. An i equal to the number of arguments
+// appends text after the last one.
+func (b *Builder) InsertArgBefore(cmd *ast.SimpleCommand, i int, text string) {
+	_, at, ok := b.wordBefore(cmd, i)
+	if !ok {
+		b.Fail()
+		return
+	}
+	b.Insert(at, " "+text)
+}
+
+// RemoveArg deletes argument i of cmd together with the blanks between
+// it and the word before it.
+func (b *Builder) RemoveArg(cmd *ast.SimpleCommand, i int) {
+	_, from, ok := b.wordBefore(cmd, i)
+	if !ok || i >= len(cmd.Arguments) {
+		b.Fail()
+		return
+	}
+	_, to, ok := b.Span(cmd.Arguments[i])
+	if !ok || to < from {
+		b.Fail()
+		return
+	}
+	b.Replace(from, to, "")
+}
+
+// WrapNode puts before and after around node. It emits one replacement
+// of the whole node rather than two insertions, so the fix engine sees
+// a conflict with any other edit inside the node instead of applying
+// both around it.
+func (b *Builder) WrapNode(node ast.Node, before, after string) {
+	start, end, ok := b.Span(node)
+	if !ok {
+		b.Fail()
+		return
+	}
+	b.Replace(start, end, before+string(b.src[start:end])+after)
+}
+
+// Fail marks the fix as not applicable, so Edits returns nil. A fix
+// calls it when the source fails a check of its own.
+func (b *Builder) Fail() {
+	b.failed = true
+}
+
+// Edits returns the edits in the order they were added, or nil when any
+// operation failed.
+func (b *Builder) Edits() []Edit {
+	if b.failed {
+		return nil
+	}
+	return b.edits
+}
+
+// wordBefore returns the span of the word ahead of argument i of cmd:
+// the command name for i == 0, argument i-1 otherwise.
+func (b *Builder) wordBefore(cmd *ast.SimpleCommand, i int) (start, end int, ok bool) {
+	if cmd == nil || i < 0 || i > len(cmd.Arguments) {
+		return 0, 0, false
+	}
+	if i == 0 {
+		return b.Span(cmd.Name)
+	}
+	return b.Span(cmd.Arguments[i-1])
+}
+
+// closeGroups returns end moved past the closers of the parentheses,
+// braces and backquotes opened in src[start:end] and still open at end.
+// Quoted text and comments are skipped. When the groups never close, end
+// is returned unchanged.
+func closeGroups(src []byte, start, end int) int {
+	depth, tick := 0, false
+	for i := start; i < len(src); i++ {
+		if i >= end && depth == 0 && !tick {
+			return i
+		}
+		switch src[i] {
+		case '\\':
+			i++
+		case '\'':
+			if j := bytes.IndexByte(src[i+1:], '\''); j >= 0 {
+				i += j + 1
+			}
+		case '"':
+			i = closingQuote(src, i)
+		case '#':
+			if i == start || isBlank(src[i-1]) {
+				j := bytes.IndexByte(src[i:], '\n')
+				if j < 0 {
+					return end
+				}
+				i += j - 1
+			}
+		case '`':
+			tick = !tick
+		case '(', '{':
+			depth++
+		case ')', '}':
+			if depth > 0 {
+				depth--
+			}
+		}
+	}
+	return end
+}
+
+// closingQuote returns the offset of the `"` that closes the string
+// opened at src[open], or the last offset when it is unterminated.
+func closingQuote(src []byte, open int) int {
+	for i := open + 1; i < len(src); i++ {
+		switch src[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return len(src) - 1
+}
+
+// closeDoubleBracket returns end moved past the `]]` that follows it
+// after blanks, or end unchanged when none does.
+func closeDoubleBracket(src []byte, end int) int {
+	i := end
+	for i < len(src) && isBlank(src[i]) {
+		i++
+	}
+	if bytes.HasPrefix(src[i:], []byte("]]")) {
+		return i + 2
+	}
+	return end
+}
+
+func isBlank(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n'
+}
@@ -0,0 +1,218 @@
+// SPDX-License-Identifier: MIT
+// Copyright the ZShellCheck contributors.
+package fixbuild
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/afadesigns/zshellcheck/pkg/ast"
+	"github.com/afadesigns/zshellcheck/pkg/lexer"
+	"github.com/afadesigns/zshellcheck/pkg/parser"
+)
+
+// parseCommand parses src and returns its first simple command.
+func parseCommand(t *testing.T, src string) *ast.SimpleCommand {
+	t.Helper()
+	p := parser.New(lexer.New(src))
+	program := p.ParseProgram()
+	if errs := p.Errors(); len(errs) > 0 {
+		t.Fatalf("parse %q: %v", src, errs)
+	}
+	var cmd *ast.SimpleCommand
+	ast.Walk(program, func(n ast.Node) bool {
+		if c, ok := n.(*ast.SimpleCommand); ok && cmd == nil {
+			cmd = c
+		}
+		return cmd == nil
+	})
+	if cmd == nil {
+		t.Fatalf("no simple command in %q", src)
+	}
+	return cmd
+}
+
+// apply splices edits into src, last first.
+func apply(src string, edits []Edit) string {
+	ix := NewLineIndex([]byte(src))
+	sorted := append([]Edit(nil), edits...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return ix.Offset(sorted[i].Line, sorted[i].Column) > ix.Offset(sorted[j].Line, sorted[j].Column)
+	})
+	for _, e := range sorted {
+		off := ix.Offset(e.Line, e.Column)
+		src = src[:off] + e.Replace + src[off+e.Length:]
+	}
+	return src
+}
+
+func TestLineIndex(t *testing.T) {
+	src := []byte("abc\ndef\n\ngh")
+	ix := NewLineIndex(src)
+	// Walk every offset, counting lines and columns by hand.
+	line, col := 1, 1
+	for off := 0; off <= len(src); off++ {
+		if got := ix.Offset(line, col); got != off {
+			t.Errorf("Offset(%d, %d) = %d, want %d", line, col, got, off)
+		}
+		if l, c := ix.Position(off); l != line || c != col {
+			t.Errorf("Position(%d) = %d:%d, want %d:%d", off, l, c, line, col)
+		}
+		if off < len(src) && src[off] == '\n' {
+			line, col = line+1, 1
+		} else {
+			col++
+		}
+	}
+	for _, pos := range [][2]int{{0, 1}, {1, 0}, {1, 5}, {3, 2}, {4, 4}, {5, 1}} {
+		if got := ix.Offset(pos[0], pos[1]); got != -1 {
+			t.Errorf("Offset(%d, %d) = %d, want -1", pos[0], pos[1], got)
+		}
+	}
+	if l, c := ix.Position(len(src) + 1); l != -1 || c != -1 {
+		t.Errorf("Position past the end = %d:%d, want -1:-1", l, c)
+	}
+	if got := NewLineIndex(nil).Offset(1, 1); got != 0 {
+		t.Errorf("empty source Offset(1, 1) = %d, want 0", got)
+	}
+}
+
+// newBuilder returns a Builder over src with its own line index.
+func newBuilder(src string) *Builder {
+	b := []byte(src)
+	return NewBuilder(b, NewLineIndex(b))
+}
+
+func TestSpan(t *testing.T) {
+	tests := []struct {
+		src  string
+		arg  int
+		want string
+	}{
+		{"echo $(ls -l) x\n", 0, "$(ls -l)"},
+		{"echo $( ls  )  ;\n", 0, "$( ls  )"},
+		{"echo ${x[1]} y\n", 0, "${x[1]}"},
+		{"echo ${#arr[@]}\n", 0, "${#arr[@]}"},
+		{"echo \"$a\"b c\n", 0, "\"$a\"b"},
+		{"echo $((1+2)) y\n", 0, "$((1+2))"},
+		{"diff <(ls a) b\n", 0, "<(ls a)"},
+		{"echo $(echo \")\") z\n", 0, "$(echo \")\")"},
+		{"echo $(a # it's (\nb) z\n", 0, "$(a # it's (\nb)"},
+	}
+	for _, tt := range tests {
+		cmd := parseCommand(t, tt.src)
+		if len(cmd.Arguments) <= tt.arg {
+			t.Errorf("%q: %s has %d arguments", tt.src, cmd.Name, len(cmd.Arguments))
+			continue
+		}
+		b := newBuilder(tt.src)
+		if got := b.Text(cmd.Arguments[tt.arg]); got != tt.want {
+			t.Errorf("%q: argument %d spans %q, want %q", tt.src, tt.arg, got, tt.want)
+		}
+	}
+}
+
+func TestSpan_Statements(t *testing.T) {
+	tests := []struct{ src, want string }{
+		{"[[ -f $x ]] && echo y\n", "[[ -f $x ]]"},
+		{"(( x = 1 ))\n", "(( x = 1 ))"},
+		{"( cd x; ls )\n", "( cd x; ls )"},
+		{"x=`date -u` y\n", "`date -u`"},
+	}
+	for _, tt := range tests {
+		p := parser.New(lexer.New(tt.src))
+		program := p.ParseProgram()
+		var node ast.Node
+		ast.Walk(program, func(n ast.Node) bool {
+			switch n.(type) {
+			case *ast.DoubleBracketExpression, *ast.ArithmeticCommand, *ast.Subshell, *ast.CommandSubstitution:
+				node = n
+			}
+			return node == nil
+		})
+		if got := newBuilder(tt.src).Text(node); got != tt.want {
+			t.Errorf("%q: span %q, want %q", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestBuilderOperations(t *testing.T) {
+	tests := []struct {
+		name, src, want string
+		build           func(b *Builder, cmd *ast.SimpleCommand)
+	}{
+		{"replace name", "which -a git\n", "whence -a git\n", func(b *Builder, cmd *ast.SimpleCommand) {
+			b.ReplaceCommandName(cmd, "whence")
+		}},
+		{"insert first", "read  line\n", "read -r  line\n", func(b *Builder, cmd *ast.SimpleCommand) {
+			b.InsertArgBefore(cmd, 0, "-r")
+		}},
+		{"insert into bare command", "systemctl\nx\n", "systemctl --no-pager\nx\n", func(b *Builder, cmd *ast.SimpleCommand) {
+			b.InsertArgBefore(cmd, 0, "--no-pager")
+		}},
+		{"append", "mkdir a $(b)\n", "mkdir a $(b) -p\n", func(b *Builder, cmd *ast.SimpleCommand) {
+			b.InsertArgBefore(cmd, 2, "-p")
+		}},
+		{"remove middle", "rm -f -- x\n", "rm -- x\n", func(b *Builder, cmd *ast.SimpleCommand) {
+			b.RemoveArg(cmd, 0)
+		}},
+		{"remove last", "ls -l $(pwd)\n", "ls -l\n", func(b *Builder, cmd *ast.SimpleCommand) {
+			b.RemoveArg(cmd, 1)
+		}},
+		{"wrap", "echo $x y\n", "echo \"$x\" y\n", func(b *Builder, cmd *ast.SimpleCommand) {
+			b.WrapNode(cmd.Arguments[0], `"`, `"`)
+		}},
+		{"replace node", "echo ${x[1]} y\n", "echo ${x[2]} y\n", func(b *Builder, cmd *ast.SimpleCommand) {
+			b.ReplaceNode(cmd.Arguments[0], strings.Replace(b.Text(cmd.Arguments[0]), "1", "2", 1))
+		}},
+		{"several", "which b c\necho a\n", "whence c\necho a\n", func(b *Builder, cmd *ast.SimpleCommand) {
+			b.ReplaceCommandName(cmd, "whence")
+			b.RemoveArg(cmd, 0)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newBuilder(tt.src)
+			tt.build(b, parseCommand(t, tt.src))
+			edits := b.Edits()
+			if edits == nil {
+				t.Fatal("builder failed")
+			}
+			if got := apply(tt.src, edits); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuilderFailsAsAWhole(t *testing.T) {
+	src := "read line\n"
+	cmd := parseCommand(t, src)
+	for name, build := range map[string]func(b *Builder){
+		"argument out of range": func(b *Builder) { b.RemoveArg(cmd, 1) },
+		"negative index":        func(b *Builder) { b.InsertArgBefore(cmd, -1, "-r") },
+		"nil command":           func(b *Builder) { b.ReplaceCommandName(nil, "x") },
+		"nil node":              func(b *Builder) { b.ReplaceNode(nil, "x") },
+		"typed-nil node":        func(b *Builder) { b.WrapNode((*ast.Identifier)(nil), "(", ")") },
+		"range past the end":    func(b *Builder) { b.Replace(0, len(src)+1, "") },
+		"explicit":              func(b *Builder) { b.Fail() },
+	} {
+		b := newBuilder(src)
+		b.InsertArgBefore(cmd, 0, "-r")
+		build(b)
+		if edits := b.Edits(); edits != nil {
+			t.Errorf("%s: Edits() = %+v, want nil", name, edits)
+		}
+	}
+}
+
+func TestBuilderRejectsForeignSource(t *testing.T) {
+	cmd := parseCommand(t, "which git\n")
+	// The node's position holds different text in this source.
+	b := newBuilder("print git\n")
+	b.ReplaceCommandName(cmd, "whence")
+	if edits := b.Edits(); edits != nil {
+		t.Errorf("Edits() = %+v, want nil", edits)
+	}
+}
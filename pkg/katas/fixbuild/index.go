@@ -0,0 +1,55 @@
+// SPDX-License-Identifier: MIT
+// Copyright the ZShellCheck contributors.
+package fixbuild
+
+import "sort"
+
+// LineIndex maps between byte offsets in a source and the 1-based
+// line:column positions the lexer stamps on tokens. Columns count bytes,
+// as token columns do. Both directions are a lookup or a binary search
+// rather than a scan from the start of the file.
+type LineIndex struct {
+	// starts holds the byte offset of the first byte of every line.
+	starts []int
+	size   int
+}
+
+// NewLineIndex indexes the lines of src.
+func NewLineIndex(src []byte) *LineIndex {
+	ix := &LineIndex{starts: []int{0}, size: len(src)}
+	for i, c := range src {
+		if c == '\n' {
+			ix.starts = append(ix.starts, i+1)
+		}
+	}
+	return ix
+}
+
+// Offset returns the byte offset of line:col, or -1 when the position
+// lies outside the source. The position just past a line's last byte —
+// its newline, or the end of an unterminated last line — is valid.
+func (ix *LineIndex) Offset(line, col int) int {
+	if line < 1 || col < 1 || line > len(ix.starts) {
+		return -1
+	}
+	end := ix.size
+	if line < len(ix.starts) {
+		end = ix.starts[line] - 1
+	}
+	off := ix.starts[line-1] + col - 1
+	if off > end {
+		return -1
+	}
+	return off
+}
+
+// Position returns the 1-based line and column of byte offset off, or
+// -1, -1 when off lies outside the source. The end of the source is a
+// valid offset.
+func (ix *LineIndex) Position(off int) (line, col int) {
+	if off < 0 || off > ix.size {
+		return -1, -1
+	}
+	i := sort.Search(len(ix.starts), func(i int) bool { return ix.starts[i] > off }) - 1
+	return i + 1, off - ix.starts[i] + 1
+}
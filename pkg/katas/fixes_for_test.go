@@ -75,7 +75,7 @@ func TestIsFixable(t *testing.T) {
 }
 
 func TestIsSafeFix(t *testing.T) {
-	// ZC1001 is in the value-preserving safe set; ZC1037 (echo->print)
+	// ZC1001 declares a value-preserving safe fix; ZC1037 (echo->print)
 	// changes behavior and is unsafe.
 	if !Registry.IsSafeFix("ZC1001") {
 		t.Error("ZC1001 should be a safe fix")
@@ -87,3 +87,145 @@ func TestIsSafeFix(t *testing.T) {
 		t.Error("unknown kata should not be a safe fix")
 	}
 }
+
+func fixSafetyTestRegistry() *KatasRegistry {
+	kr := NewKatasRegistry()
+	noop := func(ast.Node, Violation, []byte) []FixEdit { return nil }
+	kr.RegisterKata(ast.IdentifierNode, Kata{ID: "ZC_DEFAULT", Fix: noop})
+	kr.RegisterKata(ast.IdentifierNode, Kata{ID: "ZC_SAFE", Fix: noop, FixSafety: FixSafe})
+	kr.RegisterKata(ast.IdentifierNode, Kata{ID: "ZC_SUGGEST", Fix: noop, FixSafety: FixSuggestion})
+	kr.RegisterKata(ast.IdentifierNode, Kata{ID: "ZC_PLAIN"})
+	return kr
+}
+
+func TestFixSafetyLevels(t *testing.T) {
+	kr := fixSafetyTestRegistry()
+	cases := []struct {
+		id      string
+		level   FixSafety
+		safe    bool
+		fixable bool
+	}{
+		{"ZC_DEFAULT", FixUnsafe, false, true},
+		{"ZC_SAFE", FixSafe, true, true},
+		{"ZC_SUGGEST", FixSuggestion, false, false},
+		{"ZC_PLAIN", "", false, false},
+		{"ZC_UNKNOWN", "", false, false},
+	}
+	for _, tc := range cases {
+		if got := kr.FixSafetyOf(tc.id); got != tc.level {
+			t.Errorf("FixSafetyOf(%s) = %q, want %q", tc.id, got, tc.level)
+		}
+		if kr.IsSafeFix(tc.id) != tc.safe || kr.IsFixable(tc.id) != tc.fixable {
+			t.Errorf("%s: IsSafeFix = %v, IsFixable = %v; want %v, %v",
+				tc.id, kr.IsSafeFix(tc.id), kr.IsFixable(tc.id), tc.safe, tc.fixable)
+		}
+	}
+}
+
+func TestEditSafetyPrecedence(t *testing.T) {
+	kr := fixSafetyTestRegistry()
+	if got := kr.EditSafety(FixEdit{KataID: "ZC_SAFE"}); got != FixSafe {
+		t.Errorf("edit inherits %q, want the kata's safe", got)
+	}
+	if got := kr.EditSafety(FixEdit{KataID: "ZC_SAFE", Safety: FixUnsafe}); got != FixUnsafe {
+		t.Errorf("per-edit override = %q, want unsafe", got)
+	}
+	if got := kr.EditSafety(FixEdit{KataID: "ZC_UNKNOWN"}); got != FixUnsafe {
+		t.Errorf("edit of an unknown kata = %q, want unsafe", got)
+	}
+	if err := kr.SetFixSafety("ZC_SAFE", FixSuggestion); err != nil {
+		t.Fatal(err)
+	}
+	if got := kr.EditSafety(FixEdit{KataID: "ZC_SAFE", Safety: FixSafe}); got != FixSuggestion {
+		t.Errorf("config override = %q, want it to beat the per-edit level", got)
+	}
+	if kr.IsSafeFix("ZC_SAFE") || kr.IsFixable("ZC_SAFE") {
+		t.Error("demoted kata still reported as applicable")
+	}
+	if err := kr.SetFixSafety("ZC_DEFAULT", FixSafe); err != nil || !kr.IsSafeFix("ZC_DEFAULT") {
+		t.Errorf("promotion failed: err=%v", err)
+	}
+}
+
+func TestSetFixSafetyErrors(t *testing.T) {
+	kr := fixSafetyTestRegistry()
+	if err := kr.SetFixSafety("ZC_UNKNOWN", FixSafe); err == nil {
+		t.Error("want error for unknown kata")
+	}
+	if err := kr.SetFixSafety("ZC_PLAIN", FixSafe); err == nil {
+		t.Error("want error for a kata without a fix")
+	}
+}
+
+func TestParseFixSafety(t *testing.T) {
+	for in, want := range map[string]FixSafety{
+		"safe": FixSafe, " Unsafe ": FixUnsafe, "suggestion": FixSuggestion, "suggestion-only": FixSuggestion,
+	} {
+		if got, err := ParseFixSafety(in); err != nil || got != want {
+			t.Errorf("ParseFixSafety(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseFixSafety("maybe"); err == nil {
+		t.Error("want error for an unknown level")
+	}
+}
+
+// TestFixSafetyConsistentAcrossRegistrations guards katas registered on
+// several node types: every registration must declare the same safety,
+// or the level would depend on which one registered last.
+func TestFixSafetyConsistentAcrossRegistrations(t *testing.T) {
+	for _, list := range Registry.KatasByNodeType() {
+		for _, k := range list {
+			if got := Registry.KatasByID[k.ID].FixSafety; got != k.FixSafety {
+				t.Errorf("%s registered with fix safety %q and %q", k.ID, k.FixSafety, got)
+			}
+		}
+	}
+}
+
+func TestViolationCarriesGradedFix(t *testing.T) {
+	kr := NewKatasRegistry()
+	kr.RegisterKata(ast.IdentifierNode, Kata{
+		ID:    "ZC_TEST_GRADED",
+		Check: func(ast.Node) []Violation { return []Violation{{KataID: "ZC_TEST_GRADED", Line: 1, Column: 1}} },
+		Fix: func(ast.Node, Violation, []byte) []FixEdit {
+			return []FixEdit{{Line: 1, Column: 1, Length: 1, Replace: "y"}}
+		},
+		FixSafety: FixSafe,
+	})
+	node := &ast.Identifier{Token: token.Token{Literal: "x", Line: 1, Column: 1}, Value: "x"}
+	program := &ast.Program{Statements: []ast.Statement{&ast.ExpressionStatement{Expression: node}}}
+
+	vs, _ := kr.CheckProgram("", []byte("x"), program, nil, false)
+	if len(vs) != 1 || vs[0].Fix != nil {
+		t.Fatalf("without fixes: %+v, want one violation and no Fix", vs)
+	}
+	if err := kr.SetFixSafety("ZC_TEST_GRADED", FixUnsafe); err != nil {
+		t.Fatal(err)
+	}
+	vs, _ = kr.CheckProgram("", []byte("x"), program, nil, true)
+	if len(vs) != 1 || len(vs[0].Fix) != 1 || vs[0].Fix[0].Safety != FixUnsafe || vs[0].Fix[0].KataID != "ZC_TEST_GRADED" {
+		t.Errorf("with fixes: %+v, want the edit graded by the override", vs)
+	}
+}
+
+func TestWeakestSafety(t *testing.T) {
+	for _, tc := range []struct {
+		levels []FixSafety
+		want   FixSafety
+	}{
+		{nil, ""},
+		{[]FixSafety{FixSafe, FixSafe}, FixSafe},
+		{[]FixSafety{FixSafe, FixUnsafe}, FixUnsafe},
+		{[]FixSafety{FixSuggestion, FixUnsafe, FixSafe}, FixSuggestion},
+	} {
+		var edits []FixEdit
+		for _, level := range tc.levels {
+			edits = append(edits, FixEdit{Safety: level})
+		}
+		if got := WeakestSafety(edits); got != tc.want {
+			t.Errorf("WeakestSafety(%v) = %q, want %q", tc.levels, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+// SPDX-License-Identifier: MIT
+// Copyright the ZShellCheck contributors.
+package katas
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FixSafety grades how far an auto-fix may be trusted without review.
+type FixSafety string
+
+const (
+	// FixSafe marks a purely syntactic, value-preserving rewrite that
+	// cannot change runtime behavior or drop a comment. `-fix` applies it.
+	FixSafe FixSafety = "safe"
+	// FixUnsafe marks a rewrite that may alter behavior — a command or
+	// flag swap, a scope or glob-qualifier change. It is applied only
+	// under `-unsafe-fixes`. A kata with a Fix and no declared safety is
+	// unsafe.
+	FixUnsafe FixSafety = "unsafe"
+	// FixSuggestion marks a rewrite that is offered for a human to apply
+	// by hand and never written by the fixer.
+	FixSuggestion FixSafety = "suggestion"
+)
+
+// ParseFixSafety converts a config value into a FixSafety level.
+// `suggestion-only` is accepted as a spelling of `suggestion`.
+func ParseFixSafety(s string) (FixSafety, error) {
+	switch FixSafety(strings.ToLower(strings.TrimSpace(s))) {
+	case FixSafe:
+		return FixSafe, nil
+	case FixUnsafe:
+		return FixUnsafe, nil
+	case FixSuggestion, "suggestion-only":
+		return FixSuggestion, nil
+	}
+	return "", fmt.Errorf("unknown fix safety %q (want safe, unsafe or suggestion)", s)
+}
+
+// SetFixSafety overrides the safety of every fix kata id produces,
+// promoting or demoting it relative to what the kata declares. The
+// override also beats the per-edit levels the kata emits.
+func (kr *KatasRegistry) SetFixSafety(id string, level FixSafety) error {
+	kata, ok := kr.KatasByID[id]
+	if !ok {
+		return fmt.Errorf("unknown kata %s", id)
+	}
+	if kata.Fix == nil {
+		return fmt.Errorf("%s has no auto-fix to grade", id)
+	}
+	kr.fixSafety[id] = level
+	return nil
+}
+
+// FixSafetyOf returns the safety of kata id's fixes after any config
+// override, or "" when the kata has no auto-fix.
+func (kr *KatasRegistry) FixSafetyOf(id string) FixSafety {
+	kata, ok := kr.KatasByID[id]
+	if !ok || kata.Fix == nil {
+		return ""
+	}
+	if level, ok := kr.fixSafety[id]; ok {
+		return level
+	}
+	return kata.FixSafety
+}
+
+// EditSafety returns the safety of one edit: a config override of its
+// kata first, then the level the kata stamped on the edit, then the
+// kata's declared level.
+func (kr *KatasRegistry) EditSafety(e FixEdit) FixSafety {
+	if level, ok := kr.fixSafety[e.KataID]; ok {
+		return level
+	}
+	if e.Safety != "" {
+		return e.Safety
+	}
+	if level := kr.FixSafetyOf(e.KataID); level != "" {
+		return level
+	}
+	return FixUnsafe
+}
+
+// gradeEdits returns a copy of edits with each one's Safety set to its
+// EditSafety, so a consumer without the registry sees the level the
+// fixer would act on.
+func (kr *KatasRegistry) gradeEdits(edits []FixEdit) []FixEdit {
+	if len(edits) == 0 {
+		return nil
+	}
+	graded := make([]FixEdit, len(edits))
+	for i, e := range edits {
+		e.Safety = kr.EditSafety(e)
+		graded[i] = e
+	}
+	return graded
+}
+
+// WeakestSafety returns the least trusted level among edits' Safety
+// fields — a suggestion below an unsafe fix below a safe one — or ""
+// when edits is empty. A fix is only as safe as its riskiest edit.
+func WeakestSafety(edits []FixEdit) FixSafety {
+	var weakest FixSafety
+	for _, e := range edits {
+		if weakest == "" || safetyRank(e.Safety) < safetyRank(weakest) {
+			weakest = e.Safety
+		}
+	}
+	return weakest
+}
+
+func safetyRank(level FixSafety) int {
+	switch level {
+	case FixSafe:
+		return 2
+	case FixUnsafe:
+		return 1
+	}
+	return 0
+}
@@ -2,12 +2,93 @@
 // Copyright the ZShellCheck contributors.
 package katas
 
-import "github.com/afadesigns/zshellcheck/pkg/ast"
+import (
+	"strings"
+
+	"github.com/afadesigns/zshellcheck/pkg/ast"
+	"github.com/afadesigns/zshellcheck/pkg/katas/fixbuild"
+)
+
+// builtEdits returns the edits collected by b as FixEdits, or nil when
+// b failed. Kata Fix functions build their edits with a fixbuild.Builder and
+// return them through here.
+func builtEdits(b *fixbuild.Builder) []FixEdit {
+	edits := b.Edits()
+	if edits == nil {
+		return nil
+	}
+	out := make([]FixEdit, len(edits))
+	for i, e := range edits {
+		out[i] = FixEdit{Line: e.Line, Column: e.Column, Length: e.Length, Replace: e.Replace}
+	}
+	return out
+}
+
+// renameVariable is the fix of the katas that flag a Bash variable
+// with a differently named Zsh equivalent: an Identifier spelled name
+// or $name becomes to or $to.
+func renameVariable(ctx *Context, node ast.Node, name, to string) []FixEdit {
+	ident, ok := node.(*ast.Identifier)
+	if !ok || ident == nil {
+		return nil
+	}
+	var text string
+	switch ident.Value {
+	case "$" + name:
+		text = "$" + to
+	case name:
+		text = to
+	default:
+		return nil
+	}
+	b := ctx.NewBuilder()
+	b.ReplaceNode(ident, text)
+	return builtEdits(b)
+}
+
+// renameInArg replaces every occurrence of each old name inside arg
+// with its new name, one edit per occurrence, so the quoting and text
+// around the names stay as written. oldnew holds old, new pairs as for
+// strings.NewReplacer. An arg whose source does not spell its parsed
+// text is left alone.
+func renameInArg(b *fixbuild.Builder, arg ast.Expression, oldnew ...string) {
+	val := arg.String()
+	off := argOffset(b, arg)
+	if off < 0 {
+		return
+	}
+	for i := 0; i+1 < len(oldnew); i += 2 {
+		name := oldnew[i]
+		for idx := 0; ; {
+			pos := strings.Index(val[idx:], name)
+			if pos < 0 {
+				break
+			}
+			start := off + idx + pos
+			b.Replace(start, start+len(name), oldnew[i+1])
+			idx += pos + len(name)
+		}
+	}
+}
+
+// argOffset returns the byte offset of arg in the source of b, or -1
+// when the source there does not spell arg.String().
+func argOffset(b *fixbuild.Builder, arg ast.Expression) int {
+	val := arg.String()
+	tok := arg.TokenLiteralNode()
+	off := b.Offset(tok.Line, tok.Column)
+	source := b.Source()
+	if off < 0 || off+len(val) > len(source) || string(source[off:off+len(val)]) != val {
+		return -1
+	}
+	return off
+}
 
 // LineColToByteOffset converts a 1-based (line, column) coordinate
 // pair into a 0-based byte offset within source. It returns -1 when
-// the coordinates are out of range. Used by kata Fix functions that
-// need to splice source around a token known by its line/column only.
+// the coordinates are out of range. Kata Fix functions resolve
+// positions through a fixbuild.Builder instead, which indexes the lines once
+// per file rather than rescanning the source on every call.
 func LineColToByteOffset(source []byte, line, col int) int {
 	if line < 1 || col < 1 {
 		return -1
@@ -4,6 +4,7 @@ package katas
 
 import (
 	"fmt"
+	"slices"
 	"sort"
 
 	"github.com/afadesigns/zshellcheck/pkg/ast"
@@ -19,13 +20,39 @@ const (
 	SeverityStyle   Severity = "style"
 )
 
-// Violation represents a found violation in the code.
+// Violation represents a found violation in the code. Line and Column
+// are 1-based and mark where the finding starts; EndLine and EndColumn
+// mark where it ends, EndColumn exclusive. A kata may set the end itself;
+// otherwise the registry fills it from the span of the node that starts
+// at Line:Column. Zero end fields mean the finding is a single point.
+// Related lists secondary locations that explain the finding, such as
+// the first definition of something redefined.
 type Violation struct {
-	KataID  string
-	Message string
+	KataID    string
+	Message   string
+	Line      int
+	Column    int
+	EndLine   int
+	EndColumn int
+	Level     Severity
+	Related   []Location
+	// Suggestions lists the alternative rewrites of a kata that declares
+	// Suggest, preferred first. Filled when the registry has the source.
+	Suggestions []Suggestion
+	// Fix holds the edits the fixer would apply for the violation, each
+	// with its Safety resolved. Filled when the registry runs with fixes
+	// on, so reporters can hand them to tools that apply fixes themselves.
+	Fix []FixEdit
+}
+
+// Location is a secondary source position attached to a Violation, with
+// a note saying why it matters. File is empty for a position in the same
+// file as the violation.
+type Location struct {
+	File    string
 	Line    int
 	Column  int
-	Level   Severity
+	Message string
 }
 
 // FixEdit is a single text replacement applied by the auto-fixer.
@@ -33,33 +60,22 @@ type Violation struct {
 // replace starting at Line:Column. Replace may be empty (deletion) and
 // may span multiple lines. KataID records the kata that produced the edit
 // so the CLI can withhold behavior-changing (unsafe) fixes by default.
+// Safety, when set, overrides the kata's FixSafety for this edit alone,
+// so one kata can emit a safe rewrite in one context and an unsafe one in
+// another.
 type FixEdit struct {
 	Line    int
 	Column  int
 	Length  int
 	Replace string
 	KataID  string
-}
-
-// safeFixKatas lists the katas whose auto-fix is purely syntactic and
-// value-preserving — applying it cannot change runtime behavior or drop a
-// comment. Every other fix may alter behavior (a command or flag swap, a
-// scope or glob-qualifier change) and is applied only under -unsafe-fixes.
-var safeFixKatas = map[string]bool{
-	"ZC1001": true, // $arr[i] -> ${arr[i]}
-	"ZC1002": true, // `cmd` -> $(cmd)
-	"ZC1073": true, // drop redundant $ inside (( … ))
-	"ZC1086": true, // function f { } -> f() { }
-	"ZC1411": true, // enable -n name -> disable name
-	"ZC1502": true, // insert `--` end-of-options guard
-	"ZC1637": true, // readonly x -> typeset -r x
-	"ZC1643": true, // $(cat f) -> $(<f)
+	Safety  FixSafety
 }
 
 // IsSafeFix reports whether the kata's auto-fix is value-preserving and so
 // applied by `-fix` without `-unsafe-fixes`.
 func (kr *KatasRegistry) IsSafeFix(id string) bool {
-	return safeFixKatas[id]
+	return kr.FixSafetyOf(id) == FixSafe
 }
 
 // Kata represents a single linting rule. Fix is optional; when non-nil
@@ -67,19 +83,60 @@ func (kr *KatasRegistry) IsSafeFix(id string) bool {
 // full file source (byte slice) so the fix can inspect a span around
 // the violation before producing edits. Katas with no safe
 // deterministic fix leave Fix nil and the fixer skips them.
+//
+// A kata implements either Check or CheckContext. CheckContext also
+// receives a *Context exposing the file, its source, the kata's options,
+// the node's ancestors, and file-wide analysis; RegisterKata derives
+// Check from it so callers of the single-argument form keep working.
+// FixContext likewise stands in for Fix, reading the source from the
+// Context and building its edits over the file's shared line index.
+//
+// Options declares the kata's tunable knobs, configured under `rules:`.
+//
+// A program-scope kata sets CheckFile instead and is registered with
+// RegisterFileKata: it runs once per file over the whole *ast.Program and
+// may report any number of violations anywhere in it. Its Fix receives
+// the *ast.Program as the node.
+//
+// FixSafety grades Fix: FixSafe rewrites are applied by `-fix`, FixUnsafe
+// ones (the default for a kata with a Fix) only under `-unsafe-fixes`, and
+// FixSuggestion ones never automatically.
+//
+// A kata with several reasonable rewrites sets Suggest instead of Fix. It
+// returns the alternatives in preference order; RegisterKata derives Fix
+// from the first safe one, and `-fix-choose` can select another.
 type Kata struct {
-	ID          string
-	Title       string
-	Description string
-	Severity    Severity
-	Check       func(node ast.Node) []Violation
-	Fix         func(node ast.Node, v Violation, source []byte) []FixEdit
+	ID           string
+	Title        string
+	Description  string
+	Severity     Severity
+	Options      []Option
+	Check        func(node ast.Node) []Violation
+	CheckContext func(ctx *Context, node ast.Node) []Violation
+	CheckFile    func(ctx *Context, program *ast.Program) []Violation
+	Fix          func(node ast.Node, v Violation, source []byte) []FixEdit
+	FixContext   func(ctx *Context, node ast.Node, v Violation) []FixEdit
+	Suggest      func(ctx *Context, node ast.Node, v Violation) []Suggestion
+	FixSafety    FixSafety
+	// Tags are free-form labels grouping related katas, such as
+	// `security` or `portability`, for reports to filter and count by.
+	Tags []string
 }
 
 // KatasRegistry is a registry for all available Katas.
 type KatasRegistry struct {
 	KatasByType map[string][]Kata
 	KatasByID   map[string]Kata
+	// options holds the per-kata option values set from the config's
+	// `rules:` section. Katas absent here run with their defaults.
+	options map[string]OptionValues
+	// fixSafety holds config overrides of katas' fix safety.
+	fixSafety map[string]FixSafety
+	// choices maps a kata ID to the 1-based suggestion `-fix-choose`
+	// selected for it.
+	choices map[string]int
+	// unsafeFixes is set by SetUnsafeFixes.
+	unsafeFixes bool
 }
 
 // NewKatasRegistry creates a new KatasRegistry.
@@ -87,6 +144,9 @@ func NewKatasRegistry() *KatasRegistry {
 	return &KatasRegistry{
 		KatasByType: make(map[string][]Kata),
 		KatasByID:   make(map[string]Kata),
+		options:     make(map[string]OptionValues),
+		fixSafety:   make(map[string]FixSafety),
+		choices:     make(map[string]int),
 	}
 }
 
@@ -95,22 +155,65 @@ func (kr *KatasRegistry) RegisterKata(nodeType ast.Node, kata Kata) {
 	if kata.Severity == "" {
 		kata.Severity = SeverityWarning
 	}
+	if kata.Fix == nil && kata.FixContext == nil && kata.Suggest != nil {
+		kata.FixContext = kr.fixFromSuggest(kata.ID, kata.Suggest)
+	}
+	if kata.Fix == nil && kata.FixContext != nil {
+		fixCtx := kata.FixContext
+		kata.Fix = func(node ast.Node, v Violation, source []byte) []FixEdit {
+			return fixCtx(&Context{Source: source}, node, v)
+		}
+	}
+	if kata.Fix != nil && kata.FixSafety == "" {
+		kata.FixSafety = FixUnsafe
+	}
+	if kata.Check == nil && kata.CheckContext != nil {
+		checkCtx, id := kata.CheckContext, kata.ID
+		kata.Check = func(node ast.Node) []Violation {
+			return checkCtx(&Context{Options: kr.OptionValuesFor(id)}, node)
+		}
+	}
 	key := fmt.Sprintf("%T", nodeType)
 	kr.KatasByType[key] = append(kr.KatasByType[key], kata)
 	kr.KatasByID[kata.ID] = kata
 }
 
+// RegisterFileKata registers a program-scope kata. It is keyed on the
+// *ast.Program node, which the walk visits exactly once per file, so the
+// kata shares the node katas' directive, fix, baseline and statistics
+// handling.
+func (kr *KatasRegistry) RegisterFileKata(kata Kata) {
+	if kata.CheckContext == nil && kata.CheckFile != nil {
+		checkFile := kata.CheckFile
+		kata.CheckContext = func(ctx *Context, node ast.Node) []Violation {
+			program, ok := node.(*ast.Program)
+			if !ok || program == nil {
+				return nil
+			}
+			return checkFile(ctx, program)
+		}
+	}
+	kr.RegisterKata(ast.ProgramNode, kata)
+}
+
+// IsFileKata reports whether the kata with the given ID is program-scope.
+func (kr *KatasRegistry) IsFileKata(id string) bool {
+	kata, ok := kr.KatasByID[id]
+	return ok && kata.CheckFile != nil
+}
+
 // GetKata returns a Kata by its ID.
 func (kr *KatasRegistry) GetKata(id string) (Kata, bool) {
 	kata, ok := kr.KatasByID[id]
 	return kata, ok
 }
 
-// IsFixable reports whether the kata with the given ID ships a
-// deterministic auto-fix.
+// IsFixable reports whether the kata with the given ID ships an auto-fix
+// the fixer may apply — safe, or unsafe under `-unsafe-fixes`. A kata
+// whose fix is only a suggestion is not fixable.
 func (kr *KatasRegistry) IsFixable(id string) bool {
-	kata, ok := kr.KatasByID[id]
-	return ok && kata.Fix != nil
+	level := kr.FixSafetyOf(id)
+	return level == FixSafe || level == FixUnsafe
 }
 
 // KatasByNodeType returns all registered Katas grouped by node type.
@@ -130,31 +233,110 @@ func (kr *KatasRegistry) AllKatas() []Kata {
 	return out
 }
 
+// Check runs every enabled kata registered for the node's type. Katas
+// that take a Context see one without file, source, or ancestors; use
+// CheckProgram to lint a whole file with full context.
 func (kr *KatasRegistry) Check(node ast.Node, disabledKatas []string) []Violation {
+	violations, _ := kr.checkNode(&Context{}, node, disabledKatas, nil)
+	return violations
+}
+
+// CheckProgram walks program and runs every enabled kata over each node
+// with a Context carrying file, source, the node's ancestors, and the
+// file's analysis. When withFix is set it also invokes Fix for every
+// violation of a fixable kata and returns the concatenated edits.
+func (kr *KatasRegistry) CheckProgram(file string, source []byte, program *ast.Program, disabledKatas []string, withFix bool) ([]Violation, []FixEdit) {
+	ctx := NewContext(file, source, program)
+	fixSource := []byte(nil)
+	if withFix {
+		fixSource = source
+	}
+	violations := []Violation{}
+	var edits []FixEdit
+	ast.WalkWithParents(program, func(node ast.Node, parents []ast.Node) bool {
+		ctx.parents = parents
+		vs, es := kr.checkNode(ctx, node, disabledKatas, fixSource)
+		violations = append(violations, vs...)
+		edits = append(edits, es...)
+		return true
+	})
+	return violations, edits
+}
+
+// checkNode runs the enabled katas for node's type. A non-nil source
+// also runs each kata's Fix over its violations.
+func (kr *KatasRegistry) checkNode(ctx *Context, node ast.Node, disabledKatas []string, source []byte) ([]Violation, []FixEdit) {
 	var violations []Violation
-	key := fmt.Sprintf("%T", node)
-	if katasForNode, ok := kr.KatasByType[key]; ok {
-		for _, kata := range katasForNode {
-			// Check if disabled
-			disabled := false
-			for _, d := range disabledKatas {
-				if d == kata.ID {
-					disabled = true
-					break
-				}
+	var edits []FixEdit
+	for _, vf := range kr.checkNodeFixes(ctx, node, disabledKatas, source) {
+		violations = append(violations, vf.Violation)
+		edits = append(edits, vf.Edits...)
+	}
+	return violations, edits
+}
+
+// checkNodeFixes is checkNode keeping each violation's edits beside it.
+func (kr *KatasRegistry) checkNodeFixes(ctx *Context, node ast.Node, disabledKatas []string, source []byte) []ViolationFix {
+	var out []ViolationFix
+	katasForNode, ok := kr.KatasByType[fmt.Sprintf("%T", node)]
+	if !ok {
+		return nil
+	}
+	for _, kata := range katasForNode {
+		if slices.Contains(disabledKatas, kata.ID) {
+			continue
+		}
+		vs := kr.runKata(ctx, kata, node)
+		for i := range vs {
+			if vs[i].Level == "" {
+				vs[i].Level = kata.Severity
+			}
+			if vs[i].EndLine == 0 {
+				vs[i].EndLine, vs[i].EndColumn = violationEnd(node, vs[i].Line, vs[i].Column)
 			}
-			if !disabled {
-				vs := kata.Check(node)
-				for i := range vs {
-					if vs[i].Level == "" {
-						vs[i].Level = kata.Severity
-					}
-				}
-				violations = append(violations, vs...)
+			if ctx.Source != nil && kata.Suggest != nil {
+				vs[i].Suggestions = stampSuggestions(kata.Suggest(ctx, node, vs[i]), kata.ID)
 			}
+			vf := ViolationFix{Violation: vs[i]}
+			if source != nil {
+				vf.Edits = kr.violationEdits(ctx, kata, node, vs[i])
+				vf.Violation.Fix = kr.gradeEdits(vf.Edits)
+			}
+			out = append(out, vf)
 		}
 	}
-	return violations
+	return out
+}
+
+// violationEnd returns the end of the outermost node under root that
+// starts at line:column, so a finding reported on a command's first token
+// spans the whole command and one reported on an argument spans that
+// argument. It returns zeros when no node starts there.
+func violationEnd(root ast.Node, line, column int) (endLine, endColumn int) {
+	if line == 0 {
+		return 0, 0
+	}
+	ast.Walk(root, func(n ast.Node) bool {
+		if endLine != 0 {
+			return false
+		}
+		if tok := n.TokenLiteralNode(); tok.Line == line && tok.Column == column {
+			endLine, endColumn = ast.End(n)
+			return false
+		}
+		return true
+	})
+	return endLine, endColumn
+}
+
+// runKata invokes the kata's context-aware check when it has one, with
+// the kata's options installed, and its plain Check otherwise.
+func (kr *KatasRegistry) runKata(ctx *Context, kata Kata, node ast.Node) []Violation {
+	if kata.CheckContext == nil {
+		return kata.Check(node)
+	}
+	ctx.Options = kr.OptionValuesFor(kata.ID)
+	return kata.CheckContext(ctx, node)
 }
 
 // FixesFor invokes the Fix function of the kata that produced the
@@ -169,6 +351,27 @@ func (kr *KatasRegistry) FixesFor(node ast.Node, v Violation, source []byte) []F
 	return stampKataID(kata.Fix(node, v, source), kata.ID)
 }
 
+// ViolationFix pairs a violation with the edits its kata's fix proposes
+// for it.
+type ViolationFix struct {
+	Violation Violation
+	Edits     []FixEdit
+}
+
+// ViolationFixes lints program like CheckProgram with fixes on, but keeps
+// each violation's edits beside it, for tools that apply or judge one fix
+// at a time.
+func (kr *KatasRegistry) ViolationFixes(file string, source []byte, program *ast.Program, disabledKatas []string) []ViolationFix {
+	ctx := NewContext(file, source, program)
+	var out []ViolationFix
+	ast.WalkWithParents(program, func(node ast.Node, parents []ast.Node) bool {
+		ctx.parents = parents
+		out = append(out, kr.checkNodeFixes(ctx, node, disabledKatas, source)...)
+		return true
+	})
+	return out
+}
+
 // stampKataID records the producing kata on each edit so the CLI can
 // filter behavior-changing fixes without re-deriving their origin.
 func stampKataID(edits []FixEdit, id string) []FixEdit {
@@ -184,36 +387,10 @@ func stampKataID(edits []FixEdit, id string) []FixEdit {
 // fix) and the concatenated edits. Use this from the CLI fix mode so
 // each node is visited exactly once.
 func (kr *KatasRegistry) CheckAndFix(node ast.Node, disabledKatas []string, source []byte) ([]Violation, []FixEdit) {
-	var violations []Violation
-	var edits []FixEdit
-	key := fmt.Sprintf("%T", node)
-	katasForNode, ok := kr.KatasByType[key]
-	if !ok {
-		return nil, nil
-	}
-	for _, kata := range katasForNode {
-		skip := false
-		for _, d := range disabledKatas {
-			if d == kata.ID {
-				skip = true
-				break
-			}
-		}
-		if skip {
-			continue
-		}
-		vs := kata.Check(node)
-		for i := range vs {
-			if vs[i].Level == "" {
-				vs[i].Level = kata.Severity
-			}
-			if kata.Fix != nil {
-				edits = append(edits, stampKataID(kata.Fix(node, vs[i], source), kata.ID)...)
-			}
-		}
-		violations = append(violations, vs...)
+	if source == nil {
+		source = []byte{}
 	}
-	return violations, edits
+	return kr.checkNode(&Context{Source: source}, node, disabledKatas, source)
 }
 
 // DefaultKatasRegistry is the default registry.
@@ -224,5 +401,10 @@ func RegisterKata(nodeType ast.Node, kata Kata) {
 	DefaultKatasRegistry.RegisterKata(nodeType, kata)
 }
 
+// RegisterFileKata registers a program-scope Kata with the default registry.
+func RegisterFileKata(kata Kata) {
+	DefaultKatasRegistry.RegisterFileKata(kata)
+}
+
 // Registry is the global registry.
 var Registry = DefaultKatasRegistry
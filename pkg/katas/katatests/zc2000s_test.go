@@ -196,3 +196,91 @@ func TestZC2003(t *testing.T) {
 		})
 	}
 }
+
+func TestZC2004(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []katas.Violation
+	}{
+		{
+			name:     "valid — distinct function names",
+			input:    "f() { :; }\ng() { :; }",
+			expected: []katas.Violation{},
+		},
+		{
+			name:     "valid — alternative definitions in if branches",
+			input:    "if (( $+commands[bat] )); then\n  view() { bat $1; }\nelse\n  view() { cat $1; }\nfi",
+			expected: []katas.Violation{},
+		},
+		{
+			name:  "invalid — same name defined twice",
+			input: "f() { :; }\necho hi\nf() { true; }",
+			expected: []katas.Violation{
+				{
+					KataID:  "ZC2004",
+					Message: "Function `f` is redefined here (first definition at line 1) — the earlier body is silently replaced.",
+					Line:    3,
+					Column:  1,
+				},
+			},
+		},
+		{
+			name:  "invalid — mixed `function` and `name()` forms",
+			input: "function g { :; }\ng() { :; }",
+			expected: []katas.Violation{
+				{
+					KataID:  "ZC2004",
+					Message: "Function `g` is redefined here (first definition at line 1) — the earlier body is silently replaced.",
+					Line:    2,
+					Column:  1,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations := testutil.Check(tt.input, "ZC2004")
+			testutil.AssertViolations(t, tt.input, violations, tt.expected)
+		})
+	}
+}
+
+func TestZC2005(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []katas.Violation
+	}{
+		{
+			name:     "valid — fpath extended before compinit",
+			input:    "fpath+=(~/.zfunc)\nautoload -Uz compinit\ncompinit -i",
+			expected: []katas.Violation{},
+		},
+		{
+			name:     "valid — no compinit",
+			input:    "fpath=(~/.zfunc $fpath)",
+			expected: []katas.Violation{},
+		},
+		{
+			name:  "invalid — fpath appended after compinit",
+			input: "autoload -Uz compinit\ncompinit -i\nfpath+=(~/.zfunc)",
+			expected: []katas.Violation{
+				{
+					KataID:  "ZC2005",
+					Message: "`fpath` is extended after `compinit` ran on line 2 — completions in the new directory are not loaded. Move `compinit` below every `fpath` change.",
+					Line:    3,
+					Column:  1,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations := testutil.Check(tt.input, "ZC2005")
+			testutil.AssertViolations(t, tt.input, violations, tt.expected)
+		})
+	}
+}
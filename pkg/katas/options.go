@@ -0,0 +1,180 @@
+// SPDX-License-Identifier: MIT
+// Copyright the ZShellCheck contributors.
+package katas
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// OptionType names the value type of a kata option.
+type OptionType string
+
+const (
+	OptionInt        OptionType = "int"
+	OptionString     OptionType = "string"
+	OptionBool       OptionType = "bool"
+	OptionStringList OptionType = "list"
+)
+
+// Option declares one tunable knob of a kata: a threshold, an allowlist,
+// a command set. Default holds the value used when the config does not
+// set the option and must match Type (int, string, bool, or []string).
+type Option struct {
+	Name        string
+	Type        OptionType
+	Default     any
+	Description string
+}
+
+// OptionValues is the resolved option set handed to a kata's check: the
+// declared defaults overlaid with any `rules:` overrides from the config.
+type OptionValues map[string]any
+
+// Int returns the integer option name, or 0 when it is unset or not an int.
+func (o OptionValues) Int(name string) int {
+	n, _ := o[name].(int)
+	return n
+}
+
+// String returns the string option name, or "" when it is unset.
+func (o OptionValues) String(name string) string {
+	s, _ := o[name].(string)
+	return s
+}
+
+// Bool returns the boolean option name, or false when it is unset.
+func (o OptionValues) Bool(name string) bool {
+	b, _ := o[name].(bool)
+	return b
+}
+
+// StringList returns the list option name, or nil when it is unset.
+func (o OptionValues) StringList(name string) []string {
+	l, _ := o[name].([]string)
+	return l
+}
+
+// defaultOptionValues builds the option set a kata sees when the config
+// leaves every option at its default.
+func defaultOptionValues(schema []Option) OptionValues {
+	out := make(OptionValues, len(schema))
+	for _, opt := range schema {
+		out[opt.Name] = opt.Default
+	}
+	return out
+}
+
+// SetOptions overrides options of the kata id from raw config strings.
+// Every key must name a declared option and every value must parse as the
+// option's type; the kata keeps its previous values on error so a typo in
+// the config never half-applies.
+func (kr *KatasRegistry) SetOptions(id string, raw map[string]string) error {
+	kata, ok := kr.KatasByID[id]
+	if !ok {
+		return fmt.Errorf("rules: unknown kata %s", id)
+	}
+	next := defaultOptionValues(kata.Options)
+	for k, v := range kr.options[id] {
+		next[k] = v
+	}
+	keys := make([]string, 0, len(raw))
+	for k := range raw {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, name := range keys {
+		opt, ok := findOption(kata.Options, name)
+		if !ok {
+			return fmt.Errorf("rules: %s has no option %q", id, name)
+		}
+		val, err := ParseOptionValue(opt.Type, raw[name])
+		if err != nil {
+			return fmt.Errorf("rules: %s.%s: %w", id, name, err)
+		}
+		next[name] = val
+	}
+	kr.options[id] = next
+	return nil
+}
+
+// OptionValuesFor returns the resolved options of the kata id: its
+// defaults, overlaid with whatever SetOptions recorded.
+func (kr *KatasRegistry) OptionValuesFor(id string) OptionValues {
+	if vals, ok := kr.options[id]; ok {
+		return vals
+	}
+	kata, ok := kr.KatasByID[id]
+	if !ok || len(kata.Options) == 0 {
+		return OptionValues{}
+	}
+	return defaultOptionValues(kata.Options)
+}
+
+func findOption(schema []Option, name string) (Option, bool) {
+	for _, opt := range schema {
+		if opt.Name == name {
+			return opt, true
+		}
+	}
+	return Option{}, false
+}
+
+// ParseOptionValue converts a raw config string into the Go value for
+// typ. Lists accept the inline `[a, b]` form or a bare comma-separated
+// string; surrounding quotes on list items are stripped.
+func ParseOptionValue(typ OptionType, raw string) (any, error) {
+	raw = strings.TrimSpace(raw)
+	switch typ {
+	case OptionInt:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, fmt.Errorf("expected an integer, got %q", raw)
+		}
+		return n, nil
+	case OptionBool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("expected a boolean, got %q", raw)
+		}
+		return b, nil
+	case OptionStringList:
+		return parseOptionList(raw), nil
+	case OptionString:
+		return raw, nil
+	}
+	return nil, fmt.Errorf("unknown option type %q", typ)
+}
+
+func parseOptionList(raw string) []string {
+	if strings.HasPrefix(raw, "[") && strings.HasSuffix(raw, "]") {
+		raw = raw[1 : len(raw)-1]
+	}
+	out := []string{}
+	for _, item := range strings.Split(raw, ",") {
+		item = strings.Trim(strings.TrimSpace(item), `"'`)
+		if item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// FormatOptionDefault renders an option default the way it would be
+// written in the config, for `--explain` and generated docs.
+func FormatOptionDefault(opt Option) string {
+	switch v := opt.Default.(type) {
+	case []string:
+		return "[" + strings.Join(v, ", ") + "]"
+	case string:
+		if v == "" {
+			return `""`
+		}
+		return v
+	case nil:
+		return ""
+	}
+	return fmt.Sprint(opt.Default)
+}
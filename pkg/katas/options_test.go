@@ -0,0 +1,177 @@
+// SPDX-License-Identifier: MIT
+// Copyright the ZShellCheck contributors.
+package katas
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/afadesigns/zshellcheck/pkg/ast"
+	"github.com/afadesigns/zshellcheck/pkg/lexer"
+	"github.com/afadesigns/zshellcheck/pkg/parser"
+)
+
+func optionsTestRegistry() *KatasRegistry {
+	kr := NewKatasRegistry()
+	kr.RegisterKata(ast.IdentifierNode, Kata{
+		ID: "ZC_OPT",
+		Options: []Option{
+			{Name: "limit", Type: OptionInt, Default: 3},
+			{Name: "hosts", Type: OptionStringList, Default: []string{"a"}},
+			{Name: "strict", Type: OptionBool, Default: false},
+			{Name: "label", Type: OptionString, Default: "x"},
+		},
+		CheckContext: func(ctx *Context, node ast.Node) []Violation {
+			return []Violation{{
+				KataID:  "ZC_OPT",
+				Message: ctx.Options.String("label") + strings.Repeat("!", ctx.Options.Int("limit")),
+			}}
+		},
+	})
+	return kr
+}
+
+func TestCheckContextSeesDefaultOptions(t *testing.T) {
+	kr := optionsTestRegistry()
+	vs := kr.Check(&ast.Identifier{}, nil)
+	if len(vs) != 1 || vs[0].Message != "x!!!" {
+		t.Fatalf("violations = %+v, want one with message x!!!", vs)
+	}
+}
+
+func TestSetOptionsOverridesCheck(t *testing.T) {
+	kr := optionsTestRegistry()
+	err := kr.SetOptions("ZC_OPT", map[string]string{
+		"limit": "1", "hosts": "[b, 'c']", "strict": "true", "label": "y",
+	})
+	if err != nil {
+		t.Fatalf("SetOptions: %v", err)
+	}
+	kata, _ := kr.GetKata("ZC_OPT")
+	if vs := kata.Check(&ast.Identifier{}); len(vs) != 1 || vs[0].Message != "y!" {
+		t.Fatalf("derived Check ignored options: %+v", vs)
+	}
+	opts := kr.OptionValuesFor("ZC_OPT")
+	if !reflect.DeepEqual(opts.StringList("hosts"), []string{"b", "c"}) || !opts.Bool("strict") {
+		t.Errorf("resolved options = %#v", opts)
+	}
+}
+
+func TestSetOptionsRejectsBadInput(t *testing.T) {
+	cases := map[string]struct {
+		id  string
+		raw map[string]string
+	}{
+		"unknown kata":   {"ZC_NOPE", map[string]string{"limit": "1"}},
+		"unknown option": {"ZC_OPT", map[string]string{"nope": "1"}},
+		"bad int":        {"ZC_OPT", map[string]string{"limit": "many"}},
+		"bad bool":       {"ZC_OPT", map[string]string{"strict": "sometimes"}},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			kr := optionsTestRegistry()
+			if err := kr.SetOptions(tc.id, tc.raw); err == nil {
+				t.Fatal("want error, got nil")
+			}
+			if got := kr.OptionValuesFor("ZC_OPT").Int("limit"); got != 3 {
+				t.Errorf("failed SetOptions changed limit to %d", got)
+			}
+		})
+	}
+}
+
+func TestFormatOptionDefault(t *testing.T) {
+	cases := []struct {
+		opt  Option
+		want string
+	}{
+		{Option{Default: 16}, "16"},
+		{Option{Default: []string{"rm", "srm"}}, "[rm, srm]"},
+		{Option{Default: []string{}}, "[]"},
+		{Option{Default: ""}, `""`},
+		{Option{Default: true}, "true"},
+	}
+	for _, tc := range cases {
+		if got := FormatOptionDefault(tc.opt); got != tc.want {
+			t.Errorf("FormatOptionDefault(%#v) = %q, want %q", tc.opt.Default, got, tc.want)
+		}
+	}
+}
+
+func TestRegisteredOptionDefaultsMatchType(t *testing.T) {
+	for _, k := range Registry.AllKatas() {
+		for _, opt := range k.Options {
+			var ok bool
+			switch opt.Type {
+			case OptionInt:
+				_, ok = opt.Default.(int)
+			case OptionString:
+				_, ok = opt.Default.(string)
+			case OptionBool:
+				_, ok = opt.Default.(bool)
+			case OptionStringList:
+				_, ok = opt.Default.([]string)
+			}
+			if !ok {
+				t.Errorf("%s option %s: default %#v is not a %s", k.ID, opt.Name, opt.Default, opt.Type)
+			}
+			if k.CheckContext == nil {
+				t.Errorf("%s declares options but no CheckContext", k.ID)
+			}
+		}
+	}
+}
+
+// checkWithOptions runs kata id over a single command with the given
+// option overrides on the shared registry, restoring its defaults after.
+func checkWithOptions(t *testing.T, id, src string, raw map[string]string) []Violation {
+	t.Helper()
+	if err := Registry.SetOptions(id, raw); err != nil {
+		t.Fatalf("SetOptions: %v", err)
+	}
+	t.Cleanup(func() { delete(Registry.options, id) })
+	kata, _ := Registry.GetKata(id)
+	return kata.Check(parseFirstCommand(t, src))
+}
+
+// parseFirstCommand parses src and returns its first simple command.
+func parseFirstCommand(t *testing.T, src string) *ast.SimpleCommand {
+	t.Helper()
+	prog := parser.New(lexer.New(src)).ParseProgram()
+	var cmd *ast.SimpleCommand
+	ast.Walk(prog, func(n ast.Node) bool {
+		if c, ok := n.(*ast.SimpleCommand); ok && cmd == nil {
+			cmd = c
+		}
+		return true
+	})
+	if cmd == nil {
+		t.Fatalf("no command in %q", src)
+	}
+	return cmd
+}
+
+func TestShippedKataOptions(t *testing.T) {
+	cases := []struct {
+		id, src string
+		raw     map[string]string
+		want    int
+	}{
+		{"ZC1760", "openssl rand -hex 24", nil, 0},
+		{"ZC1760", "openssl rand -hex 24", map[string]string{"min_bytes": "32"}, 1},
+		{"ZC1552", "openssl genrsa 2048", map[string]string{"min_bits": "3072"}, 1},
+		{"ZC1136", "srm -rf $dir", nil, 0},
+		{"ZC1136", "srm -rf $dir", map[string]string{"commands": "[rm, srm]"}, 1},
+		{"ZC1141", "curl -sSL https://sh.rustup.rs", nil, 1},
+		{"ZC1141", "curl -sSL https://sh.rustup.rs", map[string]string{"allowed_hosts": "[sh.rustup.rs]"}, 0},
+		{"ZC1141", "curl -sSL https://evil.example", map[string]string{"allowed_hosts": "[sh.rustup.rs]"}, 1},
+	}
+	for _, tc := range cases {
+		t.Run(tc.id+" "+tc.src, func(t *testing.T) {
+			if got := len(checkWithOptions(t, tc.id, tc.src, tc.raw)); got != tc.want {
+				t.Errorf("got %d violations, want %d", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+// SPDX-License-Identifier: MIT
+// Copyright the ZShellCheck contributors.
+package katas
+
+import (
+	"fmt"
+
+	"github.com/afadesigns/zshellcheck/pkg/ast"
+)
+
+// Suggestion is one way to resolve a violation: a short title naming the
+// rewrite and the edits that perform it. A kata with more than one
+// reasonable rewrite offers them as a list in preference order.
+type Suggestion struct {
+	Title string
+	Edits []FixEdit
+}
+
+// fixFromSuggest derives a Fix for a kata declaring only Suggest, so it
+// still takes part in `-fix`: the first suggestion whose edits all grade
+// safe, or, under SetUnsafeFixes, the preferred first one.
+func (kr *KatasRegistry) fixFromSuggest(id string, suggest func(*Context, ast.Node, Violation) []Suggestion) func(*Context, ast.Node, Violation) []FixEdit {
+	return func(ctx *Context, node ast.Node, v Violation) []FixEdit {
+		s := suggest(ctx, node, v)
+		for _, sg := range s {
+			if WeakestSafety(kr.gradeEdits(stampKataID(sg.Edits, id))) == FixSafe {
+				return sg.Edits
+			}
+		}
+		if len(s) > 0 && kr.unsafeFixes {
+			return s[0].Edits
+		}
+		return nil
+	}
+}
+
+// SetUnsafeFixes makes a kata declaring only Suggest fall back to its
+// preferred suggestion when none of them is safe, as `-unsafe-fixes`
+// asks for.
+func (kr *KatasRegistry) SetUnsafeFixes(on bool) {
+	kr.unsafeFixes = on
+}
+
+// ChooseSuggestion makes the fixer apply the n-th (1-based) suggestion of
+// kata id instead of its preferred one. A violation offering fewer than n
+// suggestions is left unfixed rather than fixed some other way.
+func (kr *KatasRegistry) ChooseSuggestion(id string, n int) error {
+	kata, ok := kr.KatasByID[id]
+	if !ok {
+		return fmt.Errorf("unknown kata %s", id)
+	}
+	if kata.Suggest == nil {
+		return fmt.Errorf("%s offers no alternative fixes to choose from", id)
+	}
+	if n < 1 {
+		return fmt.Errorf("%s: suggestion number must be 1 or more, got %d", id, n)
+	}
+	kr.choices[id] = n
+	return nil
+}
+
+// violationEdits returns the edits the fixer should apply for v: the
+// chosen suggestion when ChooseSuggestion picked one, otherwise the
+// kata's Fix, run with ctx when the kata takes one.
+func (kr *KatasRegistry) violationEdits(ctx *Context, kata Kata, node ast.Node, v Violation) []FixEdit {
+	if n, ok := kr.choices[kata.ID]; ok {
+		if n > len(v.Suggestions) {
+			return nil
+		}
+		return v.Suggestions[n-1].Edits
+	}
+	if kata.FixContext != nil {
+		return stampKataID(kata.FixContext(ctx, node, v), kata.ID)
+	}
+	if kata.Fix == nil {
+		return nil
+	}
+	return stampKataID(kata.Fix(node, v, ctx.Source), kata.ID)
+}
+
+// stampSuggestions records the producing kata on every suggested edit.
+func stampSuggestions(suggestions []Suggestion, id string) []Suggestion {
+	for i := range suggestions {
+		stampKataID(suggestions[i].Edits, id)
+	}
+	return suggestions
+}
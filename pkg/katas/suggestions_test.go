@@ -0,0 +1,130 @@
+// SPDX-License-Identifier: MIT
+// Copyright the ZShellCheck contributors.
+package katas
+
+import (
+	"testing"
+
+	"github.com/afadesigns/zshellcheck/pkg/ast"
+	"github.com/afadesigns/zshellcheck/pkg/lexer"
+	"github.com/afadesigns/zshellcheck/pkg/parser"
+)
+
+// zc1171Registry holds only ZC1171, so choices made in a test do not leak
+// into the shared Registry. The Fix is derived afresh against it.
+func zc1171Registry() *KatasRegistry {
+	kr := NewKatasRegistry()
+	kata := Registry.KatasByID["ZC1171"]
+	kata.Fix, kata.FixContext = nil, nil
+	kr.RegisterKata(ast.SimpleCommandNode, kata)
+	return kr
+}
+
+func checkWithFix(kr *KatasRegistry, src string) ([]Violation, []FixEdit) {
+	prog := parser.New(lexer.New(src)).ParseProgram()
+	return kr.CheckProgram("", []byte(src), prog, nil, true)
+}
+
+func TestZC1171Suggestions(t *testing.T) {
+	kr := zc1171Registry()
+	// Neither rewrite is safe, so only -unsafe-fixes falls back to `print`.
+	if _, edits := checkWithFix(kr, "echo -e \"a\\tb\"\n"); len(edits) != 0 {
+		t.Errorf("fix edits without unsafe fixes = %+v, want none", edits)
+	}
+	kr.SetUnsafeFixes(true)
+	vs, edits := checkWithFix(kr, "echo -e \"a\\tb\"\n")
+	if len(vs) != 1 || len(vs[0].Suggestions) != 2 {
+		t.Fatalf("want one finding with two suggestions, got %+v", vs)
+	}
+	if got := vs[0].Suggestions[1].Edits[0].Replace; got != `printf '%b\n'` {
+		t.Errorf("second suggestion replaces with %q", got)
+	}
+	for _, s := range vs[0].Suggestions {
+		if s.Edits[0].KataID != "ZC1171" {
+			t.Errorf("suggestion %q edit not stamped with its kata", s.Title)
+		}
+	}
+	if len(edits) != 1 || edits[0].Replace != "print" {
+		t.Errorf("fix edits = %+v, want the preferred `print` rewrite", edits)
+	}
+
+	vs, _ = checkWithFix(zc1171Registry(), "echo -e a b\n")
+	if len(vs) != 1 || len(vs[0].Suggestions) != 1 {
+		t.Errorf("several arguments should offer only `print`, got %+v", vs)
+	}
+}
+
+func TestChooseSuggestion(t *testing.T) {
+	kr := zc1171Registry()
+	if err := kr.ChooseSuggestion("ZC1171", 2); err != nil {
+		t.Fatal(err)
+	}
+	_, edits := checkWithFix(kr, "echo -e \"a\\tb\"\n")
+	if len(edits) != 1 || edits[0].Replace != `printf '%b\n'` {
+		t.Errorf("chosen edits = %+v, want the printf rewrite", edits)
+	}
+	// A finding offering fewer suggestions than chosen stays unfixed.
+	if _, edits := checkWithFix(kr, "echo -e a b\n"); len(edits) != 0 {
+		t.Errorf("out-of-range choice produced edits %+v", edits)
+	}
+}
+
+func TestChooseSuggestionErrors(t *testing.T) {
+	kr := zc1171Registry()
+	kr.RegisterKata(ast.IdentifierNode, Kata{
+		ID:    "ZC_PLAIN_FIX",
+		Check: func(ast.Node) []Violation { return nil },
+		Fix:   func(ast.Node, Violation, []byte) []FixEdit { return nil },
+	})
+	for _, tc := range []struct {
+		id string
+		n  int
+	}{{"ZC_UNKNOWN", 1}, {"ZC_PLAIN_FIX", 1}, {"ZC1171", 0}} {
+		if err := kr.ChooseSuggestion(tc.id, tc.n); err == nil {
+			t.Errorf("ChooseSuggestion(%q, %d): want error", tc.id, tc.n)
+		}
+	}
+}
+
+func TestSuggestDerivesFix(t *testing.T) {
+	kr := NewKatasRegistry()
+	kr.RegisterKata(ast.IdentifierNode, Kata{
+		ID:    "ZC_SUGGEST",
+		Check: func(ast.Node) []Violation { return nil },
+		Suggest: func(*Context, ast.Node, Violation) []Suggestion {
+			return []Suggestion{
+				{Title: "first", Edits: []FixEdit{{Line: 1, Column: 1, Replace: "a"}}},
+				{Title: "second", Edits: []FixEdit{{Line: 1, Column: 1, Replace: "b", Safety: FixSafe}}},
+			}
+		},
+	})
+	if !kr.IsFixable("ZC_SUGGEST") {
+		t.Error("a kata with Suggest should be fixable")
+	}
+	got := kr.KatasByID["ZC_SUGGEST"].Fix(nil, Violation{}, nil)
+	if len(got) != 1 || got[0].Replace != "b" {
+		t.Errorf("derived Fix = %+v, want the first safe suggestion", got)
+	}
+}
+
+func TestSuggestDerivesFix_UnsafeFallback(t *testing.T) {
+	kr := NewKatasRegistry()
+	kr.RegisterKata(ast.IdentifierNode, Kata{
+		ID:    "ZC_SUGGEST",
+		Check: func(ast.Node) []Violation { return nil },
+		Suggest: func(*Context, ast.Node, Violation) []Suggestion {
+			return []Suggestion{
+				{Title: "first", Edits: []FixEdit{{Line: 1, Column: 1, Replace: "a"}}},
+				{Title: "second", Edits: []FixEdit{{Line: 1, Column: 1, Replace: "b", Safety: FixSuggestion}}},
+			}
+		},
+	})
+	derived := kr.KatasByID["ZC_SUGGEST"].Fix
+	if got := derived(nil, Violation{}, nil); len(got) != 0 {
+		t.Errorf("derived Fix = %+v, want none without a safe suggestion", got)
+	}
+	kr.SetUnsafeFixes(true)
+	if got := derived(nil, Violation{}, nil); len(got) != 1 || got[0].Replace != "a" {
+		t.Errorf("derived Fix under unsafe fixes = %+v, want the first suggestion", got)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/afadesigns/zshellcheck/pkg/ast"
+	"github.com/afadesigns/zshellcheck/pkg/katas/fixbuild"
 	"github.com/afadesigns/zshellcheck/pkg/token"
 )
 
@@ -18,9 +19,10 @@ func init() {
 		Description: "In native Zsh, `$my_array[1]` accesses array element 1 and is valid. " +
 			"The braced form `${my_array[1]}` is preferred: it is unambiguous, reads " +
 			"clearly inside double quotes, and behaves the same under `KSH_ARRAYS`.",
-		Severity: SeverityStyle,
-		Check:    checkZC1001,
-		Fix:      fixZC1001,
+		Severity:   SeverityStyle,
+		Check:      checkZC1001,
+		FixContext: fixZC1001,
+		FixSafety:  FixSafe,
 	})
 	RegisterKata(ast.InvalidArrayAccessNode, Kata{
 		ID:    "ZC1001",
@@ -28,9 +30,10 @@ func init() {
 		Description: "In native Zsh, `$my_array[1]` accesses array element 1 and is valid. " +
 			"The braced form `${my_array[1]}` is preferred: it is unambiguous, reads " +
 			"clearly inside double quotes, and behaves the same under `KSH_ARRAYS`.",
-		Severity: SeverityStyle,
-		Check:    checkZC1001,
-		Fix:      fixZC1001,
+		Severity:   SeverityStyle,
+		Check:      checkZC1001,
+		FixContext: fixZC1001,
+		FixSafety:  FixSafe,
 	})
 }
 
@@ -39,8 +42,10 @@ func init() {
 // the closing `]`. Source positions are derived from the violation
 // column (which points at the leading `$`) and a quote/brace-aware
 // scan for the matching `]`.
-func fixZC1001(node ast.Node, v Violation, source []byte) []FixEdit {
-	dollarOff := LineColToByteOffset(source, v.Line, v.Column)
+func fixZC1001(ctx *Context, node ast.Node, v Violation) []FixEdit {
+	source := ctx.Source
+	b := ctx.NewBuilder()
+	dollarOff := b.Offset(v.Line, v.Column)
 	if dollarOff < 0 || dollarOff >= len(source) || source[dollarOff] != '$' {
 		return nil
 	}
@@ -63,12 +68,8 @@ func fixZC1001(node ast.Node, v Violation, source []byte) []FixEdit {
 	// token — notably ZC1073, which deletes the `$` inside `(( … ))`.
 	// Two zero-width inserts straddle that delete without overlapping it,
 	// so both used to apply and produced the broken `{name[subscript]}`.
-	return []FixEdit{{
-		Line:    v.Line,
-		Column:  v.Column,
-		Length:  closeOff + 1 - dollarOff,
-		Replace: "${" + string(source[dollarOff+1:closeOff+1]) + "}",
-	}}
+	b.Replace(dollarOff, closeOff+1, "${"+string(source[dollarOff+1:closeOff+1])+"}")
+	return builtEdits(b)
 }
 
 // findSubscriptClose returns the byte offset of the `]` that closes
@@ -166,9 +167,10 @@ func init() {
 		Title: "Use $(...) instead of backticks",
 		Description: "Backticks are the old-style command substitution. " +
 			"$(...) is nesting-safe, easier to read, and generally preferred.",
-		Severity: SeverityStyle,
-		Check:    checkZC1002,
-		Fix:      fixZC1002,
+		Severity:   SeverityStyle,
+		Check:      checkZC1002,
+		FixContext: fixZC1002,
+		FixSafety:  FixSafe,
 	})
 }
 
@@ -178,12 +180,14 @@ func init() {
 // a single replacement edit spanning both delimiters. Unterminated
 // backtick spans are skipped (the parser rejects them earlier; this is
 // defensive).
-func fixZC1002(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1002(ctx *Context, node ast.Node, v Violation) []FixEdit {
+	source := ctx.Source
 	cs, ok := node.(*ast.CommandSubstitution)
 	if !ok {
 		return nil
 	}
-	start := LineColToByteOffset(source, v.Line, v.Column)
+	b := ctx.NewBuilder()
+	start := b.Offset(v.Line, v.Column)
 	if start < 0 || start >= len(source) || source[start] != '`' {
 		return nil
 	}
@@ -213,12 +217,17 @@ func fixZC1002(node ast.Node, v Violation, source []byte) []FixEdit {
 	if cs.Command != nil && cs.Command.String() == "" {
 		return nil
 	}
-	return []FixEdit{{
-		Line:    v.Line,
-		Column:  v.Column,
-		Length:  end - start + 1,
-		Replace: "$(" + inner + ")",
-	}}
+	b.Replace(start, end+1, "$("+inner+")")
+	edits := builtEdits(b)
+	// A body without escapes, parentheses, comments or nested backticks
+	// reads the same inside `$(...)`, so the rewrite is safe even for a
+	// kata that grades it unsafe in general.
+	if !strings.ContainsAny(inner, "\\()#`") {
+		for i := range edits {
+			edits[i].Safety = FixSafe
+		}
+	}
+	return edits
 }
 
 func checkZC1002(node ast.Node) []Violation {
@@ -244,9 +253,9 @@ func init() {
 		Title: "Use `((...))` for arithmetic comparisons instead of `[` or `test`",
 		Description: "Bash/Zsh have a dedicated arithmetic context `((...))` " +
 			"which is cleaner and faster than `[` or `test` for numeric comparisons.",
-		Severity: SeverityStyle,
-		Check:    checkZC1003,
-		Fix:      fixZC1003,
+		Severity:   SeverityStyle,
+		Check:      checkZC1003,
+		FixContext: fixZC1003,
 	})
 }
 
@@ -300,7 +309,8 @@ func checkZC1003(node ast.Node) []Violation {
 // Bails when the command shape doesn't match: more than one
 // comparison operator, no `[` byte at the violation column,
 // missing close bracket, or no recognised operator.
-func fixZC1003(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1003(ctx *Context, node ast.Node, v Violation) []FixEdit {
+	source := ctx.Source
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok || cmd.Name == nil {
 		return nil
@@ -323,22 +333,19 @@ func fixZC1003(node ast.Node, v Violation, source []byte) []FixEdit {
 	if op == "" {
 		return nil
 	}
-	openOff := LineColToByteOffset(source, v.Line, v.Column)
-	if openOff < 0 || openOff >= len(source) || source[openOff] != '[' {
+	b := ctx.NewBuilder()
+	openOff, _, ok := b.Span(cmd.Name)
+	if !ok {
 		return nil
 	}
 	closeOff := findTestCloseBracket(source, openOff)
 	if closeOff < 0 {
 		return nil
 	}
-	opTok := cmd.Arguments[opIdx]
-	opLine := opTok.TokenLiteralNode().Line
-	opCol := opTok.TokenLiteralNode().Column
-	return []FixEdit{
-		{Line: v.Line, Column: v.Column, Length: 1, Replace: "(("},
-		{Line: opLine, Column: opCol, Length: len(op), Replace: arithCmpReplacements[op]},
-		offsetToEdit(source, closeOff, 1, "))"),
-	}
+	b.ReplaceCommandName(cmd, "((")
+	b.ReplaceNode(cmd.Arguments[opIdx], arithCmpReplacements[op])
+	b.Replace(closeOff, closeOff+1, "))")
+	return builtEdits(b)
 }
 
 func init() {
@@ -427,16 +434,16 @@ func init() {
 		Description: "The `which` command is an external command and may not be available on all systems. " +
 			"The `whence` command is a built-in Zsh command that provides a more reliable and consistent " +
 			"way to find the location of a command.",
-		Severity: SeverityInfo,
-		Check:    checkZC1005,
-		Fix:      fixZC1005,
+		Severity:   SeverityInfo,
+		Check:      checkZC1005,
+		FixContext: fixZC1005,
 	})
 }
 
 // fixZC1005 rewrites `which` -> `whence` at the command name position.
 // Arguments are unchanged; the two builtins share the identifier-query
 // shape for the common case.
-func fixZC1005(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1005(ctx *Context, node ast.Node, v Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -445,12 +452,9 @@ func fixZC1005(node ast.Node, v Violation, source []byte) []FixEdit {
 	if !ok || ident.Value != "which" {
 		return nil
 	}
-	return []FixEdit{{
-		Line:    v.Line,
-		Column:  v.Column,
-		Length:  len("which"),
-		Replace: "whence",
-	}}
+	b := ctx.NewBuilder()
+	b.ReplaceCommandName(cmd, "whence")
+	return builtEdits(b)
 }
 
 func checkZC1005(node ast.Node) []Violation {
@@ -487,7 +491,7 @@ func init() {
 		// All three of ZC1006 / ZC1020 / ZC1036 fire on the same `test`
 		// shape and want the same `[[ … ]]` rewrite that ZC1293 ships.
 		// The conflict resolver dedupes overlapping edits.
-		Fix: fixZC1293,
+		FixContext: fixZC1293,
 	})
 }
 
@@ -573,7 +577,7 @@ func init() {
 		// All three of ZC1008, ZC1013, ZC1022 fire on the same `let`
 		// shape and want the same arithmetic-command form; the
 		// conflict resolver dedupes overlapping edits.
-		Fix: fixZC1013,
+		FixContext: fixZC1013,
 	})
 }
 
@@ -634,9 +638,9 @@ func init() {
 		Title: "Use [[ ... ]] instead of [ ... ]",
 		Description: "Zsh's [[ ... ]] is more powerful and safer than [ ... ]. " +
 			"It supports pattern matching, regex, and doesn't require quoting variables to prevent word splitting.",
-		Severity: SeverityStyle,
-		Check:    checkZC1010,
-		Fix:      fixZC1010,
+		Severity:   SeverityStyle,
+		Check:      checkZC1010,
+		FixContext: fixZC1010,
 	})
 }
 
@@ -648,7 +652,8 @@ func init() {
 // Bail when the shape is not a simple `[ … ]` test (e.g. second token
 // is not `[`, or the logical line has no closing bracket): a
 // malformed test is not safely auto-fixable.
-func fixZC1010(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1010(ctx *Context, node ast.Node, v Violation) []FixEdit {
+	source := ctx.Source
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -667,18 +672,18 @@ func fixZC1010(node ast.Node, v Violation, source []byte) []FixEdit {
 			return nil
 		}
 	}
-	open := LineColToByteOffset(source, v.Line, v.Column)
-	if open < 0 || open >= len(source) || source[open] != '[' {
+	b := ctx.NewBuilder()
+	open, _, ok := b.Span(cmd.Name)
+	if !ok {
 		return nil
 	}
 	close := findTestCloseBracket(source, open)
 	if close < 0 {
 		return nil
 	}
-	return []FixEdit{
-		{Line: v.Line, Column: v.Column, Length: 1, Replace: "[["},
-		offsetToEdit(source, close, 1, "]]"),
-	}
+	b.ReplaceCommandName(cmd, "[[")
+	b.Replace(close, close+1, "]]")
+	return builtEdits(b)
 }
 
 // findTestCloseBracket returns the byte offset of the closing `]`
@@ -760,23 +765,6 @@ func (s *bracketScan) terminated(source []byte, i int) bool {
 	return c == '\n' || c == ';'
 }
 
-// offsetToEdit builds a FixEdit whose Line/Column correspond to the
-// given byte offset inside source. Used when a Fix already has a
-// byte offset but the FixEdit type expects 1-based coordinates.
-func offsetToEdit(source []byte, offset, length int, replace string) FixEdit {
-	line := 1
-	col := 1
-	for i := 0; i < offset && i < len(source); i++ {
-		if source[i] == '\n' {
-			line++
-			col = 1
-			continue
-		}
-		col++
-	}
-	return FixEdit{Line: line, Column: col, Length: length, Replace: replace}
-}
-
 func checkZC1010(node ast.Node) []Violation {
 	violations := []Violation{}
 
@@ -875,9 +863,9 @@ func init() {
 		Title: "Use `read -r` to prevent backslash escaping",
 		Description: "By default, `read` interprets backslashes as escape characters. " +
 			"Use `read -r` to treat backslashes literally, which is usually what you want.",
-		Severity: SeverityStyle,
-		Check:    checkZC1012,
-		Fix:      fixZC1012,
+		Severity:   SeverityStyle,
+		Check:      checkZC1012,
+		FixContext: fixZC1012,
 	})
 }
 
@@ -886,7 +874,7 @@ func init() {
 // `read -r -p "x" VAR`) so the fix is order-preserving and idempotent
 // on a second pass (the re-parse will see `-r` and detection won't
 // fire).
-func fixZC1012(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1012(ctx *Context, node ast.Node, v Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -894,45 +882,9 @@ func fixZC1012(node ast.Node, v Violation, source []byte) []FixEdit {
 	if cmd.Name.String() != "read" {
 		return nil
 	}
-	nameOffset := LineColToByteOffset(source, v.Line, v.Column)
-	if nameOffset < 0 {
-		return nil
-	}
-	nameLen := IdentLenAt(source, nameOffset)
-	if nameLen != len("read") {
-		return nil
-	}
-	insertAt := nameOffset + nameLen
-	insertLine, insertCol := byteOffsetToLineColZC1012(source, insertAt)
-	if insertLine < 0 {
-		return nil
-	}
-	return []FixEdit{{
-		Line:    insertLine,
-		Column:  insertCol,
-		Length:  0,
-		Replace: " -r",
-	}}
-}
-
-// byteOffsetToLineColZC1012 converts a byte offset to a 1-based
-// (line, column). Kept kata-local to avoid exposing a shared helper
-// that the rest of the package does not yet need.
-func byteOffsetToLineColZC1012(source []byte, offset int) (int, int) {
-	if offset < 0 || offset > len(source) {
-		return -1, -1
-	}
-	line := 1
-	col := 1
-	for i := 0; i < offset; i++ {
-		if source[i] == '\n' {
-			line++
-			col = 1
-			continue
-		}
-		col++
-	}
-	return line, col
+	b := ctx.NewBuilder()
+	b.InsertArgBefore(cmd, 0, "-r")
+	return builtEdits(b)
 }
 
 func checkZC1012(node ast.Node) []Violation {
@@ -981,9 +933,9 @@ func init() {
 		Title: "Use `((...))` for arithmetic operations instead of `let`",
 		Description: "The `let` command is a shell builtin, but the `((...))` syntax is more portable " +
 			"and generally preferred for arithmetic operations in Zsh.",
-		Severity: SeverityInfo,
-		Check:    checkZC1013,
-		Fix:      fixZC1013,
+		Severity:   SeverityInfo,
+		Check:      checkZC1013,
+		FixContext: fixZC1013,
 	})
 }
 
@@ -994,7 +946,8 @@ func init() {
 // match the source. Multi-assignment forms (`let a=1 b=2`) are not
 // attempted — the Fix bails when the AST does not have a single
 // Name/Value pair.
-func fixZC1013(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1013(ctx *Context, node ast.Node, v Violation) []FixEdit {
+	source := ctx.Source
 	stmt, ok := node.(*ast.LetStatement)
 	if !ok {
 		return nil
@@ -1011,20 +964,12 @@ func fixZC1013(node ast.Node, v Violation, source []byte) []FixEdit {
 	if _, increment := zc1032Op(stmt); increment {
 		return nil
 	}
-	start := LineColToByteOffset(source, v.Line, v.Column)
+	b := ctx.NewBuilder()
+	start := b.Offset(v.Line, v.Column)
 	if start < 0 {
 		return nil
 	}
-	// Scan for the end of the statement: first semicolon, newline,
-	// or EOF.
-	end := start
-	for end < len(source) {
-		b := source[end]
-		if b == '\n' || b == ';' {
-			break
-		}
-		end++
-	}
+	end := letStatementEnd(source, start)
 	// `let NAME=EXPR` — after the keyword the source is NAME=EXPR.
 	// Split on the first `=` to honour the original spelling (the
 	// AST stores Name separately but Value.String() can lose
@@ -1056,12 +1001,18 @@ func fixZC1013(node ast.Node, v Violation, source []byte) []FixEdit {
 	name := body[:opStart]
 	op := body[opStart : eq+1]
 	rhs := body[eq+1:]
-	return []FixEdit{{
-		Line:    v.Line,
-		Column:  v.Column,
-		Length:  end - start,
-		Replace: "(( " + name + " " + op + " " + rhs + " ))",
-	}}
+	b.Replace(start, end, "(( "+name+" "+op+" "+rhs+" ))")
+	return builtEdits(b)
+}
+
+// letStatementEnd returns the offset that ends the `let` statement
+// starting at start: its first `;` or newline, or the end of source.
+func letStatementEnd(source []byte, start int) int {
+	end := start
+	for end < len(source) && source[end] != '\n' && source[end] != ';' {
+		end++
+	}
+	return end
 }
 
 // isArithAssignOpChar reports whether b can form part of a Zsh compound
@@ -1131,9 +1082,9 @@ func init() {
 		Title: "Use `$(...)` for command substitution instead of backticks",
 		Description: "The `$(...)` syntax is the modern, recommended way to perform command substitution. " +
 			"It is more readable and can be nested easily, unlike backticks.",
-		Severity: SeverityStyle,
-		Check:    checkZC1015,
-		Fix:      fixZC1002,
+		Severity:   SeverityStyle,
+		Check:      checkZC1015,
+		FixContext: fixZC1002,
 	})
 }
 
@@ -1159,16 +1110,16 @@ func init() {
 		Title: "Use `read -s` when reading sensitive information",
 		Description: "When asking for passwords or secrets, use `read -s` to prevent " +
 			"the input from being echoed to the terminal.",
-		Severity: SeverityStyle,
-		Check:    checkZC1016,
-		Fix:      fixZC1016,
+		Severity:   SeverityStyle,
+		Check:      checkZC1016,
+		FixContext: fixZC1016,
 	})
 }
 
 // fixZC1016 inserts ` -s` after the `read` command name. The detector
 // gates on the absence of `-s` in any flag bundle, so the insertion
 // is idempotent on a re-run.
-func fixZC1016(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1016(ctx *Context, node ast.Node, v Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -1176,38 +1127,9 @@ func fixZC1016(node ast.Node, v Violation, source []byte) []FixEdit {
 	if cmd.Name == nil || cmd.Name.String() != "read" {
 		return nil
 	}
-	nameOff := LineColToByteOffset(source, v.Line, v.Column)
-	if nameOff < 0 || IdentLenAt(source, nameOff) != len("read") {
-		return nil
-	}
-	insertAt := nameOff + len("read")
-	insLine, insCol := offsetLineColZC1016(source, insertAt)
-	if insLine < 0 {
-		return nil
-	}
-	return []FixEdit{{
-		Line:    insLine,
-		Column:  insCol,
-		Length:  0,
-		Replace: " -s",
-	}}
-}
-
-func offsetLineColZC1016(source []byte, offset int) (int, int) {
-	if offset < 0 || offset > len(source) {
-		return -1, -1
-	}
-	line := 1
-	col := 1
-	for i := 0; i < offset; i++ {
-		if source[i] == '\n' {
-			line++
-			col = 1
-			continue
-		}
-		col++
-	}
-	return line, col
+	b := ctx.NewBuilder()
+	b.InsertArgBefore(cmd, 0, "-s")
+	return builtEdits(b)
 }
 
 func checkZC1016(node ast.Node) []Violation {
@@ -1312,9 +1234,9 @@ func init() {
 		Title: "Use `print -r` to print strings literally",
 		Description: "The `print` command interprets backslash escape sequences by default. " +
 			"To print a string literally, use the `-r` option.",
-		Severity: SeverityStyle,
-		Check:    checkZC1017,
-		Fix:      fixZC1017,
+		Severity:   SeverityStyle,
+		Check:      checkZC1017,
+		FixContext: fixZC1017,
 	})
 }
 
@@ -1323,7 +1245,7 @@ func init() {
 // insertion: `print "x"` becomes `print -r "x"`, `print -n "x"`
 // becomes `print -r -n "x"`. Idempotent on a second pass — once
 // `-r` appears among the flags the detector no longer fires.
-func fixZC1017(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1017(ctx *Context, node ast.Node, v Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -1332,42 +1254,9 @@ func fixZC1017(node ast.Node, v Violation, source []byte) []FixEdit {
 	if !ok || name.Value != "print" {
 		return nil
 	}
-	nameOffset := LineColToByteOffset(source, v.Line, v.Column)
-	if nameOffset < 0 {
-		return nil
-	}
-	nameLen := IdentLenAt(source, nameOffset)
-	if nameLen != len("print") {
-		return nil
-	}
-	insertAt := nameOffset + nameLen
-	insLine, insCol := byteOffsetToLineColZC1017(source, insertAt)
-	if insLine < 0 {
-		return nil
-	}
-	return []FixEdit{{
-		Line:    insLine,
-		Column:  insCol,
-		Length:  0,
-		Replace: " -r",
-	}}
-}
-
-func byteOffsetToLineColZC1017(source []byte, offset int) (int, int) {
-	if offset < 0 || offset > len(source) {
-		return -1, -1
-	}
-	line := 1
-	col := 1
-	for i := 0; i < offset; i++ {
-		if source[i] == '\n' {
-			line++
-			col = 1
-			continue
-		}
-		col++
-	}
-	return line, col
+	b := ctx.NewBuilder()
+	b.InsertArgBefore(cmd, 0, "-r")
+	return builtEdits(b)
 }
 
 func checkZC1017(node ast.Node) []Violation {
@@ -1472,9 +1361,9 @@ func init() {
 		Description: "The `test` command is an external command and may not be available on all systems. " +
 			"The `[[...]]` construct is a Zsh keyword, offering safer and more powerful conditional " +
 			"expressions than the traditional `test` command.",
-		Severity: SeverityStyle,
-		Check:    checkZC1020,
-		Fix:      fixZC1293,
+		Severity:   SeverityStyle,
+		Check:      checkZC1020,
+		FixContext: fixZC1293,
 	})
 }
 
@@ -1544,7 +1433,7 @@ func init() {
 		// For a standalone arithmetic statement the `(( ))` command
 		// form is the right shape; the `$((...))` text in the message
 		// reads as the broader "use Zsh arithmetic" recommendation.
-		Fix: fixZC1013,
+		FixContext: fixZC1013,
 	})
 }
 
@@ -1753,16 +1642,16 @@ func init() {
 		Title: "Use `#!/usr/bin/env zsh` for portability",
 		Description: "Using `#!/usr/bin/env zsh` is more portable than `#!/bin/zsh` because it searches " +
 			"for the `zsh` executable in the user's `PATH`.",
-		Severity: SeverityInfo,
-		Check:    checkZC1031,
-		Fix:      fixZC1031,
+		Severity:   SeverityInfo,
+		Check:      checkZC1031,
+		FixContext: fixZC1031,
 	})
 }
 
 // fixZC1031 rewrites `#!/bin/zsh` to `#!/usr/bin/env zsh` in the
 // shebang line. Span-aware: replaces the whole `#!/bin/zsh` run as a
 // single edit at column 1, line 1.
-func fixZC1031(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1031(ctx *Context, node ast.Node, v Violation) []FixEdit {
 	shebang, ok := node.(*ast.Shebang)
 	if !ok {
 		return nil
@@ -1770,12 +1659,10 @@ func fixZC1031(node ast.Node, v Violation, source []byte) []FixEdit {
 	if shebang.Path != "#!/bin/zsh" {
 		return nil
 	}
-	return []FixEdit{{
-		Line:    v.Line,
-		Column:  v.Column,
-		Length:  len("#!/bin/zsh"),
-		Replace: "#!/usr/bin/env zsh",
-	}}
+	b := ctx.NewBuilder()
+	off := b.Offset(v.Line, v.Column)
+	b.Replace(off, off+len(shebang.Path), "#!/usr/bin/env zsh")
+	return builtEdits(b)
 }
 
 func checkZC1031(node ast.Node) []Violation {
@@ -1802,9 +1689,9 @@ func init() {
 		Title: "Use `((...))` for C-style incrementing",
 		Description: "Instead of `let i=i+1` or `let i=i-1`, you can use the more concise and idiomatic " +
 			"C-style increment `(( i++ ))` / decrement `(( i-- ))` in Zsh.",
-		Severity: SeverityStyle,
-		Check:    checkZC1032,
-		Fix:      fixZC1032,
+		Severity:   SeverityStyle,
+		Check:      checkZC1032,
+		FixContext: fixZC1032,
 	})
 }
 
@@ -1857,7 +1744,8 @@ func zc1032Op(stmt *ast.LetStatement) (string, bool) {
 // the increment/decrement shape so the fix stays unambiguous and
 // idempotent on a re-run (the rewritten form is no longer a
 // LetStatement).
-func fixZC1032(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1032(ctx *Context, node ast.Node, v Violation) []FixEdit {
+	source := ctx.Source
 	stmt, ok := node.(*ast.LetStatement)
 	if !ok {
 		return nil
@@ -1866,24 +1754,13 @@ func fixZC1032(node ast.Node, v Violation, source []byte) []FixEdit {
 	if !ok {
 		return nil
 	}
-	start := LineColToByteOffset(source, v.Line, v.Column)
+	b := ctx.NewBuilder()
+	start := b.Offset(v.Line, v.Column)
 	if start < 0 {
 		return nil
 	}
-	end := start
-	for end < len(source) {
-		b := source[end]
-		if b == '\n' || b == ';' {
-			break
-		}
-		end++
-	}
-	return []FixEdit{{
-		Line:    v.Line,
-		Column:  v.Column,
-		Length:  end - start,
-		Replace: "(( " + stmt.Name.Value + suffix + " ))",
-	}}
+	b.Replace(start, letStatementEnd(source, start), "(( "+stmt.Name.Value+suffix+" ))")
+	return builtEdits(b)
 }
 
 func checkZC1032(node ast.Node) []Violation {
@@ -1939,16 +1816,16 @@ func init() {
 		Description: "`which` is an external command and may not be available or consistent across all " +
 			"systems. `command -v` is a POSIX standard and a shell builtin, making it more portable " +
 			"and reliable for checking if a command exists.",
-		Severity: SeverityStyle,
-		Check:    checkZC1034,
-		Fix:      fixZC1034,
+		Severity:   SeverityStyle,
+		Check:      checkZC1034,
+		FixContext: fixZC1034,
 	})
 }
 
 // fixZC1034 rewrites `which` to `command -v` at the command name
 // position inside an ExpressionStatement. Single replacement — arguments
 // stay untouched.
-func fixZC1034(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1034(ctx *Context, node ast.Node, v Violation) []FixEdit {
 	es, ok := node.(*ast.ExpressionStatement)
 	if !ok {
 		return nil
@@ -1960,12 +1837,9 @@ func fixZC1034(node ast.Node, v Violation, source []byte) []FixEdit {
 	if cmd.Name == nil || cmd.Name.TokenLiteral() != "which" {
 		return nil
 	}
-	return []FixEdit{{
-		Line:    v.Line,
-		Column:  v.Column,
-		Length:  len("which"),
-		Replace: "command -v",
-	}}
+	b := ctx.NewBuilder()
+	b.ReplaceCommandName(cmd, "command -v")
+	return builtEdits(b)
 }
 
 func checkZC1034(node ast.Node) []Violation {
@@ -2017,9 +1891,9 @@ func init() {
 		Description: "The `[[ ... ]]` construct is a more powerful and safer alternative to the `test` " +
 			"command (or `[ ... ]`) for conditional expressions in modern shells. It handles word " +
 			"splitting and globbing more intuitively and supports advanced features like regex matching.",
-		Severity: SeverityStyle,
-		Check:    checkZC1036,
-		Fix:      fixZC1293,
+		Severity:   SeverityStyle,
+		Check:      checkZC1036,
+		FixContext: fixZC1293,
 	})
 }
 
@@ -2054,7 +1928,7 @@ func init() {
 		// here is a stricter subset (only fires when echo prints a
 		// variable expansion) but the rewrite is identical; the
 		// conflict resolver dedupes overlapping edits.
-		Fix: fixZC1092,
+		FixContext: fixZC1092,
 	})
 }
 
@@ -2197,9 +2071,9 @@ func init() {
 		Title: "Use (N) nullglob qualifier for globs in loops",
 		Description: "In Zsh, a glob that matches nothing (e.g., `*.txt`) will cause an error by default. " +
 			"Use the `(N)` glob qualifier to make it null (empty) if no matches found, preventing the error.",
-		Severity: SeverityStyle,
-		Check:    checkZC1040,
-		Fix:      fixZC1040,
+		Severity:   SeverityStyle,
+		Check:      checkZC1040,
+		FixContext: fixZC1040,
 	})
 }
 
@@ -2207,8 +2081,10 @@ func init() {
 // list, turning `for f in *.txt` into `for f in *.txt(N)` so an
 // empty match produces an empty iterator instead of an error.
 // Span scanning ends at the first unescaped whitespace / delimiter.
-func fixZC1040(_ ast.Node, v Violation, source []byte) []FixEdit {
-	start := LineColToByteOffset(source, v.Line, v.Column)
+func fixZC1040(ctx *Context, _ ast.Node, v Violation) []FixEdit {
+	source := ctx.Source
+	b := ctx.NewBuilder()
+	start := b.Offset(v.Line, v.Column)
 	if start < 0 || start >= len(source) {
 		return nil
 	}
@@ -2225,33 +2101,8 @@ func fixZC1040(_ ast.Node, v Violation, source []byte) []FixEdit {
 	if hasNullGlobQualifier(string(source[start:end])) {
 		return nil
 	}
-	endLine, endCol := offsetLineColZC1040(source, end)
-	if endLine < 0 {
-		return nil
-	}
-	return []FixEdit{{
-		Line:    endLine,
-		Column:  endCol,
-		Length:  0,
-		Replace: "(N)",
-	}}
-}
-
-func offsetLineColZC1040(source []byte, offset int) (int, int) {
-	if offset < 0 || offset > len(source) {
-		return -1, -1
-	}
-	line := 1
-	col := 1
-	for i := 0; i < offset; i++ {
-		if source[i] == '\n' {
-			line++
-			col = 1
-			continue
-		}
-		col++
-	}
-	return line, col
+	b.Insert(end, "(N)")
+	return builtEdits(b)
 }
 
 func checkZC1040(node ast.Node) []Violation {
@@ -2534,9 +2385,9 @@ func init() {
 		Title: "Use `local` for variables in functions",
 		Description: "Variables defined in functions are global by default in Zsh. " +
 			"Use `local` to scope them to the function.",
-		Severity: SeverityStyle,
-		Check:    checkZC1043,
-		Fix:      fixZC1043,
+		Severity:   SeverityStyle,
+		Check:      checkZC1043,
+		FixContext: fixZC1043,
 	})
 }
 
@@ -2545,8 +2396,10 @@ func init() {
 // inserting `local ` there yields `local NAME=value`. On re-run the
 // detector recognises `local …` as a declaration and skips the line,
 // so the rewrite is idempotent.
-func fixZC1043(_ ast.Node, v Violation, source []byte) []FixEdit {
-	off := LineColToByteOffset(source, v.Line, v.Column)
+func fixZC1043(ctx *Context, _ ast.Node, v Violation) []FixEdit {
+	source := ctx.Source
+	b := ctx.NewBuilder()
+	off := b.Offset(v.Line, v.Column)
 	if off < 0 || off >= len(source) {
 		return nil
 	}
@@ -2558,12 +2411,8 @@ func fixZC1043(_ ast.Node, v Violation, source []byte) []FixEdit {
 			return nil
 		}
 	}
-	return []FixEdit{{
-		Line:    v.Line,
-		Column:  v.Column,
-		Length:  0,
-		Replace: "local ",
-	}}
+	b.Insert(off, "local ")
+	return builtEdits(b)
 }
 
 var zc1043LocalDecls = map[string]struct{}{
@@ -3324,9 +3173,9 @@ func init() {
 			"with `${dir:?}` or `[[ -n $dir ]]`. In default Zsh an unquoted `$var` " +
 			"does not word-split or glob (those are Bash / `emulate sh` behaviors), " +
 			"but unquoted command substitution `$(...)` does split.",
-		Severity: SeverityWarning,
-		Check:    checkZC1051,
-		Fix:      fixZC1051,
+		Severity:   SeverityWarning,
+		Check:      checkZC1051,
+		FixContext: fixZC1051,
 	})
 }
 
@@ -3336,8 +3185,10 @@ func init() {
 // position until the first unescaped whitespace / delimiter,
 // honouring `{…}` / `[…]` / `(…)` nesting so expansions like
 // `${var[1]}`, `$(cmd)`, `${arr[@]}` stay whole.
-func fixZC1051(_ ast.Node, v Violation, source []byte) []FixEdit {
-	start := LineColToByteOffset(source, v.Line, v.Column)
+func fixZC1051(ctx *Context, _ ast.Node, v Violation) []FixEdit {
+	source := ctx.Source
+	b := ctx.NewBuilder()
+	start := b.Offset(v.Line, v.Column)
 	if start < 0 || start >= len(source) {
 		return nil
 	}
@@ -3345,14 +3196,9 @@ func fixZC1051(_ ast.Node, v Violation, source []byte) []FixEdit {
 	if argLen == 0 {
 		return nil
 	}
-	endLine, endCol := offsetLineColZC1051(source, start+argLen)
-	if endLine < 0 {
-		return nil
-	}
-	return []FixEdit{
-		{Line: v.Line, Column: v.Column, Length: 0, Replace: `"`},
-		{Line: endLine, Column: endCol, Length: 0, Replace: `"`},
-	}
+	b.Insert(start, `"`)
+	b.Insert(start+argLen, `"`)
+	return builtEdits(b)
 }
 
 // unquotedArgLen returns the byte length of a shell-word starting
@@ -3413,23 +3259,6 @@ func unquotedArgIsTerminator(c byte) bool {
 	return false
 }
 
-func offsetLineColZC1051(source []byte, offset int) (int, int) {
-	if offset < 0 || offset > len(source) {
-		return -1, -1
-	}
-	line := 1
-	col := 1
-	for i := 0; i < offset; i++ {
-		if source[i] == '\n' {
-			line++
-			col = 1
-			continue
-		}
-		col++
-	}
-	return line, col
-}
-
 func checkZC1051(node ast.Node) []Violation {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
@@ -3530,18 +3359,18 @@ func init() {
 		Title: "Silence `grep` output in conditions",
 		Description: "Using `grep` in a condition prints matches to stdout. " +
 			"Use `grep -q` (or `> /dev/null`) to silence output if you only care about the exit code.",
-		Severity: SeverityStyle,
-		Check:    checkZC1053,
-		Fix:      fixZC1053,
+		Severity:   SeverityStyle,
+		Check:      checkZC1053,
+		FixContext: fixZC1053,
 	})
 	RegisterKata(ast.WhileLoopStatementNode, Kata{
 		ID:    "ZC1053",
 		Title: "Silence `grep` output in conditions",
 		Description: "Using `grep` in a condition prints matches to stdout. " +
 			"Use `grep -q` (or `> /dev/null`) to silence output if you only care about the exit code.",
-		Severity: SeverityStyle,
-		Check:    checkZC1053,
-		Fix:      fixZC1053,
+		Severity:   SeverityStyle,
+		Check:      checkZC1053,
+		FixContext: fixZC1053,
 	})
 }
 
@@ -3551,8 +3380,10 @@ func init() {
 // and the kata no longer fires. Defensive byte-match guard refuses
 // to insert unless the source at the offset is one of the recognised
 // grep variants followed by whitespace.
-func fixZC1053(_ ast.Node, v Violation, source []byte) []FixEdit {
-	off := LineColToByteOffset(source, v.Line, v.Column)
+func fixZC1053(ctx *Context, _ ast.Node, v Violation) []FixEdit {
+	source := ctx.Source
+	b := ctx.NewBuilder()
+	off := b.Offset(v.Line, v.Column)
 	if off < 0 {
 		return nil
 	}
@@ -3578,34 +3409,8 @@ func fixZC1053(_ ast.Node, v Violation, source []byte) []FixEdit {
 	if name == "" {
 		return nil
 	}
-	insertAt := off + len(name)
-	line, col := offsetLineColZC1053(source, insertAt)
-	if line < 0 {
-		return nil
-	}
-	return []FixEdit{{
-		Line:    line,
-		Column:  col,
-		Length:  0,
-		Replace: " -q",
-	}}
-}
-
-func offsetLineColZC1053(source []byte, offset int) (int, int) {
-	if offset < 0 || offset > len(source) {
-		return -1, -1
-	}
-	line := 1
-	col := 1
-	for i := 0; i < offset; i++ {
-		if source[i] == '\n' {
-			line++
-			col = 1
-			continue
-		}
-		col++
-	}
-	return line, col
+	b.Insert(off+len(name), " -q")
+	return builtEdits(b)
 }
 
 func checkZC1053(node ast.Node) []Violation {
@@ -3815,9 +3620,9 @@ func init() {
 		Title: "Use `[[ -n/-z ]]` for empty string checks",
 		Description: "Comparing with empty string is less idiomatic than using `[[ -z $var ]]` (is empty) " +
 			"or `[[ -n $var ]]` (is not empty).",
-		Severity: SeverityStyle,
-		Check:    checkZC1055,
-		Fix:      fixZC1055,
+		Severity:   SeverityStyle,
+		Check:      checkZC1055,
+		FixContext: fixZC1055,
 	})
 }
 
@@ -3864,7 +3669,8 @@ func checkZC1055(node ast.Node) []Violation {
 // `-n $var` respectively. The span covers the full infix expression
 // so `[[ $var == "" ]]` ends up as `[[ -z $var ]]`. Handles both
 // left-side and right-side empty-string positions.
-func fixZC1055(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1055(ctx *Context, node ast.Node, v Violation) []FixEdit {
+	source := ctx.Source
 	expr, ok := node.(*ast.InfixExpression)
 	if !ok || (expr.Operator != "==" && expr.Operator != "!=") {
 		return nil
@@ -3873,7 +3679,8 @@ func fixZC1055(node ast.Node, v Violation, source []byte) []FixEdit {
 	if !ok {
 		return nil
 	}
-	varOffset, emptyOffset, ok := zc1055OperandOffsets(source, varNode, emptyNode)
+	b := ctx.NewBuilder()
+	varOffset, emptyOffset, ok := zc1055OperandOffsets(b, varNode, emptyNode)
 	if !ok {
 		return nil
 	}
@@ -3890,16 +3697,8 @@ func fixZC1055(node ast.Node, v Violation, source []byte) []FixEdit {
 		op = "-n"
 	}
 	varText := string(source[varOffset : varOffset+identOrVarLen(source, varOffset)])
-	line, col := v.Line, v.Column
-	if startLine, startCol := byteOffsetToLineColZC1055(source, start); startLine > 0 {
-		line, col = startLine, startCol
-	}
-	return []FixEdit{{
-		Line:    line,
-		Column:  col,
-		Length:  end - start,
-		Replace: op + " " + varText,
-	}}
+	b.Replace(start, end, op+" "+varText)
+	return builtEdits(b)
 }
 
 // zc1055SplitOperands locates the empty-string literal side of a
@@ -3926,7 +3725,7 @@ func zc1055IsEmptyLiteral(n ast.Node) (bool, int) {
 	return false, 0
 }
 
-func zc1055OperandOffsets(source []byte, varNode, emptyNode ast.Node) (int, int, bool) {
+func zc1055OperandOffsets(b *fixbuild.Builder, varNode, emptyNode ast.Node) (int, int, bool) {
 	varExpr, vok := varNode.(ast.Expression)
 	emptyExpr, eok := emptyNode.(ast.Expression)
 	if !vok || !eok {
@@ -3937,8 +3736,8 @@ func zc1055OperandOffsets(source []byte, varNode, emptyNode ast.Node) (int, int,
 	if varTok.Line == 0 || emptyTok.Line == 0 {
 		return 0, 0, false
 	}
-	varOff := LineColToByteOffset(source, varTok.Line, varTok.Column)
-	emptyOff := LineColToByteOffset(source, emptyTok.Line, emptyTok.Column)
+	varOff := b.Offset(varTok.Line, varTok.Column)
+	emptyOff := b.Offset(emptyTok.Line, emptyTok.Column)
 	if varOff < 0 || emptyOff < 0 {
 		return 0, 0, false
 	}
@@ -3978,23 +3777,6 @@ func identOrVarLen(source []byte, offset int) int {
 	return n
 }
 
-func byteOffsetToLineColZC1055(source []byte, offset int) (int, int) {
-	if offset < 0 || offset > len(source) {
-		return -1, -1
-	}
-	line := 1
-	col := 1
-	for i := 0; i < offset; i++ {
-		if source[i] == '\n' {
-			line++
-			col = 1
-			continue
-		}
-		col++
-	}
-	return line, col
-}
-
 func init() {
 	RegisterKata(ast.SimpleCommandNode, Kata{
 		ID:    "ZC1056",
@@ -4402,7 +4184,7 @@ func init() {
 		Description: "Using `seq` creates an external process. Zsh supports integer range expansion natively: `{1..10}`.",
 		Severity:    SeverityStyle,
 		Check:       checkZC1061,
-		Fix:         fixZC1061,
+		FixContext:  fixZC1061,
 	})
 }
 
@@ -4410,7 +4192,7 @@ func init() {
 // literal arguments into Zsh's brace range expansion `{M..N}` or
 // `{M..N..S}`. Forms with `-s sep` separator, variable arguments,
 // or floats are left alone because the rewrite semantics differ.
-func fixZC1061(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1061(ctx *Context, node ast.Node, v Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -4439,28 +4221,11 @@ func fixZC1061(node ast.Node, v Violation, source []byte) []FixEdit {
 	case 3:
 		rng = "{" + nums[0] + ".." + nums[2] + ".." + nums[1] + "}"
 	}
-	// Replace from the `seq` command name through the last argument
-	// so the whole invocation becomes a single brace expansion.
-	start := LineColToByteOffset(source, v.Line, v.Column)
-	if start < 0 {
-		return nil
-	}
-	lastArg := cmd.Arguments[len(cmd.Arguments)-1]
-	lastTok := lastArg.TokenLiteralNode()
-	lastOff := LineColToByteOffset(source, lastTok.Line, lastTok.Column)
-	if lastOff < 0 {
-		return nil
-	}
-	end := lastOff + len(lastTok.Literal)
-	if end <= start {
-		return nil
-	}
-	return []FixEdit{{
-		Line:    v.Line,
-		Column:  v.Column,
-		Length:  end - start,
-		Replace: rng,
-	}}
+	// Replace the whole `seq` invocation, name through last argument,
+	// with a single brace expansion.
+	b := ctx.NewBuilder()
+	b.ReplaceNode(cmd, rng)
+	return builtEdits(b)
 }
 
 func isAllDigits(s string) bool {
@@ -4502,13 +4267,13 @@ func init() {
 		Description: "`egrep` is deprecated. Use `grep -E` instead.",
 		Severity:    SeverityInfo,
 		Check:       checkZC1062,
-		Fix:         fixZC1062,
+		FixContext:  fixZC1062,
 	})
 }
 
 // fixZC1062 rewrites `egrep` to `grep -E` at the command name
 // position. Single replacement — arguments stay untouched.
-func fixZC1062(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1062(ctx *Context, node ast.Node, v Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -4517,12 +4282,9 @@ func fixZC1062(node ast.Node, v Violation, source []byte) []FixEdit {
 	if !ok || ident.Value != "egrep" {
 		return nil
 	}
-	return []FixEdit{{
-		Line:    v.Line,
-		Column:  v.Column,
-		Length:  len("egrep"),
-		Replace: "grep -E",
-	}}
+	b := ctx.NewBuilder()
+	b.ReplaceCommandName(cmd, "grep -E")
+	return builtEdits(b)
 }
 
 func checkZC1062(node ast.Node) []Violation {
@@ -4551,13 +4313,13 @@ func init() {
 		Description: "`fgrep` is deprecated. Use `grep -F` instead.",
 		Severity:    SeverityInfo,
 		Check:       checkZC1063,
-		Fix:         fixZC1063,
+		FixContext:  fixZC1063,
 	})
 }
 
 // fixZC1063 rewrites `fgrep` to `grep -F` at the command name
 // position. Single replacement — arguments stay untouched.
-func fixZC1063(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1063(ctx *Context, node ast.Node, v Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -4566,12 +4328,9 @@ func fixZC1063(node ast.Node, v Violation, source []byte) []FixEdit {
 	if !ok || ident.Value != "fgrep" {
 		return nil
 	}
-	return []FixEdit{{
-		Line:    v.Line,
-		Column:  v.Column,
-		Length:  len("fgrep"),
-		Replace: "grep -F",
-	}}
+	b := ctx.NewBuilder()
+	b.ReplaceCommandName(cmd, "grep -F")
+	return builtEdits(b)
 }
 
 func checkZC1063(node ast.Node) []Violation {
@@ -4599,15 +4358,15 @@ func init() {
 		Title: "Prefer `command -v` over `type`",
 		Description: "`type` output format varies and is not POSIX standard for checking existence. " +
 			"`command -v` is quieter and standard.",
-		Severity: SeverityInfo,
-		Check:    checkZC1064,
-		Fix:      fixZC1064,
+		Severity:   SeverityInfo,
+		Check:      checkZC1064,
+		FixContext: fixZC1064,
 	})
 }
 
 // fixZC1064 rewrites `type` to `command -v` at the command name
 // position. Single replacement — arguments stay untouched.
-func fixZC1064(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1064(ctx *Context, node ast.Node, v Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -4616,12 +4375,9 @@ func fixZC1064(node ast.Node, v Violation, source []byte) []FixEdit {
 	if !ok || ident.Value != "type" {
 		return nil
 	}
-	return []FixEdit{{
-		Line:    v.Line,
-		Column:  v.Column,
-		Length:  len("type"),
-		Replace: "command -v",
-	}}
+	b := ctx.NewBuilder()
+	b.ReplaceCommandName(cmd, "command -v")
+	return builtEdits(b)
 }
 
 func checkZC1064(node ast.Node) []Violation {
@@ -5185,7 +4941,8 @@ func init() {
 		Description: "Variables in `((...))` do not need `$` prefix. Use `(( var > 0 ))` instead of `(( $var > 0 ))`.",
 		Severity:    SeverityStyle,
 		Check:       checkZC1073,
-		Fix:         fixZC1073,
+		FixContext:  fixZC1073,
+		FixSafety:   FixSafe,
 	})
 }
 
@@ -5193,17 +4950,15 @@ func init() {
 // `(( … ))`. The violation coordinates already point at the `$`
 // byte, so a single zero-replacement edit removes it. A second pass
 // won't re-trigger because the identifier no longer carries `$`.
-func fixZC1073(_ ast.Node, v Violation, source []byte) []FixEdit {
-	off := LineColToByteOffset(source, v.Line, v.Column)
+func fixZC1073(ctx *Context, _ ast.Node, v Violation) []FixEdit {
+	source := ctx.Source
+	b := ctx.NewBuilder()
+	off := b.Offset(v.Line, v.Column)
 	if off < 0 || off >= len(source) || source[off] != '$' {
 		return nil
 	}
-	return []FixEdit{{
-		Line:    v.Line,
-		Column:  v.Column,
-		Length:  1,
-		Replace: "",
-	}}
+	b.Replace(off, off+1, "")
+	return builtEdits(b)
 }
 
 func checkZC1073(node ast.Node) []Violation {
@@ -5735,16 +5490,16 @@ func init() {
 		Title: "Use `autoload -Uz` for lazy loading",
 		Description: "When using `autoload`, prefer `-Uz` to ensure standard Zsh behavior (no alias expansion, zsh style). " +
 			"`-U` prevents alias expansion, and `-z` ensures Zsh style autoloading.",
-		Severity: SeverityStyle,
-		Check:    checkZC1076,
-		Fix:      fixZC1076,
+		Severity:   SeverityStyle,
+		Check:      checkZC1076,
+		FixContext: fixZC1076,
 	})
 }
 
 // fixZC1076 inserts ` -Uz` after the `autoload` command name. Only
 // fires when neither `U` nor `z` are already present; the detector
 // already gates on that. Idempotent on re-run once both flags exist.
-func fixZC1076(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1076(ctx *Context, node ast.Node, v Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -5752,29 +5507,13 @@ func fixZC1076(node ast.Node, v Violation, source []byte) []FixEdit {
 	if cmd.Name.String() != "autoload" {
 		return nil
 	}
-	nameOffset := LineColToByteOffset(source, v.Line, v.Column)
-	if nameOffset < 0 {
-		return nil
-	}
-	nameLen := IdentLenAt(source, nameOffset)
-	if nameLen != len("autoload") {
-		return nil
-	}
 	insert := zc1076MissingFlags(cmd)
 	if insert == "" {
 		return nil
 	}
-	insertAt := nameOffset + nameLen
-	insLine, insCol := offsetLineColZC1076(source, insertAt)
-	if insLine < 0 {
-		return nil
-	}
-	return []FixEdit{{
-		Line:    insLine,
-		Column:  insCol,
-		Length:  0,
-		Replace: insert,
-	}}
+	b := ctx.NewBuilder()
+	b.InsertArgBefore(cmd, 0, strings.TrimPrefix(insert, " "))
+	return builtEdits(b)
 }
 
 // zc1076MissingFlags returns the flags to insert after `autoload` so the
@@ -5807,23 +5546,6 @@ func zc1076MissingFlags(cmd *ast.SimpleCommand) string {
 	}
 }
 
-func offsetLineColZC1076(source []byte, offset int) (int, int) {
-	if offset < 0 || offset > len(source) {
-		return -1, -1
-	}
-	line := 1
-	col := 1
-	for i := 0; i < offset; i++ {
-		if source[i] == '\n' {
-			line++
-			col = 1
-			continue
-		}
-		col++
-	}
-	return line, col
-}
-
 func checkZC1076(node ast.Node) []Violation {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
@@ -5964,48 +5686,28 @@ func init() {
 		Description: "Unlike Bash, Zsh does not word-split `$@`/`$*` (SH_WORD_SPLIT is off by default), so element grouping is preserved. " +
 			"The real difference is that unquoted `$@`/`$*` drops empty elements: with `set -- a '' c`, `$@` yields `a c` while `\"$@\"` yields `a '' c`. " +
 			"Use `\"$@\"` to keep empty positional parameters, or `\"$*\"` to join all elements into a single string.",
-		Severity: SeverityWarning,
-		Check:    checkZC1078,
-		Fix:      fixZC1078,
+		Severity:   SeverityWarning,
+		Check:      checkZC1078,
+		FixContext: fixZC1078,
 	})
 }
 
 // fixZC1078 wraps an unquoted `$@` / `$*` argument in double-quotes.
 // Both tokens are exactly two bytes; the two-edit insertion always
 // surrounds the same 2-byte run.
-func fixZC1078(_ ast.Node, v Violation, source []byte) []FixEdit {
-	start := LineColToByteOffset(source, v.Line, v.Column)
+func fixZC1078(ctx *Context, _ ast.Node, v Violation) []FixEdit {
+	source := ctx.Source
+	b := ctx.NewBuilder()
+	start := b.Offset(v.Line, v.Column)
 	if start < 0 || start+2 > len(source) {
 		return nil
 	}
 	if source[start] != '$' || (source[start+1] != '@' && source[start+1] != '*') {
 		return nil
 	}
-	endLine, endCol := offsetLineColZC1078(source, start+2)
-	if endLine < 0 {
-		return nil
-	}
-	return []FixEdit{
-		{Line: v.Line, Column: v.Column, Length: 0, Replace: `"`},
-		{Line: endLine, Column: endCol, Length: 0, Replace: `"`},
-	}
-}
-
-func offsetLineColZC1078(source []byte, offset int) (int, int) {
-	if offset < 0 || offset > len(source) {
-		return -1, -1
-	}
-	line := 1
-	col := 1
-	for i := 0; i < offset; i++ {
-		if source[i] == '\n' {
-			line++
-			col = 1
-			continue
-		}
-		col++
-	}
-	return line, col
+	b.Insert(start, `"`)
+	b.Insert(start+2, `"`)
+	return builtEdits(b)
 }
 
 func checkZC1078(node ast.Node) []Violation {
@@ -6366,9 +6068,9 @@ func init() {
 		Description: "Unquoted globs in `find` commands are expanded by the shell before `find` runs. " +
 			"If files match, `find` receives the list of files instead of the pattern. " +
 			"Quote arguments to `-name`, `-path`, etc.",
-		Severity: SeverityWarning,
-		Check:    checkZC1084,
-		Fix:      fixZC1084,
+		Severity:   SeverityWarning,
+		Check:      checkZC1084,
+		FixContext: fixZC1084,
 	})
 }
 
@@ -6377,8 +6079,10 @@ func init() {
 // column already points at the pattern arg start. Span scanning
 // respects `[…]` / `{…}` so character classes and alternations
 // stay whole.
-func fixZC1084(_ ast.Node, v Violation, source []byte) []FixEdit {
-	start := LineColToByteOffset(source, v.Line, v.Column)
+func fixZC1084(ctx *Context, _ ast.Node, v Violation) []FixEdit {
+	source := ctx.Source
+	b := ctx.NewBuilder()
+	start := b.Offset(v.Line, v.Column)
 	if start < 0 || start >= len(source) {
 		return nil
 	}
@@ -6386,31 +6090,9 @@ func fixZC1084(_ ast.Node, v Violation, source []byte) []FixEdit {
 	if argLen == 0 {
 		return nil
 	}
-	endLine, endCol := offsetLineColZC1084(source, start+argLen)
-	if endLine < 0 {
-		return nil
-	}
-	return []FixEdit{
-		{Line: v.Line, Column: v.Column, Length: 0, Replace: `'`},
-		{Line: endLine, Column: endCol, Length: 0, Replace: `'`},
-	}
-}
-
-func offsetLineColZC1084(source []byte, offset int) (int, int) {
-	if offset < 0 || offset > len(source) {
-		return -1, -1
-	}
-	line := 1
-	col := 1
-	for i := 0; i < offset; i++ {
-		if source[i] == '\n' {
-			line++
-			col = 1
-			continue
-		}
-		col++
-	}
-	return line, col
+	b.Insert(start, `'`)
+	b.Insert(start+argLen, `'`)
+	return builtEdits(b)
 }
 
 func checkZC1084(node ast.Node) []Violation {
@@ -6634,30 +6316,34 @@ func init() {
 		Title: "Prefer `func() { ... }` over `function func { ... }`",
 		Description: "The `function` keyword is optional in Zsh and non-standard in POSIX sh. " +
 			"Using `func() { ... }` is more portable and consistent.",
-		Severity: SeverityStyle,
-		Check:    checkZC1086,
-		Fix:      fixZC1086,
+		Severity:   SeverityStyle,
+		Check:      checkZC1086,
+		FixContext: fixZC1086,
+		FixSafety:  FixSafe,
 	})
 	RegisterKata(ast.FunctionLiteralNode, Kata{
 		ID:    "ZC1086",
 		Title: "Prefer `func() { ... }` over `function func { ... }`",
 		Description: "The `function` keyword is optional in Zsh and non-standard in POSIX sh. " +
 			"Using `func() { ... }` is more portable and consistent.",
-		Severity: SeverityStyle,
-		Check:    checkZC1086,
-		Fix:      fixZC1086,
+		Severity:   SeverityStyle,
+		Check:      checkZC1086,
+		FixContext: fixZC1086,
+		FixSafety:  FixSafe,
 	})
 }
 
 // fixZC1086 rewrites `function name [()] { body }` to the portable
 // `name() { body }` form. Deletes the `function ` prefix and, when
 // the source doesn't already carry `()` after the name, inserts it.
-func fixZC1086(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1086(ctx *Context, node ast.Node, v Violation) []FixEdit {
+	source := ctx.Source
 	name, ok := zc1086FunctionName(node)
 	if !ok || name == "" {
 		return nil
 	}
-	kwOffset, ok := zc1086KeywordOffset(source, v)
+	b := ctx.NewBuilder()
+	kwOffset, ok := zc1086KeywordOffset(b, v)
 	if !ok {
 		return nil
 	}
@@ -6677,16 +6363,11 @@ func fixZC1086(node ast.Node, v Violation, source []byte) []FixEdit {
 			return nil
 		}
 	}
-	edits := []FixEdit{{
-		Line:    v.Line,
-		Column:  v.Column,
-		Length:  nameStart - kwOffset,
-		Replace: "",
-	}}
-	if extra := zc1086MaybeAppendParens(source, nameStart+len(name)); extra != nil {
-		edits = append(edits, *extra)
+	b.Replace(kwOffset, nameStart, "")
+	if zc1086NeedsParens(source, nameStart+len(name)) {
+		b.Insert(nameStart+len(name), "()")
 	}
-	return edits
+	return builtEdits(b)
 }
 
 func zc1086FunctionName(node ast.Node) (string, bool) {
@@ -6705,8 +6386,9 @@ func zc1086FunctionName(node ast.Node) (string, bool) {
 	return "", false
 }
 
-func zc1086KeywordOffset(source []byte, v Violation) (int, bool) {
-	off := LineColToByteOffset(source, v.Line, v.Column)
+func zc1086KeywordOffset(b *fixbuild.Builder, v Violation) (int, bool) {
+	source := b.Source()
+	off := b.Offset(v.Line, v.Column)
 	if off < 0 || off+len("function ") > len(source) {
 		return 0, false
 	}
@@ -6727,36 +6409,14 @@ func zc1086NameStart(source []byte, kwOffset int, name string) (int, bool) {
 	return i, true
 }
 
-func zc1086MaybeAppendParens(source []byte, after int) *FixEdit {
+// zc1086NeedsParens reports whether the name ending at after lacks the
+// `()` the portable form needs.
+func zc1086NeedsParens(source []byte, after int) bool {
 	j := after
 	for j < len(source) && (source[j] == ' ' || source[j] == '\t') {
 		j++
 	}
-	if j < len(source) && source[j] == '(' {
-		return nil
-	}
-	line, col := offsetLineColZC1086(source, after)
-	if line < 0 {
-		return nil
-	}
-	return &FixEdit{Line: line, Column: col, Length: 0, Replace: "()"}
-}
-
-func offsetLineColZC1086(source []byte, offset int) (int, int) {
-	if offset < 0 || offset > len(source) {
-		return -1, -1
-	}
-	line := 1
-	col := 1
-	for i := 0; i < offset; i++ {
-		if source[i] == '\n' {
-			line++
-			col = 1
-			continue
-		}
-		col++
-	}
-	return line, col
+	return j >= len(source) || source[j] != '('
 }
 
 func checkZC1086(node ast.Node) []Violation {
@@ -7191,9 +6851,9 @@ func init() {
 		Description: "The `[[ ... ]]` construct is primarily for string comparisons and file tests. " +
 			"For arithmetic comparisons (`-eq`, `-lt`, etc.), use the dedicated arithmetic context `(( ... ))`. " +
 			"It is cleaner and strictly numeric.",
-		Severity: SeverityStyle,
-		Check:    checkZC1091,
-		Fix:      fixZC1091,
+		Severity:   SeverityStyle,
+		Check:      checkZC1091,
+		FixContext: fixZC1091,
 	})
 }
 
@@ -7202,13 +6862,15 @@ func init() {
 // `[[ x -lt 10 ]]` → `(( x < 10 ))`. Only fires when exactly one
 // recognised operator appears inside the brackets to keep the
 // rewrite unambiguous.
-func fixZC1091(node ast.Node, _ Violation, source []byte) []FixEdit {
+func fixZC1091(ctx *Context, node ast.Node, _ Violation) []FixEdit {
+	source := ctx.Source
 	dbe, ok := node.(*ast.DoubleBracketExpression)
 	if !ok {
 		return nil
 	}
-	openOff, openLine, openCol, ok := zc1091OpenBracket(source, dbe)
-	if !ok {
+	b := ctx.NewBuilder()
+	openOff := b.TokenOffset(dbe.Token)
+	if openOff < 0 || openOff+2 > len(source) || source[openOff] != '[' || source[openOff+1] != '[' {
 		return nil
 	}
 	closeOff := findDoubleBracketClose(source, openOff+2)
@@ -7219,32 +6881,14 @@ func fixZC1091(node ast.Node, _ Violation, source []byte) []FixEdit {
 	if !ok {
 		return nil
 	}
-	closeLine, closeCol := offsetLineColZC1091(source, closeOff)
-	if closeLine < 0 {
+	opOff := b.Offset(infix.Token.Line, infix.Token.Column)
+	if opOff < 0 {
 		return nil
 	}
-	return []FixEdit{
-		{Line: openLine, Column: openCol, Length: 2, Replace: "(("},
-		{Line: infix.Token.Line, Column: infix.Token.Column, Length: len(infix.Operator), Replace: arithCmpReplacements[infix.Operator]},
-		{Line: closeLine, Column: closeCol, Length: 2, Replace: "))"},
-	}
-}
-
-func zc1091OpenBracket(source []byte, dbe *ast.DoubleBracketExpression) (off, line, col int, ok bool) {
-	line = dbe.Token.Line
-	col = dbe.Token.Column
-	off = LineColToByteOffset(source, line, col)
-	if off < 0 {
-		return 0, 0, 0, false
-	}
-	if off > 0 && source[off] == '[' && source[off-1] == '[' {
-		off--
-		col--
-	}
-	if off+2 > len(source) || source[off] != '[' || source[off+1] != '[' {
-		return 0, 0, 0, false
-	}
-	return off, line, col, true
+	b.Replace(openOff, openOff+2, "((")
+	b.Replace(opOff, opOff+len(infix.Operator), arithCmpReplacements[infix.Operator])
+	b.Replace(closeOff, closeOff+2, "))")
+	return builtEdits(b)
 }
 
 func zc1091SingleArithOp(dbe *ast.DoubleBracketExpression) (*ast.InfixExpression, bool) {
@@ -7289,23 +6933,6 @@ func findDoubleBracketClose(source []byte, start int) int {
 	return -1
 }
 
-func offsetLineColZC1091(source []byte, offset int) (int, int) {
-	if offset < 0 || offset > len(source) {
-		return -1, -1
-	}
-	line := 1
-	col := 1
-	for i := 0; i < offset; i++ {
-		if source[i] == '\n' {
-			line++
-			col = 1
-			continue
-		}
-		col++
-	}
-	return line, col
-}
-
 func checkZC1091(node ast.Node) []Violation {
 	dbe, ok := node.(*ast.DoubleBracketExpression)
 	if !ok {
@@ -7344,9 +6971,9 @@ func init() {
 		Description: "In Zsh, `echo` behavior can vary significantly based on options like `BSD_ECHO`. " +
 			"`print` is a builtin with consistent behavior and more features. " +
 			"For formatted output, `printf` is preferred.",
-		Severity: SeverityWarning,
-		Check:    checkZC1092,
-		Fix:      fixZC1092,
+		Severity:   SeverityWarning,
+		Check:      checkZC1092,
+		FixContext: fixZC1092,
 	})
 }
 
@@ -7356,7 +6983,7 @@ func init() {
 // translation to print differs per flag and is deferred to human
 // review. The replacement covers only the command name — arguments
 // stay byte-identical so quoting and expansions are preserved.
-func fixZC1092(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1092(ctx *Context, node ast.Node, v Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -7370,12 +6997,9 @@ func fixZC1092(node ast.Node, v Violation, source []byte) []FixEdit {
 			return nil
 		}
 	}
-	return []FixEdit{{
-		Line:    v.Line,
-		Column:  v.Column,
-		Length:  len("echo"),
-		Replace: "print -r --",
-	}}
+	b := ctx.NewBuilder()
+	b.ReplaceCommandName(cmd, "print -r --")
+	return builtEdits(b)
 }
 
 func checkZC1092(node ast.Node) []Violation {
@@ -7563,7 +7187,7 @@ func init() {
 		// here fires on a single-numeric-arg `seq N`, which fixZC1061
 		// rewrites to `{1..N}` — exactly the brace expansion this kata
 		// suggests for `for i in {1..N}`.
-		Fix: fixZC1061,
+		FixContext: fixZC1061,
 	})
 }
 
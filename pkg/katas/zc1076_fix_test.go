@@ -57,7 +57,7 @@ func TestZC1076MissingFlags(t *testing.T) {
 func TestFixZC1076BothFlagsPresent(t *testing.T) {
 	cmd, src := firstAutoload(t, "autoload -Uz foo")
 	v := Violation{KataID: "ZC1076", Line: 1, Column: 1}
-	if edits := fixZC1076(cmd, v, src); edits != nil {
+	if edits := fixZC1076(&Context{Source: src}, cmd, v); edits != nil {
 		t.Errorf("expected no edits for an already-flagged autoload, got %v", edits)
 	}
 }
@@ -3,9 +3,12 @@
 package katas
 
 import (
+	"net/url"
+	"slices"
 	"strings"
 
 	"github.com/afadesigns/zshellcheck/pkg/ast"
+	"github.com/afadesigns/zshellcheck/pkg/katas/fixbuild"
 	"github.com/afadesigns/zshellcheck/pkg/token"
 )
 
@@ -816,16 +819,17 @@ func init() {
 		Title: "Use `print -rn` instead of `echo -n`",
 		Description: "The behavior of `echo -n` varies across shells and platforms. " +
 			"In Zsh, `print -rn` is the reliable way to output text without a trailing newline.",
-		Severity: SeverityStyle,
-		Check:    checkZC1118,
-		Fix:      fixZC1118,
+		Severity:   SeverityStyle,
+		Check:      checkZC1118,
+		FixContext: fixZC1118,
 	})
 }
 
 // fixZC1118 collapses `echo -n` (with any whitespace between) into
 // `print -rn`. Spans the `echo` name, intervening whitespace, and
 // the `-n` flag in a single edit; remaining arguments stay in place.
-func fixZC1118(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1118(ctx *Context, node ast.Node, v Violation) []FixEdit {
+	source := ctx.Source
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -837,7 +841,8 @@ func fixZC1118(node ast.Node, v Violation, source []byte) []FixEdit {
 	if len(cmd.Arguments) == 0 {
 		return nil
 	}
-	nameOff := LineColToByteOffset(source, v.Line, v.Column)
+	b := ctx.NewBuilder()
+	nameOff := b.Offset(v.Line, v.Column)
 	if nameOff < 0 || nameOff+len("echo") > len(source) {
 		return nil
 	}
@@ -852,13 +857,8 @@ func fixZC1118(node ast.Node, v Violation, source []byte) []FixEdit {
 	if i+2 > len(source) || source[i] != '-' || source[i+1] != 'n' {
 		return nil
 	}
-	end := i + 2
-	return []FixEdit{{
-		Line:    v.Line,
-		Column:  v.Column,
-		Length:  end - nameOff,
-		Replace: "print -rn",
-	}}
+	b.Replace(nameOff, i+2, "print -rn")
+	return builtEdits(b)
 }
 
 func checkZC1118(node ast.Node) []Violation {
@@ -1096,9 +1096,9 @@ func init() {
 		Title: "Use `: > file` instead of `cat /dev/null > file` to truncate",
 		Description: "Truncating a file with `cat /dev/null > file` spawns an unnecessary process. " +
 			"Use `: > file` or simply `> file` in Zsh.",
-		Severity: SeverityStyle,
-		Check:    checkZC1124,
-		Fix:      fixZC1124,
+		Severity:   SeverityStyle,
+		Check:      checkZC1124,
+		FixContext: fixZC1124,
 	})
 }
 
@@ -1107,7 +1107,8 @@ func init() {
 // `cat /dev/null > file` becomes `: > file`. Only fires when
 // `/dev/null` is the first argument — the detector already requires
 // that shape.
-func fixZC1124(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1124(ctx *Context, node ast.Node, v Violation) []FixEdit {
+	source := ctx.Source
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -1125,7 +1126,8 @@ func fixZC1124(node ast.Node, v Violation, source []byte) []FixEdit {
 	if devNull == nil {
 		return nil
 	}
-	nameOff := LineColToByteOffset(source, v.Line, v.Column)
+	b := ctx.NewBuilder()
+	nameOff := b.Offset(v.Line, v.Column)
 	if nameOff < 0 || nameOff+3 > len(source) {
 		return nil
 	}
@@ -1133,7 +1135,7 @@ func fixZC1124(node ast.Node, v Violation, source []byte) []FixEdit {
 		return nil
 	}
 	argTok := devNull.TokenLiteralNode()
-	argOff := LineColToByteOffset(source, argTok.Line, argTok.Column)
+	argOff := b.Offset(argTok.Line, argTok.Column)
 	if argOff < 0 {
 		return nil
 	}
@@ -1141,12 +1143,8 @@ func fixZC1124(node ast.Node, v Violation, source []byte) []FixEdit {
 	if end > len(source) || string(source[argOff:end]) != "/dev/null" {
 		return nil
 	}
-	return []FixEdit{{
-		Line:    v.Line,
-		Column:  v.Column,
-		Length:  end - nameOff,
-		Replace: ":",
-	}}
+	b.Replace(nameOff, end, ":")
+	return builtEdits(b)
 }
 
 func checkZC1124(node ast.Node) []Violation {
@@ -1240,9 +1238,9 @@ func init() {
 		Title: "Use `sort -u` instead of `sort | uniq`",
 		Description: "`sort | uniq` spawns two processes when `sort -u` does the same in one. " +
 			"Use `sort -u` to deduplicate sorted output efficiently.",
-		Severity: SeverityStyle,
-		Check:    checkZC1126,
-		Fix:      fixZC1126,
+		Severity:   SeverityStyle,
+		Check:      checkZC1126,
+		FixContext: fixZC1126,
 	})
 }
 
@@ -1251,7 +1249,8 @@ func init() {
 // through the end of `uniq`, rewriting the region to ` -u` +
 // whatever sort args sit between the name and the pipe. Only fires
 // when `uniq` has no flags (ZC1126's detector already guards that).
-func fixZC1126(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1126(ctx *Context, node ast.Node, v Violation) []FixEdit {
+	source := ctx.Source
 	pipe, ok := node.(*ast.InfixExpression)
 	if !ok || pipe.Operator != "|" {
 		return nil
@@ -1264,19 +1263,14 @@ func fixZC1126(node ast.Node, v Violation, source []byte) []FixEdit {
 	if !ok {
 		return nil
 	}
-	sortTok := sortCmd.TokenLiteralNode()
-	sortNameOff := LineColToByteOffset(source, sortTok.Line, sortTok.Column)
-	if sortNameOff < 0 {
-		return nil
-	}
-	sortNameLen := IdentLenAt(source, sortNameOff)
-	if sortNameLen == 0 {
+	b := ctx.NewBuilder()
+	_, spanStart, ok := b.Span(sortCmd.Name)
+	if !ok {
 		return nil
 	}
-	spanStart := sortNameOff + sortNameLen
 
 	// Find the pipe byte and walk back past trailing whitespace.
-	pipeOff := LineColToByteOffset(source, pipe.Token.Line, pipe.Token.Column)
+	pipeOff := b.Offset(pipe.Token.Line, pipe.Token.Column)
 	if pipeOff < 0 || source[pipeOff] != '|' {
 		return nil
 	}
@@ -1287,42 +1281,12 @@ func fixZC1126(node ast.Node, v Violation, source []byte) []FixEdit {
 	middle := string(source[spanStart:argsEnd])
 
 	// End of uniq: the identifier itself; detector forbids flags.
-	uniqTok := uniqCmd.TokenLiteralNode()
-	uniqOff := LineColToByteOffset(source, uniqTok.Line, uniqTok.Column)
-	uniqLen := IdentLenAt(source, uniqOff)
-	if uniqOff < 0 || uniqLen == 0 {
-		return nil
-	}
-	spanEnd := uniqOff + uniqLen
-
-	replace := " -u" + middle
-	startLine, startCol := offsetLineColZC1126(source, spanStart)
-	if startLine < 0 {
+	_, spanEnd, ok := b.Span(uniqCmd.Name)
+	if !ok {
 		return nil
 	}
-	return []FixEdit{{
-		Line:    startLine,
-		Column:  startCol,
-		Length:  spanEnd - spanStart,
-		Replace: replace,
-	}}
-}
-
-func offsetLineColZC1126(source []byte, offset int) (int, int) {
-	if offset < 0 || offset > len(source) {
-		return -1, -1
-	}
-	line := 1
-	col := 1
-	for i := 0; i < offset; i++ {
-		if source[i] == '\n' {
-			line++
-			col = 1
-			continue
-		}
-		col++
-	}
-	return line, col
+	b.Replace(spanStart, spanEnd, " -u"+middle)
+	return builtEdits(b)
 }
 
 func checkZC1126(node ast.Node) []Violation {
@@ -1698,16 +1662,17 @@ func init() {
 		Title: "Avoid `env VAR=val cmd` — use inline assignment",
 		Description: "Zsh supports inline environment variable assignment with `VAR=val cmd`. " +
 			"Avoid spawning `env` for simple variable-prefixed command execution.",
-		Severity: SeverityStyle,
-		Check:    checkZC1135,
-		Fix:      fixZC1135,
+		Severity:   SeverityStyle,
+		Check:      checkZC1135,
+		FixContext: fixZC1135,
 	})
 }
 
 // fixZC1135 strips the `env ` prefix from `env VAR=val cmd`. Detector
 // already forbids `env` flags, so the remaining args form a valid
 // inline-assignment command.
-func fixZC1135(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1135(ctx *Context, node ast.Node, v Violation) []FixEdit {
+	source := ctx.Source
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -1716,7 +1681,8 @@ func fixZC1135(node ast.Node, v Violation, source []byte) []FixEdit {
 	if !ok || ident.Value != "env" {
 		return nil
 	}
-	nameOff := LineColToByteOffset(source, v.Line, v.Column)
+	b := ctx.NewBuilder()
+	nameOff := b.Offset(v.Line, v.Column)
 	if nameOff < 0 || nameOff+len("env") > len(source) {
 		return nil
 	}
@@ -1728,12 +1694,8 @@ func fixZC1135(node ast.Node, v Violation, source []byte) []FixEdit {
 	for end < len(source) && (source[end] == ' ' || source[end] == '\t') {
 		end++
 	}
-	return []FixEdit{{
-		Line:    v.Line,
-		Column:  v.Column,
-		Length:  end - nameOff,
-		Replace: "",
-	}}
+	b.Replace(nameOff, end, "")
+	return builtEdits(b)
 }
 
 func checkZC1135(node ast.Node) []Violation {
@@ -1781,18 +1743,24 @@ func init() {
 		Description: "`rm -rf` with a variable path is dangerous if the variable is empty. " +
 			"Always validate the path or use `${var:?}` to fail on empty values.",
 		Severity: SeverityWarning,
-		Check:    checkZC1136,
+		Options: []Option{{
+			Name:        "commands",
+			Type:        OptionStringList,
+			Default:     []string{"rm"},
+			Description: "Commands whose `-rf` / `-fr` form with a bare `$var` path is flagged.",
+		}},
+		CheckContext: checkZC1136,
 	})
 }
 
-func checkZC1136(node ast.Node) []Violation {
+func checkZC1136(ctx *Context, node ast.Node) []Violation {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
 	}
 
 	ident, ok := cmd.Name.(*ast.Identifier)
-	if !ok || ident.Value != "rm" {
+	if !ok || !slices.Contains(ctx.Options.StringList("commands"), ident.Value) {
 		return nil
 	}
 
@@ -1930,16 +1898,16 @@ func init() {
 		Title: "Use `command -v` instead of `hash` for command existence",
 		Description: "`hash cmd` is a POSIX way to check command existence but provides " +
 			"poor error messages. Use `command -v cmd` for cleaner checks in Zsh.",
-		Severity: SeverityStyle,
-		Check:    checkZC1140,
-		Fix:      fixZC1140,
+		Severity:   SeverityStyle,
+		Check:      checkZC1140,
+		FixContext: fixZC1140,
 	})
 }
 
 // fixZC1140 rewrites `hash cmd` to `command -v cmd`. Single-edit
 // command-name replacement — arguments stay intact. Detector gates
 // on flagged forms like `hash -r`, so those stay as-is.
-func fixZC1140(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1140(ctx *Context, node ast.Node, v Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -1948,12 +1916,9 @@ func fixZC1140(node ast.Node, v Violation, source []byte) []FixEdit {
 	if !ok || ident.Value != "hash" {
 		return nil
 	}
-	return []FixEdit{{
-		Line:    v.Line,
-		Column:  v.Column,
-		Length:  len("hash"),
-		Replace: "command -v",
-	}}
+	b := ctx.NewBuilder()
+	b.ReplaceCommandName(cmd, "command -v")
+	return builtEdits(b)
 }
 
 func checkZC1140(node ast.Node) []Violation {
@@ -1997,11 +1962,17 @@ func init() {
 		Description: "Piping curl output to sh/bash/zsh is a security risk. Download first, " +
 			"verify integrity (checksum or signature), then execute.",
 		Severity: SeverityWarning,
-		Check:    checkZC1141,
+		Options: []Option{{
+			Name:        "allowed_hosts",
+			Type:        OptionStringList,
+			Default:     []string{},
+			Description: "Hosts trusted to serve install scripts; a `curl` whose URLs all point at one is not flagged.",
+		}},
+		CheckContext: checkZC1141,
 	})
 }
 
-func checkZC1141(node ast.Node) []Violation {
+func checkZC1141(ctx *Context, node ast.Node) []Violation {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -2021,7 +1992,7 @@ func checkZC1141(node ast.Node) []Violation {
 		}
 	}
 
-	if !hasSilent {
+	if !hasSilent || zc1141AllowedHosts(cmd, ctx.Options.StringList("allowed_hosts")) {
 		return nil
 	}
 
@@ -2035,6 +2006,27 @@ func checkZC1141(node ast.Node) []Violation {
 	}}
 }
 
+// zc1141AllowedHosts reports whether cmd fetches at least one URL and
+// every URL it fetches is served by a host in allowed.
+func zc1141AllowedHosts(cmd *ast.SimpleCommand, allowed []string) bool {
+	if len(allowed) == 0 {
+		return false
+	}
+	seen := false
+	for _, arg := range cmd.Arguments {
+		val := strings.Trim(arg.String(), `"'`)
+		if !strings.Contains(val, "://") {
+			continue
+		}
+		u, err := url.Parse(val)
+		if err != nil || !slices.Contains(allowed, u.Hostname()) {
+			return false
+		}
+		seen = true
+	}
+	return seen
+}
+
 func init() {
 	RegisterKata(ast.InfixExpressionNode, Kata{
 		ID:       "ZC1142",
@@ -2121,8 +2113,8 @@ func init() {
 		Severity: SeverityInfo,
 		Description: "Signal numbers vary across platforms. Use signal names like " +
 			"`SIGTERM`, `SIGINT`, `EXIT` instead of numeric values for portability.",
-		Check: checkZC1144,
-		Fix:   fixZC1144,
+		Check:      checkZC1144,
+		FixContext: fixZC1144,
 	})
 }
 
@@ -2165,7 +2157,7 @@ var zc1144SignalNames = map[string]string{
 // fixZC1144 replaces numeric signal arguments in a `trap` call with
 // their canonical names. Each numeric arg becomes a separate edit at
 // that arg's position. Unknown numbers stay untouched.
-func fixZC1144(node ast.Node, _ Violation, source []byte) []FixEdit {
+func fixZC1144(ctx *Context, node ast.Node, _ Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -2174,30 +2166,17 @@ func fixZC1144(node ast.Node, _ Violation, source []byte) []FixEdit {
 	if !ok || ident.Value != "trap" {
 		return nil
 	}
-	var edits []FixEdit
+	b := ctx.NewBuilder()
 	for i := 1; i < len(cmd.Arguments); i++ {
 		arg := cmd.Arguments[i]
 		val := arg.String()
 		name, ok := zc1144SignalNames[val]
-		if !ok {
-			continue
-		}
-		tok := arg.TokenLiteralNode()
-		off := LineColToByteOffset(source, tok.Line, tok.Column)
-		if off < 0 || off+len(val) > len(source) {
-			continue
-		}
-		if string(source[off:off+len(val)]) != val {
+		if !ok || b.Text(arg) != val {
 			continue
 		}
-		edits = append(edits, FixEdit{
-			Line:    tok.Line,
-			Column:  tok.Column,
-			Length:  len(val),
-			Replace: name,
-		})
+		b.ReplaceNode(arg, name)
 	}
-	return edits
+	return builtEdits(b)
 }
 
 func checkZC1144(node ast.Node) []Violation {
@@ -2293,8 +2272,8 @@ func init() {
 		Severity: SeverityStyle,
 		Description: "`cat file | awk` spawns an unnecessary cat process. " +
 			"Pass the file directly as `awk '...' file`.",
-		Check: checkZC1146,
-		Fix:   fixZC1146,
+		Check:      checkZC1146,
+		FixContext: fixZC1146,
 	})
 }
 
@@ -2303,50 +2282,45 @@ func init() {
 // the right-hand command; the replacement is the right-hand source
 // verbatim with ` FILE` appended. Only fires when the cat command has
 // exactly one filename argument (the detector already guards that).
-func fixZC1146(node ast.Node, _ Violation, source []byte) []FixEdit {
+func fixZC1146(ctx *Context, node ast.Node, _ Violation) []FixEdit {
+	source := ctx.Source
 	_, catCmd, rightCmd, _, ok := zc1146Pipe(node)
 	if !ok {
 		return nil
 	}
-	catStart, ok := zc1146Offset(source, catCmd.TokenLiteralNode())
+	b := ctx.NewBuilder()
+	catStart, ok := zc1146Offset(b, catCmd.TokenLiteralNode())
 	if !ok {
 		return nil
 	}
-	fileLit, _, ok := zc1146ArgSlice(source, catCmd.Arguments[0])
+	fileLit, _, ok := zc1146ArgSlice(b, catCmd.Arguments[0])
 	if !ok {
 		return nil
 	}
-	rightStart, ok := zc1146Offset(source, rightCmd.TokenLiteralNode())
+	rightStart, ok := zc1146Offset(b, rightCmd.TokenLiteralNode())
 	if !ok {
 		return nil
 	}
-	rightEnd, ok := zc1146RightEnd(source, rightCmd, rightStart)
+	rightEnd, ok := zc1146RightEnd(b, rightCmd, rightStart)
 	if !ok {
 		return nil
 	}
-	startLine, startCol := offsetLineColZC1146(source, catStart)
-	if startLine < 0 {
-		return nil
-	}
-	return []FixEdit{{
-		Line:    startLine,
-		Column:  startCol,
-		Length:  rightEnd - catStart,
-		Replace: string(source[rightStart:rightEnd]) + " " + fileLit,
-	}}
+	b.Replace(catStart, rightEnd, string(source[rightStart:rightEnd])+" "+fileLit)
+	return builtEdits(b)
 }
 
-func zc1146Offset(source []byte, tok token.Token) (int, bool) {
-	off := LineColToByteOffset(source, tok.Line, tok.Column)
+func zc1146Offset(b *fixbuild.Builder, tok token.Token) (int, bool) {
+	off := b.Offset(tok.Line, tok.Column)
 	return off, off >= 0
 }
 
 // zc1146ArgSlice returns the literal text of arg as it appears in
 // source plus the offset, or ok=false when the AST coordinates do not
 // line up with the source bytes.
-func zc1146ArgSlice(source []byte, arg ast.Expression) (lit string, off int, ok bool) {
+func zc1146ArgSlice(b *fixbuild.Builder, arg ast.Expression) (lit string, off int, ok bool) {
+	source := b.Source()
 	tok := arg.TokenLiteralNode()
-	off, ok = zc1146Offset(source, tok)
+	off, ok = zc1146Offset(b, tok)
 	if !ok {
 		return "", 0, false
 	}
@@ -2357,7 +2331,7 @@ func zc1146ArgSlice(source []byte, arg ast.Expression) (lit string, off int, ok
 	return lit, off, true
 }
 
-func zc1146RightEnd(source []byte, rightCmd *ast.SimpleCommand, rightStart int) (int, bool) {
+func zc1146RightEnd(b *fixbuild.Builder, rightCmd *ast.SimpleCommand, rightStart int) (int, bool) {
 	rightIdent, ok := rightCmd.Name.(*ast.Identifier)
 	if !ok {
 		return 0, false
@@ -2365,35 +2339,18 @@ func zc1146RightEnd(source []byte, rightCmd *ast.SimpleCommand, rightStart int)
 	end := rightStart + len(rightIdent.Value)
 	if n := len(rightCmd.Arguments); n > 0 {
 		lastArg := rightCmd.Arguments[n-1]
-		laOff, ok := zc1146Offset(source, lastArg.TokenLiteralNode())
+		laOff, ok := zc1146Offset(b, lastArg.TokenLiteralNode())
 		if !ok {
 			return 0, false
 		}
 		end = laOff + len(lastArg.String())
 	}
-	if end > len(source) || end < rightStart {
+	if end > len(b.Source()) || end < rightStart {
 		return 0, false
 	}
 	return end, true
 }
 
-func offsetLineColZC1146(source []byte, offset int) (int, int) {
-	if offset < 0 || offset > len(source) {
-		return -1, -1
-	}
-	line := 1
-	col := 1
-	for i := 0; i < offset; i++ {
-		if source[i] == '\n' {
-			line++
-			col = 1
-			continue
-		}
-		col++
-	}
-	return line, col
-}
-
 var zc1146FileTakers = map[string]struct{}{
 	"awk":  {},
 	"sed":  {},
@@ -2452,15 +2409,15 @@ func init() {
 		Severity: SeverityInfo,
 		Description: "Using `mkdir` without `-p` fails if parent directories don't exist. " +
 			"Use `mkdir -p` to create the full path safely.",
-		Check: checkZC1147,
-		Fix:   fixZC1147,
+		Check:      checkZC1147,
+		FixContext: fixZC1147,
 	})
 }
 
 // fixZC1147 inserts ` -p` after the `mkdir` command name so nested
 // paths survive missing intermediates. Detector already gates on
 // absence of `-p` and presence of a nested path.
-func fixZC1147(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1147(ctx *Context, node ast.Node, v Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -2469,42 +2426,9 @@ func fixZC1147(node ast.Node, v Violation, source []byte) []FixEdit {
 	if !ok || ident.Value != "mkdir" {
 		return nil
 	}
-	nameOff := LineColToByteOffset(source, v.Line, v.Column)
-	if nameOff < 0 {
-		return nil
-	}
-	nameLen := IdentLenAt(source, nameOff)
-	if nameLen != len("mkdir") {
-		return nil
-	}
-	insertAt := nameOff + nameLen
-	insLine, insCol := offsetLineColZC1147(source, insertAt)
-	if insLine < 0 {
-		return nil
-	}
-	return []FixEdit{{
-		Line:    insLine,
-		Column:  insCol,
-		Length:  0,
-		Replace: " -p",
-	}}
-}
-
-func offsetLineColZC1147(source []byte, offset int) (int, int) {
-	if offset < 0 || offset > len(source) {
-		return -1, -1
-	}
-	line := 1
-	col := 1
-	for i := 0; i < offset; i++ {
-		if source[i] == '\n' {
-			line++
-			col = 1
-			continue
-		}
-		col++
-	}
-	return line, col
+	b := ctx.NewBuilder()
+	b.InsertArgBefore(cmd, 0, "-p")
+	return builtEdits(b)
 }
 
 func checkZC1147(node ast.Node) []Violation {
@@ -2752,8 +2676,8 @@ func init() {
 		Severity: SeverityStyle,
 		Description: "When only checking if two files are identical (not viewing differences), " +
 			"`cmp -s` is faster than `diff` as it stops at the first difference.",
-		Check: checkZC1153,
-		Fix:   fixZC1153,
+		Check:      checkZC1153,
+		FixContext: fixZC1153,
 	})
 }
 
@@ -2761,7 +2685,7 @@ func init() {
 // Two non-overlapping edits: the command name (`diff` → `cmp`) and the
 // quiet flag (`-q` → `-s`). Other arguments stay byte-identical.
 // Idempotent because the detector gates on `diff -q` literal presence.
-func fixZC1153(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1153(ctx *Context, node ast.Node, v Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -2780,25 +2704,10 @@ func fixZC1153(node ast.Node, v Violation, source []byte) []FixEdit {
 	if dashQ == nil {
 		return nil
 	}
-	nameOff := LineColToByteOffset(source, v.Line, v.Column)
-	if nameOff < 0 || nameOff+len("diff") > len(source) {
-		return nil
-	}
-	if string(source[nameOff:nameOff+len("diff")]) != "diff" {
-		return nil
-	}
-	dashTok := dashQ.TokenLiteralNode()
-	dashOff := LineColToByteOffset(source, dashTok.Line, dashTok.Column)
-	if dashOff < 0 || dashOff+2 > len(source) {
-		return nil
-	}
-	if string(source[dashOff:dashOff+2]) != "-q" {
-		return nil
-	}
-	return []FixEdit{
-		{Line: v.Line, Column: v.Column, Length: len("diff"), Replace: "cmp"},
-		{Line: dashTok.Line, Column: dashTok.Column, Length: 2, Replace: "-s"},
-	}
+	b := ctx.NewBuilder()
+	b.ReplaceCommandName(cmd, "cmp")
+	b.ReplaceNode(dashQ, "-s")
+	return builtEdits(b)
 }
 
 func checkZC1153(node ast.Node) []Violation {
@@ -2885,15 +2794,15 @@ func init() {
 		Severity: SeverityInfo,
 		Description: "`which -a` may be an external command on some systems. " +
 			"Zsh builtin `whence -a` reliably lists all command locations.",
-		Check: checkZC1155,
-		Fix:   fixZC1155,
+		Check:      checkZC1155,
+		FixContext: fixZC1155,
 	})
 }
 
 // fixZC1155 rewrites the `which` command name to `whence`, leaving the
 // `-a` flag and any other arguments in place. Detector already
 // guarantees the shape (which + -a anywhere in argv).
-func fixZC1155(node ast.Node, v Violation, _ []byte) []FixEdit {
+func fixZC1155(ctx *Context, node ast.Node, v Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -2902,12 +2811,9 @@ func fixZC1155(node ast.Node, v Violation, _ []byte) []FixEdit {
 	if !ok || ident.Value != "which" {
 		return nil
 	}
-	return []FixEdit{{
-		Line:    v.Line,
-		Column:  v.Column,
-		Length:  len("which"),
-		Replace: "whence",
-	}}
+	b := ctx.NewBuilder()
+	b.ReplaceCommandName(cmd, "whence")
+	return builtEdits(b)
 }
 
 func checkZC1155(node ast.Node) []Violation {
@@ -3239,14 +3145,14 @@ func init() {
 		Severity: SeverityInfo,
 		Description: "`cp -r` copies recursively but may not preserve permissions, timestamps, " +
 			"or symlinks. Use `cp -a` (archive mode) to preserve all attributes.",
-		Check: checkZC1162,
-		Fix:   fixZC1162,
+		Check:      checkZC1162,
+		FixContext: fixZC1162,
 	})
 }
 
 // fixZC1162 rewrites `cp -r` / `cp -R` to `cp -a`. Single-edit
 // replacement of the recursive flag; surrounding args stay put.
-func fixZC1162(node ast.Node, _ Violation, source []byte) []FixEdit {
+func fixZC1162(ctx *Context, node ast.Node, _ Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -3260,20 +3166,9 @@ func fixZC1162(node ast.Node, _ Violation, source []byte) []FixEdit {
 		if val != "-r" && val != "-R" {
 			continue
 		}
-		tok := arg.TokenLiteralNode()
-		off := LineColToByteOffset(source, tok.Line, tok.Column)
-		if off < 0 || off+2 > len(source) {
-			return nil
-		}
-		if string(source[off:off+2]) != val {
-			return nil
-		}
-		return []FixEdit{{
-			Line:    tok.Line,
-			Column:  tok.Column,
-			Length:  2,
-			Replace: "-a",
-		}}
+		b := ctx.NewBuilder()
+		b.ReplaceNode(arg, "-a")
+		return builtEdits(b)
 	}
 	return nil
 }
@@ -3323,8 +3218,8 @@ func init() {
 		Severity: SeverityStyle,
 		Description: "`grep pattern | head -1` spawns two processes when `grep -m 1` does the same. " +
 			"The `-m` flag stops after the first match, avoiding the pipeline.",
-		Check: checkZC1163,
-		Fix:   fixZC1163,
+		Check:      checkZC1163,
+		FixContext: fixZC1163,
 	})
 }
 
@@ -3335,33 +3230,26 @@ func init() {
 // fires for the `-1` / `-n1` shapes the detector already guards.
 var zc1163FirstFlags = map[string]struct{}{"-1": {}, "-n1": {}}
 
-func fixZC1163(node ast.Node, _ Violation, source []byte) []FixEdit {
+func fixZC1163(ctx *Context, node ast.Node, _ Violation) []FixEdit {
 	grepCmd, headCmd, pipe, ok := zc1163Pipeline(node)
 	if !ok {
 		return nil
 	}
-	spanStart, ok := zc1163GrepArgsStart(source, grepCmd)
+	b := ctx.NewBuilder()
+	spanStart, ok := zc1163GrepArgsStart(b, grepCmd)
 	if !ok {
 		return nil
 	}
-	middle, ok := zc1163GrepArgsSlice(source, pipe, spanStart)
+	middle, ok := zc1163GrepArgsSlice(b, pipe, spanStart)
 	if !ok {
 		return nil
 	}
-	spanEnd, ok := zc1163HeadEnd(source, headCmd)
+	spanEnd, ok := zc1163HeadEnd(b, headCmd)
 	if !ok || spanEnd <= spanStart {
 		return nil
 	}
-	startLine, startCol := offsetLineColZC1163(source, spanStart)
-	if startLine < 0 {
-		return nil
-	}
-	return []FixEdit{{
-		Line:    startLine,
-		Column:  startCol,
-		Length:  spanEnd - spanStart,
-		Replace: " -m 1" + middle,
-	}}
+	b.Replace(spanStart, spanEnd, " -m 1"+middle)
+	return builtEdits(b)
 }
 
 func zc1163Pipeline(node ast.Node) (*ast.SimpleCommand, *ast.SimpleCommand, *ast.InfixExpression, bool) {
@@ -3383,21 +3271,14 @@ func zc1163Pipeline(node ast.Node) (*ast.SimpleCommand, *ast.SimpleCommand, *ast
 	return grepCmd, headCmd, pipe, true
 }
 
-func zc1163GrepArgsStart(source []byte, grepCmd *ast.SimpleCommand) (int, bool) {
-	tok := grepCmd.TokenLiteralNode()
-	off := LineColToByteOffset(source, tok.Line, tok.Column)
-	if off < 0 {
-		return 0, false
-	}
-	n := IdentLenAt(source, off)
-	if n == 0 {
-		return 0, false
-	}
-	return off + n, true
+func zc1163GrepArgsStart(b *fixbuild.Builder, grepCmd *ast.SimpleCommand) (int, bool) {
+	_, end, ok := b.Span(grepCmd.Name)
+	return end, ok
 }
 
-func zc1163GrepArgsSlice(source []byte, pipe *ast.InfixExpression, spanStart int) (string, bool) {
-	pipeOff := LineColToByteOffset(source, pipe.Token.Line, pipe.Token.Column)
+func zc1163GrepArgsSlice(b *fixbuild.Builder, pipe *ast.InfixExpression, spanStart int) (string, bool) {
+	source := b.Source()
+	pipeOff := b.Offset(pipe.Token.Line, pipe.Token.Column)
 	if pipeOff < 0 || pipeOff >= len(source) || source[pipeOff] != '|' {
 		return "", false
 	}
@@ -3408,33 +3289,16 @@ func zc1163GrepArgsSlice(source []byte, pipe *ast.InfixExpression, spanStart int
 	return string(source[spanStart:end]), true
 }
 
-func zc1163HeadEnd(source []byte, headCmd *ast.SimpleCommand) (int, bool) {
+func zc1163HeadEnd(b *fixbuild.Builder, headCmd *ast.SimpleCommand) (int, bool) {
 	last := headCmd.Arguments[len(headCmd.Arguments)-1]
 	tok := last.TokenLiteralNode()
-	off := LineColToByteOffset(source, tok.Line, tok.Column)
+	off := b.Offset(tok.Line, tok.Column)
 	if off < 0 {
 		return 0, false
 	}
 	return off + len(last.String()), true
 }
 
-func offsetLineColZC1163(source []byte, offset int) (int, int) {
-	if offset < 0 || offset > len(source) {
-		return -1, -1
-	}
-	line := 1
-	col := 1
-	for i := 0; i < offset; i++ {
-		if source[i] == '\n' {
-			line++
-			col = 1
-			continue
-		}
-		col++
-	}
-	return line, col
-}
-
 func checkZC1163(node ast.Node) []Violation {
 	pipe, ok := node.(*ast.InfixExpression)
 	if !ok || pipe.Operator != "|" {
@@ -3737,14 +3601,14 @@ func init() {
 		Severity: SeverityStyle,
 		Description: "`pushd` and `popd` print the directory stack by default, cluttering output. " +
 			"Use `-q` flag to suppress output in scripts.",
-		Check: checkZC1170,
-		Fix:   fixZC1170,
+		Check:      checkZC1170,
+		FixContext: fixZC1170,
 	})
 }
 
 // fixZC1170 inserts ` -q` after `pushd` or `popd` so the directory
 // stack output is suppressed in scripts.
-func fixZC1170(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1170(ctx *Context, node ast.Node, v Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -3753,42 +3617,9 @@ func fixZC1170(node ast.Node, v Violation, source []byte) []FixEdit {
 	if !ok || (ident.Value != "pushd" && ident.Value != "popd") {
 		return nil
 	}
-	nameOff := LineColToByteOffset(source, v.Line, v.Column)
-	if nameOff < 0 {
-		return nil
-	}
-	nameLen := IdentLenAt(source, nameOff)
-	if nameLen != len(ident.Value) {
-		return nil
-	}
-	insertAt := nameOff + nameLen
-	insLine, insCol := offsetLineColZC1170(source, insertAt)
-	if insLine < 0 {
-		return nil
-	}
-	return []FixEdit{{
-		Line:    insLine,
-		Column:  insCol,
-		Length:  0,
-		Replace: " -q",
-	}}
-}
-
-func offsetLineColZC1170(source []byte, offset int) (int, int) {
-	if offset < 0 || offset > len(source) {
-		return -1, -1
-	}
-	line := 1
-	col := 1
-	for i := 0; i < offset; i++ {
-		if source[i] == '\n' {
-			line++
-			col = 1
-			continue
-		}
-		col++
-	}
-	return line, col
+	b := ctx.NewBuilder()
+	b.InsertArgBefore(cmd, 0, "-q")
+	return builtEdits(b)
 }
 
 func checkZC1170(node ast.Node) []Violation {
@@ -3830,15 +3661,35 @@ func init() {
 		Severity: SeverityStyle,
 		Description: "`echo -e` behavior varies across shells and platforms. " +
 			"In Zsh, `print` natively interprets escape sequences and is more reliable.",
-		Check: checkZC1171,
-		Fix:   fixZC1171,
+		Check:   checkZC1171,
+		Suggest: suggestZC1171,
 	})
 }
 
+// suggestZC1171 offers `print` first and, when a single argument follows
+// `-e`, `printf '%b\n'` — which expands the same escapes and adds the
+// same trailing newline — as the portable alternative. With several
+// arguments `printf` would print each on its own line, so only `print`
+// is offered.
+func suggestZC1171(ctx *Context, node ast.Node, v Violation) []Suggestion {
+	edits := fixZC1171(ctx, node, v)
+	if len(edits) == 0 {
+		return nil
+	}
+	suggestions := []Suggestion{{Title: "Use `print`", Edits: edits}}
+	if cmd, ok := node.(*ast.SimpleCommand); ok && len(cmd.Arguments) == 2 {
+		printf := edits[0]
+		printf.Replace = `printf '%b\n'`
+		suggestions = append(suggestions, Suggestion{Title: "Use `printf '%b\\n'`", Edits: []FixEdit{printf}})
+	}
+	return suggestions
+}
+
 // fixZC1171 collapses `echo -e` into `print`. Span covers the
 // command name, intervening whitespace, and the `-e` flag; remaining
 // arguments stay in place.
-func fixZC1171(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1171(ctx *Context, node ast.Node, v Violation) []FixEdit {
+	source := ctx.Source
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -3850,7 +3701,8 @@ func fixZC1171(node ast.Node, v Violation, source []byte) []FixEdit {
 	if len(cmd.Arguments) == 0 {
 		return nil
 	}
-	nameOff := LineColToByteOffset(source, v.Line, v.Column)
+	b := ctx.NewBuilder()
+	nameOff := b.Offset(v.Line, v.Column)
 	if nameOff < 0 || nameOff+len("echo") > len(source) {
 		return nil
 	}
@@ -3864,13 +3716,8 @@ func fixZC1171(node ast.Node, v Violation, source []byte) []FixEdit {
 	if i+2 > len(source) || source[i] != '-' || source[i+1] != 'e' {
 		return nil
 	}
-	end := i + 2
-	return []FixEdit{{
-		Line:    v.Line,
-		Column:  v.Column,
-		Length:  end - nameOff,
-		Replace: "print",
-	}}
+	b.Replace(nameOff, i+2, "print")
+	return builtEdits(b)
 }
 
 func checkZC1171(node ast.Node) []Violation {
@@ -3910,8 +3757,8 @@ func init() {
 		Severity: SeverityInfo,
 		Description: "Bash uses `read -a` to read into an array, but Zsh uses `read -A`. " +
 			"Using `-a` in Zsh reads into a scalar, not an array.",
-		Check: checkZC1172,
-		Fix:   fixZC1172,
+		Check:      checkZC1172,
+		FixContext: fixZC1172,
 	})
 }
 
@@ -3920,7 +3767,7 @@ func init() {
 // Idempotent: a re-run sees `-A`, not `-a`, so the detector won't
 // fire. Defensive byte-match guard refuses to insert unless the
 // source at the offset is literally `-a`.
-func fixZC1172(node ast.Node, _ Violation, source []byte) []FixEdit {
+func fixZC1172(ctx *Context, node ast.Node, _ Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -3933,20 +3780,12 @@ func fixZC1172(node ast.Node, _ Violation, source []byte) []FixEdit {
 		if arg.String() != "-a" {
 			continue
 		}
-		tok := arg.TokenLiteralNode()
-		off := LineColToByteOffset(source, tok.Line, tok.Column)
-		if off < 0 || off+2 > len(source) {
+		b := ctx.NewBuilder()
+		if b.Text(arg) != "-a" {
 			return nil
 		}
-		if string(source[off:off+2]) != "-a" {
-			return nil
-		}
-		return []FixEdit{{
-			Line:    tok.Line,
-			Column:  tok.Column,
-			Length:  2,
-			Replace: "-A",
-		}}
+		b.ReplaceNode(arg, "-A")
+		return builtEdits(b)
 	}
 	return nil
 }
@@ -4673,8 +4512,8 @@ func init() {
 		Severity: SeverityStyle,
 		Description: "`grep -v p1 | grep -v p2` spawns two processes. " +
 			"Use `grep -v -e p1 -e p2` to combine exclusions in one invocation.",
-		Check: checkZC1190,
-		Fix:   fixZC1190,
+		Check:      checkZC1190,
+		FixContext: fixZC1190,
 	})
 }
 
@@ -4682,7 +4521,7 @@ func init() {
 // `grep -v -e p1 -e p2`. Only fires when each grep has exactly one
 // non-flag pattern argument and at most the lone `-v` flag — keeps the
 // rewrite safe in the presence of trailing FILE / additional flags.
-func fixZC1190(node ast.Node, _ Violation, source []byte) []FixEdit {
+func fixZC1190(ctx *Context, node ast.Node, _ Violation) []FixEdit {
 	pipe, ok := node.(*ast.InfixExpression)
 	if !ok || pipe.Operator != "|" {
 		return nil
@@ -4701,8 +4540,9 @@ func fixZC1190(node ast.Node, _ Violation, source []byte) []FixEdit {
 		return nil
 	}
 
+	b := ctx.NewBuilder()
 	leftTok := left.TokenLiteralNode()
-	leftStart := LineColToByteOffset(source, leftTok.Line, leftTok.Column)
+	leftStart := b.Offset(leftTok.Line, leftTok.Column)
 	if leftStart < 0 {
 		return nil
 	}
@@ -4711,25 +4551,12 @@ func fixZC1190(node ast.Node, _ Violation, source []byte) []FixEdit {
 	}
 	lastArg := right.Arguments[len(right.Arguments)-1]
 	laTok := lastArg.TokenLiteralNode()
-	laOff := LineColToByteOffset(source, laTok.Line, laTok.Column)
+	laOff := b.Offset(laTok.Line, laTok.Column)
 	if laOff < 0 {
 		return nil
 	}
-	laLit := lastArg.String()
-	end := laOff + len(laLit)
-	if end > len(source) {
-		return nil
-	}
-	startLine, startCol := offsetLineColZC1190(source, leftStart)
-	if startLine < 0 {
-		return nil
-	}
-	return []FixEdit{{
-		Line:    startLine,
-		Column:  startCol,
-		Length:  end - leftStart,
-		Replace: "grep -v -e " + leftPat + " -e " + rightPat,
-	}}
+	b.Replace(leftStart, laOff+len(lastArg.String()), "grep -v -e "+leftPat+" -e "+rightPat)
+	return builtEdits(b)
 }
 
 // zc1190SinglePattern returns the lone non-flag argument of a
@@ -4758,23 +4585,6 @@ func zc1190SinglePattern(cmd *ast.SimpleCommand) (string, bool) {
 	return pattern, true
 }
 
-func offsetLineColZC1190(source []byte, offset int) (int, int) {
-	if offset < 0 || offset > len(source) {
-		return -1, -1
-	}
-	line := 1
-	col := 1
-	for i := 0; i < offset; i++ {
-		if source[i] == '\n' {
-			line++
-			col = 1
-			continue
-		}
-		col++
-	}
-	return line, col
-}
-
 func checkZC1190(node ast.Node) []Violation {
 	pipe, ok := node.(*ast.InfixExpression)
 	if !ok || pipe.Operator != "|" {
@@ -4826,8 +4636,8 @@ func init() {
 		Severity: SeverityStyle,
 		Description: "`clear` spawns an external process for screen clearing. " +
 			"Use `print -n '\\e[2J\\e[H'` for faster terminal clearing.",
-		Check: checkZC1191,
-		Fix:   fixZC1191,
+		Check:      checkZC1191,
+		FixContext: fixZC1191,
 	})
 }
 
@@ -4838,17 +4648,14 @@ func init() {
 // flag-bundle matches the canonical `print -rn` form ZShellCheck
 // recommends elsewhere (see ZC1017, ZC1118), so the rewrite is
 // idempotent on re-run.
-func fixZC1191(node ast.Node, v Violation, _ []byte) []FixEdit {
+func fixZC1191(ctx *Context, node ast.Node, v Violation) []FixEdit {
 	ident, ok := node.(*ast.Identifier)
 	if !ok || ident == nil || ident.Value != "clear" {
 		return nil
 	}
-	return []FixEdit{{
-		Line:    v.Line,
-		Column:  v.Column,
-		Length:  len("clear"),
-		Replace: "print -rn $'\\e[2J\\e[H'",
-	}}
+	b := ctx.NewBuilder()
+	b.ReplaceNode(ident, "print -rn $'\\e[2J\\e[H'")
+	return builtEdits(b)
 }
 
 func checkZC1191(node ast.Node) []Violation {
@@ -4874,14 +4681,15 @@ func init() {
 		Severity: SeverityInfo,
 		Description: "`sleep 0` spawns an external process that does nothing. " +
 			"Remove it or use `:` if an explicit no-op is needed.",
-		Check: checkZC1192,
-		Fix:   fixZC1192,
+		Check:      checkZC1192,
+		FixContext: fixZC1192,
 	})
 }
 
 // fixZC1192 rewrites the no-op `sleep 0` invocation into `:`, the
 // builtin no-op. Span covers the command name through the `0` arg.
-func fixZC1192(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1192(ctx *Context, node ast.Node, v Violation) []FixEdit {
+	source := ctx.Source
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -4893,7 +4701,8 @@ func fixZC1192(node ast.Node, v Violation, source []byte) []FixEdit {
 	if zeroArg.String() != "0" {
 		return nil
 	}
-	nameOff := LineColToByteOffset(source, v.Line, v.Column)
+	b := ctx.NewBuilder()
+	nameOff := b.Offset(v.Line, v.Column)
 	if nameOff < 0 || nameOff+len("sleep") > len(source) {
 		return nil
 	}
@@ -4901,17 +4710,12 @@ func fixZC1192(node ast.Node, v Violation, source []byte) []FixEdit {
 		return nil
 	}
 	argTok := zeroArg.TokenLiteralNode()
-	argOff := LineColToByteOffset(source, argTok.Line, argTok.Column)
+	argOff := b.Offset(argTok.Line, argTok.Column)
 	if argOff < 0 || argOff+1 > len(source) || source[argOff] != '0' {
 		return nil
 	}
-	end := argOff + 1
-	return []FixEdit{{
-		Line:    v.Line,
-		Column:  v.Column,
-		Length:  end - nameOff,
-		Replace: ":",
-	}}
+	b.Replace(nameOff, argOff+1, ":")
+	return builtEdits(b)
 }
 
 func checkZC1192(node ast.Node) []Violation {
@@ -47,8 +47,8 @@ func init() {
 		Severity: SeverityWarning,
 		Description: "`rsh`, `rlogin`, and `rcp` are insecure legacy protocols. " +
 			"Use `ssh`, `scp`, or `rsync` over SSH for encrypted remote operations.",
-		Check: checkZC1201,
-		Fix:   fixZC1201,
+		Check:      checkZC1201,
+		FixContext: fixZC1201,
 	})
 }
 
@@ -58,7 +58,7 @@ func init() {
 // compatible (host + optional command for rsh/rlogin/ssh; src dst
 // for rcp/scp). Idempotent — a re-run sees `ssh` or `scp`, not
 // the legacy names.
-func fixZC1201(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1201(ctx *Context, node ast.Node, v Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -76,19 +76,9 @@ func fixZC1201(node ast.Node, v Violation, source []byte) []FixEdit {
 	default:
 		return nil
 	}
-	off := LineColToByteOffset(source, v.Line, v.Column)
-	if off < 0 || off+len(ident.Value) > len(source) {
-		return nil
-	}
-	if string(source[off:off+len(ident.Value)]) != ident.Value {
-		return nil
-	}
-	return []FixEdit{{
-		Line:    v.Line,
-		Column:  v.Column,
-		Length:  len(ident.Value),
-		Replace: replacement,
-	}}
+	b := ctx.NewBuilder()
+	b.ReplaceCommandName(cmd, replacement)
+	return builtEdits(b)
 }
 
 func checkZC1201(node ast.Node) []Violation {
@@ -124,8 +114,8 @@ func init() {
 		Severity: SeverityInfo,
 		Description: "`ifconfig` is deprecated on modern Linux. " +
 			"Use `ip addr`, `ip link`, or `ip route` from iproute2 for network operations.",
-		Check: checkZC1202,
-		Fix:   fixZC1202,
+		Check:      checkZC1202,
+		FixContext: fixZC1202,
 	})
 }
 
@@ -133,7 +123,7 @@ func init() {
 // position. `ip addr` is the closest single-token-equivalent iproute2
 // invocation; arguments stay untouched and operators/flags must be
 // adjusted manually for non-trivial cases.
-func fixZC1202(node ast.Node, v Violation, _ []byte) []FixEdit {
+func fixZC1202(ctx *Context, node ast.Node, v Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -142,12 +132,9 @@ func fixZC1202(node ast.Node, v Violation, _ []byte) []FixEdit {
 	if !ok || ident.Value != "ifconfig" {
 		return nil
 	}
-	return []FixEdit{{
-		Line:    v.Line,
-		Column:  v.Column,
-		Length:  len("ifconfig"),
-		Replace: "ip addr",
-	}}
+	b := ctx.NewBuilder()
+	b.ReplaceCommandName(cmd, "ip addr")
+	return builtEdits(b)
 }
 
 func checkZC1202(node ast.Node) []Violation {
@@ -178,8 +165,8 @@ func init() {
 		Severity: SeverityInfo,
 		Description: "`netstat` is deprecated on modern Linux in favor of `ss` from iproute2. " +
 			"`ss` is faster and provides more detailed socket information.",
-		Check: checkZC1203,
-		Fix:   fixZC1203,
+		Check:      checkZC1203,
+		FixContext: fixZC1203,
 	})
 }
 
@@ -187,7 +174,7 @@ func init() {
 // Single replacement — arguments stay untouched. The two tools share
 // most short flags (`-t`, `-u`, `-l`, `-n`) so the swap is sound for
 // the common cases; exotic netstat-only flags need manual review.
-func fixZC1203(node ast.Node, v Violation, _ []byte) []FixEdit {
+func fixZC1203(ctx *Context, node ast.Node, v Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -196,12 +183,9 @@ func fixZC1203(node ast.Node, v Violation, _ []byte) []FixEdit {
 	if !ok || ident.Value != "netstat" {
 		return nil
 	}
-	return []FixEdit{{
-		Line:    v.Line,
-		Column:  v.Column,
-		Length:  len("netstat"),
-		Replace: "ss",
-	}}
+	b := ctx.NewBuilder()
+	b.ReplaceCommandName(cmd, "ss")
+	return builtEdits(b)
 }
 
 func checkZC1203(node ast.Node) []Violation {
@@ -406,15 +390,15 @@ func init() {
 		Severity: SeverityStyle,
 		Description: "`systemctl` invokes a pager by default which hangs in non-interactive scripts. " +
 			"Use `--no-pager` or pipe to `cat` for reliable script output.",
-		Check: checkZC1209,
-		Fix:   fixZC1209,
+		Check:      checkZC1209,
+		FixContext: fixZC1209,
 	})
 }
 
 // fixZC1209 inserts ` --no-pager` after the `systemctl` command
 // name so subcommands that emit pager output (status, list-*)
 // behave predictably in scripts.
-func fixZC1209(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1209(ctx *Context, node ast.Node, v Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -423,42 +407,9 @@ func fixZC1209(node ast.Node, v Violation, source []byte) []FixEdit {
 	if !ok || ident.Value != "systemctl" {
 		return nil
 	}
-	nameOff := LineColToByteOffset(source, v.Line, v.Column)
-	if nameOff < 0 {
-		return nil
-	}
-	nameLen := IdentLenAt(source, nameOff)
-	if nameLen != len("systemctl") {
-		return nil
-	}
-	insertAt := nameOff + nameLen
-	insLine, insCol := offsetLineColZC1209(source, insertAt)
-	if insLine < 0 {
-		return nil
-	}
-	return []FixEdit{{
-		Line:    insLine,
-		Column:  insCol,
-		Length:  0,
-		Replace: " --no-pager",
-	}}
-}
-
-func offsetLineColZC1209(source []byte, offset int) (int, int) {
-	if offset < 0 || offset > len(source) {
-		return -1, -1
-	}
-	line := 1
-	col := 1
-	for i := 0; i < offset; i++ {
-		if source[i] == '\n' {
-			line++
-			col = 1
-			continue
-		}
-		col++
-	}
-	return line, col
+	b := ctx.NewBuilder()
+	b.InsertArgBefore(cmd, 0, "--no-pager")
+	return builtEdits(b)
 }
 
 func checkZC1209(node ast.Node) []Violation {
@@ -504,15 +455,15 @@ func init() {
 		Severity: SeverityStyle,
 		Description: "`journalctl` invokes a pager by default which hangs in non-interactive scripts. " +
 			"Use `--no-pager` for reliable script output.",
-		Check: checkZC1210,
-		Fix:   fixZC1210,
+		Check:      checkZC1210,
+		FixContext: fixZC1210,
 	})
 }
 
 // fixZC1210 inserts ` --no-pager` after the `journalctl` command
 // name, preventing the pager from hanging in non-interactive runs.
 // Mirrors ZC1209's insertion for `systemctl`.
-func fixZC1210(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1210(ctx *Context, node ast.Node, v Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -521,42 +472,9 @@ func fixZC1210(node ast.Node, v Violation, source []byte) []FixEdit {
 	if !ok || ident.Value != "journalctl" {
 		return nil
 	}
-	nameOff := LineColToByteOffset(source, v.Line, v.Column)
-	if nameOff < 0 {
-		return nil
-	}
-	nameLen := IdentLenAt(source, nameOff)
-	if nameLen != len("journalctl") {
-		return nil
-	}
-	insertAt := nameOff + nameLen
-	insLine, insCol := offsetLineColZC1210(source, insertAt)
-	if insLine < 0 {
-		return nil
-	}
-	return []FixEdit{{
-		Line:    insLine,
-		Column:  insCol,
-		Length:  0,
-		Replace: " --no-pager",
-	}}
-}
-
-func offsetLineColZC1210(source []byte, offset int) (int, int) {
-	if offset < 0 || offset > len(source) {
-		return -1, -1
-	}
-	line := 1
-	col := 1
-	for i := 0; i < offset; i++ {
-		if source[i] == '\n' {
-			line++
-			col = 1
-			continue
-		}
-		col++
-	}
-	return line, col
+	b := ctx.NewBuilder()
+	b.InsertArgBefore(cmd, 0, "--no-pager")
+	return builtEdits(b)
 }
 
 func checkZC1210(node ast.Node) []Violation {
@@ -695,15 +613,15 @@ func init() {
 		Severity: SeverityWarning,
 		Description: "`apt-get install` without `-y` prompts for confirmation which hangs scripts. " +
 			"Use `-y` or set `DEBIAN_FRONTEND=noninteractive` for unattended installs.",
-		Check: checkZC1213,
-		Fix:   fixZC1213,
+		Check:      checkZC1213,
+		FixContext: fixZC1213,
 	})
 }
 
 // fixZC1213 inserts ` -y` after `apt-get` so install / upgrade /
 // dist-upgrade run without interactive confirmation. Detector
 // already guards the shape (install-class subcommand + no -y).
-func fixZC1213(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1213(ctx *Context, node ast.Node, v Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -712,42 +630,9 @@ func fixZC1213(node ast.Node, v Violation, source []byte) []FixEdit {
 	if !ok || ident.Value != "apt-get" {
 		return nil
 	}
-	nameOff := LineColToByteOffset(source, v.Line, v.Column)
-	if nameOff < 0 {
-		return nil
-	}
-	nameLen := IdentLenAt(source, nameOff)
-	if nameLen != len("apt-get") {
-		return nil
-	}
-	insertAt := nameOff + nameLen
-	insLine, insCol := offsetLineColZC1213(source, insertAt)
-	if insLine < 0 {
-		return nil
-	}
-	return []FixEdit{{
-		Line:    insLine,
-		Column:  insCol,
-		Length:  0,
-		Replace: " -y",
-	}}
-}
-
-func offsetLineColZC1213(source []byte, offset int) (int, int) {
-	if offset < 0 || offset > len(source) {
-		return -1, -1
-	}
-	line := 1
-	col := 1
-	for i := 0; i < offset; i++ {
-		if source[i] == '\n' {
-			line++
-			col = 1
-			continue
-		}
-		col++
-	}
-	return line, col
+	b := ctx.NewBuilder()
+	b.InsertArgBefore(cmd, 0, "-y")
+	return builtEdits(b)
 }
 
 func checkZC1213(node ast.Node) []Violation {
@@ -827,8 +712,8 @@ func init() {
 		Severity: SeverityStyle,
 		Description: "`/etc/os-release` is designed to be sourced directly. " +
 			"Use `. /etc/os-release` to get variables like `$ID`, `$VERSION_ID` without parsing.",
-		Check: checkZC1215,
-		Fix:   fixZC1215,
+		Check:      checkZC1215,
+		FixContext: fixZC1215,
 	})
 }
 
@@ -837,7 +722,7 @@ func init() {
 // name with the source builtin `.`. Only fires when cat has exactly
 // one argument; piped or multi-file shapes are left alone. Idempotent
 // — a re-run sees `.`, not `cat`. Defensive byte-match guard.
-func fixZC1215(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1215(ctx *Context, node ast.Node, v Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -853,19 +738,9 @@ func fixZC1215(node ast.Node, v Violation, source []byte) []FixEdit {
 	if val != "/etc/os-release" && val != "/etc/lsb-release" {
 		return nil
 	}
-	off := LineColToByteOffset(source, v.Line, v.Column)
-	if off < 0 || off+len("cat") > len(source) {
-		return nil
-	}
-	if string(source[off:off+len("cat")]) != "cat" {
-		return nil
-	}
-	return []FixEdit{{
-		Line:    v.Line,
-		Column:  v.Column,
-		Length:  len("cat"),
-		Replace: ".",
-	}}
+	b := ctx.NewBuilder()
+	b.ReplaceCommandName(cmd, ".")
+	return builtEdits(b)
 }
 
 func checkZC1215(node ast.Node) []Violation {
@@ -903,8 +778,8 @@ func init() {
 		Severity: SeverityInfo,
 		Description: "`nslookup` is deprecated in many distributions. " +
 			"`dig` provides more detailed output and `host` is simpler for basic lookups.",
-		Check: checkZC1216,
-		Fix:   fixZC1216,
+		Check:      checkZC1216,
+		FixContext: fixZC1216,
 	})
 }
 
@@ -912,7 +787,7 @@ func init() {
 // `host <name>` matches the most common `nslookup <name>` invocation;
 // arguments stay untouched and exotic nslookup-only flags need manual
 // review.
-func fixZC1216(node ast.Node, v Violation, _ []byte) []FixEdit {
+func fixZC1216(ctx *Context, node ast.Node, v Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -921,12 +796,9 @@ func fixZC1216(node ast.Node, v Violation, _ []byte) []FixEdit {
 	if !ok || ident.Value != "nslookup" {
 		return nil
 	}
-	return []FixEdit{{
-		Line:    v.Line,
-		Column:  v.Column,
-		Length:  len("nslookup"),
-		Replace: "host",
-	}}
+	b := ctx.NewBuilder()
+	b.ReplaceCommandName(cmd, "host")
+	return builtEdits(b)
 }
 
 func checkZC1216(node ast.Node) []Violation {
@@ -961,7 +833,7 @@ func init() {
 		// Reuse the `service UNIT VERB` → `systemctl VERB UNIT` rewrite
 		// from ZC1512. Both detectors fire on the same shape; the
 		// conflict resolver dedupes overlapping edits.
-		Fix: fixZC1512,
+		FixContext: fixZC1512,
 	})
 }
 
@@ -1042,8 +914,8 @@ func init() {
 		Severity: SeverityStyle,
 		Description: "`wget -O -` outputs to stdout but lacks `curl`'s error handling. " +
 			"`curl -fsSL` fails on HTTP errors, is silent, follows redirects, and is more portable.",
-		Check: checkZC1219,
-		Fix:   fixZC1219,
+		Check:      checkZC1219,
+		FixContext: fixZC1219,
 	})
 }
 
@@ -1052,7 +924,7 @@ func init() {
 // `-O-`/`-qO-` flag in a single edit so the rewrite stays deterministic
 // even if a separate kata also fires on the `wget` name; trailing URL
 // argument(s) stay in place.
-func fixZC1219(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1219(ctx *Context, node ast.Node, v Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -1072,28 +944,24 @@ func fixZC1219(node ast.Node, v Violation, source []byte) []FixEdit {
 	if flag == nil {
 		return nil
 	}
-	nameOff := LineColToByteOffset(source, v.Line, v.Column)
-	if nameOff < 0 || nameOff+len("wget") > len(source) {
-		return nil
-	}
-	if string(source[nameOff:nameOff+len("wget")]) != "wget" {
-		return nil
-	}
-	flagTok := flag.TokenLiteralNode()
-	flagOff := LineColToByteOffset(source, flagTok.Line, flagTok.Column)
-	flagLen := len(flag.String())
-	if flagOff < 0 || flagOff+flagLen > len(source) {
+	return replaceNameThrough(ctx, cmd, flag, "curl -fsSL")
+}
+
+// replaceNameThrough replaces the source from the name of cmd through
+// the end of its argument last with text, folding a flag into a new
+// command name in one edit.
+func replaceNameThrough(ctx *Context, cmd *ast.SimpleCommand, last ast.Node, text string) []FixEdit {
+	b := ctx.NewBuilder()
+	start, _, ok := b.Span(cmd.Name)
+	if !ok {
 		return nil
 	}
-	if string(source[flagOff:flagOff+flagLen]) != flag.String() {
+	_, end, ok := b.Span(last)
+	if !ok {
 		return nil
 	}
-	return []FixEdit{{
-		Line:    v.Line,
-		Column:  v.Column,
-		Length:  flagOff + flagLen - nameOff,
-		Replace: "curl -fsSL",
-	}}
+	b.Replace(start, end, text)
+	return builtEdits(b)
 }
 
 func checkZC1219(node ast.Node) []Violation {
@@ -1354,14 +1222,14 @@ func init() {
 		Severity: SeverityStyle,
 		Description: "`dmesg` without `-T` shows raw kernel timestamps in seconds since boot. " +
 			"Use `-T` for human-readable timestamps or `--time-format=iso` for ISO 8601.",
-		Check: checkZC1226,
-		Fix:   fixZC1226,
+		Check:      checkZC1226,
+		FixContext: fixZC1226,
 	})
 }
 
 // fixZC1226 inserts ` -T` after the `dmesg` command name. Mirrors
 // other insertion-style fixes (ZC1012 / ZC1017 / ZC1170 / ZC1209).
-func fixZC1226(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1226(ctx *Context, node ast.Node, v Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -1370,42 +1238,9 @@ func fixZC1226(node ast.Node, v Violation, source []byte) []FixEdit {
 	if !ok || ident.Value != "dmesg" {
 		return nil
 	}
-	nameOff := LineColToByteOffset(source, v.Line, v.Column)
-	if nameOff < 0 {
-		return nil
-	}
-	nameLen := IdentLenAt(source, nameOff)
-	if nameLen != len("dmesg") {
-		return nil
-	}
-	insertAt := nameOff + nameLen
-	insLine, insCol := offsetLineColZC1226(source, insertAt)
-	if insLine < 0 {
-		return nil
-	}
-	return []FixEdit{{
-		Line:    insLine,
-		Column:  insCol,
-		Length:  0,
-		Replace: " -T",
-	}}
-}
-
-func offsetLineColZC1226(source []byte, offset int) (int, int) {
-	if offset < 0 || offset > len(source) {
-		return -1, -1
-	}
-	line := 1
-	col := 1
-	for i := 0; i < offset; i++ {
-		if source[i] == '\n' {
-			line++
-			col = 1
-			continue
-		}
-		col++
-	}
-	return line, col
+	b := ctx.NewBuilder()
+	b.InsertArgBefore(cmd, 0, "-T")
+	return builtEdits(b)
 }
 
 func checkZC1226(node ast.Node) []Violation {
@@ -1448,15 +1283,15 @@ func init() {
 		Severity: SeverityWarning,
 		Description: "`curl` without `-f` silently returns error pages (404, 500) as success. " +
 			"Use `-f` or `--fail` to return exit code 22 on HTTP errors.",
-		Check: checkZC1227,
-		Fix:   fixZC1227,
+		Check:      checkZC1227,
+		FixContext: fixZC1227,
 	})
 }
 
 // fixZC1227 inserts ` -f` after the `curl` command name so HTTP
 // errors translate into a non-zero exit code. Detector guards the
 // shape (URL arg present, no existing -f/-fsSL/etc.).
-func fixZC1227(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1227(ctx *Context, node ast.Node, v Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -1465,42 +1300,9 @@ func fixZC1227(node ast.Node, v Violation, source []byte) []FixEdit {
 	if !ok || ident.Value != "curl" {
 		return nil
 	}
-	nameOff := LineColToByteOffset(source, v.Line, v.Column)
-	if nameOff < 0 {
-		return nil
-	}
-	nameLen := IdentLenAt(source, nameOff)
-	if nameLen != len("curl") {
-		return nil
-	}
-	insertAt := nameOff + nameLen
-	insLine, insCol := offsetLineColZC1227(source, insertAt)
-	if insLine < 0 {
-		return nil
-	}
-	return []FixEdit{{
-		Line:    insLine,
-		Column:  insCol,
-		Length:  0,
-		Replace: " -f",
-	}}
-}
-
-func offsetLineColZC1227(source []byte, offset int) (int, int) {
-	if offset < 0 || offset > len(source) {
-		return -1, -1
-	}
-	line := 1
-	col := 1
-	for i := 0; i < offset; i++ {
-		if source[i] == '\n' {
-			line++
-			col = 1
-			continue
-		}
-		col++
-	}
-	return line, col
+	b := ctx.NewBuilder()
+	b.InsertArgBefore(cmd, 0, "-f")
+	return builtEdits(b)
 }
 
 func checkZC1227(node ast.Node) []Violation {
@@ -1625,15 +1427,15 @@ func init() {
 		Severity: SeverityWarning,
 		Description: "`ping` without `-c` runs indefinitely on Linux, hanging scripts. " +
 			"Always specify `-c N` to limit the number of packets.",
-		Check: checkZC1230,
-		Fix:   fixZC1230,
+		Check:      checkZC1230,
+		FixContext: fixZC1230,
 	})
 }
 
 // fixZC1230 inserts ` -c 4` after the `ping` command name. Detector
 // already guards against an existing `-c` / `-W` flag, so the
 // insertion is safe and idempotent on a re-run.
-func fixZC1230(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1230(ctx *Context, node ast.Node, v Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -1642,41 +1444,9 @@ func fixZC1230(node ast.Node, v Violation, source []byte) []FixEdit {
 	if !ok || ident.Value != "ping" {
 		return nil
 	}
-	nameOff := LineColToByteOffset(source, v.Line, v.Column)
-	if nameOff < 0 {
-		return nil
-	}
-	if IdentLenAt(source, nameOff) != len("ping") {
-		return nil
-	}
-	insertAt := nameOff + len("ping")
-	insLine, insCol := offsetLineColZC1230(source, insertAt)
-	if insLine < 0 {
-		return nil
-	}
-	return []FixEdit{{
-		Line:    insLine,
-		Column:  insCol,
-		Length:  0,
-		Replace: " -c 4",
-	}}
-}
-
-func offsetLineColZC1230(source []byte, offset int) (int, int) {
-	if offset < 0 || offset > len(source) {
-		return -1, -1
-	}
-	line := 1
-	col := 1
-	for i := 0; i < offset; i++ {
-		if source[i] == '\n' {
-			line++
-			col = 1
-			continue
-		}
-		col++
-	}
-	return line, col
+	b := ctx.NewBuilder()
+	b.InsertArgBefore(cmd, 0, "-c 4")
+	return builtEdits(b)
 }
 
 func checkZC1230(node ast.Node) []Violation {
@@ -1719,15 +1489,15 @@ func init() {
 		Severity: SeverityStyle,
 		Description: "`git clone` without `--depth` downloads the entire history. " +
 			"Use `--depth 1` in CI/build scripts where only the latest commit is needed.",
-		Check: checkZC1231,
-		Fix:   fixZC1231,
+		Check:      checkZC1231,
+		FixContext: fixZC1231,
 	})
 }
 
 // fixZC1231 inserts ` --depth 1` after the `clone` subcommand in
 // `git clone …`. Mirrors ZC1234's subcommand-level insertion for
 // docker run --rm.
-func fixZC1231(node ast.Node, _ Violation, source []byte) []FixEdit {
+func fixZC1231(ctx *Context, node ast.Node, _ Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -1735,46 +1505,12 @@ func fixZC1231(node ast.Node, _ Violation, source []byte) []FixEdit {
 	if len(cmd.Arguments) == 0 {
 		return nil
 	}
-	cloneArg := cmd.Arguments[0]
-	if cloneArg.String() != "clone" {
-		return nil
-	}
-	tok := cloneArg.TokenLiteralNode()
-	off := LineColToByteOffset(source, tok.Line, tok.Column)
-	if off < 0 || off+5 > len(source) {
-		return nil
-	}
-	if string(source[off:off+5]) != "clone" {
-		return nil
-	}
-	insertAt := off + 5
-	insLine, insCol := offsetLineColZC1231(source, insertAt)
-	if insLine < 0 {
+	if cmd.Arguments[0].String() != "clone" {
 		return nil
 	}
-	return []FixEdit{{
-		Line:    insLine,
-		Column:  insCol,
-		Length:  0,
-		Replace: " --depth 1",
-	}}
-}
-
-func offsetLineColZC1231(source []byte, offset int) (int, int) {
-	if offset < 0 || offset > len(source) {
-		return -1, -1
-	}
-	line := 1
-	col := 1
-	for i := 0; i < offset; i++ {
-		if source[i] == '\n' {
-			line++
-			col = 1
-			continue
-		}
-		col++
-	}
-	return line, col
+	b := ctx.NewBuilder()
+	b.InsertArgBefore(cmd, 1, "--depth 1")
+	return builtEdits(b)
 }
 
 func checkZC1231(node ast.Node) []Violation {
@@ -1927,15 +1663,15 @@ func init() {
 		Severity: SeverityStyle,
 		Description: "`docker run` without `--rm` leaves stopped containers behind. " +
 			"Use `--rm` in scripts to automatically clean up after execution.",
-		Check: checkZC1234,
-		Fix:   fixZC1234,
+		Check:      checkZC1234,
+		FixContext: fixZC1234,
 	})
 }
 
 // fixZC1234 inserts ` --rm` after the `run` subcommand in a
 // `docker run …` invocation. Detector has already verified the shape
 // (docker + run + no --rm + no -d).
-func fixZC1234(node ast.Node, _ Violation, source []byte) []FixEdit {
+func fixZC1234(ctx *Context, node ast.Node, _ Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -1943,46 +1679,12 @@ func fixZC1234(node ast.Node, _ Violation, source []byte) []FixEdit {
 	if len(cmd.Arguments) == 0 {
 		return nil
 	}
-	runArg := cmd.Arguments[0]
-	if runArg.String() != "run" {
+	if cmd.Arguments[0].String() != "run" {
 		return nil
 	}
-	tok := runArg.TokenLiteralNode()
-	off := LineColToByteOffset(source, tok.Line, tok.Column)
-	if off < 0 || off+3 > len(source) {
-		return nil
-	}
-	if string(source[off:off+3]) != "run" {
-		return nil
-	}
-	insertAt := off + 3
-	insLine, insCol := offsetLineColZC1234(source, insertAt)
-	if insLine < 0 {
-		return nil
-	}
-	return []FixEdit{{
-		Line:    insLine,
-		Column:  insCol,
-		Length:  0,
-		Replace: " --rm",
-	}}
-}
-
-func offsetLineColZC1234(source []byte, offset int) (int, int) {
-	if offset < 0 || offset > len(source) {
-		return -1, -1
-	}
-	line := 1
-	col := 1
-	for i := 0; i < offset; i++ {
-		if source[i] == '\n' {
-			line++
-			col = 1
-			continue
-		}
-		col++
-	}
-	return line, col
+	b := ctx.NewBuilder()
+	b.InsertArgBefore(cmd, 1, "--rm")
+	return builtEdits(b)
 }
 
 func checkZC1234(node ast.Node) []Violation {
@@ -2034,15 +1736,15 @@ func init() {
 		Severity: SeverityWarning,
 		Description: "`git push --force` overwrites remote history unconditionally. " +
 			"`--force-with-lease` is safer as it fails if the remote has changed.",
-		Check: checkZC1235,
-		Fix:   fixZC1235,
+		Check:      checkZC1235,
+		FixContext: fixZC1235,
 	})
 }
 
 // fixZC1235 rewrites `git push -f` to `git push --force-with-lease`.
 // Single-edit replacement of the `-f` flag at its argument position;
 // surrounding subcommand and refspec arguments stay in place.
-func fixZC1235(node ast.Node, _ Violation, source []byte) []FixEdit {
+func fixZC1235(ctx *Context, node ast.Node, _ Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -2058,20 +1760,9 @@ func fixZC1235(node ast.Node, _ Violation, source []byte) []FixEdit {
 		if arg.String() != "-f" {
 			continue
 		}
-		tok := arg.TokenLiteralNode()
-		off := LineColToByteOffset(source, tok.Line, tok.Column)
-		if off < 0 || off+2 > len(source) {
-			return nil
-		}
-		if string(source[off:off+2]) != "-f" {
-			return nil
-		}
-		return []FixEdit{{
-			Line:    tok.Line,
-			Column:  tok.Column,
-			Length:  2,
-			Replace: "--force-with-lease",
-		}}
+		b := ctx.NewBuilder()
+		b.ReplaceNode(arg, "--force-with-lease")
+		return builtEdits(b)
 	}
 	return nil
 }
@@ -2218,15 +1909,15 @@ func init() {
 		Severity: SeverityWarning,
 		Description: "`docker exec -it` allocates a TTY and attaches stdin, which hangs " +
 			"in non-interactive scripts. Use `docker exec` without `-it` for scripted commands.",
-		Check: checkZC1238,
-		Fix:   fixZC1238,
+		Check:      checkZC1238,
+		FixContext: fixZC1238,
 	})
 }
 
 // fixZC1238 strips the `-it` (or `-ti`) flag from a `docker exec`
 // invocation. The span covers the leading whitespace plus the flag
 // token so the surrounding source stays byte-identical.
-func fixZC1238(node ast.Node, _ Violation, source []byte) []FixEdit {
+func fixZC1238(ctx *Context, node ast.Node, _ Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -2238,58 +1929,24 @@ func fixZC1238(node ast.Node, _ Violation, source []byte) []FixEdit {
 	if len(cmd.Arguments) < 1 || cmd.Arguments[0].String() != "exec" {
 		return nil
 	}
-	for _, arg := range cmd.Arguments[1:] {
+	for i, arg := range cmd.Arguments {
+		if i == 0 {
+			continue
+		}
 		if v := arg.String(); v == "-it" || v == "-ti" {
-			return zc1238StripFlag(source, arg, v)
+			return zc1238StripFlag(ctx, cmd, i)
 		}
 	}
 	return nil
 }
 
-// zc1238StripFlag deletes the flag arg plus the run of horizontal
-// whitespace immediately preceding it; the leading space the user
-// typed disappears with the flag, leaving `docker exec CMD`.
-func zc1238StripFlag(source []byte, arg ast.Expression, lit string) []FixEdit {
-	tok := arg.TokenLiteralNode()
-	off := LineColToByteOffset(source, tok.Line, tok.Column)
-	if off < 0 || off+len(lit) > len(source) {
-		return nil
-	}
-	if string(source[off:off+len(lit)]) != lit {
-		return nil
-	}
-	start := off
-	for start > 0 && (source[start-1] == ' ' || source[start-1] == '\t') {
-		start--
-	}
-	end := off + len(lit)
-	startLine, startCol := offsetLineColZC1238(source, start)
-	if startLine < 0 {
-		return nil
-	}
-	return []FixEdit{{
-		Line:    startLine,
-		Column:  startCol,
-		Length:  end - start,
-		Replace: "",
-	}}
-}
-
-func offsetLineColZC1238(source []byte, offset int) (int, int) {
-	if offset < 0 || offset > len(source) {
-		return -1, -1
-	}
-	line := 1
-	col := 1
-	for i := 0; i < offset; i++ {
-		if source[i] == '\n' {
-			line++
-			col = 1
-			continue
-		}
-		col++
-	}
-	return line, col
+// zc1238StripFlag deletes argument i of cmd plus the whitespace
+// immediately preceding it; the leading space the user typed
+// disappears with the flag, leaving `docker exec CMD`.
+func zc1238StripFlag(ctx *Context, cmd *ast.SimpleCommand, i int) []FixEdit {
+	b := ctx.NewBuilder()
+	b.RemoveArg(cmd, i)
+	return builtEdits(b)
 }
 
 func checkZC1238(node ast.Node) []Violation {
@@ -2331,14 +1988,14 @@ func init() {
 		Severity: SeverityWarning,
 		Description: "`kubectl exec -it` allocates a TTY which hangs in non-interactive scripts. " +
 			"Use `kubectl exec` without `-it` or use `kubectl exec -- cmd` for scripted commands.",
-		Check: checkZC1239,
-		Fix:   fixZC1239,
+		Check:      checkZC1239,
+		FixContext: fixZC1239,
 	})
 }
 
 // fixZC1239 strips the `-it` (or `-ti`) flag from a `kubectl exec`
 // invocation. Reuses the token-strip helper introduced for ZC1238.
-func fixZC1239(node ast.Node, _ Violation, source []byte) []FixEdit {
+func fixZC1239(ctx *Context, node ast.Node, _ Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -2350,9 +2007,12 @@ func fixZC1239(node ast.Node, _ Violation, source []byte) []FixEdit {
 	if len(cmd.Arguments) < 1 || cmd.Arguments[0].String() != "exec" {
 		return nil
 	}
-	for _, arg := range cmd.Arguments[1:] {
+	for i, arg := range cmd.Arguments {
+		if i == 0 {
+			continue
+		}
 		if v := arg.String(); v == "-it" || v == "-ti" {
-			return zc1238StripFlag(source, arg, v)
+			return zc1238StripFlag(ctx, cmd, i)
 		}
 	}
 	return nil
@@ -2446,15 +2106,15 @@ func init() {
 		Severity: SeverityWarning,
 		Description: "`xargs` without `-0` splits on whitespace, breaking on filenames with spaces. " +
 			"Use `xargs -0` paired with `find -print0` for safe handling.",
-		Check: checkZC1241,
-		Fix:   fixZC1241,
+		Check:      checkZC1241,
+		FixContext: fixZC1241,
 	})
 }
 
 // fixZC1241 inserts ` -0` after the `xargs` command name so
 // null-terminated input from `find -print0` is consumed safely.
 // Detector gates on `xargs rm` without `-0`.
-func fixZC1241(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1241(ctx *Context, node ast.Node, v Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -2463,42 +2123,9 @@ func fixZC1241(node ast.Node, v Violation, source []byte) []FixEdit {
 	if !ok || ident.Value != "xargs" {
 		return nil
 	}
-	nameOff := LineColToByteOffset(source, v.Line, v.Column)
-	if nameOff < 0 {
-		return nil
-	}
-	nameLen := IdentLenAt(source, nameOff)
-	if nameLen != len("xargs") {
-		return nil
-	}
-	insertAt := nameOff + nameLen
-	insLine, insCol := offsetLineColZC1241(source, insertAt)
-	if insLine < 0 {
-		return nil
-	}
-	return []FixEdit{{
-		Line:    insLine,
-		Column:  insCol,
-		Length:  0,
-		Replace: " -0",
-	}}
-}
-
-func offsetLineColZC1241(source []byte, offset int) (int, int) {
-	if offset < 0 || offset > len(source) {
-		return -1, -1
-	}
-	line := 1
-	col := 1
-	for i := 0; i < offset; i++ {
-		if source[i] == '\n' {
-			line++
-			col = 1
-			continue
-		}
-		col++
-	}
-	return line, col
+	b := ctx.NewBuilder()
+	b.InsertArgBefore(cmd, 0, "-0")
+	return builtEdits(b)
 }
 
 func checkZC1241(node ast.Node) []Violation {
@@ -2995,8 +2622,8 @@ func init() {
 		Severity: SeverityStyle,
 		Description: "`cat /etc/passwd` misses users from LDAP, NIS, or SSSD sources. " +
 			"`getent passwd` queries NSS and returns all configured user databases.",
-		Check: checkZC1252,
-		Fix:   fixZC1252,
+		Check:      checkZC1252,
+		FixContext: fixZC1252,
 	})
 }
 
@@ -3007,7 +2634,7 @@ func init() {
 // (ZC1146 handles `cat FILE | tool`, and multi-file `cat` doesn't
 // translate cleanly to `getent`). Idempotent: a re-run sees `getent`,
 // not `cat`, so the detector won't fire.
-func fixZC1252(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1252(ctx *Context, node ast.Node, v Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -3020,9 +2647,8 @@ func fixZC1252(node ast.Node, v Violation, source []byte) []FixEdit {
 		return nil
 	}
 	arg := cmd.Arguments[0]
-	val := arg.String()
 	var dbName string
-	switch val {
+	switch arg.String() {
 	case "/etc/passwd":
 		dbName = "passwd"
 	case "/etc/group":
@@ -3032,25 +2658,10 @@ func fixZC1252(node ast.Node, v Violation, source []byte) []FixEdit {
 	default:
 		return nil
 	}
-	catOff := LineColToByteOffset(source, v.Line, v.Column)
-	if catOff < 0 || catOff+len("cat") > len(source) {
-		return nil
-	}
-	if string(source[catOff:catOff+len("cat")]) != "cat" {
-		return nil
-	}
-	argTok := arg.TokenLiteralNode()
-	argOff := LineColToByteOffset(source, argTok.Line, argTok.Column)
-	if argOff < 0 || argOff+len(val) > len(source) {
-		return nil
-	}
-	if string(source[argOff:argOff+len(val)]) != val {
-		return nil
-	}
-	return []FixEdit{
-		{Line: v.Line, Column: v.Column, Length: len("cat"), Replace: "getent"},
-		{Line: argTok.Line, Column: argTok.Column, Length: len(val), Replace: dbName},
-	}
+	b := ctx.NewBuilder()
+	b.ReplaceCommandName(cmd, "getent")
+	b.ReplaceNode(arg, dbName)
+	return builtEdits(b)
 }
 
 func checkZC1252(node ast.Node) []Violation {
@@ -3088,14 +2699,14 @@ func init() {
 		Severity: SeverityStyle,
 		Description: "`docker build` uses layer caching which can mask dependency changes. " +
 			"Use `--no-cache` in CI pipelines to ensure fully reproducible builds.",
-		Check: checkZC1253,
-		Fix:   fixZC1253,
+		Check:      checkZC1253,
+		FixContext: fixZC1253,
 	})
 }
 
 // fixZC1253 inserts ` --no-cache` after the `build` subcommand in
 // `docker build …`. Mirrors ZC1234's subcommand-level insertion.
-func fixZC1253(node ast.Node, _ Violation, source []byte) []FixEdit {
+func fixZC1253(ctx *Context, node ast.Node, _ Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -3103,46 +2714,12 @@ func fixZC1253(node ast.Node, _ Violation, source []byte) []FixEdit {
 	if len(cmd.Arguments) == 0 {
 		return nil
 	}
-	subArg := cmd.Arguments[0]
-	if subArg.String() != "build" {
-		return nil
-	}
-	tok := subArg.TokenLiteralNode()
-	off := LineColToByteOffset(source, tok.Line, tok.Column)
-	if off < 0 || off+5 > len(source) {
-		return nil
-	}
-	if string(source[off:off+5]) != "build" {
-		return nil
-	}
-	insertAt := off + 5
-	insLine, insCol := offsetLineColZC1253(source, insertAt)
-	if insLine < 0 {
+	if cmd.Arguments[0].String() != "build" {
 		return nil
 	}
-	return []FixEdit{{
-		Line:    insLine,
-		Column:  insCol,
-		Length:  0,
-		Replace: " --no-cache",
-	}}
-}
-
-func offsetLineColZC1253(source []byte, offset int) (int, int) {
-	if offset < 0 || offset > len(source) {
-		return -1, -1
-	}
-	line := 1
-	col := 1
-	for i := 0; i < offset; i++ {
-		if source[i] == '\n' {
-			line++
-			col = 1
-			continue
-		}
-		col++
-	}
-	return line, col
+	b := ctx.NewBuilder()
+	b.InsertArgBefore(cmd, 1, "--no-cache")
+	return builtEdits(b)
 }
 
 func checkZC1253(node ast.Node) []Violation {
@@ -3232,15 +2809,15 @@ func init() {
 		Severity: SeverityInfo,
 		Description: "`curl` without `-L` does not follow redirects, returning 301/302 responses " +
 			"instead of the actual content. Use `-L` to follow redirects automatically.",
-		Check: checkZC1255,
-		Fix:   fixZC1255,
+		Check:      checkZC1255,
+		FixContext: fixZC1255,
 	})
 }
 
 // fixZC1255 inserts ` -L` after the `curl` command name. Detector
 // already guards against any existing follow-redirect flag, so the
 // insertion is idempotent on a re-run.
-func fixZC1255(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1255(ctx *Context, node ast.Node, v Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -3249,41 +2826,9 @@ func fixZC1255(node ast.Node, v Violation, source []byte) []FixEdit {
 	if !ok || ident.Value != "curl" {
 		return nil
 	}
-	nameOff := LineColToByteOffset(source, v.Line, v.Column)
-	if nameOff < 0 {
-		return nil
-	}
-	if IdentLenAt(source, nameOff) != len("curl") {
-		return nil
-	}
-	insertAt := nameOff + len("curl")
-	insLine, insCol := offsetLineColZC1255(source, insertAt)
-	if insLine < 0 {
-		return nil
-	}
-	return []FixEdit{{
-		Line:    insLine,
-		Column:  insCol,
-		Length:  0,
-		Replace: " -L",
-	}}
-}
-
-func offsetLineColZC1255(source []byte, offset int) (int, int) {
-	if offset < 0 || offset > len(source) {
-		return -1, -1
-	}
-	line := 1
-	col := 1
-	for i := 0; i < offset; i++ {
-		if source[i] == '\n' {
-			line++
-			col = 1
-			continue
-		}
-		col++
-	}
-	return line, col
+	b := ctx.NewBuilder()
+	b.InsertArgBefore(cmd, 0, "-L")
+	return builtEdits(b)
 }
 
 func checkZC1255(node ast.Node) []Violation {
@@ -3363,15 +2908,15 @@ func init() {
 		Severity: SeverityStyle,
 		Description: "`docker stop` defaults to 10s before SIGKILL. In CI scripts, " +
 			"set an explicit timeout with `-t` to control shutdown behavior.",
-		Check: checkZC1257,
-		Fix:   fixZC1257,
+		Check:      checkZC1257,
+		FixContext: fixZC1257,
 	})
 }
 
 // fixZC1257 inserts ` -t 10` after the `stop` subcommand of a
 // `docker stop …` invocation. Mirrors the subcommand-level pattern
 // used by ZC1265 (`systemctl enable --now`).
-func fixZC1257(node ast.Node, _ Violation, source []byte) []FixEdit {
+func fixZC1257(ctx *Context, node ast.Node, _ Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -3383,43 +2928,9 @@ func fixZC1257(node ast.Node, _ Violation, source []byte) []FixEdit {
 	if len(cmd.Arguments) < 1 || cmd.Arguments[0].String() != "stop" {
 		return nil
 	}
-	stopArg := cmd.Arguments[0]
-	tok := stopArg.TokenLiteralNode()
-	off := LineColToByteOffset(source, tok.Line, tok.Column)
-	if off < 0 || off+len("stop") > len(source) {
-		return nil
-	}
-	if string(source[off:off+len("stop")]) != "stop" {
-		return nil
-	}
-	insertAt := off + len("stop")
-	insLine, insCol := offsetLineColZC1257(source, insertAt)
-	if insLine < 0 {
-		return nil
-	}
-	return []FixEdit{{
-		Line:    insLine,
-		Column:  insCol,
-		Length:  0,
-		Replace: " -t 10",
-	}}
-}
-
-func offsetLineColZC1257(source []byte, offset int) (int, int) {
-	if offset < 0 || offset > len(source) {
-		return -1, -1
-	}
-	line := 1
-	col := 1
-	for i := 0; i < offset; i++ {
-		if source[i] == '\n' {
-			line++
-			col = 1
-			continue
-		}
-		col++
-	}
-	return line, col
+	b := ctx.NewBuilder()
+	b.InsertArgBefore(cmd, 1, "-t 10")
+	return builtEdits(b)
 }
 
 func checkZC1257(node ast.Node) []Violation {
@@ -3555,15 +3066,15 @@ func init() {
 		Severity: SeverityWarning,
 		Description: "`git branch -D` force-deletes branches even if unmerged. " +
 			"Use `-d` which refuses to delete unmerged branches, preventing data loss.",
-		Check: checkZC1260,
-		Fix:   fixZC1260,
+		Check:      checkZC1260,
+		FixContext: fixZC1260,
 	})
 }
 
 // fixZC1260 rewrites `git branch -D` to `git branch -d`. Single-character
 // flag swap at the `-D` argument position; surrounding subcommand and
 // branch-name arguments stay in place.
-func fixZC1260(node ast.Node, _ Violation, source []byte) []FixEdit {
+func fixZC1260(ctx *Context, node ast.Node, _ Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -3579,20 +3090,9 @@ func fixZC1260(node ast.Node, _ Violation, source []byte) []FixEdit {
 		if arg.String() != "-D" {
 			continue
 		}
-		tok := arg.TokenLiteralNode()
-		off := LineColToByteOffset(source, tok.Line, tok.Column)
-		if off < 0 || off+2 > len(source) {
-			return nil
-		}
-		if string(source[off:off+2]) != "-D" {
-			return nil
-		}
-		return []FixEdit{{
-			Line:    tok.Line,
-			Column:  tok.Column,
-			Length:  2,
-			Replace: "-d",
-		}}
+		b := ctx.NewBuilder()
+		b.ReplaceNode(arg, "-d")
+		return builtEdits(b)
 	}
 	return nil
 }
@@ -3723,15 +3223,15 @@ func init() {
 		Severity: SeverityStyle,
 		Description: "`apt` is designed for interactive use and its output format may change. " +
 			"`apt-get` has a stable interface suitable for scripts and CI.",
-		Check: checkZC1263,
-		Fix:   fixZC1263,
+		Check:      checkZC1263,
+		FixContext: fixZC1263,
 	})
 }
 
 // fixZC1263 rewrites `apt` to `apt-get` at the command name position.
 // Arguments stay intact — the two tools accept the same shape for
 // install / upgrade / update / remove.
-func fixZC1263(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1263(ctx *Context, node ast.Node, v Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -3740,12 +3240,9 @@ func fixZC1263(node ast.Node, v Violation, source []byte) []FixEdit {
 	if !ok || ident.Value != "apt" {
 		return nil
 	}
-	return []FixEdit{{
-		Line:    v.Line,
-		Column:  v.Column,
-		Length:  len("apt"),
-		Replace: "apt-get",
-	}}
+	b := ctx.NewBuilder()
+	b.ReplaceCommandName(cmd, "apt-get")
+	return builtEdits(b)
 }
 
 func checkZC1263(node ast.Node) []Violation {
@@ -3776,14 +3273,14 @@ func init() {
 		Severity: SeverityStyle,
 		Description: "`yum` is deprecated on Fedora 22+ and RHEL 8+. " +
 			"`dnf` is the modern replacement with better dependency resolution.",
-		Check: checkZC1264,
-		Fix:   fixZC1264,
+		Check:      checkZC1264,
+		FixContext: fixZC1264,
 	})
 }
 
 // fixZC1264 rewrites `yum` to `dnf`. dnf is broadly compatible with
 // yum's CLI surface so arguments carry over unchanged.
-func fixZC1264(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1264(ctx *Context, node ast.Node, v Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -3792,12 +3289,9 @@ func fixZC1264(node ast.Node, v Violation, source []byte) []FixEdit {
 	if !ok || ident.Value != "yum" {
 		return nil
 	}
-	return []FixEdit{{
-		Line:    v.Line,
-		Column:  v.Column,
-		Length:  len("yum"),
-		Replace: "dnf",
-	}}
+	b := ctx.NewBuilder()
+	b.ReplaceCommandName(cmd, "dnf")
+	return builtEdits(b)
 }
 
 func checkZC1264(node ast.Node) []Violation {
@@ -3828,15 +3322,15 @@ func init() {
 		Severity: SeverityStyle,
 		Description: "`systemctl enable` without `--now` only enables on next boot. " +
 			"Use `--now` to enable and immediately start the service.",
-		Check: checkZC1265,
-		Fix:   fixZC1265,
+		Check:      checkZC1265,
+		FixContext: fixZC1265,
 	})
 }
 
 // fixZC1265 inserts ` --now` after the `enable` subcommand in a
 // `systemctl enable …` invocation. Same subcommand-level insertion
 // pattern as ZC1234's docker-run --rm.
-func fixZC1265(node ast.Node, _ Violation, source []byte) []FixEdit {
+func fixZC1265(ctx *Context, node ast.Node, _ Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -3845,52 +3339,14 @@ func fixZC1265(node ast.Node, _ Violation, source []byte) []FixEdit {
 	if !ok || ident.Value != "systemctl" {
 		return nil
 	}
-	var enableArg ast.Expression
-	for _, arg := range cmd.Arguments {
+	for i, arg := range cmd.Arguments {
 		if arg.String() == "enable" {
-			enableArg = arg
-			break
-		}
-	}
-	if enableArg == nil {
-		return nil
-	}
-	tok := enableArg.TokenLiteralNode()
-	off := LineColToByteOffset(source, tok.Line, tok.Column)
-	if off < 0 || off+len("enable") > len(source) {
-		return nil
-	}
-	if string(source[off:off+len("enable")]) != "enable" {
-		return nil
-	}
-	insertAt := off + len("enable")
-	insLine, insCol := offsetLineColZC1265(source, insertAt)
-	if insLine < 0 {
-		return nil
-	}
-	return []FixEdit{{
-		Line:    insLine,
-		Column:  insCol,
-		Length:  0,
-		Replace: " --now",
-	}}
-}
-
-func offsetLineColZC1265(source []byte, offset int) (int, int) {
-	if offset < 0 || offset > len(source) {
-		return -1, -1
-	}
-	line := 1
-	col := 1
-	for i := 0; i < offset; i++ {
-		if source[i] == '\n' {
-			line++
-			col = 1
-			continue
+			b := ctx.NewBuilder()
+			b.InsertArgBefore(cmd, i+1, "--now")
+			return builtEdits(b)
 		}
-		col++
 	}
-	return line, col
+	return nil
 }
 
 func checkZC1265(node ast.Node) []Violation {
@@ -3977,15 +3433,15 @@ func init() {
 		Severity: SeverityStyle,
 		Description: "`df -h` output format varies across systems and locales. " +
 			"Use `df -P` for single-line, fixed-format output safe for script parsing.",
-		Check: checkZC1267,
-		Fix:   fixZC1267,
+		Check:      checkZC1267,
+		FixContext: fixZC1267,
 	})
 }
 
 // fixZC1267 inserts ` -P` after the `df` command name. Detector
 // narrows to `df -h` (script-unsafe), so only that shape is
 // rewritten.
-func fixZC1267(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1267(ctx *Context, node ast.Node, v Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -3994,42 +3450,9 @@ func fixZC1267(node ast.Node, v Violation, source []byte) []FixEdit {
 	if !ok || ident.Value != "df" {
 		return nil
 	}
-	nameOff := LineColToByteOffset(source, v.Line, v.Column)
-	if nameOff < 0 {
-		return nil
-	}
-	nameLen := IdentLenAt(source, nameOff)
-	if nameLen != len("df") {
-		return nil
-	}
-	insertAt := nameOff + nameLen
-	insLine, insCol := offsetLineColZC1267(source, insertAt)
-	if insLine < 0 {
-		return nil
-	}
-	return []FixEdit{{
-		Line:    insLine,
-		Column:  insCol,
-		Length:  0,
-		Replace: " -P",
-	}}
-}
-
-func offsetLineColZC1267(source []byte, offset int) (int, int) {
-	if offset < 0 || offset > len(source) {
-		return -1, -1
-	}
-	line := 1
-	col := 1
-	for i := 0; i < offset; i++ {
-		if source[i] == '\n' {
-			line++
-			col = 1
-			continue
-		}
-		col++
-	}
-	return line, col
+	b := ctx.NewBuilder()
+	b.InsertArgBefore(cmd, 0, "-P")
+	return builtEdits(b)
 }
 
 func checkZC1267(node ast.Node) []Violation {
@@ -4077,8 +3500,8 @@ func init() {
 		Severity: SeverityInfo,
 		Description: "`du -sh *` breaks if a filename starts with `-`. " +
 			"Use `--` to signal end of options and safely handle all filenames.",
-		Check: checkZC1268,
-		Fix:   fixZC1268,
+		Check:      checkZC1268,
+		FixContext: fixZC1268,
 	})
 }
 
@@ -4086,7 +3509,7 @@ func init() {
 // `du …` invocation that lacks the `--` end-of-options marker. The
 // detector already gates on a glob (`*` / `.`) being present, and on
 // the absence of `--`, so the insertion is idempotent.
-func fixZC1268(node ast.Node, _ Violation, source []byte) []FixEdit {
+func fixZC1268(ctx *Context, node ast.Node, _ Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -4096,49 +3519,18 @@ func fixZC1268(node ast.Node, _ Violation, source []byte) []FixEdit {
 		return nil
 	}
 	// Find the first positional (non-flag) argument.
-	var positional ast.Expression
 	for _, arg := range cmd.Arguments {
-		v := arg.String()
-		if len(v) > 0 && v[0] != '-' {
-			positional = arg
-			break
-		}
-	}
-	if positional == nil {
-		return nil
-	}
-	tok := positional.TokenLiteralNode()
-	off := LineColToByteOffset(source, tok.Line, tok.Column)
-	if off < 0 {
-		return nil
-	}
-	insLine, insCol := offsetLineColZC1268(source, off)
-	if insLine < 0 {
-		return nil
-	}
-	return []FixEdit{{
-		Line:    insLine,
-		Column:  insCol,
-		Length:  0,
-		Replace: "-- ",
-	}}
-}
-
-func offsetLineColZC1268(source []byte, offset int) (int, int) {
-	if offset < 0 || offset > len(source) {
-		return -1, -1
-	}
-	line := 1
-	col := 1
-	for i := 0; i < offset; i++ {
-		if source[i] == '\n' {
-			line++
-			col = 1
-			continue
+		if v := arg.String(); len(v) > 0 && v[0] != '-' {
+			b := ctx.NewBuilder()
+			start, _, ok := b.Span(arg)
+			if !ok {
+				return nil
+			}
+			b.Insert(start, "-- ")
+			return builtEdits(b)
 		}
-		col++
 	}
-	return line, col
+	return nil
 }
 
 func checkZC1268(node ast.Node) []Violation {
@@ -4268,14 +3660,14 @@ func init() {
 		Severity: SeverityStyle,
 		Description: "`which` is not POSIX-standard and behaves inconsistently across systems. " +
 			"Use `command -v` which is portable and built into Zsh.",
-		Check: checkZC1271,
-		Fix:   fixZC1271,
+		Check:      checkZC1271,
+		FixContext: fixZC1271,
 	})
 }
 
 // fixZC1271 rewrites `which` to `command -v` at the command name
 // position. Single replacement — arguments stay untouched.
-func fixZC1271(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1271(ctx *Context, node ast.Node, v Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -4284,12 +3676,9 @@ func fixZC1271(node ast.Node, v Violation, source []byte) []FixEdit {
 	if !ok || ident.Value != "which" {
 		return nil
 	}
-	return []FixEdit{{
-		Line:    v.Line,
-		Column:  v.Column,
-		Length:  len("which"),
-		Replace: "command -v",
-	}}
+	b := ctx.NewBuilder()
+	b.ReplaceCommandName(cmd, "command -v")
+	return builtEdits(b)
 }
 
 func checkZC1271(node ast.Node) []Violation {
@@ -4358,8 +3747,8 @@ func init() {
 		Severity: SeverityStyle,
 		Description: "`grep -q` suppresses output and exits on first match, which is faster and more " +
 			"idiomatic than piping or redirecting to `/dev/null`.",
-		Check: checkZC1273,
-		Fix:   fixZC1273,
+		Check:      checkZC1273,
+		FixContext: fixZC1273,
 	})
 }
 
@@ -4367,7 +3756,7 @@ func init() {
 // `/dev/null` argument (including its leading whitespace). Two edits;
 // the detector already gates on the absence of `-q`, so the rewrite
 // is idempotent on a re-run.
-func fixZC1273(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1273(ctx *Context, node ast.Node, v Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -4376,52 +3765,15 @@ func fixZC1273(node ast.Node, v Violation, source []byte) []FixEdit {
 	if !ok || ident.Value != "grep" {
 		return nil
 	}
-	var devNull ast.Expression
-	for _, arg := range cmd.Arguments {
+	for i, arg := range cmd.Arguments {
 		if arg.String() == "/dev/null" {
-			devNull = arg
-			break
-		}
-	}
-	if devNull == nil {
-		return nil
-	}
-	nameOff := LineColToByteOffset(source, v.Line, v.Column)
-	if nameOff < 0 || IdentLenAt(source, nameOff) != len("grep") {
-		return nil
-	}
-	insertAt := nameOff + len("grep")
-	insLine, insCol := offsetLineColZC1273(source, insertAt)
-	if insLine < 0 {
-		return nil
-	}
-	stripEdits := zc1238StripFlag(source, devNull, "/dev/null")
-	if stripEdits == nil {
-		return nil
-	}
-	return append([]FixEdit{{
-		Line:    insLine,
-		Column:  insCol,
-		Length:  0,
-		Replace: " -q",
-	}}, stripEdits...)
-}
-
-func offsetLineColZC1273(source []byte, offset int) (int, int) {
-	if offset < 0 || offset > len(source) {
-		return -1, -1
-	}
-	line := 1
-	col := 1
-	for i := 0; i < offset; i++ {
-		if source[i] == '\n' {
-			line++
-			col = 1
-			continue
+			b := ctx.NewBuilder()
+			b.InsertArgBefore(cmd, 0, "-q")
+			b.RemoveArg(cmd, i)
+			return builtEdits(b)
 		}
-		col++
 	}
-	return line, col
+	return nil
 }
 
 func checkZC1273(node ast.Node) []Violation {
@@ -4533,8 +3885,8 @@ func init() {
 		Severity: SeverityStyle,
 		Description: "Zsh natively supports `{start..end}` brace expansion for generating number " +
 			"sequences, avoiding the overhead of forking the external `seq` command.",
-		Check: checkZC1276,
-		Fix:   fixZC1061,
+		Check:      checkZC1276,
+		FixContext: fixZC1061,
 	})
 }
 
@@ -4603,8 +3955,8 @@ func init() {
 		Severity: SeverityInfo,
 		Description: "`readlink -f` is not portable across all platforms (notably macOS). " +
 			"Use `realpath` which is POSIX-standard and available on modern systems.",
-		Check: checkZC1279,
-		Fix:   fixZC1279,
+		Check:      checkZC1279,
+		FixContext: fixZC1279,
 	})
 }
 
@@ -4615,7 +3967,7 @@ func init() {
 // are left alone to avoid clobbering unrelated flags. Idempotent —
 // a re-run sees `realpath`, not `readlink`. Defensive byte-match
 // guards on both anchors.
-func fixZC1279(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1279(ctx *Context, node ast.Node, v Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -4627,27 +3979,7 @@ func fixZC1279(node ast.Node, v Violation, source []byte) []FixEdit {
 	if len(cmd.Arguments) == 0 || cmd.Arguments[0].String() != "-f" {
 		return nil
 	}
-	cmdOff := LineColToByteOffset(source, v.Line, v.Column)
-	if cmdOff < 0 || cmdOff+len("readlink") > len(source) {
-		return nil
-	}
-	if string(source[cmdOff:cmdOff+len("readlink")]) != "readlink" {
-		return nil
-	}
-	fTok := cmd.Arguments[0].TokenLiteralNode()
-	fOff := LineColToByteOffset(source, fTok.Line, fTok.Column)
-	if fOff < 0 || fOff+len("-f") > len(source) {
-		return nil
-	}
-	if string(source[fOff:fOff+len("-f")]) != "-f" {
-		return nil
-	}
-	return []FixEdit{{
-		Line:    v.Line,
-		Column:  v.Column,
-		Length:  fOff + len("-f") - cmdOff,
-		Replace: "realpath",
-	}}
+	return replaceNameThrough(ctx, cmd, cmd.Arguments[0], "realpath")
 }
 
 func checkZC1279(node ast.Node) []Violation {
@@ -4814,15 +4146,15 @@ func init() {
 		Description: "Zsh provides `setopt` and `unsetopt` as native builtins for managing shell " +
 			"options. Using `set -o` / `set +o` is a POSIX compatibility form that is less " +
 			"idiomatic in Zsh scripts.",
-		Check: checkZC1283,
-		Fix:   fixZC1283,
+		Check:      checkZC1283,
+		FixContext: fixZC1283,
 	})
 }
 
 // fixZC1283 rewrites `set -o OPTION` into `setopt OPTION`. The span
 // covers the `set` command name and the `-o` flag in a single edit;
 // trailing option arguments stay in place.
-func fixZC1283(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1283(ctx *Context, node ast.Node, v Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -4831,37 +4163,12 @@ func fixZC1283(node ast.Node, v Violation, source []byte) []FixEdit {
 	if !ok || ident.Value != "set" {
 		return nil
 	}
-	var dashO ast.Expression
 	for _, arg := range cmd.Arguments {
 		if arg.String() == "-o" {
-			dashO = arg
-			break
+			return replaceNameThrough(ctx, cmd, arg, "setopt")
 		}
 	}
-	if dashO == nil {
-		return nil
-	}
-	nameOff := LineColToByteOffset(source, v.Line, v.Column)
-	if nameOff < 0 || nameOff+len("set") > len(source) {
-		return nil
-	}
-	if string(source[nameOff:nameOff+len("set")]) != "set" {
-		return nil
-	}
-	dashTok := dashO.TokenLiteralNode()
-	dashOff := LineColToByteOffset(source, dashTok.Line, dashTok.Column)
-	if dashOff < 0 || dashOff+2 > len(source) {
-		return nil
-	}
-	if string(source[dashOff:dashOff+2]) != "-o" {
-		return nil
-	}
-	return []FixEdit{{
-		Line:    v.Line,
-		Column:  v.Column,
-		Length:  dashOff + 2 - nameOff,
-		Replace: "setopt",
-	}}
+	return nil
 }
 
 func checkZC1283(node ast.Node) []Violation {
@@ -5065,25 +4372,23 @@ func init() {
 		Description: "`typeset` is the native Zsh builtin for variable declarations. " +
 			"`declare` is a Bash compatibility alias. Using `typeset` is more idiomatic " +
 			"and signals that the script is Zsh-native.",
-		Check: checkZC1288,
-		Fix:   fixZC1288,
+		Check:      checkZC1288,
+		FixContext: fixZC1288,
 	})
 }
 
 // fixZC1288 rewrites the `declare` keyword to `typeset`. Arguments,
 // flags and assignments carry over unchanged because the two
 // builtins share the same Zsh interface.
-func fixZC1288(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1288(ctx *Context, node ast.Node, v Violation) []FixEdit {
 	decl, ok := node.(*ast.DeclarationStatement)
 	if !ok || decl.Command != "declare" {
 		return nil
 	}
-	return []FixEdit{{
-		Line:    v.Line,
-		Column:  v.Column,
-		Length:  len("declare"),
-		Replace: "typeset",
-	}}
+	b := ctx.NewBuilder()
+	off := b.Offset(v.Line, v.Column)
+	b.Replace(off, off+len("declare"), "typeset")
+	return builtEdits(b)
 }
 
 func checkZC1288(node ast.Node) []Violation {
@@ -5301,8 +4606,8 @@ func init() {
 		Description: "Zsh `[[ ]]` provides a more powerful conditional expression syntax than " +
 			"the `test` command. It supports pattern matching, regex, and does not require " +
 			"quoting of variable expansions to prevent word splitting.",
-		Check: checkZC1293,
-		Fix:   fixZC1293,
+		Check:      checkZC1293,
+		FixContext: fixZC1293,
 	})
 }
 
@@ -5311,7 +4616,7 @@ func init() {
 // last argument's source span. Bails when there are no arguments
 // (a bare `test` is invalid anyway). Idempotent — a re-run sees
 // `[[ ... ]]`, not `test`.
-func fixZC1293(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1293(ctx *Context, node ast.Node, v Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -5323,49 +4628,10 @@ func fixZC1293(node ast.Node, v Violation, source []byte) []FixEdit {
 	if len(cmd.Arguments) == 0 {
 		return nil
 	}
-	cmdOff := LineColToByteOffset(source, v.Line, v.Column)
-	if cmdOff < 0 || cmdOff+len("test") > len(source) {
-		return nil
-	}
-	if string(source[cmdOff:cmdOff+len("test")]) != "test" {
-		return nil
-	}
-	lastArg := cmd.Arguments[len(cmd.Arguments)-1]
-	lastTok := lastArg.TokenLiteralNode()
-	lastOff := LineColToByteOffset(source, lastTok.Line, lastTok.Column)
-	if lastOff < 0 {
-		return nil
-	}
-	lastVal := lastArg.String()
-	lastEnd := lastOff + len(lastVal)
-	if lastEnd > len(source) {
-		return nil
-	}
-	endLine, endCol := offsetLineColZC1293(source, lastEnd)
-	if endLine < 0 {
-		return nil
-	}
-	return []FixEdit{
-		{Line: v.Line, Column: v.Column, Length: len("test"), Replace: "[["},
-		{Line: endLine, Column: endCol, Length: 0, Replace: " ]]"},
-	}
-}
-
-func offsetLineColZC1293(source []byte, offset int) (int, int) {
-	if offset < 0 || offset > len(source) {
-		return -1, -1
-	}
-	line := 1
-	col := 1
-	for i := 0; i < offset; i++ {
-		if source[i] == '\n' {
-			line++
-			col = 1
-			continue
-		}
-		col++
-	}
-	return line, col
+	b := ctx.NewBuilder()
+	b.ReplaceCommandName(cmd, "[[")
+	b.InsertArgBefore(cmd, len(cmd.Arguments), "]]")
+	return builtEdits(b)
 }
 
 func checkZC1293(node ast.Node) []Violation {
@@ -5499,8 +4765,8 @@ func init() {
 		Description: "`$BASH_SOURCE` is a Bash-specific variable that does not exist in Zsh. " +
 			"In Zsh, use `$0` inside a sourced file to get the script path, or " +
 			"`${(%):-%x}` for the current file regardless of sourcing context.",
-		Check: checkZC1297,
-		Fix:   fixZC1297,
+		Check:      checkZC1297,
+		FixContext: fixZC1297,
 	})
 }
 
@@ -5510,7 +4776,7 @@ func init() {
 // rewritten — the bare `BASH_SOURCE` form (inside `${...}` or as an
 // assignment target) is left to manual review because the surrounding
 // braces would need adjusting too.
-func fixZC1297(node ast.Node, v Violation, _ []byte) []FixEdit {
+func fixZC1297(ctx *Context, node ast.Node, v Violation) []FixEdit {
 	ident, ok := node.(*ast.Identifier)
 	if !ok || ident == nil {
 		return nil
@@ -5518,12 +4784,9 @@ func fixZC1297(node ast.Node, v Violation, _ []byte) []FixEdit {
 	if ident.Value != "$BASH_SOURCE" {
 		return nil
 	}
-	return []FixEdit{{
-		Line:    v.Line,
-		Column:  v.Column,
-		Length:  len("$BASH_SOURCE"),
-		Replace: "${(%):-%x}",
-	}}
+	b := ctx.NewBuilder()
+	b.ReplaceNode(ident, "${(%):-%x}")
+	return builtEdits(b)
 }
 
 func checkZC1297(node ast.Node) []Violation {
@@ -5556,39 +4819,16 @@ func init() {
 		Description: "`$FUNCNAME` is a Bash-specific array that does not exist in Zsh. " +
 			"Zsh provides `$funcstack` as the equivalent, containing the call stack " +
 			"of function names with the current function at index 1.",
-		Check: checkZC1298,
-		Fix:   fixZC1298,
+		Check:      checkZC1298,
+		FixContext: fixZC1298,
 	})
 }
 
 // fixZC1298 renames the Bash `$FUNCNAME` identifier to the Zsh
 // `$funcstack` equivalent. Handles both the dollar-prefixed and
 // bare forms.
-func fixZC1298(node ast.Node, v Violation, source []byte) []FixEdit {
-	ident, ok := node.(*ast.Identifier)
-	if !ok {
-		return nil
-	}
-	if ident == nil {
-		return nil
-	}
-	switch ident.Value {
-	case "$FUNCNAME":
-		return []FixEdit{{
-			Line:    v.Line,
-			Column:  v.Column,
-			Length:  len("$FUNCNAME"),
-			Replace: "$funcstack",
-		}}
-	case "FUNCNAME":
-		return []FixEdit{{
-			Line:    v.Line,
-			Column:  v.Column,
-			Length:  len("FUNCNAME"),
-			Replace: "funcstack",
-		}}
-	}
-	return nil
+func fixZC1298(ctx *Context, node ast.Node, v Violation) []FixEdit {
+	return renameVariable(ctx, node, "FUNCNAME", "funcstack")
 }
 
 func checkZC1298(node ast.Node) []Violation {
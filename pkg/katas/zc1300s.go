@@ -17,8 +17,8 @@ func init() {
 		Description: "`$BASH_VERSINFO` is a Bash-specific array containing version components. " +
 			"In Zsh, use `$ZSH_VERSION` (string) or `${(s:.:)ZSH_VERSION}` to split " +
 			"it into components for version comparison.",
-		Check: checkZC1300,
-		Fix:   fixZC1300,
+		Check:      checkZC1300,
+		FixContext: fixZC1300,
 	})
 }
 
@@ -27,31 +27,11 @@ func init() {
 // array, ZSH_VERSION is a string) is the best single-token swap
 // available; callers that need components can split the string with
 // the `${(s:.:)ZSH_VERSION}` flag.
-func fixZC1300(node ast.Node, v Violation, source []byte) []FixEdit {
-	ident, ok := node.(*ast.Identifier)
-	if !ok {
-		return nil
-	}
-	if ident == nil {
-		return nil
-	}
-	switch ident.Value {
-	case "$BASH_VERSION", "$BASH_VERSINFO":
-		return []FixEdit{{
-			Line:    v.Line,
-			Column:  v.Column,
-			Length:  len(ident.Value),
-			Replace: "$ZSH_VERSION",
-		}}
-	case "BASH_VERSION", "BASH_VERSINFO":
-		return []FixEdit{{
-			Line:    v.Line,
-			Column:  v.Column,
-			Length:  len(ident.Value),
-			Replace: "ZSH_VERSION",
-		}}
+func fixZC1300(ctx *Context, node ast.Node, v Violation) []FixEdit {
+	if edits := renameVariable(ctx, node, "BASH_VERSION", "ZSH_VERSION"); edits != nil {
+		return edits
 	}
-	return nil
+	return renameVariable(ctx, node, "BASH_VERSINFO", "ZSH_VERSION")
 }
 
 func checkZC1300(node ast.Node) []Violation {
@@ -85,8 +65,8 @@ func init() {
 		Description: "`$PIPESTATUS` is a Bash array containing exit statuses from the last " +
 			"pipeline. Zsh uses `$pipestatus` (lowercase) for the same purpose. " +
 			"The uppercase form is undefined in Zsh.",
-		Check: checkZC1301,
-		Fix:   fixZC1301,
+		Check:      checkZC1301,
+		FixContext: fixZC1301,
 	})
 }
 
@@ -94,31 +74,8 @@ func init() {
 // identifier to the lowercase Zsh `$pipestatus` / `pipestatus`
 // form. Span covers only the name itself — subscripts and surrounding
 // context stay in place.
-func fixZC1301(node ast.Node, v Violation, source []byte) []FixEdit {
-	ident, ok := node.(*ast.Identifier)
-	if !ok {
-		return nil
-	}
-	if ident == nil {
-		return nil
-	}
-	switch ident.Value {
-	case "$PIPESTATUS":
-		return []FixEdit{{
-			Line:    v.Line,
-			Column:  v.Column,
-			Length:  len("$PIPESTATUS"),
-			Replace: "$pipestatus",
-		}}
-	case "PIPESTATUS":
-		return []FixEdit{{
-			Line:    v.Line,
-			Column:  v.Column,
-			Length:  len("PIPESTATUS"),
-			Replace: "pipestatus",
-		}}
-	}
-	return nil
+func fixZC1301(ctx *Context, node ast.Node, v Violation) []FixEdit {
+	return renameVariable(ctx, node, "PIPESTATUS", "pipestatus")
 }
 
 func checkZC1301(node ast.Node) []Violation {
@@ -222,39 +179,16 @@ func init() {
 		Severity: SeverityWarning,
 		Description: "`$BASH_SUBSHELL` tracks subshell nesting depth in Bash. " +
 			"Zsh provides `$ZSH_SUBSHELL` as the native equivalent.",
-		Check: checkZC1304,
-		Fix:   fixZC1304,
+		Check:      checkZC1304,
+		FixContext: fixZC1304,
 	})
 }
 
 // fixZC1304 renames the Bash `$BASH_SUBSHELL` identifier to the Zsh
 // `$ZSH_SUBSHELL` equivalent. Handles both the dollar-prefixed and
 // bare forms.
-func fixZC1304(node ast.Node, v Violation, source []byte) []FixEdit {
-	ident, ok := node.(*ast.Identifier)
-	if !ok {
-		return nil
-	}
-	if ident == nil {
-		return nil
-	}
-	switch ident.Value {
-	case "$BASH_SUBSHELL":
-		return []FixEdit{{
-			Line:    v.Line,
-			Column:  v.Column,
-			Length:  len("$BASH_SUBSHELL"),
-			Replace: "$ZSH_SUBSHELL",
-		}}
-	case "BASH_SUBSHELL":
-		return []FixEdit{{
-			Line:    v.Line,
-			Column:  v.Column,
-			Length:  len("BASH_SUBSHELL"),
-			Replace: "ZSH_SUBSHELL",
-		}}
-	}
-	return nil
+func fixZC1304(ctx *Context, node ast.Node, v Violation) []FixEdit {
+	return renameVariable(ctx, node, "BASH_SUBSHELL", "ZSH_SUBSHELL")
 }
 
 func checkZC1304(node ast.Node) []Violation {
@@ -286,39 +220,16 @@ func init() {
 		Severity: SeverityWarning,
 		Description: "`$COMP_WORDS` is a Bash completion variable containing the words on " +
 			"the command line. Zsh completion uses `$words` array for the same purpose.",
-		Check: checkZC1305,
-		Fix:   fixZC1305,
+		Check:      checkZC1305,
+		FixContext: fixZC1305,
 	})
 }
 
 // fixZC1305 renames the Bash `$COMP_WORDS` identifier to the Zsh
 // `$words` completion array. Handles both dollar-prefixed and bare
 // forms.
-func fixZC1305(node ast.Node, v Violation, source []byte) []FixEdit {
-	ident, ok := node.(*ast.Identifier)
-	if !ok {
-		return nil
-	}
-	if ident == nil {
-		return nil
-	}
-	switch ident.Value {
-	case "$COMP_WORDS":
-		return []FixEdit{{
-			Line:    v.Line,
-			Column:  v.Column,
-			Length:  len("$COMP_WORDS"),
-			Replace: "$words",
-		}}
-	case "COMP_WORDS":
-		return []FixEdit{{
-			Line:    v.Line,
-			Column:  v.Column,
-			Length:  len("COMP_WORDS"),
-			Replace: "words",
-		}}
-	}
-	return nil
+func fixZC1305(ctx *Context, node ast.Node, v Violation) []FixEdit {
+	return renameVariable(ctx, node, "COMP_WORDS", "words")
 }
 
 func checkZC1305(node ast.Node) []Violation {
@@ -350,38 +261,15 @@ func init() {
 		Severity: SeverityWarning,
 		Description: "`$COMP_CWORD` is a Bash completion variable for the current cursor " +
 			"word index. Zsh completion uses `$CURRENT` for the same purpose.",
-		Check: checkZC1306,
-		Fix:   fixZC1306,
+		Check:      checkZC1306,
+		FixContext: fixZC1306,
 	})
 }
 
 // fixZC1306 renames the Bash `$COMP_CWORD` identifier to the Zsh
 // `$CURRENT` completion variable.
-func fixZC1306(node ast.Node, v Violation, source []byte) []FixEdit {
-	ident, ok := node.(*ast.Identifier)
-	if !ok {
-		return nil
-	}
-	if ident == nil {
-		return nil
-	}
-	switch ident.Value {
-	case "$COMP_CWORD":
-		return []FixEdit{{
-			Line:    v.Line,
-			Column:  v.Column,
-			Length:  len("$COMP_CWORD"),
-			Replace: "$CURRENT",
-		}}
-	case "COMP_CWORD":
-		return []FixEdit{{
-			Line:    v.Line,
-			Column:  v.Column,
-			Length:  len("COMP_CWORD"),
-			Replace: "CURRENT",
-		}}
-	}
-	return nil
+func fixZC1306(ctx *Context, node ast.Node, v Violation) []FixEdit {
+	return renameVariable(ctx, node, "COMP_CWORD", "CURRENT")
 }
 
 func checkZC1306(node ast.Node) []Violation {
@@ -413,39 +301,16 @@ func init() {
 		Severity: SeverityWarning,
 		Description: "`$DIRSTACK` is the Bash form of the directory stack array. " +
 			"Zsh uses `$dirstack` (lowercase) for the same purpose.",
-		Check: checkZC1307,
-		Fix:   fixZC1307,
+		Check:      checkZC1307,
+		FixContext: fixZC1307,
 	})
 }
 
 // fixZC1307 renames the Bash `$DIRSTACK` / `DIRSTACK` identifier to
 // the Zsh lowercase `$dirstack` / `dirstack` form. Mirrors ZC1301's
 // rename pattern.
-func fixZC1307(node ast.Node, v Violation, source []byte) []FixEdit {
-	ident, ok := node.(*ast.Identifier)
-	if !ok {
-		return nil
-	}
-	if ident == nil {
-		return nil
-	}
-	switch ident.Value {
-	case "$DIRSTACK":
-		return []FixEdit{{
-			Line:    v.Line,
-			Column:  v.Column,
-			Length:  len("$DIRSTACK"),
-			Replace: "$dirstack",
-		}}
-	case "DIRSTACK":
-		return []FixEdit{{
-			Line:    v.Line,
-			Column:  v.Column,
-			Length:  len("DIRSTACK"),
-			Replace: "dirstack",
-		}}
-	}
-	return nil
+func fixZC1307(ctx *Context, node ast.Node, v Violation) []FixEdit {
+	return renameVariable(ctx, node, "DIRSTACK", "dirstack")
 }
 
 func checkZC1307(node ast.Node) []Violation {
@@ -477,38 +342,15 @@ func init() {
 		Severity: SeverityWarning,
 		Description: "`$COMP_LINE` is a Bash completion variable containing the full command " +
 			"line. Zsh completion uses `$BUFFER` for the current command line content.",
-		Check: checkZC1308,
-		Fix:   fixZC1308,
+		Check:      checkZC1308,
+		FixContext: fixZC1308,
 	})
 }
 
 // fixZC1308 renames the Bash `$COMP_LINE` identifier to the Zsh
 // `$BUFFER` completion variable.
-func fixZC1308(node ast.Node, v Violation, source []byte) []FixEdit {
-	ident, ok := node.(*ast.Identifier)
-	if !ok {
-		return nil
-	}
-	if ident == nil {
-		return nil
-	}
-	switch ident.Value {
-	case "$COMP_LINE":
-		return []FixEdit{{
-			Line:    v.Line,
-			Column:  v.Column,
-			Length:  len("$COMP_LINE"),
-			Replace: "$BUFFER",
-		}}
-	case "COMP_LINE":
-		return []FixEdit{{
-			Line:    v.Line,
-			Column:  v.Column,
-			Length:  len("COMP_LINE"),
-			Replace: "BUFFER",
-		}}
-	}
-	return nil
+func fixZC1308(ctx *Context, node ast.Node, v Violation) []FixEdit {
+	return renameVariable(ctx, node, "COMP_LINE", "BUFFER")
 }
 
 func checkZC1308(node ast.Node) []Violation {
@@ -671,38 +513,15 @@ func init() {
 		Severity: SeverityWarning,
 		Description: "`$BASH_ALIASES` is a Bash associative array of defined aliases. " +
 			"Zsh provides the `aliases` associative array for the same purpose.",
-		Check: checkZC1313,
-		Fix:   fixZC1313,
+		Check:      checkZC1313,
+		FixContext: fixZC1313,
 	})
 }
 
 // fixZC1313 renames the Bash `$BASH_ALIASES` identifier to the Zsh
 // `$aliases` associative array.
-func fixZC1313(node ast.Node, v Violation, source []byte) []FixEdit {
-	ident, ok := node.(*ast.Identifier)
-	if !ok {
-		return nil
-	}
-	if ident == nil {
-		return nil
-	}
-	switch ident.Value {
-	case "$BASH_ALIASES":
-		return []FixEdit{{
-			Line:    v.Line,
-			Column:  v.Column,
-			Length:  len("$BASH_ALIASES"),
-			Replace: "$aliases",
-		}}
-	case "BASH_ALIASES":
-		return []FixEdit{{
-			Line:    v.Line,
-			Column:  v.Column,
-			Length:  len("BASH_ALIASES"),
-			Replace: "aliases",
-		}}
-	}
-	return nil
+func fixZC1313(ctx *Context, node ast.Node, v Violation) []FixEdit {
+	return renameVariable(ctx, node, "BASH_ALIASES", "aliases")
 }
 
 func checkZC1313(node ast.Node) []Violation {
@@ -867,38 +686,15 @@ func init() {
 		Description: "`$BASH_CMDS` is a Bash associative array caching command lookups. " +
 			"Zsh provides the `$commands` hash for the same purpose, mapping " +
 			"command names to their full paths.",
-		Check: checkZC1318,
-		Fix:   fixZC1318,
+		Check:      checkZC1318,
+		FixContext: fixZC1318,
 	})
 }
 
 // fixZC1318 renames the Bash `$BASH_CMDS` identifier to the Zsh
 // `$commands` hash.
-func fixZC1318(node ast.Node, v Violation, source []byte) []FixEdit {
-	ident, ok := node.(*ast.Identifier)
-	if !ok {
-		return nil
-	}
-	if ident == nil {
-		return nil
-	}
-	switch ident.Value {
-	case "$BASH_CMDS":
-		return []FixEdit{{
-			Line:    v.Line,
-			Column:  v.Column,
-			Length:  len("$BASH_CMDS"),
-			Replace: "$commands",
-		}}
-	case "BASH_CMDS":
-		return []FixEdit{{
-			Line:    v.Line,
-			Column:  v.Column,
-			Length:  len("BASH_CMDS"),
-			Replace: "commands",
-		}}
-	}
-	return nil
+func fixZC1318(ctx *Context, node ast.Node, v Violation) []FixEdit {
+	return renameVariable(ctx, node, "BASH_CMDS", "commands")
 }
 
 func checkZC1318(node ast.Node) []Violation {
@@ -930,8 +726,8 @@ func init() {
 		Severity: SeverityWarning,
 		Description: "`$BASH_ARGC` is a Bash array tracking argument counts per stack frame. " +
 			"Zsh uses `$#` for argument count and `$argv` for the argument array.",
-		Check: checkZC1319,
-		Fix:   fixZC1319,
+		Check:      checkZC1319,
+		FixContext: fixZC1319,
 	})
 }
 
@@ -940,28 +736,8 @@ func init() {
 // is the current-frame argument count in Zsh. The rewrite is correct
 // for the common single-value usage; multi-frame stack inspection is
 // not portable and stays the user's responsibility.
-func fixZC1319(node ast.Node, v Violation, source []byte) []FixEdit {
-	ident, ok := node.(*ast.Identifier)
-	if !ok || ident == nil {
-		return nil
-	}
-	switch ident.Value {
-	case "$BASH_ARGC":
-		return []FixEdit{{
-			Line:    v.Line,
-			Column:  v.Column,
-			Length:  len("$BASH_ARGC"),
-			Replace: "$#",
-		}}
-	case "BASH_ARGC":
-		return []FixEdit{{
-			Line:    v.Line,
-			Column:  v.Column,
-			Length:  len("BASH_ARGC"),
-			Replace: "#",
-		}}
-	}
-	return nil
+func fixZC1319(ctx *Context, node ast.Node, v Violation) []FixEdit {
+	return renameVariable(ctx, node, "BASH_ARGC", "#")
 }
 
 func checkZC1319(node ast.Node) []Violation {
@@ -993,8 +769,8 @@ func init() {
 		Severity: SeverityWarning,
 		Description: "`$BASH_ARGV` is a Bash array containing arguments in reverse order. " +
 			"Zsh provides `$argv` (or `$@`) for positional parameters.",
-		Check: checkZC1320,
-		Fix:   fixZC1320,
+		Check:      checkZC1320,
+		FixContext: fixZC1320,
 	})
 }
 
@@ -1003,28 +779,8 @@ func init() {
 // stacking order in Bash; `$argv` is the current-frame positional
 // array. Most usages target the current frame and the rewrite is
 // correct; deeper stack walks need a hand-port.
-func fixZC1320(node ast.Node, v Violation, source []byte) []FixEdit {
-	ident, ok := node.(*ast.Identifier)
-	if !ok || ident == nil {
-		return nil
-	}
-	switch ident.Value {
-	case "$BASH_ARGV":
-		return []FixEdit{{
-			Line:    v.Line,
-			Column:  v.Column,
-			Length:  len("$BASH_ARGV"),
-			Replace: "$argv",
-		}}
-	case "BASH_ARGV":
-		return []FixEdit{{
-			Line:    v.Line,
-			Column:  v.Column,
-			Length:  len("BASH_ARGV"),
-			Replace: "argv",
-		}}
-	}
-	return nil
+func fixZC1320(ctx *Context, node ast.Node, v Violation) []FixEdit {
+	return renameVariable(ctx, node, "BASH_ARGV", "argv")
 }
 
 func checkZC1320(node ast.Node) []Violation {
@@ -1400,38 +1156,15 @@ func init() {
 		Description: "`$BASH_REMATCH` holds regex capture groups in Bash. Zsh stores " +
 			"regex matches in the `$match` array (and `$MATCH` for the full match) " +
 			"when using `=~` with `setopt BASH_REMATCH` disabled.",
-		Check: checkZC1331,
-		Fix:   fixZC1331,
+		Check:      checkZC1331,
+		FixContext: fixZC1331,
 	})
 }
 
 // fixZC1331 renames the Bash `$BASH_REMATCH` identifier to the Zsh
 // `$match` regex-capture array.
-func fixZC1331(node ast.Node, v Violation, source []byte) []FixEdit {
-	ident, ok := node.(*ast.Identifier)
-	if !ok {
-		return nil
-	}
-	if ident == nil {
-		return nil
-	}
-	switch ident.Value {
-	case "$BASH_REMATCH":
-		return []FixEdit{{
-			Line:    v.Line,
-			Column:  v.Column,
-			Length:  len("$BASH_REMATCH"),
-			Replace: "$match",
-		}}
-	case "BASH_REMATCH":
-		return []FixEdit{{
-			Line:    v.Line,
-			Column:  v.Column,
-			Length:  len("BASH_REMATCH"),
-			Replace: "match",
-		}}
-	}
-	return nil
+func fixZC1331(ctx *Context, node ast.Node, v Violation) []FixEdit {
+	return renameVariable(ctx, node, "BASH_REMATCH", "match")
 }
 
 func checkZC1331(node ast.Node) []Violation {
@@ -1497,8 +1230,8 @@ func init() {
 		Severity: SeverityInfo,
 		Description: "`$TIMEFORMAT` is the Bash variable for customizing `time` output. " +
 			"Zsh uses `$TIMEFMT` for the same purpose, with different format specifiers.",
-		Check: checkZC1333,
-		Fix:   fixZC1333,
+		Check:      checkZC1333,
+		FixContext: fixZC1333,
 	})
 }
 
@@ -1506,31 +1239,8 @@ func init() {
 // `$TIMEFMT` variable. Format specifiers differ between the two
 // shells; the rename preserves the identifier itself but authors
 // should still review the format string after conversion.
-func fixZC1333(node ast.Node, v Violation, source []byte) []FixEdit {
-	ident, ok := node.(*ast.Identifier)
-	if !ok {
-		return nil
-	}
-	if ident == nil {
-		return nil
-	}
-	switch ident.Value {
-	case "$TIMEFORMAT":
-		return []FixEdit{{
-			Line:    v.Line,
-			Column:  v.Column,
-			Length:  len("$TIMEFORMAT"),
-			Replace: "$TIMEFMT",
-		}}
-	case "TIMEFORMAT":
-		return []FixEdit{{
-			Line:    v.Line,
-			Column:  v.Column,
-			Length:  len("TIMEFORMAT"),
-			Replace: "TIMEFMT",
-		}}
-	}
-	return nil
+func fixZC1333(ctx *Context, node ast.Node, v Violation) []FixEdit {
+	return renameVariable(ctx, node, "TIMEFORMAT", "TIMEFMT")
 }
 
 func checkZC1333(node ast.Node) []Violation {
@@ -1563,8 +1273,8 @@ func init() {
 		Description: "`type -p` is a Bash flag that prints the path of a command. " +
 			"Zsh `type` does not support `-p`. Use `whence -p` to get " +
 			"the path of an external command in Zsh.",
-		Check: checkZC1334,
-		Fix:   fixZC1334,
+		Check:      checkZC1334,
+		FixContext: fixZC1334,
 	})
 }
 
@@ -1573,7 +1283,7 @@ func init() {
 // single edit — emitting the wider rewrite ensures it wins over the
 // narrower `type` -> `command -v` swap from ZC1064 when both katas fire
 // on the same input. Trailing argument(s) stay in place.
-func fixZC1334(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1334(ctx *Context, node ast.Node, v Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -1582,38 +1292,12 @@ func fixZC1334(node ast.Node, v Violation, source []byte) []FixEdit {
 	if !ok || ident.Value != "type" {
 		return nil
 	}
-	var flag ast.Expression
 	for _, arg := range cmd.Arguments {
-		val := arg.String()
-		if val == "-p" || val == "-P" {
-			flag = arg
-			break
+		if val := arg.String(); val == "-p" || val == "-P" {
+			return replaceNameThrough(ctx, cmd, arg, "whence -p")
 		}
 	}
-	if flag == nil {
-		return nil
-	}
-	nameOff := LineColToByteOffset(source, v.Line, v.Column)
-	if nameOff < 0 || nameOff+len("type") > len(source) {
-		return nil
-	}
-	if string(source[nameOff:nameOff+len("type")]) != "type" {
-		return nil
-	}
-	flagTok := flag.TokenLiteralNode()
-	flagOff := LineColToByteOffset(source, flagTok.Line, flagTok.Column)
-	if flagOff < 0 || flagOff+2 > len(source) {
-		return nil
-	}
-	if string(source[flagOff:flagOff+2]) != "-p" && string(source[flagOff:flagOff+2]) != "-P" {
-		return nil
-	}
-	return []FixEdit{{
-		Line:    v.Line,
-		Column:  v.Column,
-		Length:  flagOff + 2 - nameOff,
-		Replace: "whence -p",
-	}}
+	return nil
 }
 
 func checkZC1334(node ast.Node) []Violation {
@@ -2423,14 +2107,15 @@ func init() {
 			"ignored by POSIX `echo`. Zsh's `print -r` is the idiomatic raw-printer; combine " +
 			"with `-n` (no newline), `-l` (one per line), `-u<fd>` (file descriptor), or `--` " +
 			"(end of flags) as needed.",
-		Check: checkZC1355,
-		Fix:   fixZC1355,
+		Check:      checkZC1355,
+		FixContext: fixZC1355,
 	})
 }
 
 // fixZC1355 collapses `echo -E` into `print -r`. Span covers the
 // command name, intervening whitespace, and the `-E` flag.
-func fixZC1355(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1355(ctx *Context, node ast.Node, v Violation) []FixEdit {
+	source := ctx.Source
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -2442,7 +2127,8 @@ func fixZC1355(node ast.Node, v Violation, source []byte) []FixEdit {
 	if len(cmd.Arguments) == 0 {
 		return nil
 	}
-	nameOff := LineColToByteOffset(source, v.Line, v.Column)
+	b := ctx.NewBuilder()
+	nameOff := b.Offset(v.Line, v.Column)
 	if nameOff < 0 || nameOff+len("echo") > len(source) {
 		return nil
 	}
@@ -2456,13 +2142,8 @@ func fixZC1355(node ast.Node, v Violation, source []byte) []FixEdit {
 	if i+2 > len(source) || source[i] != '-' || source[i+1] != 'E' {
 		return nil
 	}
-	end := i + 2
-	return []FixEdit{{
-		Line:    v.Line,
-		Column:  v.Column,
-		Length:  end - nameOff,
-		Replace: "print -r",
-	}}
+	b.Replace(nameOff, i+2, "print -r")
+	return builtEdits(b)
 }
 
 func checkZC1355(node ast.Node) []Violation {
@@ -2500,14 +2181,14 @@ func init() {
 		Description: "Zsh's `read` uses `-A` (uppercase A) to read into an array. Bash uses `-a` " +
 			"(lowercase) for the same thing. In Zsh, `read -a` assigns a flag to a scalar " +
 			"variable — not what Bash users expect. Use `-A` for portable-Zsh behavior.",
-		Check: checkZC1356,
-		Fix:   fixZC1356,
+		Check:      checkZC1356,
+		FixContext: fixZC1356,
 	})
 }
 
 // fixZC1356 rewrites the Bash-flavoured `read -a` flag to the
 // uppercase `-A` that Zsh uses for array reads.
-func fixZC1356(node ast.Node, _ Violation, source []byte) []FixEdit {
+func fixZC1356(ctx *Context, node ast.Node, _ Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -2520,20 +2201,12 @@ func fixZC1356(node ast.Node, _ Violation, source []byte) []FixEdit {
 		if arg.String() != "-a" {
 			continue
 		}
-		tok := arg.TokenLiteralNode()
-		off := LineColToByteOffset(source, tok.Line, tok.Column)
-		if off < 0 || off+2 > len(source) {
-			return nil
-		}
-		if string(source[off:off+2]) != "-a" {
+		b := ctx.NewBuilder()
+		if argOffset(b, arg) < 0 {
 			return nil
 		}
-		return []FixEdit{{
-			Line:    tok.Line,
-			Column:  tok.Column,
-			Length:  2,
-			Replace: "-A",
-		}}
+		b.ReplaceNode(arg, "-A")
+		return builtEdits(b)
 	}
 	return nil
 }
@@ -3249,8 +2922,8 @@ func init() {
 			"depth-query vehicle. Zsh's `$FUNCNEST` is only the limit — to read the current depth " +
 			"use `${#funcstack}`. Reading `$FUNCNEST` expecting depth returns the limit, not " +
 			"the current depth.",
-		Check: checkZC1374,
-		Fix:   fixZC1374,
+		Check:      checkZC1374,
+		FixContext: fixZC1374,
 	})
 }
 
@@ -3258,7 +2931,7 @@ func init() {
 // `${#funcstack}` inside echo / print / printf calls. One edit per
 // matching arg. Idempotent — a re-run sees `${#funcstack}`, which
 // the detector's exact-match guard won't match.
-func fixZC1374(node ast.Node, _ Violation, source []byte) []FixEdit {
+func fixZC1374(ctx *Context, node ast.Node, _ Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -3270,28 +2943,17 @@ func fixZC1374(node ast.Node, _ Violation, source []byte) []FixEdit {
 	if ident.Value != "echo" && ident.Value != "print" && ident.Value != "printf" {
 		return nil
 	}
-	var edits []FixEdit
+	b := ctx.NewBuilder()
 	for _, arg := range cmd.Arguments {
 		val := arg.String()
 		if val != "$FUNCNEST" && val != "${FUNCNEST}" {
 			continue
 		}
-		tok := arg.TokenLiteralNode()
-		off := LineColToByteOffset(source, tok.Line, tok.Column)
-		if off < 0 || off+len(val) > len(source) {
-			continue
-		}
-		if string(source[off:off+len(val)]) != val {
-			continue
+		if off := argOffset(b, arg); off >= 0 {
+			b.Replace(off, off+len(val), "${#funcstack}")
 		}
-		edits = append(edits, FixEdit{
-			Line:    tok.Line,
-			Column:  tok.Column,
-			Length:  len(val),
-			Replace: "${#funcstack}",
-		})
 	}
-	return edits
+	return builtEdits(b)
 }
 
 func checkZC1374(node ast.Node) []Violation {
@@ -3416,8 +3078,8 @@ func init() {
 		Description: "Bash's `$BASH_ALIASES` is an associative array of alias→value mappings. Zsh " +
 			"exposes the same information via `$aliases` (also an assoc array). `$BASH_ALIASES` " +
 			"is unset in Zsh; reading it yields nothing.",
-		Check: checkZC1377,
-		Fix:   fixZC1377,
+		Check:      checkZC1377,
+		FixContext: fixZC1377,
 	})
 }
 
@@ -3425,7 +3087,7 @@ func init() {
 // printf argument to `aliases`. Each occurrence becomes its own edit at
 // the absolute source offset of that arg's token + the substring index;
 // surrounding quoting and adjoining text stay byte-exact.
-func fixZC1377(node ast.Node, _ Violation, source []byte) []FixEdit {
+func fixZC1377(ctx *Context, node ast.Node, _ Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -3437,58 +3099,11 @@ func fixZC1377(node ast.Node, _ Violation, source []byte) []FixEdit {
 	if ident.Value != "echo" && ident.Value != "print" && ident.Value != "printf" {
 		return nil
 	}
-	var edits []FixEdit
+	b := ctx.NewBuilder()
 	for _, arg := range cmd.Arguments {
-		val := arg.String()
-		if !strings.Contains(val, "BASH_ALIASES") {
-			continue
-		}
-		tok := arg.TokenLiteralNode()
-		off := LineColToByteOffset(source, tok.Line, tok.Column)
-		if off < 0 || off+len(val) > len(source) {
-			continue
-		}
-		if string(source[off:off+len(val)]) != val {
-			continue
-		}
-		idx := 0
-		for {
-			pos := strings.Index(val[idx:], "BASH_ALIASES")
-			if pos < 0 {
-				break
-			}
-			abs := off + idx + pos
-			line, col := offsetLineColZC1377(source, abs)
-			if line < 0 {
-				break
-			}
-			edits = append(edits, FixEdit{
-				Line:    line,
-				Column:  col,
-				Length:  len("BASH_ALIASES"),
-				Replace: "aliases",
-			})
-			idx += pos + len("BASH_ALIASES")
-		}
-	}
-	return edits
-}
-
-func offsetLineColZC1377(source []byte, offset int) (int, int) {
-	if offset < 0 || offset > len(source) {
-		return -1, -1
-	}
-	line := 1
-	col := 1
-	for i := 0; i < offset; i++ {
-		if source[i] == '\n' {
-			line++
-			col = 1
-			continue
-		}
-		col++
+		renameInArg(b, arg, "BASH_ALIASES", "aliases")
 	}
-	return line, col
+	return builtEdits(b)
 }
 
 func checkZC1377(node ast.Node) []Violation {
@@ -3530,8 +3145,8 @@ func init() {
 		Description: "Bash's `$DIRSTACK` is the `pushd`/`popd` directory stack. Zsh exposes the " +
 			"same stack as lowercase `$dirstack` (per zsh/parameter module). Using uppercase " +
 			"`$DIRSTACK` in Zsh accesses an unrelated (and usually empty) variable.",
-		Check: checkZC1378,
-		Fix:   fixZC1378,
+		Check:      checkZC1378,
+		FixContext: fixZC1378,
 	})
 }
 
@@ -3539,7 +3154,7 @@ func init() {
 // printf argument to `dirstack`. Each occurrence becomes its own edit at
 // the absolute source offset of that arg's token + the substring index;
 // surrounding quoting and adjoining text stay byte-exact.
-func fixZC1378(node ast.Node, _ Violation, source []byte) []FixEdit {
+func fixZC1378(ctx *Context, node ast.Node, _ Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -3551,58 +3166,11 @@ func fixZC1378(node ast.Node, _ Violation, source []byte) []FixEdit {
 	if ident.Value != "echo" && ident.Value != "print" && ident.Value != "printf" {
 		return nil
 	}
-	var edits []FixEdit
+	b := ctx.NewBuilder()
 	for _, arg := range cmd.Arguments {
-		val := arg.String()
-		if !strings.Contains(val, "DIRSTACK") {
-			continue
-		}
-		tok := arg.TokenLiteralNode()
-		off := LineColToByteOffset(source, tok.Line, tok.Column)
-		if off < 0 || off+len(val) > len(source) {
-			continue
-		}
-		if string(source[off:off+len(val)]) != val {
-			continue
-		}
-		idx := 0
-		for {
-			pos := strings.Index(val[idx:], "DIRSTACK")
-			if pos < 0 {
-				break
-			}
-			abs := off + idx + pos
-			line, col := offsetLineColZC1378(source, abs)
-			if line < 0 {
-				break
-			}
-			edits = append(edits, FixEdit{
-				Line:    line,
-				Column:  col,
-				Length:  len("DIRSTACK"),
-				Replace: "dirstack",
-			})
-			idx += pos + len("DIRSTACK")
-		}
-	}
-	return edits
-}
-
-func offsetLineColZC1378(source []byte, offset int) (int, int) {
-	if offset < 0 || offset > len(source) {
-		return -1, -1
-	}
-	line := 1
-	col := 1
-	for i := 0; i < offset; i++ {
-		if source[i] == '\n' {
-			line++
-			col = 1
-			continue
-		}
-		col++
+		renameInArg(b, arg, "DIRSTACK", "dirstack")
 	}
-	return line, col
+	return builtEdits(b)
 }
 
 func checkZC1378(node ast.Node) []Violation {
@@ -3686,8 +3254,8 @@ func init() {
 		Description: "Bash filters history entries matching `$HISTIGNORE` patterns. Zsh uses a " +
 			"parameter named `$HISTORY_IGNORE` (underscore in the middle). Setting `HISTIGNORE` " +
 			"in Zsh is a no-op.",
-		Check: checkZC1380,
-		Fix:   fixZC1380,
+		Check:      checkZC1380,
+		FixContext: fixZC1380,
 	})
 }
 
@@ -3700,7 +3268,7 @@ var zc1380PrintCmds = map[string]struct{}{
 	"echo": {}, "print": {}, "printf": {}, "export": {},
 }
 
-func fixZC1380(node ast.Node, _ Violation, source []byte) []FixEdit {
+func fixZC1380(ctx *Context, node ast.Node, _ Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -3708,69 +3276,14 @@ func fixZC1380(node ast.Node, _ Violation, source []byte) []FixEdit {
 	if _, hit := zc1380PrintCmds[CommandIdentifier(cmd)]; !hit {
 		return nil
 	}
-	var edits []FixEdit
+	b := ctx.NewBuilder()
 	for _, arg := range cmd.Arguments {
-		edits = append(edits, zc1380ArgEdits(arg, source)...)
-	}
-	if len(edits) == 0 {
-		return nil
-	}
-	return edits
-}
-
-func zc1380ArgEdits(arg ast.Expression, source []byte) []FixEdit {
-	v := arg.String()
-	if !strings.Contains(v, "HISTIGNORE") || strings.Contains(v, "HISTORY_IGNORE") {
-		return nil
-	}
-	tok := arg.TokenLiteralNode()
-	argOff := LineColToByteOffset(source, tok.Line, tok.Column)
-	if argOff < 0 {
-		return nil
-	}
-	if argOff+len(v) > len(source) || string(source[argOff:argOff+len(v)]) != v {
-		return nil
-	}
-	return zc1380BuildEdits(source, v, argOff)
-}
-
-func zc1380BuildEdits(source []byte, v string, argOff int) []FixEdit {
-	var edits []FixEdit
-	idx := 0
-	for {
-		rel := strings.Index(v[idx:], "HISTIGNORE")
-		if rel < 0 {
-			return edits
-		}
-		absStart := argOff + idx + rel
-		line, col := offsetLineColZC1380(source, absStart)
-		if line > 0 {
-			edits = append(edits, FixEdit{
-				Line:    line,
-				Column:  col,
-				Length:  len("HISTIGNORE"),
-				Replace: "HISTORY_IGNORE",
-			})
-		}
-		idx += rel + len("HISTIGNORE")
-	}
-}
-
-func offsetLineColZC1380(source []byte, offset int) (int, int) {
-	if offset < 0 || offset > len(source) {
-		return -1, -1
-	}
-	line := 1
-	col := 1
-	for i := 0; i < offset; i++ {
-		if source[i] == '\n' {
-			line++
-			col = 1
+		if strings.Contains(arg.String(), "HISTORY_IGNORE") {
 			continue
 		}
-		col++
+		renameInArg(b, arg, "HISTIGNORE", "HISTORY_IGNORE")
 	}
-	return line, col
+	return builtEdits(b)
 }
 
 func checkZC1380(node ast.Node) []Violation {
@@ -3813,8 +3326,8 @@ func init() {
 			"(array of tokens) and `$COMP_CWORD` (index of cursor). Zsh's completion system " +
 			"exposes the same via `words` (array) and `$CURRENT` (1-based cursor index). Using " +
 			"the Bash names in Zsh completion functions produces empty expansions.",
-		Check: checkZC1381,
-		Fix:   fixZC1381,
+		Check:      checkZC1381,
+		FixContext: fixZC1381,
 	})
 }
 
@@ -3829,7 +3342,7 @@ func init() {
 // Per-arg byte-anchored scan; one edit per match. Idempotent — a
 // re-run sees the Zsh names, which the detector's substring guard
 // won't match.
-func fixZC1381(node ast.Node, _ Violation, source []byte) []FixEdit {
+func fixZC1381(ctx *Context, node ast.Node, _ Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -3841,63 +3354,16 @@ func fixZC1381(node ast.Node, _ Violation, source []byte) []FixEdit {
 	if ident.Value != "echo" && ident.Value != "print" && ident.Value != "printf" {
 		return nil
 	}
-	mapping := []struct{ old, new string }{
-		{"COMP_WORDS", "words"},
-		{"COMP_CWORD", "CURRENT"},
-		{"COMP_LINE", "BUFFER"},
-		{"COMP_POINT", "CURSOR"},
-	}
-	var edits []FixEdit
+	b := ctx.NewBuilder()
 	for _, arg := range cmd.Arguments {
-		val := arg.String()
-		tok := arg.TokenLiteralNode()
-		off := LineColToByteOffset(source, tok.Line, tok.Column)
-		if off < 0 || off+len(val) > len(source) {
-			continue
-		}
-		if string(source[off:off+len(val)]) != val {
-			continue
-		}
-		for _, m := range mapping {
-			idx := 0
-			for {
-				pos := strings.Index(val[idx:], m.old)
-				if pos < 0 {
-					break
-				}
-				abs := off + idx + pos
-				line, col := offsetLineColZC1381(source, abs)
-				if line < 0 {
-					break
-				}
-				edits = append(edits, FixEdit{
-					Line:    line,
-					Column:  col,
-					Length:  len(m.old),
-					Replace: m.new,
-				})
-				idx += pos + len(m.old)
-			}
-		}
-	}
-	return edits
-}
-
-func offsetLineColZC1381(source []byte, offset int) (int, int) {
-	if offset < 0 || offset > len(source) {
-		return -1, -1
-	}
-	line := 1
-	col := 1
-	for i := 0; i < offset; i++ {
-		if source[i] == '\n' {
-			line++
-			col = 1
-			continue
-		}
-		col++
+		renameInArg(b, arg,
+			"COMP_WORDS", "words",
+			"COMP_CWORD", "CURRENT",
+			"COMP_LINE", "BUFFER",
+			"COMP_POINT", "CURSOR",
+		)
 	}
-	return line, col
+	return builtEdits(b)
 }
 
 func checkZC1381(node ast.Node) []Violation {
@@ -3942,8 +3408,8 @@ func init() {
 			"offset as `$READLINE_POINT` inside `bind -x` handlers. Zsh's Line Editor (ZLE) uses " +
 			"`$BUFFER` (line text) and `$CURSOR` (1-based column) inside widget functions. The " +
 			"Bash names are unset in Zsh.",
-		Check: checkZC1382,
-		Fix:   fixZC1382,
+		Check:      checkZC1382,
+		FixContext: fixZC1382,
 	})
 }
 
@@ -3957,7 +3423,7 @@ func init() {
 // Per-arg byte-anchored scan; one edit per match. Idempotent — a
 // re-run sees the Zsh names, which the detector's substring guard
 // won't match.
-func fixZC1382(node ast.Node, _ Violation, source []byte) []FixEdit {
+func fixZC1382(ctx *Context, node ast.Node, _ Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -3969,62 +3435,15 @@ func fixZC1382(node ast.Node, _ Violation, source []byte) []FixEdit {
 	if ident.Value != "echo" && ident.Value != "print" && ident.Value != "printf" {
 		return nil
 	}
-	mapping := []struct{ old, new string }{
-		{"READLINE_LINE", "BUFFER"},
-		{"READLINE_POINT", "CURSOR"},
-		{"READLINE_MARK", "MARK"},
-	}
-	var edits []FixEdit
+	b := ctx.NewBuilder()
 	for _, arg := range cmd.Arguments {
-		val := arg.String()
-		tok := arg.TokenLiteralNode()
-		off := LineColToByteOffset(source, tok.Line, tok.Column)
-		if off < 0 || off+len(val) > len(source) {
-			continue
-		}
-		if string(source[off:off+len(val)]) != val {
-			continue
-		}
-		for _, m := range mapping {
-			idx := 0
-			for {
-				pos := strings.Index(val[idx:], m.old)
-				if pos < 0 {
-					break
-				}
-				abs := off + idx + pos
-				line, col := offsetLineColZC1382(source, abs)
-				if line < 0 {
-					break
-				}
-				edits = append(edits, FixEdit{
-					Line:    line,
-					Column:  col,
-					Length:  len(m.old),
-					Replace: m.new,
-				})
-				idx += pos + len(m.old)
-			}
-		}
+		renameInArg(b, arg,
+			"READLINE_LINE", "BUFFER",
+			"READLINE_POINT", "CURSOR",
+			"READLINE_MARK", "MARK",
+		)
 	}
-	return edits
-}
-
-func offsetLineColZC1382(source []byte, offset int) (int, int) {
-	if offset < 0 || offset > len(source) {
-		return -1, -1
-	}
-	line := 1
-	col := 1
-	for i := 0; i < offset; i++ {
-		if source[i] == '\n' {
-			line++
-			col = 1
-			continue
-		}
-		col++
-	}
-	return line, col
+	return builtEdits(b)
 }
 
 func checkZC1382(node ast.Node) []Violation {
@@ -4067,8 +3486,8 @@ func init() {
 		Description: "Bash's `$TIMEFORMAT` controls the output of the `time` builtin. Zsh uses a " +
 			"shorter name, `$TIMEFMT`, for the same purpose. Setting `TIMEFORMAT` in a Zsh script " +
 			"has no effect; the Zsh `time` builtin reads `$TIMEFMT`.",
-		Check: checkZC1383,
-		Fix:   fixZC1383,
+		Check:      checkZC1383,
+		FixContext: fixZC1383,
 	})
 }
 
@@ -4076,7 +3495,7 @@ func init() {
 // printf / export argument to `TIMEFMT`. Each occurrence becomes its own
 // edit at the absolute source offset of that arg's token + the substring
 // index; surrounding quoting and adjoining text stay byte-exact.
-func fixZC1383(node ast.Node, _ Violation, source []byte) []FixEdit {
+func fixZC1383(ctx *Context, node ast.Node, _ Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -4088,58 +3507,11 @@ func fixZC1383(node ast.Node, _ Violation, source []byte) []FixEdit {
 	if ident.Value != "echo" && ident.Value != "print" && ident.Value != "printf" && ident.Value != "export" {
 		return nil
 	}
-	var edits []FixEdit
+	b := ctx.NewBuilder()
 	for _, arg := range cmd.Arguments {
-		val := arg.String()
-		if !strings.Contains(val, "TIMEFORMAT") {
-			continue
-		}
-		tok := arg.TokenLiteralNode()
-		off := LineColToByteOffset(source, tok.Line, tok.Column)
-		if off < 0 || off+len(val) > len(source) {
-			continue
-		}
-		if string(source[off:off+len(val)]) != val {
-			continue
-		}
-		idx := 0
-		for {
-			pos := strings.Index(val[idx:], "TIMEFORMAT")
-			if pos < 0 {
-				break
-			}
-			abs := off + idx + pos
-			line, col := offsetLineColZC1383(source, abs)
-			if line < 0 {
-				break
-			}
-			edits = append(edits, FixEdit{
-				Line:    line,
-				Column:  col,
-				Length:  len("TIMEFORMAT"),
-				Replace: "TIMEFMT",
-			})
-			idx += pos + len("TIMEFORMAT")
-		}
+		renameInArg(b, arg, "TIMEFORMAT", "TIMEFMT")
 	}
-	return edits
-}
-
-func offsetLineColZC1383(source []byte, offset int) (int, int) {
-	if offset < 0 || offset > len(source) {
-		return -1, -1
-	}
-	line := 1
-	col := 1
-	for i := 0; i < offset; i++ {
-		if source[i] == '\n' {
-			line++
-			col = 1
-			continue
-		}
-		col++
-	}
-	return line, col
+	return builtEdits(b)
 }
 
 func checkZC1383(node ast.Node) []Violation {
@@ -4626,8 +3998,8 @@ func init() {
 		Description: "Bash's `$BASH` holds the path to the running Bash executable. Zsh's " +
 			"equivalent is `$ZSH_NAME` (for the binary name) or `$0` (interactive shell). " +
 			"Using `$BASH` in a Zsh script yields empty output.",
-		Check: checkZC1394,
-		Fix:   fixZC1394,
+		Check:      checkZC1394,
+		FixContext: fixZC1394,
 	})
 }
 
@@ -4636,7 +4008,7 @@ func init() {
 // `$ZSH_NAME`. Each occurrence becomes its own edit at the absolute
 // source offset of that arg's token + the substring index; surrounding
 // quoting, trailing punctuation, and adjoining text stay byte-exact.
-func fixZC1394(node ast.Node, _ Violation, source []byte) []FixEdit {
+func fixZC1394(ctx *Context, node ast.Node, _ Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -4648,56 +4020,24 @@ func fixZC1394(node ast.Node, _ Violation, source []byte) []FixEdit {
 	if ident.Value != "echo" && ident.Value != "print" && ident.Value != "printf" {
 		return nil
 	}
-	var edits []FixEdit
+	b := ctx.NewBuilder()
 	for _, arg := range cmd.Arguments {
-		val := arg.String()
-		matches := bashVarRE.FindAllStringIndex(val, -1)
+		matches := bashVarRE.FindAllStringIndex(arg.String(), -1)
 		if len(matches) == 0 {
 			continue
 		}
-		tok := arg.TokenLiteralNode()
-		off := LineColToByteOffset(source, tok.Line, tok.Column)
-		if off < 0 || off+len(val) > len(source) {
-			continue
-		}
-		if string(source[off:off+len(val)]) != val {
+		off := argOffset(b, arg)
+		if off < 0 {
 			continue
 		}
 		for _, m := range matches {
 			// The regex spans `$BASH` plus one trailing byte (or end).
 			// Rewrite only the `$BASH` prefix; leave the trailing byte
 			// (the boundary char such as a space or quote) intact.
-			abs := off + m[0]
-			line, col := offsetLineColZC1394(source, abs)
-			if line < 0 {
-				continue
-			}
-			edits = append(edits, FixEdit{
-				Line:    line,
-				Column:  col,
-				Length:  len("$BASH"),
-				Replace: "$ZSH_NAME",
-			})
-		}
-	}
-	return edits
-}
-
-func offsetLineColZC1394(source []byte, offset int) (int, int) {
-	if offset < 0 || offset > len(source) {
-		return -1, -1
-	}
-	line := 1
-	col := 1
-	for i := 0; i < offset; i++ {
-		if source[i] == '\n' {
-			line++
-			col = 1
-			continue
+			b.Replace(off+m[0], off+m[0]+len("$BASH"), "$ZSH_NAME")
 		}
-		col++
 	}
-	return line, col
+	return builtEdits(b)
 }
 
 func checkZC1394(node ast.Node) []Violation {
@@ -151,8 +151,8 @@ func init() {
 			"`$HISTSIZE` (in-memory) and `$SAVEHIST` (on disk). Setting only `$HISTFILESIZE` in " +
 			"Zsh has no effect on disk — `$SAVEHIST` must be set. Mixing both names leaves " +
 			"disk-history behavior undefined.",
-		Check: checkZC1403,
-		Fix:   fixZC1403,
+		Check:      checkZC1403,
+		FixContext: fixZC1403,
 	})
 }
 
@@ -160,7 +160,7 @@ func init() {
 // print / printf / export args. Per-arg substring scan; one edit
 // per match. Idempotent — a re-run sees `SAVEHIST`, which the
 // detector's substring guard won't match.
-func fixZC1403(node ast.Node, _ Violation, source []byte) []FixEdit {
+func fixZC1403(ctx *Context, node ast.Node, _ Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -172,57 +172,11 @@ func fixZC1403(node ast.Node, _ Violation, source []byte) []FixEdit {
 	if ident.Value != "echo" && ident.Value != "print" && ident.Value != "printf" && ident.Value != "export" {
 		return nil
 	}
-	const oldName = "HISTFILESIZE"
-	const newName = "SAVEHIST"
-	var edits []FixEdit
+	b := ctx.NewBuilder()
 	for _, arg := range cmd.Arguments {
-		val := arg.String()
-		tok := arg.TokenLiteralNode()
-		off := LineColToByteOffset(source, tok.Line, tok.Column)
-		if off < 0 || off+len(val) > len(source) {
-			continue
-		}
-		if string(source[off:off+len(val)]) != val {
-			continue
-		}
-		idx := 0
-		for {
-			pos := strings.Index(val[idx:], oldName)
-			if pos < 0 {
-				break
-			}
-			abs := off + idx + pos
-			line, col := offsetLineColZC1403(source, abs)
-			if line < 0 {
-				break
-			}
-			edits = append(edits, FixEdit{
-				Line:    line,
-				Column:  col,
-				Length:  len(oldName),
-				Replace: newName,
-			})
-			idx += pos + len(oldName)
-		}
-	}
-	return edits
-}
-
-func offsetLineColZC1403(source []byte, offset int) (int, int) {
-	if offset < 0 || offset > len(source) {
-		return -1, -1
-	}
-	line := 1
-	col := 1
-	for i := 0; i < offset; i++ {
-		if source[i] == '\n' {
-			line++
-			col = 1
-			continue
-		}
-		col++
+		renameInArg(b, arg, "HISTFILESIZE", "SAVEHIST")
 	}
-	return line, col
+	return builtEdits(b)
 }
 
 func checkZC1403(node ast.Node) []Violation {
@@ -264,8 +218,8 @@ func init() {
 		Description: "Bash's `$BASH_CMDS` associative array mirrors the hash-table of command " +
 			"names→paths. Zsh exposes the same via `$commands` (assoc array from " +
 			"`zsh/parameter`). `$BASH_CMDS` is unset in Zsh.",
-		Check: checkZC1404,
-		Fix:   fixZC1404,
+		Check:      checkZC1404,
+		FixContext: fixZC1404,
 	})
 }
 
@@ -273,7 +227,7 @@ func init() {
 // printf args. Per-arg substring scan; one edit per match.
 // Idempotent — a re-run sees `commands`, which the detector's
 // substring guard won't match.
-func fixZC1404(node ast.Node, _ Violation, source []byte) []FixEdit {
+func fixZC1404(ctx *Context, node ast.Node, _ Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -285,57 +239,11 @@ func fixZC1404(node ast.Node, _ Violation, source []byte) []FixEdit {
 	if ident.Value != "echo" && ident.Value != "print" && ident.Value != "printf" {
 		return nil
 	}
-	const oldName = "BASH_CMDS"
-	const newName = "commands"
-	var edits []FixEdit
+	b := ctx.NewBuilder()
 	for _, arg := range cmd.Arguments {
-		val := arg.String()
-		tok := arg.TokenLiteralNode()
-		off := LineColToByteOffset(source, tok.Line, tok.Column)
-		if off < 0 || off+len(val) > len(source) {
-			continue
-		}
-		if string(source[off:off+len(val)]) != val {
-			continue
-		}
-		idx := 0
-		for {
-			pos := strings.Index(val[idx:], oldName)
-			if pos < 0 {
-				break
-			}
-			abs := off + idx + pos
-			line, col := offsetLineColZC1404(source, abs)
-			if line < 0 {
-				break
-			}
-			edits = append(edits, FixEdit{
-				Line:    line,
-				Column:  col,
-				Length:  len(oldName),
-				Replace: newName,
-			})
-			idx += pos + len(oldName)
-		}
-	}
-	return edits
-}
-
-func offsetLineColZC1404(source []byte, offset int) (int, int) {
-	if offset < 0 || offset > len(source) {
-		return -1, -1
-	}
-	line := 1
-	col := 1
-	for i := 0; i < offset; i++ {
-		if source[i] == '\n' {
-			line++
-			col = 1
-			continue
-		}
-		col++
+		renameInArg(b, arg, "BASH_CMDS", "commands")
 	}
-	return line, col
+	return builtEdits(b)
 }
 
 func checkZC1404(node ast.Node) []Violation {
@@ -612,15 +520,16 @@ func init() {
 		Description: "Bash's `enable -n name` disables a builtin so that the external of the same " +
 			"name is used. Zsh provides a dedicated `disable` builtin: `disable name` achieves " +
 			"the same in one verb. Re-enable later with `enable name`.",
-		Check: checkZC1411,
-		Fix:   fixZC1411,
+		Check:      checkZC1411,
+		FixContext: fixZC1411,
+		FixSafety:  FixSafe,
 	})
 }
 
 // fixZC1411 collapses `enable -n NAME` into `disable NAME`. The span
 // covers the `enable` command name and the `-n` flag in a single edit;
 // trailing builtin name(s) stay in place.
-func fixZC1411(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1411(ctx *Context, node ast.Node, v Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -629,37 +538,12 @@ func fixZC1411(node ast.Node, v Violation, source []byte) []FixEdit {
 	if !ok || ident.Value != "enable" {
 		return nil
 	}
-	var dashN ast.Expression
 	for _, arg := range cmd.Arguments {
 		if arg.String() == "-n" {
-			dashN = arg
-			break
+			return replaceNameThrough(ctx, cmd, arg, "disable")
 		}
 	}
-	if dashN == nil {
-		return nil
-	}
-	nameOff := LineColToByteOffset(source, v.Line, v.Column)
-	if nameOff < 0 || nameOff+len("enable") > len(source) {
-		return nil
-	}
-	if string(source[nameOff:nameOff+len("enable")]) != "enable" {
-		return nil
-	}
-	dashTok := dashN.TokenLiteralNode()
-	dashOff := LineColToByteOffset(source, dashTok.Line, dashTok.Column)
-	if dashOff < 0 || dashOff+2 > len(source) {
-		return nil
-	}
-	if string(source[dashOff:dashOff+2]) != "-n" {
-		return nil
-	}
-	return []FixEdit{{
-		Line:    v.Line,
-		Column:  v.Column,
-		Length:  dashOff + 2 - nameOff,
-		Replace: "disable",
-	}}
+	return nil
 }
 
 func checkZC1411(node ast.Node) []Violation {
@@ -740,8 +624,8 @@ func init() {
 		Description: "Bash's `hash -t cmd` prints the hashed path for `cmd` (or fails if not " +
 			"hashed). Zsh's `whence -p cmd` prints the PATH-resolved absolute path, whether " +
 			"hashed or not — more reliable and the native Zsh idiom.",
-		Check: checkZC1413,
-		Fix:   fixZC1413,
+		Check:      checkZC1413,
+		FixContext: fixZC1413,
 	})
 }
 
@@ -750,7 +634,7 @@ func init() {
 // sees `whence`, not `hash`, so the detector won't fire. Defensive
 // byte-match guards on both edits refuse to insert if the source
 // at the offset doesn't match.
-func fixZC1413(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1413(ctx *Context, node ast.Node, v Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -759,35 +643,15 @@ func fixZC1413(node ast.Node, v Violation, source []byte) []FixEdit {
 	if !ok || ident.Value != "hash" {
 		return nil
 	}
-	var dashT ast.Node
 	for _, arg := range cmd.Arguments {
 		if arg.String() == "-t" {
-			dashT = arg
-			break
+			b := ctx.NewBuilder()
+			b.ReplaceCommandName(cmd, "whence")
+			b.ReplaceNode(arg, "-p")
+			return builtEdits(b)
 		}
 	}
-	if dashT == nil {
-		return nil
-	}
-	cmdOff := LineColToByteOffset(source, v.Line, v.Column)
-	if cmdOff < 0 || cmdOff+len("hash") > len(source) {
-		return nil
-	}
-	if string(source[cmdOff:cmdOff+len("hash")]) != "hash" {
-		return nil
-	}
-	tTok := dashT.TokenLiteralNode()
-	tOff := LineColToByteOffset(source, tTok.Line, tTok.Column)
-	if tOff < 0 || tOff+len("-t") > len(source) {
-		return nil
-	}
-	if string(source[tOff:tOff+len("-t")]) != "-t" {
-		return nil
-	}
-	return []FixEdit{
-		{Line: v.Line, Column: v.Column, Length: len("hash"), Replace: "whence"},
-		{Line: tTok.Line, Column: tTok.Column, Length: len("-t"), Replace: "-p"},
-	}
+	return nil
 }
 
 func checkZC1413(node ast.Node) []Violation {
@@ -2359,8 +2223,8 @@ func init() {
 			"interactive confirmation and stalls CI/Dockerfiles indefinitely. Always pass `-y` " +
 			"(or `--yes`), and for unattended upgrades also set " +
 			"`DEBIAN_FRONTEND=noninteractive` in the environment.",
-		Check: checkZC1448,
-		Fix:   fixZC1448,
+		Check:      checkZC1448,
+		FixContext: fixZC1448,
 	})
 }
 
@@ -2369,7 +2233,7 @@ func init() {
 // confirmation. Only fires for plain `apt` — for `apt-get` the legacy
 // ZC1213 fix already handles the rewrite, and emitting a duplicate
 // zero-length insert here would yield ` -y -y` after both edits apply.
-func fixZC1448(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1448(ctx *Context, node ast.Node, v Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -2378,42 +2242,9 @@ func fixZC1448(node ast.Node, v Violation, source []byte) []FixEdit {
 	if !ok || ident.Value != "apt" {
 		return nil
 	}
-	nameOff := LineColToByteOffset(source, v.Line, v.Column)
-	if nameOff < 0 {
-		return nil
-	}
-	nameLen := IdentLenAt(source, nameOff)
-	if nameLen != len("apt") {
-		return nil
-	}
-	insertAt := nameOff + nameLen
-	insLine, insCol := offsetLineColZC1448(source, insertAt)
-	if insLine < 0 {
-		return nil
-	}
-	return []FixEdit{{
-		Line:    insLine,
-		Column:  insCol,
-		Length:  0,
-		Replace: " -y",
-	}}
-}
-
-func offsetLineColZC1448(source []byte, offset int) (int, int) {
-	if offset < 0 || offset > len(source) {
-		return -1, -1
-	}
-	line := 1
-	col := 1
-	for i := 0; i < offset; i++ {
-		if source[i] == '\n' {
-			line++
-			col = 1
-			continue
-		}
-		col++
-	}
-	return line, col
+	b := ctx.NewBuilder()
+	b.InsertArgBefore(cmd, 0, "-y")
+	return builtEdits(b)
 }
 
 func checkZC1448(node ast.Node) []Violation {
@@ -72,15 +72,15 @@ func init() {
 			"compose` subcommand. Scripts that invoke `docker-compose` silently degrade on " +
 			"fresh installs and miss V2-only options (`--profile`, `--wait`, richer env " +
 			"interpolation). Call `docker compose` (space) or pin the V2 binary explicitly.",
-		Check: checkZC1501,
-		Fix:   fixZC1501,
+		Check:      checkZC1501,
+		FixContext: fixZC1501,
 	})
 }
 
 // fixZC1501 rewrites the hyphenated `docker-compose` command name into
 // the space-separated `docker compose` subcommand form. Arguments stay
 // untouched — the V2 plugin accepts the same shape.
-func fixZC1501(node ast.Node, v Violation, _ []byte) []FixEdit {
+func fixZC1501(ctx *Context, node ast.Node, v Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -89,12 +89,9 @@ func fixZC1501(node ast.Node, v Violation, _ []byte) []FixEdit {
 	if !ok || ident.Value != "docker-compose" {
 		return nil
 	}
-	return []FixEdit{{
-		Line:    v.Line,
-		Column:  v.Column,
-		Length:  len("docker-compose"),
-		Replace: "docker compose",
-	}}
+	b := ctx.NewBuilder()
+	b.ReplaceCommandName(cmd, "docker compose")
+	return builtEdits(b)
 }
 
 func checkZC1501(node ast.Node) []Violation {
@@ -131,8 +128,9 @@ func init() {
 			"fuzzed filename, an attacker can pass `--include=*secret*` or `-f /etc/shadow` " +
 			"and get grep to read paths the script author never intended. Always write " +
 			"`grep -- \"$var\" file` or use a grep-compatible library with explicit pattern API.",
-		Check: checkZC1502,
-		Fix:   fixZC1502,
+		Check:      checkZC1502,
+		FixContext: fixZC1502,
+		FixSafety:  FixSafe,
 	})
 }
 
@@ -141,7 +139,7 @@ func init() {
 // `--` end-of-options marker. Idempotent — the detector gates on
 // the absence of `--`, so once `-- ` is present a re-run won't
 // re-insert.
-func fixZC1502(node ast.Node, _ Violation, source []byte) []FixEdit {
+func fixZC1502(ctx *Context, node ast.Node, _ Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok || !zc1502IsGrepFamily(cmd) {
 		return nil
@@ -150,16 +148,13 @@ func fixZC1502(node ast.Node, _ Violation, source []byte) []FixEdit {
 	if firstVar == nil {
 		return nil
 	}
-	tok := firstVar.TokenLiteralNode()
-	off := LineColToByteOffset(source, tok.Line, tok.Column)
-	if off < 0 {
-		return nil
-	}
-	insLine, insCol := offsetLineColZC1502(source, off)
-	if insLine < 0 {
+	b := ctx.NewBuilder()
+	start, _, ok := b.Span(firstVar)
+	if !ok {
 		return nil
 	}
-	return []FixEdit{{Line: insLine, Column: insCol, Length: 0, Replace: "-- "}}
+	b.Insert(start, "-- ")
+	return builtEdits(b)
 }
 
 var zc1502GrepFamily = map[string]struct{}{
@@ -185,23 +180,6 @@ func zc1502FirstVarArg(cmd *ast.SimpleCommand) ast.Expression {
 	return first
 }
 
-func offsetLineColZC1502(source []byte, offset int) (int, int) {
-	if offset < 0 || offset > len(source) {
-		return -1, -1
-	}
-	line := 1
-	col := 1
-	for i := 0; i < offset; i++ {
-		if source[i] == '\n' {
-			line++
-			col = 1
-			continue
-		}
-		col++
-	}
-	return line, col
-}
-
 func checkZC1502(node ast.Node) []Violation {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok || !zc1502IsGrepFamily(cmd) {
@@ -702,8 +680,8 @@ func init() {
 			"but reverses argument order, loses `--user` scope, ignores unit templating, and " +
 			"can't restart sockets or timers. Prefer `systemctl start|stop|restart|reload " +
 			"<unit>` for consistency across scripts and interactive shells.",
-		Check: checkZC1512,
-		Fix:   fixZC1512,
+		Check:      checkZC1512,
+		FixContext: fixZC1512,
 	})
 }
 
@@ -712,7 +690,7 @@ func init() {
 // textual contents of the UNIT and VERB positions. Gated to simple
 // Identifier args so the swap stays byte-exact; concat-form units
 // (rare in practice) stay detection-only.
-func fixZC1512(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1512(ctx *Context, node ast.Node, v Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -732,41 +710,11 @@ func fixZC1512(node ast.Node, v Violation, source []byte) []FixEdit {
 	if !ok {
 		return nil
 	}
-	unitTok := unitIdent.TokenLiteralNode()
-	verbTok := verbIdent.TokenLiteralNode()
-	unitOff := LineColToByteOffset(source, unitTok.Line, unitTok.Column)
-	verbOff := LineColToByteOffset(source, verbTok.Line, verbTok.Column)
-	if unitOff < 0 || verbOff < 0 {
-		return nil
-	}
-	if unitOff+len(unitIdent.Value) > len(source) ||
-		string(source[unitOff:unitOff+len(unitIdent.Value)]) != unitIdent.Value {
-		return nil
-	}
-	if verbOff+len(verbIdent.Value) > len(source) ||
-		string(source[verbOff:verbOff+len(verbIdent.Value)]) != verbIdent.Value {
-		return nil
-	}
-	return []FixEdit{
-		{
-			Line:    v.Line,
-			Column:  v.Column,
-			Length:  len("service"),
-			Replace: "systemctl",
-		},
-		{
-			Line:    unitTok.Line,
-			Column:  unitTok.Column,
-			Length:  len(unitIdent.Value),
-			Replace: verbIdent.Value,
-		},
-		{
-			Line:    verbTok.Line,
-			Column:  verbTok.Column,
-			Length:  len(verbIdent.Value),
-			Replace: unitIdent.Value,
-		},
-	}
+	b := ctx.NewBuilder()
+	b.ReplaceCommandName(cmd, "systemctl")
+	b.ReplaceNode(unitIdent, verbIdent.Value)
+	b.ReplaceNode(verbIdent, unitIdent.Value)
+	return builtEdits(b)
 }
 
 func checkZC1512(node ast.Node) []Violation {
@@ -2841,11 +2789,17 @@ func init() {
 			"A 1024-bit RSA modulus or DH group is within reach of academic precomputation " +
 			"(Logjam) and a 512-bit one was broken on commodity hardware in the 1990s. Use " +
 			"2048 as a floor and 3072 / 4096 for long-lived keys.",
-		Check: checkZC1552,
+		Options: []Option{{
+			Name:        "min_bits",
+			Type:        OptionInt,
+			Default:     2048,
+			Description: "Smallest `dhparam` / `genrsa` / `gendsa` size accepted without a finding.",
+		}},
+		CheckContext: checkZC1552,
 	})
 }
 
-func checkZC1552(node ast.Node) []Violation {
+func checkZC1552(ctx *Context, node ast.Node) []Violation {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -2867,13 +2821,15 @@ func checkZC1552(node ast.Node) []Violation {
 		return nil
 	}
 
+	minBits := ctx.Options.Int("min_bits")
 	for _, arg := range cmd.Arguments[1:] {
 		v := arg.String()
-		if n, err := strconv.Atoi(v); err == nil && n > 0 && n < 2048 {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 && n < minBits {
 			return []Violation{{
 				KataID: "ZC1552",
 				Message: "`openssl " + sub + " " + v + "` uses a weak key/param size — " +
-					"modern baselines require 2048+. Use 2048 or 3072/4096 for long-lived keys.",
+					"modern baselines require " + strconv.Itoa(minBits) + "+. Use " +
+					strconv.Itoa(minBits) + " or 3072/4096 for long-lived keys.",
 				Line:   cmd.Token.Line,
 				Column: cmd.Token.Column,
 				Level:  SeverityWarning,
@@ -3560,15 +3516,15 @@ func init() {
 			"a scripted \"does this command exist?\" check, `command -v <cmd>` respects the " +
 			"current `$PATH`, returns the selected resolution, and has no index-refresh " +
 			"coupling.",
-		Check: checkZC1565,
-		Fix:   fixZC1565,
+		Check:      checkZC1565,
+		FixContext: fixZC1565,
 	})
 }
 
 // fixZC1565 rewrites a `whereis` / `locate` / `mlocate` / `plocate`
 // command-name lookup into `command -v`. The detector restricts to the
 // four index-based forms so the swap is safe; arguments stay untouched.
-func fixZC1565(node ast.Node, v Violation, _ []byte) []FixEdit {
+func fixZC1565(ctx *Context, node ast.Node, v Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -3579,12 +3535,9 @@ func fixZC1565(node ast.Node, v Violation, _ []byte) []FixEdit {
 	}
 	switch ident.Value {
 	case "whereis", "locate", "mlocate", "plocate":
-		return []FixEdit{{
-			Line:    v.Line,
-			Column:  v.Column,
-			Length:  len(ident.Value),
-			Replace: "command -v",
-		}}
+		b := ctx.NewBuilder()
+		b.ReplaceCommandName(cmd, "command -v")
+		return builtEdits(b)
 	}
 	return nil
 }
@@ -4972,8 +4925,8 @@ func init() {
 			"raw, sentinel-safe) and the parameter-expansion flag `${(F)array}` (newline-join, " +
 			"fine for `$(...)`). Both are shorter than the printf incantation and avoid format-" +
 			"string surprises if the array ever contains a literal `%`.",
-		Check: checkZC1591,
-		Fix:   fixZC1591,
+		Check:      checkZC1591,
+		FixContext: fixZC1591,
 	})
 }
 
@@ -4982,7 +4935,7 @@ func init() {
 // replacement covers the `printf` command name and the format
 // argument; subsequent args (the array expansion) pass through
 // unchanged. Idempotent — a re-run sees `print`, not `printf`.
-func fixZC1591(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1591(ctx *Context, node ast.Node, v Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -4995,33 +4948,11 @@ func fixZC1591(node ast.Node, v Violation, source []byte) []FixEdit {
 		return nil
 	}
 	formatArg := cmd.Arguments[0]
-	formatVal := formatArg.String()
-	trimmed := strings.Trim(formatVal, "'\"")
+	trimmed := strings.Trim(formatArg.String(), "'\"")
 	if trimmed != `%s\n` && trimmed != "%s" {
 		return nil
 	}
-	cmdOff := LineColToByteOffset(source, v.Line, v.Column)
-	if cmdOff < 0 || cmdOff+len("printf") > len(source) {
-		return nil
-	}
-	if string(source[cmdOff:cmdOff+len("printf")]) != "printf" {
-		return nil
-	}
-	formatTok := formatArg.TokenLiteralNode()
-	formatOff := LineColToByteOffset(source, formatTok.Line, formatTok.Column)
-	if formatOff < 0 || formatOff+len(formatVal) > len(source) {
-		return nil
-	}
-	if string(source[formatOff:formatOff+len(formatVal)]) != formatVal {
-		return nil
-	}
-	end := formatOff + len(formatVal)
-	return []FixEdit{{
-		Line:    v.Line,
-		Column:  v.Column,
-		Length:  end - cmdOff,
-		Replace: "print -l -r --",
-	}}
+	return replaceNameThrough(ctx, cmd, formatArg, "print -l -r --")
 }
 
 func checkZC1591(node ast.Node) []Violation {
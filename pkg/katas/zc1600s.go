@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/afadesigns/zshellcheck/pkg/ast"
+	"github.com/afadesigns/zshellcheck/pkg/katas/fixbuild"
 )
 
 func init() {
@@ -2035,8 +2036,9 @@ func init() {
 			"(`-ir` for readonly integer, `-xr` for readonly export, `-gr` to pin a readonly " +
 			"global from inside a function). `readonly` works but reads as a Bash / POSIX-ism " +
 			"in a Zsh codebase.",
-		Check: checkZC1637,
-		Fix:   fixZC1637,
+		Check:      checkZC1637,
+		FixContext: fixZC1637,
+		FixSafety:  FixSafe,
 	})
 }
 
@@ -2044,7 +2046,7 @@ func init() {
 // Single-edit replacement at the violation column. Detector gates on
 // the bare command name match, so the rewrite is idempotent on a
 // re-run (the new line starts with `typeset` not `readonly`).
-func fixZC1637(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1637(ctx *Context, node ast.Node, v Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -2053,19 +2055,9 @@ func fixZC1637(node ast.Node, v Violation, source []byte) []FixEdit {
 	if !ok || ident.Value != "readonly" {
 		return nil
 	}
-	off := LineColToByteOffset(source, v.Line, v.Column)
-	if off < 0 || off+len("readonly") > len(source) {
-		return nil
-	}
-	if string(source[off:off+len("readonly")]) != "readonly" {
-		return nil
-	}
-	return []FixEdit{{
-		Line:    v.Line,
-		Column:  v.Column,
-		Length:  len("readonly"),
-		Replace: "typeset -r",
-	}}
+	b := ctx.NewBuilder()
+	b.ReplaceCommandName(cmd, "typeset -r")
+	return builtEdits(b)
 }
 
 func checkZC1637(node ast.Node) []Violation {
@@ -2386,8 +2378,9 @@ func init() {
 			"directly into the command-substitution buffer with no fork and no exec. In a hot " +
 			"path the speedup is dramatic, and even in cold paths it avoids one of the most " +
 			"common useless-use-of-cat patterns in review feedback.",
-		Check: checkZC1643,
-		Fix:   fixZC1643,
+		Check:      checkZC1643,
+		FixContext: fixZC1643,
+		FixSafety:  FixSafe,
 	})
 }
 
@@ -2401,22 +2394,22 @@ func init() {
 // Idempotent because the literal `$(cat ` no longer appears in the
 // rewritten text — the second-pass detector sees `$(<…)` and stays
 // silent.
-func fixZC1643(node ast.Node, _ Violation, source []byte) []FixEdit {
+func fixZC1643(ctx *Context, node ast.Node, _ Violation) []FixEdit {
+	source := ctx.Source
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
 	}
-	var edits []FixEdit
+	b := ctx.NewBuilder()
 	for _, arg := range cmd.Arguments {
 		tok := arg.TokenLiteralNode()
-		argOff := LineColToByteOffset(source, tok.Line, tok.Column)
+		argOff := b.Offset(tok.Line, tok.Column)
 		if argOff < 0 {
 			continue
 		}
-		end := zc1643UnquotedArgEnd(source, argOff)
-		edits = append(edits, zc1643CatEditsIn(source, argOff, end)...)
+		zc1643ReplaceCats(b, argOff, zc1643UnquotedArgEnd(source, argOff))
 	}
-	return edits
+	return builtEdits(b)
 }
 
 // zc1643UnquotedArgEnd walks forward from offset until it leaves the
@@ -2479,44 +2472,20 @@ func (s *zc1643ArgScan) absorb(c byte) bool {
 	return false
 }
 
-func zc1643CatEditsIn(source []byte, start, end int) []FixEdit {
+// zc1643ReplaceCats replaces the `cat ` of every `$(cat ` in
+// source[start:end] with `<`.
+func zc1643ReplaceCats(b *fixbuild.Builder, start, end int) {
 	const needle = "$(cat "
-	var edits []FixEdit
+	source := b.Source()
 	i := start
 	for i+len(needle) <= end {
 		if string(source[i:i+len(needle)]) != needle {
 			i++
 			continue
 		}
-		line, col := offsetLineColZC1643(source, i+2)
-		if line >= 0 {
-			edits = append(edits, FixEdit{
-				Line:    line,
-				Column:  col,
-				Length:  4,
-				Replace: "<",
-			})
-		}
+		b.Replace(i+2, i+len(needle), "<")
 		i += len(needle)
 	}
-	return edits
-}
-
-func offsetLineColZC1643(source []byte, offset int) (int, int) {
-	if offset < 0 || offset > len(source) {
-		return -1, -1
-	}
-	line := 1
-	col := 1
-	for i := 0; i < offset; i++ {
-		if source[i] == '\n' {
-			line++
-			col = 1
-			continue
-		}
-		col++
-	}
-	return line, col
 }
 
 func checkZC1643(node ast.Node) []Violation {
@@ -4318,8 +4287,8 @@ func init() {
 			"subshell) and `typeset +x VAR` to drop the export flag. Functions that must " +
 			"cross a subshell are usually better handled by `autoload -Uz` from an `fpath` " +
 			"directory than by serialisation.",
-		Check: checkZC1675,
-		Fix:   fixZC1675,
+		Check:      checkZC1675,
+		FixContext: fixZC1675,
 	})
 }
 
@@ -4332,7 +4301,7 @@ var zc1675FlagReplace = map[string]string{
 	"-n": "typeset +x",
 }
 
-func fixZC1675(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1675(ctx *Context, node ast.Node, v Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok || CommandIdentifier(cmd) != "export" {
 		return nil
@@ -4341,20 +4310,7 @@ func fixZC1675(node ast.Node, v Violation, source []byte) []FixEdit {
 	if flag == nil {
 		return nil
 	}
-	nameOff, ok := zc1675ExportOffset(source, v)
-	if !ok {
-		return nil
-	}
-	flagOff, ok := zc1675FlagOffset(source, flag)
-	if !ok {
-		return nil
-	}
-	return []FixEdit{{
-		Line:    v.Line,
-		Column:  v.Column,
-		Length:  flagOff + 2 - nameOff,
-		Replace: replace,
-	}}
+	return replaceNameThrough(ctx, cmd, flag, replace)
 }
 
 func zc1675FindFlag(cmd *ast.SimpleCommand) (ast.Expression, string) {
@@ -4366,30 +4322,6 @@ func zc1675FindFlag(cmd *ast.SimpleCommand) (ast.Expression, string) {
 	return nil, ""
 }
 
-func zc1675ExportOffset(source []byte, v Violation) (int, bool) {
-	off := LineColToByteOffset(source, v.Line, v.Column)
-	if off < 0 || off+len("export") > len(source) {
-		return 0, false
-	}
-	if string(source[off:off+len("export")]) != "export" {
-		return 0, false
-	}
-	return off, true
-}
-
-func zc1675FlagOffset(source []byte, flag ast.Expression) (int, bool) {
-	tok := flag.TokenLiteralNode()
-	off := LineColToByteOffset(source, tok.Line, tok.Column)
-	if off < 0 || off+2 > len(source) {
-		return 0, false
-	}
-	lit := string(source[off : off+2])
-	if lit != "-f" && lit != "-n" {
-		return 0, false
-	}
-	return off, true
-}
-
 func checkZC1675(node ast.Node) []Violation {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
@@ -4956,8 +4888,8 @@ func init() {
 			"hung stop, logs look wrong, and any cleanup registered on signal handlers in " +
 			"a wrapping shell never runs. Replace with `exec tail -f /dev/null` (signal-" +
 			"handles cleanly) or front with `tini` / `dumb-init` when PID 1 must stay.",
-		Check: checkZC1685,
-		Fix:   fixZC1685,
+		Check:      checkZC1685,
+		FixContext: fixZC1685,
 	})
 }
 
@@ -4965,7 +4897,7 @@ func init() {
 // Single span replacement covers both tokens. Idempotent — a re-run
 // sees `exec`, not `sleep`, so the detector won't fire. Defensive
 // byte-match guards on both anchors.
-func fixZC1685(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1685(ctx *Context, node ast.Node, v Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -4977,28 +4909,7 @@ func fixZC1685(node ast.Node, v Violation, source []byte) []FixEdit {
 	if len(cmd.Arguments) != 1 || cmd.Arguments[0].String() != "infinity" {
 		return nil
 	}
-	cmdOff := LineColToByteOffset(source, v.Line, v.Column)
-	if cmdOff < 0 || cmdOff+len("sleep") > len(source) {
-		return nil
-	}
-	if string(source[cmdOff:cmdOff+len("sleep")]) != "sleep" {
-		return nil
-	}
-	argTok := cmd.Arguments[0].TokenLiteralNode()
-	argOff := LineColToByteOffset(source, argTok.Line, argTok.Column)
-	if argOff < 0 || argOff+len("infinity") > len(source) {
-		return nil
-	}
-	if string(source[argOff:argOff+len("infinity")]) != "infinity" {
-		return nil
-	}
-	end := argOff + len("infinity")
-	return []FixEdit{{
-		Line:    v.Line,
-		Column:  v.Column,
-		Length:  end - cmdOff,
-		Replace: "exec tail -f /dev/null",
-	}}
+	return replaceNameThrough(ctx, cmd, cmd.Arguments[0], "exec tail -f /dev/null")
 }
 
 func checkZC1685(node ast.Node) []Violation {
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/afadesigns/zshellcheck/pkg/ast"
+	"github.com/afadesigns/zshellcheck/pkg/katas/fixbuild"
 )
 
 func init() {
@@ -1045,8 +1046,8 @@ func init() {
 			"the registry where downstream pulls cannot verify provenance. Drop the flag and " +
 			"sign the artifact (`docker trust sign IMAGE:TAG`) instead, or scope the bypass " +
 			"with a tight Notary signer policy.",
-		Check: checkZC1717,
-		Fix:   fixZC1717,
+		Check:      checkZC1717,
+		FixContext: fixZC1717,
 	})
 }
 
@@ -1062,7 +1063,8 @@ var zc1717DockerSubs = map[string]struct{}{
 	"pull": {}, "push": {}, "build": {}, "create": {}, "run": {},
 }
 
-func fixZC1717(node ast.Node, _ Violation, source []byte) []FixEdit {
+func fixZC1717(ctx *Context, node ast.Node, _ Violation) []FixEdit {
+	source := ctx.Source
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok || CommandIdentifier(cmd) != "docker" {
 		return nil
@@ -1071,16 +1073,14 @@ func fixZC1717(node ast.Node, _ Violation, source []byte) []FixEdit {
 	if flagArg == nil {
 		return nil
 	}
-	off, ok := zc1717ResolveFlagOffset(source, flagArg)
+	b := ctx.NewBuilder()
+	off, ok := zc1717ResolveFlagOffset(b, flagArg)
 	if !ok {
 		return nil
 	}
 	start, end := zc1717TrimWhitespaceLeft(source, off, off+len("--disable-content-trust"))
-	startLine, startCol := offsetLineColZC1717(source, start)
-	if startLine < 0 {
-		return nil
-	}
-	return []FixEdit{{Line: startLine, Column: startCol, Length: end - start, Replace: ""}}
+	b.Replace(start, end, "")
+	return builtEdits(b)
 }
 
 func zc1717FindDisableTrustFlag(args []ast.Expression) ast.Expression {
@@ -1105,10 +1105,11 @@ func zc1717FindDisableTrustFlag(args []ast.Expression) ast.Expression {
 // `--disable-content-trust` literal. The lexer emits `--` as its own
 // token then concatenates the flag body, so the token column may land
 // one byte off — scan a 3-byte window for the literal.
-func zc1717ResolveFlagOffset(source []byte, arg ast.Expression) (int, bool) {
+func zc1717ResolveFlagOffset(b *fixbuild.Builder, arg ast.Expression) (int, bool) {
 	const flag = "--disable-content-trust"
+	source := b.Source()
 	tok := arg.TokenLiteralNode()
-	anchor := LineColToByteOffset(source, tok.Line, tok.Column)
+	anchor := b.Offset(tok.Line, tok.Column)
 	if anchor < 0 {
 		return 0, false
 	}
@@ -1131,23 +1132,6 @@ func zc1717TrimWhitespaceLeft(source []byte, start, end int) (int, int) {
 	return start, end
 }
 
-func offsetLineColZC1717(source []byte, offset int) (int, int) {
-	if offset < 0 || offset > len(source) {
-		return -1, -1
-	}
-	line := 1
-	col := 1
-	for i := 0; i < offset; i++ {
-		if source[i] == '\n' {
-			line++
-			col = 1
-			continue
-		}
-		col++
-	}
-	return line, col
-}
-
 func checkZC1717(node ast.Node) []Violation {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
@@ -3880,11 +3864,17 @@ func init() {
 			"for passwords, API tokens, reset URLs, or any other secret that sits at rest. " +
 			"Use `-hex 32` (256-bit) for secrets and long-lived tokens; `-hex 16` is " +
 			"acceptable only for short-validity nonces paired with rate-limited consumers.",
-		Check: checkZC1760,
+		Options: []Option{{
+			Name:        "min_bytes",
+			Type:        OptionInt,
+			Default:     16,
+			Description: "Smallest `-hex` / `-base64` byte count accepted without a finding.",
+		}},
+		CheckContext: checkZC1760,
 	})
 }
 
-func checkZC1760(node ast.Node) []Violation {
+func checkZC1760(ctx *Context, node ast.Node) []Violation {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -3901,11 +3891,12 @@ func checkZC1760(node ast.Node) []Violation {
 		return nil
 	}
 
+	minBytes := ctx.Options.Int("min_bytes")
 	prevEnc := ""
 	for _, arg := range cmd.Arguments[1:] {
 		v := arg.String()
 		if prevEnc != "" {
-			if n, err := strconv.Atoi(v); err == nil && n > 0 && n < 16 {
+			if n, err := strconv.Atoi(v); err == nil && n > 0 && n < minBytes {
 				return zc1760Hit(cmd, prevEnc+" "+v)
 			}
 			prevEnc = ""
@@ -4606,15 +4597,15 @@ func init() {
 			"can return no results, or switch to `find ... -exec cmd {} +` which never runs " +
 			"the child on empty input. BSD xargs defaults to this behavior, but the portable " +
 			"and explicit choice is to pass `-r` and document the intent.",
-		Check: checkZC1773,
-		Fix:   fixZC1773,
+		Check:      checkZC1773,
+		FixContext: fixZC1773,
 	})
 }
 
 // fixZC1773 inserts ` -r` after the `xargs` command name. Detector
 // already guards against any existing `-r` / `--no-run-if-empty` /
 // combined-short-flag form so the insertion is idempotent.
-func fixZC1773(node ast.Node, v Violation, source []byte) []FixEdit {
+func fixZC1773(ctx *Context, node ast.Node, v Violation) []FixEdit {
 	cmd, ok := node.(*ast.SimpleCommand)
 	if !ok {
 		return nil
@@ -4623,41 +4614,9 @@ func fixZC1773(node ast.Node, v Violation, source []byte) []FixEdit {
 	if !ok || ident.Value != "xargs" {
 		return nil
 	}
-	nameOff := LineColToByteOffset(source, v.Line, v.Column)
-	if nameOff < 0 {
-		return nil
-	}
-	if IdentLenAt(source, nameOff) != len("xargs") {
-		return nil
-	}
-	insertAt := nameOff + len("xargs")
-	insLine, insCol := offsetLineColZC1773(source, insertAt)
-	if insLine < 0 {
-		return nil
-	}
-	return []FixEdit{{
-		Line:    insLine,
-		Column:  insCol,
-		Length:  0,
-		Replace: " -r",
-	}}
-}
-
-func offsetLineColZC1773(source []byte, offset int) (int, int) {
-	if offset < 0 || offset > len(source) {
-		return -1, -1
-	}
-	line := 1
-	col := 1
-	for i := 0; i < offset; i++ {
-		if source[i] == '\n' {
-			line++
-			col = 1
-			continue
-		}
-		col++
-	}
-	return line, col
+	b := ctx.NewBuilder()
+	b.InsertArgBefore(cmd, 0, "-r")
+	return builtEdits(b)
 }
 
 func checkZC1773(node ast.Node) []Violation {
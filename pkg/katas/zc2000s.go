@@ -3,9 +3,11 @@
 package katas
 
 import (
+	"strconv"
 	"strings"
 
 	"github.com/afadesigns/zshellcheck/pkg/ast"
+	"github.com/afadesigns/zshellcheck/pkg/token"
 )
 
 func init() {
@@ -290,3 +292,128 @@ func zc2003Hit(cmd *ast.SimpleCommand, form string) []Violation {
 		Level:  SeverityWarning,
 	}}
 }
+
+func init() {
+	RegisterFileKata(Kata{
+		ID:       "ZC2004",
+		Title:    "Warn on a function defined twice at file scope — the later body silently replaces the first",
+		Severity: SeverityWarning,
+		Description: "Zsh function definitions are plain assignments to the `functions` table: " +
+			"a second `name() { … }` in the same file replaces the first without a word, " +
+			"so every call after it runs the new body and the first one is dead code. In " +
+			"a dotfile assembled from snippets this usually means two plugins or two " +
+			"copy-pasted blocks fight over one name, and whichever loads last wins. Keep " +
+			"one definition, rename one of them, or guard the fallback with " +
+			"`(( $+functions[name] )) || name() { … }`. Definitions inside `if` / `case` " +
+			"branches are alternatives, not redefinitions, and are not flagged.",
+		CheckFile: checkZC2004,
+	})
+}
+
+func checkZC2004(_ *Context, program *ast.Program) []Violation {
+	first := map[string]token.Token{}
+	var violations []Violation
+	for _, stmt := range program.Statements {
+		name, tok, ok := zc2004Definition(stmt)
+		if !ok {
+			continue
+		}
+		prev, seen := first[name]
+		if !seen {
+			first[name] = tok
+			continue
+		}
+		violations = append(violations, Violation{
+			KataID: "ZC2004",
+			Message: "Function `" + name + "` is redefined here (first definition at line " +
+				strconv.Itoa(prev.Line) + ") — the earlier body is silently replaced.",
+			Line:   tok.Line,
+			Column: tok.Column,
+			Level:  SeverityWarning,
+			Related: []Location{{
+				Line: prev.Line, Column: prev.Column,
+				Message: "`" + name + "` first defined here",
+			}},
+		})
+	}
+	return violations
+}
+
+// zc2004Definition returns the name and position of a file-scope function
+// definition statement in either the `name() { … }` or `function name`
+// form.
+func zc2004Definition(stmt ast.Statement) (string, token.Token, bool) {
+	switch n := stmt.(type) {
+	case *ast.FunctionDefinition:
+		if n.Name != nil {
+			return n.Name.Value, n.Token, true
+		}
+	case *ast.ExpressionStatement:
+		if fn, ok := n.Expression.(*ast.FunctionLiteral); ok && fn != nil && fn.Name != nil {
+			return fn.Name.Value, fn.Token, true
+		}
+	}
+	return "", token.Token{}, false
+}
+
+func init() {
+	RegisterFileKata(Kata{
+		ID:       "ZC2005",
+		Title:    "Warn on `compinit` run before `fpath` is extended — later completion dirs are never loaded",
+		Severity: SeverityWarning,
+		Description: "`compinit` scans every directory in `$fpath` once, when it runs, and " +
+			"builds the completion table (and the `.zcompdump` cache) from what it finds. " +
+			"Adding a directory to `fpath` afterwards — `fpath+=(~/.zfunc)`, a plugin's " +
+			"`fpath=(… $fpath)` — has no effect on completion until the next shell, and " +
+			"with a warm dump cache not even then. Extend `fpath` first and call " +
+			"`compinit` once, after every `fpath` change.",
+		CheckFile: checkZC2005,
+	})
+}
+
+func checkZC2005(_ *Context, program *ast.Program) []Violation {
+	var compinit *ast.SimpleCommand
+	for _, stmt := range program.Statements {
+		es, ok := stmt.(*ast.ExpressionStatement)
+		if !ok {
+			continue
+		}
+		if cmd, ok := es.Expression.(*ast.SimpleCommand); ok && isCommandName(cmd, "compinit") {
+			if compinit == nil {
+				compinit = cmd
+			}
+			continue
+		}
+		if compinit == nil || !zc2005ExtendsFpath(es.Expression) {
+			continue
+		}
+		tok := es.Expression.TokenLiteralNode()
+		if infix, ok := es.Expression.(*ast.InfixExpression); ok && infix.Left != nil {
+			tok = infix.Left.TokenLiteralNode()
+		}
+		return []Violation{{
+			KataID: "ZC2005",
+			Message: "`fpath` is extended after `compinit` ran on line " +
+				strconv.Itoa(compinit.Token.Line) + " — completions in the new directory " +
+				"are not loaded. Move `compinit` below every `fpath` change.",
+			Line:   tok.Line,
+			Column: tok.Column,
+			Level:  SeverityWarning,
+			Related: []Location{{
+				Line: compinit.Token.Line, Column: compinit.Token.Column,
+				Message: "`compinit` runs here",
+			}},
+		}}
+	}
+	return nil
+}
+
+// zc2005ExtendsFpath reports whether expr assigns to `fpath` or `FPATH`.
+func zc2005ExtendsFpath(expr ast.Expression) bool {
+	infix, ok := expr.(*ast.InfixExpression)
+	if !ok || (infix.Operator != "=" && infix.Operator != "+=") {
+		return false
+	}
+	ident, ok := infix.Left.(*ast.Identifier)
+	return ok && (ident.Value == "fpath" || ident.Value == "FPATH")
+}
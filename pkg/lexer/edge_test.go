@@ -122,3 +122,24 @@ func TestAngleBracketOperatorVariants(t *testing.T) {
 		tokensFor(t, src)
 	}
 }
+
+func TestTokenEndPositions(t *testing.T) {
+	toks := tokensFor(t, "rm -rf \"a\nb\" >> out\ncat <<EOF\nhi\nEOF\n")
+	want := []struct {
+		lit                string
+		endLine, endColumn int
+	}{
+		{"rm", 1, 3}, {"-", 1, 5}, {"rf", 1, 7}, {"\"a\nb\"", 2, 3},
+		{">>", 2, 6}, {"out", 2, 10}, {"cat", 3, 4}, {"<<", 3, 7},
+	}
+	for i, w := range want {
+		tok := toks[i]
+		if tok.Literal != w.lit || tok.EndLine != w.endLine || tok.EndColumn != w.endColumn {
+			t.Errorf("token %d = %q ending %d:%d, want %q ending %d:%d",
+				i, tok.Literal, tok.EndLine, tok.EndColumn, w.lit, w.endLine, w.endColumn)
+		}
+	}
+	if eof := toks[len(toks)-1]; eof.EndLine != 0 || eof.EndColumn != 0 {
+		t.Errorf("EOF carries an end %d:%d, want none", eof.EndLine, eof.EndColumn)
+	}
+}
@@ -13,6 +13,11 @@ type Lexer struct {
 	ch           byte // current char under examination
 	line         int  // current line number
 	column       int  // current column number
+	// prevLine and prevColumn locate the byte read before the current
+	// one — the last byte of the token just scanned — so NextToken can
+	// stamp each token's end position.
+	prevLine   int
+	prevColumn int
 
 	// dbracketDepth is kept for historical parity with katas that
 	// look at it, but the primary source of truth is now
@@ -80,6 +85,7 @@ func New(input string) *Lexer {
 }
 
 func (l *Lexer) readChar() {
+	l.prevLine, l.prevColumn = l.line, l.column
 	if l.readPosition >= len(l.input) {
 		l.ch = 0
 	} else {
@@ -143,6 +149,9 @@ func (l *Lexer) NextToken() (tok token.Token) {
 				tok.ClosesDollarBrace = true
 			}
 		}
+		if tok.EndColumn == 0 && tok.Type != token.EOF {
+			tok.EndLine, tok.EndColumn = l.prevLine, l.prevColumn+1
+		}
 		l.lastEmittedType = tok.Type
 		l.lastEmittedHadSpace = tok.HasPrecedingSpace
 	}()
@@ -706,6 +715,9 @@ func (l *Lexer) readLtLead(peek byte, two func(token.Type) token.Token) token.To
 			return l.readFused3Token(token.PLUSEQ)
 		}
 		tok := two(token.LTLT)
+		// Stamp the end before the heredoc body is skipped, so the
+		// token covers `<<` rather than everything up to the closer.
+		tok.EndLine, tok.EndColumn = l.line, l.column+1
 		l.consumeHeredocBody()
 		return tok
 	case '&':
@@ -3,7 +3,9 @@
 package reporter
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/url"
 	"path/filepath"
@@ -19,33 +21,143 @@ import (
 type FileViolations struct {
 	Filename   string
 	Violations []katas.Violation
+	// Source is the scanned text, used to turn a suggested edit's byte
+	// length into an end position. Without it an edit is assumed to stay
+	// on its starting line.
+	Source []byte
+	// ParseErrors says why the file failed to parse, in which case it
+	// has no findings. Only collected for the formats that report it.
+	ParseErrors []ParseError
+	// Suppressed holds the findings a `# noka` directive or the baseline
+	// silenced. Only collected for the formats that report suppression.
+	Suppressed []Suppressed
+	// Absent holds the -baseline snapshot's findings in this file that
+	// the run no longer found.
+	Absent []AbsentFinding
+}
+
+// AbsentFinding is a -baseline snapshot finding the run no longer found:
+// its FindingKey and, as fingerprints number them, its 0-based ordinal
+// among the snapshot's findings with that key.
+type AbsentFinding struct {
+	Key     string
+	Ordinal int
+}
+
+// RunInfo describes the run a report comes from: the tool version and
+// the settings that shaped the findings. It is shared by the reports that
+// record them — json-v2 lists the configuration, SARIF marks baseline
+// state and suppressions — and each reads only the fields it needs.
+type RunInfo struct {
+	ToolVersion   string
+	DisabledKatas []string
+	// Severities is the -severity filter; empty reports every severity.
+	Severities []katas.Severity
+	// Baseline is the -baseline snapshot findings were filtered against.
+	Baseline    string
+	UnsafeFixes bool
+	// IncludeSuppressed lists silenced findings in the SARIF report
+	// (-sarif-include-suppressed).
+	IncludeSuppressed bool
+}
+
+// ParseError is one parser error, at the position the parser reached.
+type ParseError struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+// SuppressionKind says what silenced a finding.
+type SuppressionKind string
+
+const (
+	// SuppressedByNoka marks a finding silenced by a `# noka` directive
+	// in the source.
+	SuppressedByNoka SuppressionKind = "noka"
+	// SuppressedByBaseline marks a finding recorded in the -baseline
+	// snapshot.
+	SuppressedByBaseline SuppressionKind = "baseline"
+)
+
+// Suppressed is a finding that was silenced rather than reported.
+type Suppressed struct {
+	Violation katas.Violation
+	Kind      SuppressionKind
+	// Justification is the reason given for the suppression, if any.
+	Justification string
+	// InBaseline is true when the -baseline snapshot records the finding.
+	InBaseline bool
 }
 
 type jsonFinding struct {
-	File    string         `json:"File"`
-	KataID  string         `json:"KataID"`
-	Message string         `json:"Message"`
-	Line    int            `json:"Line"`
-	Column  int            `json:"Column"`
-	Level   katas.Severity `json:"Level"`
+	File      string         `json:"File"`
+	KataID    string         `json:"KataID"`
+	Message   string         `json:"Message"`
+	Line      int            `json:"Line"`
+	Column    int            `json:"Column"`
+	EndLine   int            `json:"EndLine,omitempty"`
+	EndColumn int            `json:"EndColumn,omitempty"`
+	Level     katas.Severity `json:"Level"`
+	Related   []jsonLocation `json:"Related,omitempty"`
+	Fixes     []jsonFix      `json:"Fixes,omitempty"`
+	Fixable   bool           `json:"Fixable"`
+	Fix       *jsonAutoFix   `json:"Fix,omitempty"`
+}
+
+// jsonAutoFix is the rewrite the fixer would apply for a finding, graded
+// by its least safe edit, with each edit resolved against the source.
+type jsonAutoFix struct {
+	Safety katas.FixSafety    `json:"Safety"`
+	Edits  []jsonResolvedEdit `json:"Edits"`
+}
+
+// jsonResolvedEdit is a fix edit with its end position and, when the
+// source is known, the byte range [StartOffset, EndOffset) it replaces.
+type jsonResolvedEdit struct {
+	Line        int             `json:"Line"`
+	Column      int             `json:"Column"`
+	EndLine     int             `json:"EndLine"`
+	EndColumn   int             `json:"EndColumn"`
+	StartOffset *int            `json:"StartOffset,omitempty"`
+	EndOffset   *int            `json:"EndOffset,omitempty"`
+	Length      int             `json:"Length"`
+	Replace     string          `json:"Replace"`
+	Safety      katas.FixSafety `json:"Safety"`
+}
+
+type jsonFix struct {
+	Title string     `json:"Title"`
+	Edits []jsonEdit `json:"Edits"`
+}
+
+type jsonEdit struct {
+	Line    int    `json:"Line"`
+	Column  int    `json:"Column"`
+	Length  int    `json:"Length"`
+	Replace string `json:"Replace"`
+}
+
+type jsonLocation struct {
+	File    string `json:"File"`
+	Line    int    `json:"Line"`
+	Column  int    `json:"Column"`
+	Message string `json:"Message"`
 }
 
 // ReportJSON writes every finding across all files as one JSON array.
 // Each element keeps the original single-file fields and adds `File`, so
 // existing single-file consumers are unaffected and multi-file output is
-// valid and attributed.
+// valid and attributed. EndLine and EndColumn appear when the finding
+// covers a range, Related when it has secondary locations, and Fixes when
+// its kata suggests rewrites, in preference order. Fix carries the edits
+// the fixer would apply, and Fixable says whether there are any it would
+// apply at all — under `-unsafe-fixes` for an unsafe one.
 func ReportJSON(w io.Writer, files []FileViolations) error {
 	findings := []jsonFinding{}
 	for _, f := range files {
 		for _, v := range f.Violations {
-			findings = append(findings, jsonFinding{
-				File:    f.Filename,
-				KataID:  v.KataID,
-				Message: v.Message,
-				Line:    v.Line,
-				Column:  v.Column,
-				Level:   v.Level,
-			})
+			findings = append(findings, jsonFindingOf(f, v))
 		}
 	}
 	enc := json.NewEncoder(w)
@@ -53,6 +165,85 @@ func ReportJSON(w io.Writer, files []FileViolations) error {
 	return enc.Encode(findings)
 }
 
+// jsonFindingOf renders one finding of f as a JSON array element.
+func jsonFindingOf(f FileViolations, v katas.Violation) jsonFinding {
+	return jsonFinding{
+		File:      f.Filename,
+		KataID:    v.KataID,
+		Message:   v.Message,
+		Line:      v.Line,
+		Column:    v.Column,
+		EndLine:   v.EndLine,
+		EndColumn: v.EndColumn,
+		Level:     v.Level,
+		Related:   jsonRelated(f.Filename, v.Related),
+		Fixes:     jsonFixes(v.Suggestions),
+		Fixable:   fixable(v),
+		Fix:       jsonFixOf(f.Source, v.Fix),
+	}
+}
+
+// jsonRelated renders a finding's related locations, attributing the ones
+// without a file to the finding's own file.
+func jsonRelated(file string, related []katas.Location) []jsonLocation {
+	var out []jsonLocation
+	for _, rel := range related {
+		out = append(out, jsonLocation{
+			File:    relatedFile(file, rel),
+			Line:    rel.Line,
+			Column:  rel.Column,
+			Message: rel.Message,
+		})
+	}
+	return out
+}
+
+// jsonFixes renders a finding's suggested rewrites.
+func jsonFixes(suggestions []katas.Suggestion) []jsonFix {
+	var out []jsonFix
+	for _, s := range suggestions {
+		fix := jsonFix{Title: s.Title, Edits: []jsonEdit{}}
+		for _, e := range s.Edits {
+			fix.Edits = append(fix.Edits, jsonEdit{Line: e.Line, Column: e.Column, Length: e.Length, Replace: e.Replace})
+		}
+		out = append(out, fix)
+	}
+	return out
+}
+
+// fixable reports whether the fixer would apply v's fix: it has edits and
+// they are not graded as suggestions for a human to apply.
+func fixable(v katas.Violation) bool {
+	return len(v.Fix) > 0 && katas.WeakestSafety(v.Fix) != katas.FixSuggestion
+}
+
+// jsonFixOf renders a finding's fix edits with their end positions and
+// byte offsets, or nil when it has none.
+func jsonFixOf(source []byte, edits []katas.FixEdit) *jsonAutoFix {
+	if len(edits) == 0 {
+		return nil
+	}
+	fix := &jsonAutoFix{Safety: katas.WeakestSafety(edits)}
+	for _, e := range edits {
+		r := jsonResolvedEdit{Line: e.Line, Column: e.Column, Length: e.Length, Replace: e.Replace, Safety: e.Safety}
+		r.EndLine, r.EndColumn = editEnd(source, e)
+		if start := editOffset(source, e); start >= 0 {
+			end := start + e.Length
+			r.StartOffset, r.EndOffset = &start, &end
+		}
+		fix.Edits = append(fix.Edits, r)
+	}
+	return fix
+}
+
+// relatedFile returns the file a related location points into.
+func relatedFile(file string, rel katas.Location) string {
+	if rel.File != "" {
+		return rel.File
+	}
+	return file
+}
+
 // SARIF 2.1.0 document shape, trimmed to the fields ZShellCheck emits.
 type sarifDoc struct {
 	Schema  string     `json:"$schema"`
@@ -83,6 +274,10 @@ type RuleMeta struct {
 	Title       string
 	Description string
 	HelpURI     string
+	Tags        []string
+	// Severity is the kata's registered severity, the rule's default
+	// level for a result that has no finding of its own to take it from.
+	Severity katas.Severity
 }
 
 type sarifRule struct {
@@ -99,11 +294,54 @@ type sarifRuleConfig struct {
 }
 
 type sarifResult struct {
-	RuleID    string          `json:"ruleId"`
-	RuleIndex int             `json:"ruleIndex"`
-	Level     string          `json:"level"`
-	Message   sarifMessage    `json:"message"`
-	Locations []sarifLocation `json:"locations"`
+	RuleID           string          `json:"ruleId"`
+	RuleIndex        int             `json:"ruleIndex"`
+	Level            string          `json:"level"`
+	Message          sarifMessage    `json:"message"`
+	Locations        []sarifLocation `json:"locations"`
+	RelatedLocations []sarifLocation `json:"relatedLocations,omitempty"`
+	Fixes            []sarifFix      `json:"fixes,omitempty"`
+	Properties       sarifProperties `json:"properties"`
+	// PartialFingerprints holds sarifFingerprintKey: the hash of the
+	// finding's FindingKey, which code scanning uses to keep tracking an
+	// alert when unrelated lines move.
+	PartialFingerprints map[string]string  `json:"partialFingerprints,omitempty"`
+	Suppressions        []sarifSuppression `json:"suppressions,omitempty"`
+	BaselineState       string             `json:"baselineState,omitempty"`
+}
+
+// sarifFingerprintKey names the partial fingerprint ZShellCheck emits.
+const sarifFingerprintKey = "findingKeyHash/v1"
+
+type sarifSuppression struct {
+	Kind          string `json:"kind"`
+	Justification string `json:"justification,omitempty"`
+}
+
+// sarifProperties is a result's property bag: whether the fixer would
+// apply its fix, and how far that fix may be trusted.
+type sarifProperties struct {
+	Fixable   bool            `json:"fixable"`
+	FixSafety katas.FixSafety `json:"fixSafety,omitempty"`
+}
+
+type sarifFix struct {
+	Description     sarifMessage          `json:"description"`
+	ArtifactChanges []sarifArtifactChange `json:"artifactChanges"`
+}
+
+type sarifArtifactChange struct {
+	ArtifactLocation sarifArtifact      `json:"artifactLocation"`
+	Replacements     []sarifReplacement `json:"replacements"`
+}
+
+type sarifReplacement struct {
+	DeletedRegion   sarifRegion   `json:"deletedRegion"`
+	InsertedContent *sarifContent `json:"insertedContent,omitempty"`
+}
+
+type sarifContent struct {
+	Text string `json:"text"`
 }
 
 type sarifMessage struct {
@@ -111,12 +349,16 @@ type sarifMessage struct {
 }
 
 type sarifLocation struct {
+	ID               int           `json:"id,omitempty"`
 	PhysicalLocation sarifPhysical `json:"physicalLocation"`
+	Message          *sarifMessage `json:"message,omitempty"`
 }
 
 type sarifPhysical struct {
 	ArtifactLocation sarifArtifact `json:"artifactLocation"`
-	Region           sarifRegion   `json:"region"`
+	// Region is nil for a baseline finding no longer found, which has no
+	// position left to point at.
+	Region *sarifRegion `json:"region,omitempty"`
 }
 
 type sarifArtifact struct {
@@ -126,40 +368,71 @@ type sarifArtifact struct {
 type sarifRegion struct {
 	StartLine   int `json:"startLine"`
 	StartColumn int `json:"startColumn"`
+	EndLine     int `json:"endLine,omitempty"`
+	EndColumn   int `json:"endColumn,omitempty"`
 }
 
 // ReportSARIF writes every finding across all files as one SARIF 2.1.0
 // document: a single run whose results each carry a physical location
-// (file URI + 1-based line/column) and reference a rule in the driver's
+// (file URI + 1-based line/column, plus the end when known) and reference a rule in the driver's
 // rules array, which meta populates with each kata's description, level,
 // and help URI so GitHub code scanning can render them.
 func ReportSARIF(w io.Writer, files []FileViolations, toolVersion string, meta func(string) RuleMeta) error {
+	return ReportSARIFWith(w, files, RunInfo{ToolVersion: toolVersion}, meta)
+}
+
+// ReportSARIFWith is ReportSARIF for the run described by run. With
+// IncludeSuppressed, findings a `# noka` directive or the baseline
+// silenced are listed too, each with an inSource or external suppression.
+// When the run used a -baseline, every result carries its baselineState:
+// new, unchanged when the snapshot records it, or absent for a snapshot
+// finding the run no longer found.
+func ReportSARIFWith(w io.Writer, files []FileViolations, run RunInfo, meta func(string) RuleMeta) error {
 	results := []sarifResult{}
 	rules := []sarifRule{}
 	ruleIndex := map[string]int{}
+	rule := func(v katas.Violation) int {
+		idx, ok := ruleIndex[v.KataID]
+		if !ok {
+			idx = len(rules)
+			ruleIndex[v.KataID] = idx
+			rules = append(rules, buildSarifRule(v, meta))
+		}
+		return idx
+	}
+	baseline := run.Baseline != ""
+	var absent []FileViolations
 	for _, f := range files {
-		for _, v := range f.Violations {
-			idx, ok := ruleIndex[v.KataID]
-			if !ok {
-				idx = len(rules)
-				ruleIndex[v.KataID] = idx
-				rules = append(rules, buildSarifRule(v, meta))
+		activeFP, suppressedFP := fileFingerprints(f)
+		for i, v := range f.Violations {
+			result := sarifResultOf(f, v, rule(v), activeFP[i])
+			if baseline {
+				result.BaselineState = "new"
 			}
-			results = append(results, sarifResult{
-				RuleID:    v.KataID,
-				RuleIndex: idx,
-				Level:     sarifLevel(v.Level),
-				Message:   sarifMessage{Text: v.Message},
-				Locations: []sarifLocation{{
-					PhysicalLocation: sarifPhysical{
-						ArtifactLocation: sarifArtifact{URI: sarifFileURI(f.Filename)},
-						Region: sarifRegion{
-							StartLine:   atLeastOne(v.Line),
-							StartColumn: atLeastOne(v.Column),
-						},
-					},
-				}},
-			})
+			results = append(results, result)
+		}
+		if run.IncludeSuppressed {
+			for i, sup := range f.Suppressed {
+				result := sarifResultOf(f, sup.Violation, rule(sup.Violation), suppressedFP[i])
+				result.Suppressions = []sarifSuppression{{Kind: sarifSuppressionKind(sup.Kind), Justification: sup.Justification}}
+				if baseline {
+					result.BaselineState = "new"
+					if sup.InBaseline {
+						result.BaselineState = "unchanged"
+					}
+				}
+				results = append(results, result)
+			}
+		}
+		if baseline && len(f.Absent) > 0 {
+			absent = append(absent, f)
+		}
+	}
+	// Absent results come last, so a kata also found this run declares
+	// its rule from a real finding.
+	for _, f := range absent {
+		for _, a := range f.Absent {
+			results = append(results, sarifAbsentResult(f.Filename, a, rule, meta))
 		}
 	}
 	doc := sarifDoc{
@@ -169,7 +442,7 @@ func ReportSARIF(w io.Writer, files []FileViolations, toolVersion string, meta f
 			Tool: sarifTool{Driver: sarifDriver{
 				Name:           "zshellcheck",
 				InformationURI: "https://github.com/afadesigns/zshellcheck",
-				Version:        toolVersion,
+				Version:        run.ToolVersion,
 				Rules:          rules,
 			}},
 			Results: results,
@@ -180,6 +453,170 @@ func ReportSARIF(w io.Writer, files []FileViolations, toolVersion string, meta f
 	return enc.Encode(doc)
 }
 
+// sarifResultOf describes one finding of f as a SARIF result referencing
+// the rule at ruleIdx.
+func sarifResultOf(f FileViolations, v katas.Violation, ruleIdx int, fingerprint string) sarifResult {
+	region := buildSarifRegion(v)
+	return sarifResult{
+		RuleID:    v.KataID,
+		RuleIndex: ruleIdx,
+		Level:     sarifLevel(v.Level),
+		Message:   sarifMessage{Text: v.Message},
+		Locations: []sarifLocation{{
+			PhysicalLocation: sarifPhysical{
+				ArtifactLocation: sarifArtifact{URI: sarifFileURI(f.Filename)},
+				Region:           &region,
+			},
+		}},
+		RelatedLocations:    sarifRelated(f.Filename, v.Related),
+		Fixes:               sarifFixes(f, v),
+		Properties:          sarifProperties{Fixable: fixable(v), FixSafety: katas.WeakestSafety(v.Fix)},
+		PartialFingerprints: map[string]string{sarifFingerprintKey: fingerprint},
+	}
+}
+
+// sarifAbsentResult describes a baseline finding the run no longer found,
+// from its FindingKey. The key keeps the kata and the source line's text
+// but not its position, so the result points at the file alone; a rule
+// first declared here takes its level from the kata's severity in meta.
+func sarifAbsentResult(file string, absent AbsentFinding, rule func(katas.Violation) int, meta func(string) RuleMeta) sarifResult {
+	kataID, content := splitFindingKey(absent.Key)
+	v := katas.Violation{KataID: kataID}
+	if meta != nil {
+		v.Level = meta(kataID).Severity
+	}
+	return sarifResult{
+		RuleID:    kataID,
+		RuleIndex: rule(v),
+		Level:     "none",
+		Message:   sarifMessage{Text: fmt.Sprintf("%s no longer found: %s", kataID, content)},
+		Locations: []sarifLocation{{
+			PhysicalLocation: sarifPhysical{ArtifactLocation: sarifArtifact{URI: sarifFileURI(file)}},
+		}},
+		PartialFingerprints: map[string]string{sarifFingerprintKey: ordinalFingerprint(absent.Key, absent.Ordinal)},
+		BaselineState:       "absent",
+	}
+}
+
+// sarifSuppressionKind maps what silenced a finding onto SARIF's
+// suppression kinds: a `# noka` directive is in the source, the baseline
+// snapshot is external to it.
+func sarifSuppressionKind(k SuppressionKind) string {
+	if k == SuppressedByNoka {
+		return "inSource"
+	}
+	return "external"
+}
+
+// buildSarifRegion maps a finding onto a SARIF region. The end is emitted
+// only when the finding covers a range that ends after it starts; SARIF's
+// endColumn is exclusive, matching Violation.EndColumn.
+func buildSarifRegion(v katas.Violation) sarifRegion {
+	r := sarifRegion{StartLine: atLeastOne(v.Line), StartColumn: atLeastOne(v.Column)}
+	if v.EndLine > r.StartLine || (v.EndLine == r.StartLine && v.EndColumn > r.StartColumn) {
+		r.EndLine, r.EndColumn = v.EndLine, v.EndColumn
+	}
+	return r
+}
+
+// sarifRelated maps a finding's related locations onto SARIF
+// relatedLocations, numbered from 1 so messages can link to them.
+func sarifRelated(file string, related []katas.Location) []sarifLocation {
+	var out []sarifLocation
+	for i, rel := range related {
+		out = append(out, sarifLocation{
+			ID: i + 1,
+			PhysicalLocation: sarifPhysical{
+				ArtifactLocation: sarifArtifact{URI: sarifFileURI(relatedFile(file, rel))},
+				Region:           &sarifRegion{StartLine: atLeastOne(rel.Line), StartColumn: atLeastOne(rel.Column)},
+			},
+			Message: &sarifMessage{Text: rel.Message},
+		})
+	}
+	return out
+}
+
+// sarifFixes maps a finding's suggested rewrites onto SARIF fixes, one
+// per suggestion, each replacing regions of the finding's own file. A
+// finding without suggestions gets its auto-fix as the single fix.
+func sarifFixes(f FileViolations, v katas.Violation) []sarifFix {
+	var out []sarifFix
+	for _, s := range v.Suggestions {
+		out = append(out, sarifFixOf(f, s.Title, s.Edits))
+	}
+	if len(out) == 0 && len(v.Fix) > 0 {
+		title := fmt.Sprintf("Apply the %s fix (%s)", v.KataID, katas.WeakestSafety(v.Fix))
+		out = append(out, sarifFixOf(f, title, v.Fix))
+	}
+	return out
+}
+
+// sarifFixOf builds one SARIF fix replacing the regions edits cover.
+func sarifFixOf(f FileViolations, title string, edits []katas.FixEdit) sarifFix {
+	change := sarifArtifactChange{ArtifactLocation: sarifArtifact{URI: sarifFileURI(f.Filename)}}
+	for _, e := range edits {
+		r := sarifReplacement{DeletedRegion: sarifRegion{StartLine: atLeastOne(e.Line), StartColumn: atLeastOne(e.Column)}}
+		r.DeletedRegion.EndLine, r.DeletedRegion.EndColumn = editEnd(f.Source, e)
+		if e.Replace != "" {
+			r.InsertedContent = &sarifContent{Text: e.Replace}
+		}
+		change.Replacements = append(change.Replacements, r)
+	}
+	return sarifFix{Description: sarifMessage{Text: title}, ArtifactChanges: []sarifArtifactChange{change}}
+}
+
+// editEnd returns the exclusive end of the span an edit replaces: its
+// start advanced by Length bytes of source, stepping onto the next line at
+// each newline. Without source the span is taken to stay on one line.
+func editEnd(source []byte, e katas.FixEdit) (int, int) {
+	line, col := atLeastOne(e.Line), atLeastOne(e.Column)
+	start := lineOffset(source, line)
+	if start < 0 {
+		return line, col + e.Length
+	}
+	from := start + col - 1
+	for i := from; i < from+e.Length && i < len(source); i++ {
+		if source[i] == '\n' {
+			line++
+			col = 1
+			continue
+		}
+		col++
+	}
+	return line, col
+}
+
+// editOffset returns the byte offset at which an edit starts in source,
+// or -1 when source is nil or does not reach it.
+func editOffset(source []byte, e katas.FixEdit) int {
+	start := lineOffset(source, atLeastOne(e.Line))
+	if start < 0 {
+		return -1
+	}
+	off := start + atLeastOne(e.Column) - 1
+	if off > len(source) {
+		return -1
+	}
+	return off
+}
+
+// lineOffset returns the byte offset at which 1-based line starts in
+// source, or -1 when source is nil or has fewer lines.
+func lineOffset(source []byte, line int) int {
+	if source == nil {
+		return -1
+	}
+	off := 0
+	for l := 1; l < line; l++ {
+		i := bytes.IndexByte(source[off:], '\n')
+		if i < 0 {
+			return -1
+		}
+		off += i + 1
+	}
+	return off
+}
+
 // buildSarifRule assembles a SARIF rule descriptor from a finding plus its
 // kata metadata. A nil meta yields a minimal descriptor.
 func buildSarifRule(v katas.Violation, meta func(string) RuleMeta) sarifRule {
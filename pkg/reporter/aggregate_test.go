@@ -5,6 +5,8 @@ package reporter
 import (
 	"bytes"
 	"encoding/json"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/afadesigns/zshellcheck/pkg/katas"
@@ -133,6 +135,213 @@ func TestReportSARIF_LevelMappingAndClamp(t *testing.T) {
 	}
 }
 
+func TestReportRegionEnds(t *testing.T) {
+	files := []FileViolations{{Filename: "a.zsh", Violations: []katas.Violation{
+		{KataID: "ZC1", Line: 2, Column: 3, EndLine: 2, EndColumn: 8, Level: katas.SeverityWarning},
+		{KataID: "ZC2", Line: 4, Column: 1, Level: katas.SeverityWarning},
+	}}}
+
+	var buf bytes.Buffer
+	if err := ReportJSON(&buf, files); err != nil {
+		t.Fatal(err)
+	}
+	var findings []map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &findings); err != nil {
+		t.Fatal(err)
+	}
+	if findings[0]["EndLine"] != 2.0 || findings[0]["EndColumn"] != 8.0 {
+		t.Errorf("JSON range end = %v:%v, want 2:8", findings[0]["EndLine"], findings[0]["EndColumn"])
+	}
+	if _, ok := findings[1]["EndLine"]; ok {
+		t.Errorf("JSON point finding carries an end: %v", findings[1])
+	}
+
+	buf.Reset()
+	if err := ReportSARIF(&buf, files, "0.0.0", nil); err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	results := doc["runs"].([]any)[0].(map[string]any)["results"].([]any)
+	region := func(i int) map[string]any {
+		return results[i].(map[string]any)["locations"].([]any)[0].(map[string]any)["physicalLocation"].(map[string]any)["region"].(map[string]any)
+	}
+	if r := region(0); r["endLine"] != 2.0 || r["endColumn"] != 8.0 {
+		t.Errorf("SARIF range region = %v, want endLine 2 endColumn 8", r)
+	}
+	if r := region(1); r["endLine"] != nil || r["endColumn"] != nil {
+		t.Errorf("SARIF point region carries an end: %v", r)
+	}
+}
+
+func TestReportRelatedLocations(t *testing.T) {
+	files := []FileViolations{{Filename: "a.zsh", Violations: []katas.Violation{{
+		KataID: "ZC1", Message: "m", Line: 3, Column: 1, Level: katas.SeverityWarning,
+		Related: []katas.Location{
+			{Line: 1, Column: 2, Message: "here"},
+			{File: "b.zsh", Line: 4, Column: 5, Message: "there"},
+		},
+	}}}}
+
+	var buf bytes.Buffer
+	if err := ReportJSON(&buf, files); err != nil {
+		t.Fatal(err)
+	}
+	var findings []struct {
+		Related []jsonLocation
+	}
+	if err := json.Unmarshal(buf.Bytes(), &findings); err != nil {
+		t.Fatal(err)
+	}
+	want := []jsonLocation{{"a.zsh", 1, 2, "here"}, {"b.zsh", 4, 5, "there"}}
+	if !reflect.DeepEqual(findings[0].Related, want) {
+		t.Errorf("JSON Related = %+v, want %+v", findings[0].Related, want)
+	}
+
+	buf.Reset()
+	if err := ReportSARIF(&buf, files, "0.0.0", nil); err != nil {
+		t.Fatal(err)
+	}
+	var doc sarifDoc
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	rel := doc.Runs[0].Results[0].RelatedLocations
+	if len(rel) != 2 || rel[0].ID != 1 || rel[1].ID != 2 {
+		t.Fatalf("SARIF relatedLocations = %+v", rel)
+	}
+	if rel[1].PhysicalLocation.ArtifactLocation.URI != "b.zsh" || rel[1].Message.Text != "there" ||
+		rel[1].PhysicalLocation.Region.StartLine != 4 {
+		t.Errorf("SARIF related location = %+v", rel[1])
+	}
+	if doc.Runs[0].Results[0].Locations[0].ID != 0 || doc.Runs[0].Results[0].Locations[0].Message != nil {
+		t.Error("primary location gained an id or message")
+	}
+}
+
+func TestReportFixes(t *testing.T) {
+	files := []FileViolations{{Filename: "a.zsh", Source: []byte("x\necho -e a\n"), Violations: []katas.Violation{{
+		KataID: "ZC1171", Message: "m", Line: 2, Column: 1, Level: katas.SeverityStyle,
+		Suggestions: []katas.Suggestion{
+			{Title: "Use `print`", Edits: []katas.FixEdit{{Line: 2, Column: 1, Length: 7, Replace: "print"}}},
+			{Title: "Join lines", Edits: []katas.FixEdit{{Line: 1, Column: 2, Length: 1}}},
+		},
+	}}}}
+
+	var buf bytes.Buffer
+	if err := ReportJSON(&buf, files); err != nil {
+		t.Fatal(err)
+	}
+	var findings []struct {
+		Fixes []jsonFix
+	}
+	if err := json.Unmarshal(buf.Bytes(), &findings); err != nil {
+		t.Fatal(err)
+	}
+	want := []jsonFix{
+		{Title: "Use `print`", Edits: []jsonEdit{{2, 1, 7, "print"}}},
+		{Title: "Join lines", Edits: []jsonEdit{{1, 2, 1, ""}}},
+	}
+	if !reflect.DeepEqual(findings[0].Fixes, want) {
+		t.Errorf("JSON Fixes = %+v, want %+v", findings[0].Fixes, want)
+	}
+
+	buf.Reset()
+	if err := ReportSARIF(&buf, files, "0.0.0", nil); err != nil {
+		t.Fatal(err)
+	}
+	var doc sarifDoc
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	fixes := doc.Runs[0].Results[0].Fixes
+	if len(fixes) != 2 || fixes[0].Description.Text != "Use `print`" {
+		t.Fatalf("SARIF fixes = %+v", fixes)
+	}
+	r := fixes[0].ArtifactChanges[0].Replacements[0]
+	if r.DeletedRegion != (sarifRegion{2, 1, 2, 8}) || r.InsertedContent == nil || r.InsertedContent.Text != "print" {
+		t.Errorf("SARIF replacement = %+v", r)
+	}
+	// Deleting the newline ends the region at the start of the next line,
+	// and a pure deletion inserts nothing.
+	r = fixes[1].ArtifactChanges[0].Replacements[0]
+	if r.DeletedRegion != (sarifRegion{1, 2, 2, 1}) || r.InsertedContent != nil {
+		t.Errorf("SARIF multi-line replacement = %+v", r)
+	}
+}
+
+func TestReportAutoFix(t *testing.T) {
+	files := []FileViolations{{Filename: "a.zsh", Source: []byte("x\nx=`date`\n"), Violations: []katas.Violation{
+		{
+			KataID: "ZC1002", Message: "m", Line: 2, Column: 3, Level: katas.SeverityStyle,
+			Fix: []katas.FixEdit{{Line: 2, Column: 3, Length: 6, Replace: "$(date)", KataID: "ZC1002", Safety: katas.FixSafe}},
+		},
+		{
+			KataID: "ZC1003", Message: "m", Line: 1, Column: 1, Level: katas.SeverityStyle,
+			Fix: []katas.FixEdit{
+				{Line: 1, Column: 1, Length: 2, Replace: "", Safety: katas.FixSafe},
+				{Line: 2, Column: 1, Length: 0, Replace: "y", Safety: katas.FixSuggestion},
+			},
+		},
+		{KataID: "ZC1004", Message: "m", Line: 1, Column: 1, Level: katas.SeverityStyle},
+	}}}
+
+	var buf bytes.Buffer
+	if err := ReportJSON(&buf, files); err != nil {
+		t.Fatal(err)
+	}
+	var findings []struct {
+		Fixable bool
+		Fix     *jsonAutoFix
+	}
+	if err := json.Unmarshal(buf.Bytes(), &findings); err != nil {
+		t.Fatal(err)
+	}
+	start, end := 4, 10
+	want := &jsonAutoFix{Safety: katas.FixSafe, Edits: []jsonResolvedEdit{{
+		Line: 2, Column: 3, EndLine: 2, EndColumn: 9, StartOffset: &start, EndOffset: &end,
+		Length: 6, Replace: "$(date)", Safety: katas.FixSafe,
+	}}}
+	if !findings[0].Fixable || !reflect.DeepEqual(findings[0].Fix, want) {
+		t.Errorf("JSON fix = %v %+v, want fixable %+v", findings[0].Fixable, findings[0].Fix, want)
+	}
+	// A fix is graded by its least safe edit; a suggestion is not fixable.
+	if findings[1].Fixable || findings[1].Fix == nil || findings[1].Fix.Safety != katas.FixSuggestion {
+		t.Errorf("mixed fix = %v %+v, want an unfixable suggestion", findings[1].Fixable, findings[1].Fix)
+	}
+	if e := findings[1].Fix.Edits[0]; e.EndLine != 2 || e.EndColumn != 1 {
+		t.Errorf("newline deletion ends at %d:%d, want 2:1", e.EndLine, e.EndColumn)
+	}
+	if findings[2].Fixable || findings[2].Fix != nil {
+		t.Errorf("finding without a fix = %v %+v", findings[2].Fixable, findings[2].Fix)
+	}
+
+	buf.Reset()
+	if err := ReportSARIF(&buf, files, "0.0.0", nil); err != nil {
+		t.Fatal(err)
+	}
+	var doc sarifDoc
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	results := doc.Runs[0].Results
+	if got := results[0].Properties; got != (sarifProperties{Fixable: true, FixSafety: katas.FixSafe}) {
+		t.Errorf("SARIF properties = %+v", got)
+	}
+	if len(results[0].Fixes) != 1 {
+		t.Fatalf("SARIF fixes = %+v, want one", results[0].Fixes)
+	}
+	r := results[0].Fixes[0].ArtifactChanges[0].Replacements[0]
+	if r.DeletedRegion != (sarifRegion{2, 3, 2, 9}) || r.InsertedContent == nil || r.InsertedContent.Text != "$(date)" {
+		t.Errorf("SARIF replacement = %+v", r)
+	}
+	if results[2].Fixes != nil || results[2].Properties != (sarifProperties{}) {
+		t.Errorf("SARIF result without a fix = %+v", results[2])
+	}
+}
+
 func TestReportSARIF_RulesMetadata(t *testing.T) {
 	var buf bytes.Buffer
 	if err := ReportSARIF(&buf, twoFiles(), "1.2.3", testMeta); err != nil {
@@ -165,6 +374,151 @@ func TestReportSARIF_RulesMetadata(t *testing.T) {
 	}
 }
 
+func TestReportSARIF_SuppressionsAndBaseline(t *testing.T) {
+	src := []byte("x=`date`\ny=`whoami`\n")
+	files := []FileViolations{{
+		Filename: "a.zsh",
+		Source:   src,
+		Violations: []katas.Violation{
+			{KataID: "ZC1", Message: "new", Line: 1, Column: 3, Level: katas.SeverityWarning},
+		},
+		Suppressed: []Suppressed{
+			{Violation: katas.Violation{KataID: "ZC2", Message: "noka", Line: 1, Column: 3}, Kind: SuppressedByNoka, Justification: "legacy"},
+			{Violation: katas.Violation{KataID: "ZC1", Message: "known", Line: 2, Column: 3}, Kind: SuppressedByBaseline, InBaseline: true},
+		},
+		Absent: []AbsentFinding{{Key: "ZC3\ta.zsh\tz=1"}},
+	}}
+	decode := func(run RunInfo) []sarifResult {
+		t.Helper()
+		var buf bytes.Buffer
+		if err := ReportSARIFWith(&buf, files, run, nil); err != nil {
+			t.Fatal(err)
+		}
+		var doc sarifDoc
+		if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+			t.Fatal(err)
+		}
+		return doc.Runs[0].Results
+	}
+
+	// By default only active findings, with no baseline state.
+	results := decode(RunInfo{})
+	if len(results) != 1 || results[0].BaselineState != "" || results[0].Suppressions != nil {
+		t.Fatalf("default results = %+v", results)
+	}
+	lines := strings.Split(string(src), "\n")
+	if got, want := results[0].PartialFingerprints[sarifFingerprintKey], keyFingerprint(FindingKey("a.zsh", lines, files[0].Violations[0])); got != want {
+		t.Errorf("partial fingerprint = %q, want the FindingKey hash %q", got, want)
+	}
+
+	results = decode(RunInfo{Baseline: "base.txt", IncludeSuppressed: true})
+	type row struct {
+		rule, state, kind, why string
+		region                 bool
+	}
+	var got []row
+	for _, r := range results {
+		g := row{rule: r.RuleID, state: r.BaselineState, region: r.Locations[0].PhysicalLocation.Region != nil}
+		if len(r.Suppressions) == 1 {
+			g.kind, g.why = r.Suppressions[0].Kind, r.Suppressions[0].Justification
+		}
+		got = append(got, g)
+	}
+	want := []row{
+		{"ZC1", "new", "", "", true},
+		{"ZC2", "new", "inSource", "legacy", true},
+		{"ZC1", "unchanged", "external", "", true},
+		{"ZC3", "absent", "", "", false},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("results = %+v, want %+v", got, want)
+	}
+	if results[3].Message.Text != "ZC3 no longer found: z=1" || results[3].PartialFingerprints[sarifFingerprintKey] != keyFingerprint("ZC3\ta.zsh\tz=1") {
+		t.Errorf("absent result = %+v", results[3])
+	}
+}
+
+// A kata seen only in the baseline still declares its rule at the kata's
+// severity, and an absent result never decides the level for a later
+// real finding.
+func TestReportSARIF_AbsentRuleLevel(t *testing.T) {
+	meta := func(id string) RuleMeta { return RuleMeta{Severity: katas.SeverityError} }
+	files := []FileViolations{
+		{Filename: "a.zsh", Absent: []AbsentFinding{{Key: "ZC1\ta.zsh\told"}, {Key: "ZC2\ta.zsh\told"}}},
+		{Filename: "b.zsh", Violations: []katas.Violation{
+			{KataID: "ZC2", Message: "m", Line: 1, Column: 1, Level: katas.SeverityWarning},
+		}},
+	}
+	var buf bytes.Buffer
+	if err := ReportSARIFWith(&buf, files, RunInfo{Baseline: "base.txt"}, meta); err != nil {
+		t.Fatal(err)
+	}
+	var doc sarifDoc
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	levels := map[string]string{}
+	for _, r := range doc.Runs[0].Tool.Driver.Rules {
+		levels[r.ID] = r.DefaultConfiguration.Level
+	}
+	if levels["ZC1"] != "error" || levels["ZC2"] != "warning" {
+		t.Errorf("rule levels = %v, want ZC1 error from meta, ZC2 warning from its finding", levels)
+	}
+	results := doc.Runs[0].Results
+	if len(results) != 3 || results[0].BaselineState != "new" || results[1].BaselineState != "absent" {
+		t.Errorf("results = %+v, want the new finding before the absent ones", results)
+	}
+}
+
+// Identical findings are numbered in source order, suppressed or not, so
+// silencing the first leaves both fingerprints where they were, and a
+// snapshot's second finding with the key is absent under the second's
+// fingerprint.
+func TestReportSARIF_FingerprintOrdinals(t *testing.T) {
+	src := []byte("ls\nls\n")
+	a1 := katas.Violation{KataID: "ZC1", Message: "m", Line: 1, Column: 1}
+	a2 := katas.Violation{KataID: "ZC1", Message: "m", Line: 2, Column: 1}
+	fingerprints := func(f FileViolations) map[int]string {
+		t.Helper()
+		var buf bytes.Buffer
+		if err := ReportSARIFWith(&buf, []FileViolations{f}, RunInfo{Baseline: "b", IncludeSuppressed: true}, nil); err != nil {
+			t.Fatal(err)
+		}
+		var doc sarifDoc
+		if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+			t.Fatal(err)
+		}
+		out := map[int]string{}
+		for _, r := range doc.Runs[0].Results {
+			line := 0
+			if reg := r.Locations[0].PhysicalLocation.Region; reg != nil {
+				line = reg.StartLine
+			}
+			out[line] = r.PartialFingerprints[sarifFingerprintKey]
+		}
+		return out
+	}
+
+	both := fingerprints(FileViolations{Filename: "a.zsh", Source: src, Violations: []katas.Violation{a2, a1}})
+	if both[1] == both[2] {
+		t.Fatalf("identical lines share a fingerprint: %v", both)
+	}
+	silenced := fingerprints(FileViolations{Filename: "a.zsh", Source: src,
+		Violations: []katas.Violation{a2},
+		Suppressed: []Suppressed{{Violation: a1, Kind: SuppressedByNoka}},
+	})
+	if !reflect.DeepEqual(silenced, both) {
+		t.Errorf("fingerprints after silencing line 1 = %v, want %v", silenced, both)
+	}
+	gone := fingerprints(FileViolations{Filename: "a.zsh", Source: []byte("ls\n"),
+		Violations: []katas.Violation{a1},
+		Absent:     []AbsentFinding{{Key: "ZC1\ta.zsh\tls", Ordinal: 1}},
+	})
+	if gone[1] != both[1] || gone[0] != both[2] {
+		t.Errorf("fingerprints with the second finding absent = %v, want %v", gone, both)
+	}
+}
+
 func testMeta(id string) RuleMeta {
 	return RuleMeta{
 		Name:        id + "-name",
@@ -0,0 +1,148 @@
+// SPDX-License-Identifier: MIT
+// Copyright the ZShellCheck contributors.
+package reporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/afadesigns/zshellcheck/pkg/katas"
+)
+
+// Code Climate issue shape, trimmed to the fields GitLab's Code Quality
+// widget reads.
+type codeQualityIssue struct {
+	Type        string              `json:"type"`
+	CheckName   string              `json:"check_name"`
+	Description string              `json:"description"`
+	Categories  []string            `json:"categories"`
+	Severity    string              `json:"severity"`
+	Fingerprint string              `json:"fingerprint"`
+	Location    codeQualityLocation `json:"location"`
+}
+
+type codeQualityLocation struct {
+	Path  string           `json:"path"`
+	Lines codeQualityLines `json:"lines"`
+}
+
+type codeQualityLines struct {
+	Begin int `json:"begin"`
+	End   int `json:"end,omitempty"`
+}
+
+// ReportGitLab writes every finding as one GitLab Code Quality (Code
+// Climate) JSON array. Each issue's fingerprint hashes its FindingKey,
+// the scheme `-baseline` uses, so GitLab tracks a finding across commits
+// that only move it.
+func ReportGitLab(w io.Writer, files []FileViolations) error {
+	issues := []codeQualityIssue{}
+	for _, f := range files {
+		fingerprints, _ := fileFingerprints(f)
+		for i, v := range f.Violations {
+			issue := codeQualityIssue{
+				Type:        "issue",
+				CheckName:   v.KataID,
+				Description: fmt.Sprintf("[%s] %s", v.KataID, v.Message),
+				Categories:  []string{codeQualityCategory(v.Level)},
+				Severity:    codeQualitySeverity(v.Level),
+				Fingerprint: fingerprints[i],
+				Location: codeQualityLocation{
+					Path:  codeQualityPath(f.Filename),
+					Lines: codeQualityLines{Begin: atLeastOne(v.Line)},
+				},
+			}
+			if v.EndLine > issue.Location.Lines.Begin {
+				issue.Location.Lines.End = v.EndLine
+			}
+			issues = append(issues, issue)
+		}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(issues)
+}
+
+// codeQualitySeverity maps a ZShellCheck severity onto the Code Climate
+// scale GitLab displays.
+func codeQualitySeverity(s katas.Severity) string {
+	switch s {
+	case katas.SeverityError:
+		return "critical"
+	case katas.SeverityWarning:
+		return "major"
+	case katas.SeverityInfo:
+		return "minor"
+	default:
+		return "info"
+	}
+}
+
+// codeQualityCategory files style findings under Style and the rest
+// under Bug Risk.
+func codeQualityCategory(s katas.Severity) string {
+	if s == katas.SeverityStyle {
+		return "Style"
+	}
+	return "Bug Risk"
+}
+
+// codeQualityPath renders a scanned path the way GitLab matches it
+// against the repository: forward slashes, no leading "./".
+func codeQualityPath(path string) string {
+	return strings.TrimPrefix(filepath.ToSlash(path), "./")
+}
+
+// ReportGitHub writes every finding as a GitHub Actions workflow command,
+// which the runner turns into an inline annotation on the file:
+//
+//	::warning file=a.zsh,line=3,col=5,endLine=3,endColumn=9,title=ZC1037::message
+//
+// Errors and warnings keep their level; info and style findings become
+// notices.
+func ReportGitHub(w io.Writer, files []FileViolations) error {
+	for _, f := range files {
+		for _, v := range f.Violations {
+			props := []string{
+				"file=" + escapeGitHubProperty(filepath.ToSlash(f.Filename)),
+				fmt.Sprintf("line=%d", atLeastOne(v.Line)),
+				fmt.Sprintf("col=%d", atLeastOne(v.Column)),
+			}
+			if v.EndLine > 0 {
+				props = append(props, fmt.Sprintf("endLine=%d", v.EndLine), fmt.Sprintf("endColumn=%d", v.EndColumn))
+			}
+			props = append(props, "title="+escapeGitHubProperty(v.KataID))
+			if _, err := fmt.Fprintf(w, "::%s %s::%s\n", githubCommand(v.Level), strings.Join(props, ","), escapeGitHubData(v.Message)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// githubCommand maps a ZShellCheck severity onto an annotation command.
+func githubCommand(s katas.Severity) string {
+	switch s {
+	case katas.SeverityError:
+		return "error"
+	case katas.SeverityWarning:
+		return "warning"
+	default:
+		return "notice"
+	}
+}
+
+// escapeGitHubData escapes a workflow command's message, so a `%` or a
+// newline in it cannot end the command or start another.
+func escapeGitHubData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeGitHubProperty escapes a workflow command property value, which
+// additionally may not contain the `:` and `,` that delimit properties.
+func escapeGitHubProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
@@ -0,0 +1,102 @@
+// SPDX-License-Identifier: MIT
+// Copyright the ZShellCheck contributors.
+package reporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/afadesigns/zshellcheck/pkg/katas"
+)
+
+func TestFindingKey(t *testing.T) {
+	lines := []string{"echo one", "  echo two  "}
+	v := katas.Violation{KataID: "ZC1037", Line: 2}
+	if got := FindingKey("a.zsh", lines, v); got != "ZC1037\ta.zsh\techo two" {
+		t.Errorf("FindingKey = %q", got)
+	}
+	if got := FindingKey("a.zsh", lines, katas.Violation{KataID: "ZC1", Line: 9}); got != "ZC1\ta.zsh\t" {
+		t.Errorf("out-of-range FindingKey = %q", got)
+	}
+}
+
+func TestReportGitLab(t *testing.T) {
+	src := []byte("echo $x\necho $x\n\necho $y\n")
+	files := []FileViolations{{Filename: "./dir/a.zsh", Source: src, Violations: []katas.Violation{
+		{KataID: "ZC1", Message: "m", Line: 1, Column: 6, Level: katas.SeverityError},
+		{KataID: "ZC1", Message: "m", Line: 2, Column: 6, Level: katas.SeverityError},
+		{KataID: "ZC2", Message: "n", Line: 3, Column: 1, EndLine: 4, EndColumn: 8, Level: katas.SeverityStyle},
+	}}}
+	var buf bytes.Buffer
+	if err := ReportGitLab(&buf, files); err != nil {
+		t.Fatal(err)
+	}
+	var issues []codeQualityIssue
+	if err := json.Unmarshal(buf.Bytes(), &issues); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if len(issues) != 3 {
+		t.Fatalf("got %d issues, want 3", len(issues))
+	}
+	first := issues[0]
+	if first.Type != "issue" || first.CheckName != "ZC1" || first.Description != "[ZC1] m" ||
+		first.Severity != "critical" || first.Location.Path != "dir/a.zsh" || first.Location.Lines.Begin != 1 {
+		t.Errorf("issue = %+v", first)
+	}
+	// Identical lines get distinct fingerprints.
+	if first.Fingerprint == "" || first.Fingerprint == issues[1].Fingerprint {
+		t.Errorf("fingerprints %q and %q, want distinct", first.Fingerprint, issues[1].Fingerprint)
+	}
+	if got := issues[2]; got.Severity != "info" || got.Categories[0] != "Style" || got.Location.Lines.End != 4 {
+		t.Errorf("style issue = %+v", got)
+	}
+
+	// Moving the findings down a line keeps their fingerprints.
+	moved := []FileViolations{{Filename: "./dir/a.zsh", Source: append([]byte("\n"), src...), Violations: []katas.Violation{
+		{KataID: "ZC1", Message: "m", Line: 2, Column: 6, Level: katas.SeverityError},
+		{KataID: "ZC1", Message: "m", Line: 3, Column: 6, Level: katas.SeverityError},
+	}}}
+	buf.Reset()
+	if err := ReportGitLab(&buf, moved); err != nil {
+		t.Fatal(err)
+	}
+	var again []codeQualityIssue
+	if err := json.Unmarshal(buf.Bytes(), &again); err != nil {
+		t.Fatal(err)
+	}
+	if again[0].Fingerprint != first.Fingerprint || again[1].Fingerprint != issues[1].Fingerprint {
+		t.Error("fingerprints changed when the findings only moved")
+	}
+}
+
+func TestReportGitLab_EmptyIsValidArray(t *testing.T) {
+	var buf bytes.Buffer
+	if err := ReportGitLab(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != "[]\n" {
+		t.Errorf("empty report = %q, want []", got)
+	}
+}
+
+func TestReportGitHub(t *testing.T) {
+	files := []FileViolations{{Filename: "a,b:c.zsh", Violations: []katas.Violation{
+		{KataID: "ZC1", Message: "100% bad\nreally", Line: 3, Column: 5, EndLine: 3, EndColumn: 9, Level: katas.SeverityWarning},
+		{KataID: "ZC2", Message: "note", Line: 0, Column: 0, Level: katas.SeverityStyle},
+		{KataID: "ZC3", Message: "boom", Line: 1, Column: 1, Level: katas.SeverityError},
+	}}}
+	var buf bytes.Buffer
+	if err := ReportGitHub(&buf, files); err != nil {
+		t.Fatal(err)
+	}
+	want := "::warning file=a%2Cb%3Ac.zsh,line=3,col=5,endLine=3,endColumn=9,title=ZC1::100%25 bad%0Areally\n" +
+		"::notice file=a%2Cb%3Ac.zsh,line=1,col=1,title=ZC2::note\n" +
+		"::error file=a%2Cb%3Ac.zsh,line=1,col=1,title=ZC3::boom\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+	if err := ReportGitHub(&failWriter{}, files); err == nil {
+		t.Error("expected error from failing writer")
+	}
+}
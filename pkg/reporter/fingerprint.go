@@ -0,0 +1,88 @@
+// SPDX-License-Identifier: MIT
+// Copyright the ZShellCheck contributors.
+package reporter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/afadesigns/zshellcheck/pkg/katas"
+)
+
+// FindingKey identifies a finding by kata, file, and the trimmed source
+// line — not the line number — so inserting or removing unrelated lines
+// elsewhere in the file leaves it unchanged. lines is the file split on
+// newlines. The `-baseline` snapshot stores these keys verbatim.
+func FindingKey(file string, lines []string, v katas.Violation) string {
+	content := ""
+	if v.Line >= 1 && v.Line <= len(lines) {
+		content = strings.TrimSpace(lines[v.Line-1])
+	}
+	return v.KataID + "\t" + file + "\t" + content
+}
+
+// fileFingerprints hands out stable fingerprints for one file's findings
+// and suppressed findings, in the order of f.Violations and f.Suppressed:
+// a hash of the FindingKey, plus the ordinal of the key within the file
+// when several findings share it, so identical lines stay distinct.
+// Ordinals count every finding, suppressed or not, in source order, so
+// silencing one of two identical findings leaves both fingerprints as
+// they were.
+func fileFingerprints(f FileViolations) (active, suppressed []string) {
+	lines := strings.Split(string(f.Source), "\n")
+	active = make([]string, len(f.Violations))
+	suppressed = make([]string, len(f.Suppressed))
+	type entry struct {
+		v   katas.Violation
+		out *string
+	}
+	entries := make([]entry, 0, len(active)+len(suppressed))
+	for i, v := range f.Violations {
+		entries = append(entries, entry{v, &active[i]})
+	}
+	for i, s := range f.Suppressed {
+		entries = append(entries, entry{s.Violation, &suppressed[i]})
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i].v, entries[j].v
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	seen := map[string]int{}
+	for _, e := range entries {
+		key := FindingKey(f.Filename, lines, e.v)
+		*e.out = ordinalFingerprint(key, seen[key])
+		seen[key]++
+	}
+	return active, suppressed
+}
+
+// ordinalFingerprint is the fingerprint of the finding numbered n, from
+// 0, among a file's findings that share key.
+func ordinalFingerprint(key string, n int) string {
+	if n > 0 {
+		key += "\t" + strconv.Itoa(n)
+	}
+	return keyFingerprint(key)
+}
+
+// keyFingerprint hashes a FindingKey into a fingerprint.
+func keyFingerprint(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// splitFindingKey returns the kata ID and source line text a FindingKey
+// records.
+func splitFindingKey(key string) (kataID, content string) {
+	parts := strings.SplitN(key, "\t", 3)
+	if len(parts) < 3 {
+		return parts[0], ""
+	}
+	return parts[0], parts[2]
+}
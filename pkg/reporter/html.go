@@ -0,0 +1,284 @@
+// SPDX-License-Identifier: MIT
+// Copyright the ZShellCheck contributors.
+package reporter
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/afadesigns/zshellcheck/pkg/fix"
+	"github.com/afadesigns/zshellcheck/pkg/katas"
+)
+
+// htmlSeverities lists the severities in the order the summary shows them.
+var htmlSeverities = []katas.Severity{katas.SeverityError, katas.SeverityWarning, katas.SeverityInfo, katas.SeverityStyle}
+
+type htmlReport struct {
+	Version    string
+	Total      int
+	Files      []htmlFile
+	Severities []htmlCount
+	Katas      []htmlKataCount
+	Tags       []htmlCount
+	Findings   []htmlFinding
+}
+
+type htmlCount struct {
+	Label string
+	Count int
+}
+
+type htmlKataCount struct {
+	ID      string
+	Title   string
+	HelpURI string
+	Count   int
+}
+
+type htmlFile struct {
+	Anchor   string
+	Name     string
+	Findings int
+	Lines    []htmlLine
+	// Orphans holds the findings with no source line to sit under.
+	Orphans []htmlFinding
+}
+
+type htmlLine struct {
+	Anchor   string
+	Number   int
+	Text     string
+	Findings []htmlFinding
+}
+
+type htmlFinding struct {
+	File        string
+	Anchor      string
+	Line        int
+	Column      int
+	Level       katas.Severity
+	KataID      string
+	Title       string
+	Message     string
+	Description string
+	HelpURI     string
+	Fixable     bool
+	FixSafety   katas.FixSafety
+	Preview     []htmlDiffLine
+}
+
+// htmlDiffLine is one line of a fix preview, classed for colouring.
+type htmlDiffLine struct {
+	Class string
+	Text  string
+}
+
+// ReportHTML writes every finding as one self-contained HTML page: counts
+// by severity, kata and the tags meta gives each kata, a findings table sortable by any column, and a
+// source listing per file with each finding shown under its line along
+// with its kata's description from meta and a diff previewing its
+// auto-fix. Styles and the sorting script are inline, so the page has no
+// external assets and can be mailed or archived as one file.
+func ReportHTML(w io.Writer, files []FileViolations, toolVersion string, meta func(string) RuleMeta) error {
+	report := htmlReport{Version: toolVersion}
+	bySeverity := map[katas.Severity]int{}
+	byKata := map[string]*htmlKataCount{}
+	byTag := map[string]int{}
+	for i, f := range files {
+		file := htmlFile{Anchor: fmt.Sprintf("f%d", i+1), Name: f.Filename, Findings: len(f.Violations)}
+		if f.Source != nil {
+			for n, text := range strings.Split(strings.TrimSuffix(string(f.Source), "\n"), "\n") {
+				file.Lines = append(file.Lines, htmlLine{Anchor: fmt.Sprintf("%s-L%d", file.Anchor, n+1), Number: n + 1, Text: text})
+			}
+		}
+		for _, v := range f.Violations {
+			finding := htmlFindingOf(f, v, file.Anchor, meta)
+			if v.Line >= 1 && v.Line <= len(file.Lines) {
+				finding.Anchor = file.Lines[v.Line-1].Anchor
+				file.Lines[v.Line-1].Findings = append(file.Lines[v.Line-1].Findings, finding)
+			} else {
+				file.Orphans = append(file.Orphans, finding)
+			}
+			report.Findings = append(report.Findings, finding)
+			bySeverity[v.Level]++
+			kc, ok := byKata[v.KataID]
+			if !ok {
+				kc = &htmlKataCount{ID: v.KataID, Title: finding.Title, HelpURI: finding.HelpURI}
+				byKata[v.KataID] = kc
+			}
+			kc.Count++
+			if meta != nil {
+				for _, tag := range meta(v.KataID).Tags {
+					byTag[tag]++
+				}
+			}
+		}
+		report.Total += len(f.Violations)
+		report.Files = append(report.Files, file)
+	}
+	for _, s := range htmlSeverities {
+		report.Severities = append(report.Severities, htmlCount{Label: string(s), Count: bySeverity[s]})
+	}
+	for _, kc := range byKata {
+		report.Katas = append(report.Katas, *kc)
+	}
+	sort.Slice(report.Katas, func(i, j int) bool {
+		if report.Katas[i].Count != report.Katas[j].Count {
+			return report.Katas[i].Count > report.Katas[j].Count
+		}
+		return report.Katas[i].ID < report.Katas[j].ID
+	})
+	for tag, n := range byTag {
+		report.Tags = append(report.Tags, htmlCount{Label: tag, Count: n})
+	}
+	sort.Slice(report.Tags, func(i, j int) bool {
+		if report.Tags[i].Count != report.Tags[j].Count {
+			return report.Tags[i].Count > report.Tags[j].Count
+		}
+		return report.Tags[i].Label < report.Tags[j].Label
+	})
+	return htmlPage.Execute(w, report)
+}
+
+// htmlFindingOf describes one finding for the page, previewing its
+// auto-fix as a unified diff when it has one that applies.
+func htmlFindingOf(f FileViolations, v katas.Violation, fileAnchor string, meta func(string) RuleMeta) htmlFinding {
+	finding := htmlFinding{
+		File:      f.Filename,
+		Anchor:    fileAnchor,
+		Line:      v.Line,
+		Column:    v.Column,
+		Level:     v.Level,
+		KataID:    v.KataID,
+		Message:   v.Message,
+		Fixable:   fixable(v),
+		FixSafety: katas.WeakestSafety(v.Fix),
+	}
+	if meta != nil {
+		m := meta(v.KataID)
+		finding.Title, finding.Description, finding.HelpURI = m.Title, m.Description, m.HelpURI
+	}
+	if len(v.Fix) > 0 && f.Source != nil {
+		if diff, err := fix.Diff(f.Filename, string(f.Source), v.Fix); err == nil && diff != "" {
+			finding.Preview = htmlDiffLines(diff)
+		}
+	}
+	return finding
+}
+
+// htmlDiffLines splits a unified diff into lines, classing added and
+// removed ones but not the file headers.
+func htmlDiffLines(diff string) []htmlDiffLine {
+	var out []htmlDiffLine
+	for _, line := range strings.Split(strings.TrimSuffix(diff, "\n"), "\n") {
+		class := ""
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+		case strings.HasPrefix(line, "+"):
+			class = "add"
+		case strings.HasPrefix(line, "-"):
+			class = "del"
+		}
+		out = append(out, htmlDiffLine{Class: class, Text: line})
+	}
+	return out
+}
+
+var htmlPage = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>ZShellCheck report</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2rem; color: #1f2328; }
+h1, h2, h3 { font-weight: 600; }
+table { border-collapse: collapse; margin: 0.5rem 0 1.5rem; }
+th, td { padding: 0.25rem 0.75rem; text-align: left; border-bottom: 1px solid #d0d7de; vertical-align: top; }
+th.sort { cursor: pointer; user-select: none; }
+th.sort::after { content: " \2195"; color: #8c959f; }
+.num { text-align: right; }
+.error { color: #cf222e; }
+.warning { color: #9a6700; }
+.info { color: #0969da; }
+.style { color: #8250df; }
+.src { width: 100%; font-family: ui-monospace, monospace; font-size: 0.85rem; }
+.src td { border: 0; padding: 0 0.5rem; white-space: pre; }
+.src .ln { color: #8c959f; text-align: right; user-select: none; width: 1%; }
+.src tr.hit td.code { background: #fff8c5; }
+.finding { white-space: normal; font-family: system-ui, sans-serif; border-left: 3px solid #d0d7de; margin: 0.25rem 0 0.5rem; padding: 0.25rem 0.75rem; background: #f6f8fa; }
+.finding pre { background: #fff; padding: 0.5rem; overflow-x: auto; }
+.add { color: #1a7f37; }
+.del { color: #cf222e; }
+</style>
+</head>
+<body>
+<h1>ZShellCheck report</h1>
+<p>{{.Total}} finding(s) in {{len .Files}} file(s){{with .Version}}, zshellcheck {{.}}{{end}}.</p>
+
+<h2>Summary</h2>
+<h3>By severity</h3>
+<table>
+<tr><th>Severity</th><th class="num">Findings</th></tr>
+{{range .Severities}}<tr><td class="{{.Label}}">{{.Label}}</td><td class="num">{{.Count}}</td></tr>
+{{end}}</table>
+{{if .Katas}}<h3>By kata</h3>
+<table>
+<tr><th>Kata</th><th>Title</th><th class="num">Findings</th></tr>
+{{range .Katas}}<tr><td>{{if .HelpURI}}<a href="{{.HelpURI}}">{{.ID}}</a>{{else}}{{.ID}}{{end}}</td><td>{{.Title}}</td><td class="num">{{.Count}}</td></tr>
+{{end}}</table>
+{{end}}{{if .Tags}}<h3>By tag</h3>
+<table>
+<tr><th>Tag</th><th class="num">Findings</th></tr>
+{{range .Tags}}<tr><td>{{.Label}}</td><td class="num">{{.Count}}</td></tr>
+{{end}}</table>
+{{end}}
+<h2>Findings</h2>
+<table id="findings">
+<thead><tr><th class="sort">File</th><th class="sort num">Line</th><th class="sort num">Column</th><th class="sort">Severity</th><th class="sort">Kata</th><th class="sort">Message</th><th class="sort">Fix</th></tr></thead>
+<tbody>
+{{range .Findings}}<tr><td><a href="#{{.Anchor}}">{{.File}}</a></td><td class="num">{{.Line}}</td><td class="num">{{.Column}}</td><td class="{{.Level}}">{{.Level}}</td><td>{{.KataID}}</td><td>{{.Message}}</td><td>{{.FixSafety}}</td></tr>
+{{end}}</tbody>
+</table>
+
+<h2>Files</h2>
+{{range .Files}}<section id="{{.Anchor}}">
+<h3>{{.Name}} <small>({{.Findings}} finding(s))</small></h3>
+{{range .Orphans}}{{template "finding" .}}{{end}}
+{{if .Lines}}<table class="src">
+{{range .Lines}}<tr id="{{.Anchor}}"{{if .Findings}} class="hit"{{end}}><td class="ln">{{.Number}}</td><td class="code">{{.Text}}</td></tr>
+{{if .Findings}}<tr><td></td><td>{{range .Findings}}{{template "finding" .}}{{end}}</td></tr>
+{{end}}{{end}}</table>
+{{end}}</section>
+{{end}}
+<script>
+document.querySelectorAll("#findings th.sort").forEach(function (th, col) {
+  var asc = true;
+  th.addEventListener("click", function () {
+    var body = document.querySelector("#findings tbody");
+    var rows = Array.prototype.slice.call(body.rows);
+    var numeric = th.classList.contains("num");
+    rows.sort(function (a, b) {
+      var x = a.cells[col].textContent, y = b.cells[col].textContent;
+      var d = numeric ? x - y : x.localeCompare(y);
+      return asc ? d : -d;
+    });
+    asc = !asc;
+    rows.forEach(function (r) { body.appendChild(r); });
+  });
+});
+</script>
+</body>
+</html>
+{{define "finding"}}<div class="finding">
+<strong class="{{.Level}}">{{.Level}}</strong> <strong>{{.KataID}}</strong>{{with .Title}} {{.}}{{end}} <span>({{.Line}}:{{.Column}})</span>
+<p>{{.Message}}</p>
+{{with .Description}}<details><summary>About this kata</summary><p>{{.}}</p></details>{{end}}
+{{with .HelpURI}}<p><a href="{{.}}">Kata catalog</a></p>{{end}}
+{{if .Preview}}<details{{if .Fixable}} open{{end}}><summary>Fix preview ({{.FixSafety}})</summary><pre>{{range .Preview}}<span{{with .Class}} class="{{.}}"{{end}}>{{.Text}}</span>
+{{end}}</pre></details>{{end}}
+</div>
+{{end}}`))
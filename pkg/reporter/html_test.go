@@ -0,0 +1,83 @@
+// SPDX-License-Identifier: MIT
+// Copyright the ZShellCheck contributors.
+package reporter
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/afadesigns/zshellcheck/pkg/katas"
+)
+
+func htmlFiles() []FileViolations {
+	return []FileViolations{
+		{Filename: "a.zsh", Source: []byte("x=`date`\necho <b>\n"), Violations: []katas.Violation{
+			{
+				KataID: "ZC1002", Message: "use $()", Line: 1, Column: 3, Level: katas.SeverityStyle,
+				Fix: []katas.FixEdit{{Line: 1, Column: 3, Length: 6, Replace: "$(date)", Safety: katas.FixSafe}},
+			},
+			{KataID: "ZC1003", Message: "<script>alert(1)</script>", Line: 2, Column: 1, Level: katas.SeverityError},
+			{KataID: "ZC1003", Message: "file-wide", Line: 0, Column: 0, Level: katas.SeverityError},
+		}},
+		{Filename: "clean.zsh", Source: []byte("print ok\n")},
+	}
+}
+
+func TestReportHTML(t *testing.T) {
+	var buf bytes.Buffer
+	meta := func(id string) RuleMeta {
+		m := testMeta(id)
+		m.Tags = []string{"security"}
+		if id == "ZC1003" {
+			m.Tags = append(m.Tags, "portability")
+		}
+		return m
+	}
+	if err := ReportHTML(&buf, htmlFiles(), "1.2.3", meta); err != nil {
+		t.Fatal(err)
+	}
+	page := buf.String()
+	for _, want := range []string{
+		"3 finding(s) in 2 file(s), zshellcheck 1.2.3.",
+		// Severity and kata counts, most frequent kata first.
+		`<td class="error">error</td><td class="num">2</td>`,
+		`<td class="style">style</td><td class="num">1</td>`,
+		`<a href="https://example.test/ZC1003">ZC1003</a></td><td>ZC1003 title</td><td class="num">2</td></tr>` + "\n" + `<tr><td><a href="https://example.test/ZC1002">ZC1002</a>`,
+		// Tag counts sum the findings of every kata carrying the tag.
+		`<tr><td>security</td><td class="num">3</td></tr>` + "\n" + `<tr><td>portability</td><td class="num">2</td></tr>`,
+		// The findings table links into the source listing.
+		`<td><a href="#f1-L2">a.zsh</a></td>`,
+		`<tr id="f1-L2" class="hit"><td class="ln">2</td><td class="code">echo &lt;b&gt;</td></tr>`,
+		// Descriptions and fix previews come with each finding.
+		"<p>ZC1002 desc</p>",
+		`<span class="del">-x=`,
+		`<span class="add">&#43;x=$(date)</span>`,
+		// A clean file still gets its listing.
+		`<h3>clean.zsh <small>(0 finding(s))</small></h3>`,
+		`<td class="code">print ok</td>`,
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("page lacks %q", want)
+		}
+	}
+	if strings.Contains(page, "<script>alert") {
+		t.Error("finding message was not escaped")
+	}
+	// The file-wide finding has no line to sit under, so it heads the file.
+	if i, j := strings.Index(page, "file-wide"), strings.Index(page, `id="f1-L1"`); i < 0 || i > j {
+		t.Error("finding without a line is not listed above the source")
+	}
+	// Everything is inline: no stylesheets or scripts are fetched.
+	for _, external := range []string{"<link", "<script src", "<img"} {
+		if strings.Contains(page, external) {
+			t.Errorf("page references an external asset via %s", external)
+		}
+	}
+}
+
+func TestReportHTML_WriterError(t *testing.T) {
+	if err := ReportHTML(&failWriter{}, htmlFiles(), "", nil); err == nil {
+		t.Error("expected error from failing writer")
+	}
+}
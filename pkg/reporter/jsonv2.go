@@ -0,0 +1,245 @@
+// SPDX-License-Identifier: MIT
+// Copyright the ZShellCheck contributors.
+package reporter
+
+import (
+	_ "embed"
+	"encoding/json"
+	"io"
+
+	"github.com/afadesigns/zshellcheck/pkg/katas"
+)
+
+// JSONV2Version is the version of the json-v2 document shape. A minor
+// bump only adds fields; a major bump may change or remove them.
+const JSONV2Version = "2.0"
+
+// JSONV2SchemaURL is where the JSON Schema for json-v2 is published.
+const JSONV2SchemaURL = "https://raw.githubusercontent.com/afadesigns/zshellcheck/main/pkg/reporter/schema/json-v2.schema.json"
+
+// JSONV2Schema is the JSON Schema every json-v2 document validates
+// against.
+//
+//go:embed schema/json-v2.schema.json
+var JSONV2Schema []byte
+
+// json-v2 document shape; the schema in schema/json-v2.schema.json
+// describes it field by field.
+type v2Doc struct {
+	Schema  string    `json:"$schema"`
+	Version string    `json:"version"`
+	Tool    v2Tool    `json:"tool"`
+	Config  v2Config  `json:"config"`
+	Files   []v2File  `json:"files"`
+	Summary v2Summary `json:"summary"`
+}
+
+type v2Tool struct {
+	Name           string `json:"name"`
+	Version        string `json:"version"`
+	InformationURI string `json:"informationUri"`
+}
+
+type v2Config struct {
+	DisabledKatas []string         `json:"disabledKatas"`
+	Severities    []katas.Severity `json:"severities"`
+	Baseline      string           `json:"baseline,omitempty"`
+	UnsafeFixes   bool             `json:"unsafeFixes"`
+}
+
+type v2File struct {
+	Path        string         `json:"path"`
+	Status      string         `json:"status"`
+	ParseErrors []v2ParseError `json:"parseErrors"`
+	Findings    []v2Finding    `json:"findings"`
+}
+
+type v2ParseError struct {
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Message string `json:"message"`
+}
+
+type v2Finding struct {
+	KataID      string         `json:"kataId"`
+	Title       string         `json:"title"`
+	Tags        []string       `json:"tags"`
+	Severity    katas.Severity `json:"severity"`
+	Message     string         `json:"message"`
+	Line        int            `json:"line"`
+	Column      int            `json:"column"`
+	EndLine     int            `json:"endLine,omitempty"`
+	EndColumn   int            `json:"endColumn,omitempty"`
+	HelpURI     string         `json:"helpUri,omitempty"`
+	Related     []v2Location   `json:"related"`
+	Suggestions []v2Suggestion `json:"suggestions"`
+	Fixable     bool           `json:"fixable"`
+	Fix         *v2Fix         `json:"fix"`
+	Suppression *v2Suppression `json:"suppression"`
+}
+
+type v2Location struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Message string `json:"message"`
+}
+
+type v2Suggestion struct {
+	Title string   `json:"title"`
+	Edits []v2Edit `json:"edits"`
+}
+
+type v2Edit struct {
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Length  int    `json:"length"`
+	Replace string `json:"replace"`
+}
+
+type v2Fix struct {
+	Safety katas.FixSafety  `json:"safety"`
+	Edits  []v2ResolvedEdit `json:"edits"`
+}
+
+type v2ResolvedEdit struct {
+	Line        int             `json:"line"`
+	Column      int             `json:"column"`
+	EndLine     int             `json:"endLine"`
+	EndColumn   int             `json:"endColumn"`
+	StartOffset *int            `json:"startOffset,omitempty"`
+	EndOffset   *int            `json:"endOffset,omitempty"`
+	Length      int             `json:"length"`
+	Replace     string          `json:"replace"`
+	Safety      katas.FixSafety `json:"safety"`
+}
+
+type v2Suppression struct {
+	Kind          SuppressionKind `json:"kind"`
+	Justification string          `json:"justification,omitempty"`
+}
+
+type v2Summary struct {
+	Files                int                    `json:"files"`
+	FilesWithFindings    int                    `json:"filesWithFindings"`
+	FilesWithParseErrors int                    `json:"filesWithParseErrors"`
+	Findings             int                    `json:"findings"`
+	Suppressed           int                    `json:"suppressed"`
+	Fixable              int                    `json:"fixable"`
+	BySeverity           map[katas.Severity]int `json:"bySeverity"`
+}
+
+// json-v2 file statuses.
+const (
+	v2StatusClean      = "clean"
+	v2StatusFindings   = "findings"
+	v2StatusParseError = "parse-error"
+)
+
+// ReportJSONV2 writes every scanned file as one versioned json-v2
+// document: the tool and configuration that produced it, each file with
+// its status, parse errors and findings, and a summary. Findings carry
+// their kata's title and tags from meta, their range, suggestions and
+// auto-fix; suppressed findings are listed too, marked with what
+// suppressed them, and left out of every count but `suppressed`.
+func ReportJSONV2(w io.Writer, files []FileViolations, run RunInfo, meta func(string) RuleMeta) error {
+	doc := v2Doc{
+		Schema:  JSONV2SchemaURL,
+		Version: JSONV2Version,
+		Tool: v2Tool{
+			Name:           "zshellcheck",
+			Version:        run.ToolVersion,
+			InformationURI: "https://github.com/afadesigns/zshellcheck",
+		},
+		Config: v2Config{
+			DisabledKatas: nonNil(run.DisabledKatas),
+			Severities:    nonNil(run.Severities),
+			Baseline:      run.Baseline,
+			UnsafeFixes:   run.UnsafeFixes,
+		},
+		Files: []v2File{},
+		Summary: v2Summary{BySeverity: map[katas.Severity]int{
+			katas.SeverityError: 0, katas.SeverityWarning: 0, katas.SeverityInfo: 0, katas.SeverityStyle: 0,
+		}},
+	}
+	for _, f := range files {
+		file := v2File{Path: f.Filename, Status: v2StatusClean, ParseErrors: []v2ParseError{}, Findings: []v2Finding{}}
+		for _, e := range f.ParseErrors {
+			file.ParseErrors = append(file.ParseErrors, v2ParseError{Line: e.Line, Column: e.Column, Message: e.Message})
+		}
+		for _, v := range f.Violations {
+			file.Findings = append(file.Findings, v2FindingOf(f, v, nil, meta))
+			doc.Summary.BySeverity[v.Level]++
+			if fixable(v) {
+				doc.Summary.Fixable++
+			}
+		}
+		for _, s := range f.Suppressed {
+			file.Findings = append(file.Findings, v2FindingOf(f, s.Violation, &v2Suppression{Kind: s.Kind, Justification: s.Justification}, meta))
+		}
+		switch {
+		case len(f.ParseErrors) > 0:
+			file.Status = v2StatusParseError
+			doc.Summary.FilesWithParseErrors++
+		case len(f.Violations) > 0:
+			file.Status = v2StatusFindings
+			doc.Summary.FilesWithFindings++
+		}
+		doc.Summary.Files++
+		doc.Summary.Findings += len(f.Violations)
+		doc.Summary.Suppressed += len(f.Suppressed)
+		doc.Files = append(doc.Files, file)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+// v2FindingOf renders one finding of f, suppressed when sup is non-nil.
+func v2FindingOf(f FileViolations, v katas.Violation, sup *v2Suppression, meta func(string) RuleMeta) v2Finding {
+	finding := v2Finding{
+		KataID:      v.KataID,
+		Tags:        []string{},
+		Severity:    v.Level,
+		Message:     v.Message,
+		Line:        v.Line,
+		Column:      v.Column,
+		EndLine:     v.EndLine,
+		EndColumn:   v.EndColumn,
+		Related:     []v2Location{},
+		Suggestions: []v2Suggestion{},
+		Fixable:     fixable(v),
+		Suppression: sup,
+	}
+	if meta != nil {
+		m := meta(v.KataID)
+		finding.Title, finding.HelpURI = m.Title, m.HelpURI
+		finding.Tags = nonNil(m.Tags)
+	}
+	for _, rel := range v.Related {
+		finding.Related = append(finding.Related, v2Location{File: relatedFile(f.Filename, rel), Line: rel.Line, Column: rel.Column, Message: rel.Message})
+	}
+	for _, s := range v.Suggestions {
+		sug := v2Suggestion{Title: s.Title, Edits: []v2Edit{}}
+		for _, e := range s.Edits {
+			sug.Edits = append(sug.Edits, v2Edit{Line: e.Line, Column: e.Column, Length: e.Length, Replace: e.Replace})
+		}
+		finding.Suggestions = append(finding.Suggestions, sug)
+	}
+	if fix := jsonFixOf(f.Source, v.Fix); fix != nil {
+		finding.Fix = &v2Fix{Safety: fix.Safety}
+		for _, e := range fix.Edits {
+			finding.Fix.Edits = append(finding.Fix.Edits, v2ResolvedEdit(e))
+		}
+	}
+	return finding
+}
+
+// nonNil returns s, or an empty slice in place of nil so it encodes as
+// `[]` rather than `null`.
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
@@ -0,0 +1,168 @@
+// SPDX-License-Identifier: MIT
+// Copyright the ZShellCheck contributors.
+package reporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/afadesigns/zshellcheck/pkg/katas"
+)
+
+// v2Files covers every part of the json-v2 shape: a finding with a range,
+// related location, suggestion and auto-fix, suppressed findings of both
+// kinds, a clean file and one that failed to parse.
+func v2Files() []FileViolations {
+	files := rdFiles()
+	files[0].Violations[1].Related = []katas.Location{{Line: 1, Column: 1, Message: "first here"}}
+	files[0].Violations[1].Suggestions = []katas.Suggestion{{Title: "Use print", Edits: []katas.FixEdit{{Line: 2, Column: 1, Length: 5, Replace: "print"}}}}
+	files[0].Suppressed = []Suppressed{
+		{Violation: katas.Violation{KataID: "ZC1004", Message: "quiet", Line: 2, Column: 1, Level: katas.SeverityWarning}, Kind: SuppressedByNoka, Justification: "vendored"},
+		{Violation: katas.Violation{KataID: "ZC1005", Message: "known", Line: 1, Column: 1, Level: katas.SeverityInfo}, Kind: SuppressedByBaseline},
+	}
+	return append(files,
+		FileViolations{Filename: "clean.zsh"},
+		FileViolations{Filename: "broken.zsh", ParseErrors: []ParseError{{Line: 3, Column: 7, Message: "expected `fi`"}}},
+	)
+}
+
+// reportV2 renders files as json-v2 and checks the result against the
+// published schema.
+func reportV2(t *testing.T, files []FileViolations, run RunInfo) map[string]any {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := ReportJSONV2(&buf, files, run, testMeta); err != nil {
+		t.Fatal(err)
+	}
+	schema, err := decodeJSON(JSONV2Schema)
+	if err != nil {
+		t.Fatalf("schema is not JSON: %v", err)
+	}
+	doc, err := decodeJSON(buf.Bytes())
+	if err != nil {
+		t.Fatalf("report is not JSON: %v\n%s", err, buf.String())
+	}
+	if err := validateSchema(schema, doc); err != nil {
+		t.Fatalf("report does not match the schema: %v\n%s", err, buf.String())
+	}
+	return doc.(map[string]any)
+}
+
+func TestReportJSONV2_MatchesSchema(t *testing.T) {
+	run := RunInfo{
+		ToolVersion:   "1.2.3",
+		DisabledKatas: []string{"ZC1099"},
+		Severities:    []katas.Severity{katas.SeverityError, katas.SeverityStyle},
+		Baseline:      ".zshellcheck-baseline",
+	}
+	doc := reportV2(t, v2Files(), run)
+	if doc["version"] != JSONV2Version || doc["$schema"] != JSONV2SchemaURL {
+		t.Errorf("envelope = %v %v", doc["version"], doc["$schema"])
+	}
+	if tool := doc["tool"].(map[string]any); tool["version"] != "1.2.3" {
+		t.Errorf("tool = %v", tool)
+	}
+	if cfg := doc["config"].(map[string]any); cfg["baseline"] != ".zshellcheck-baseline" || len(cfg["disabledKatas"].([]any)) != 1 {
+		t.Errorf("config = %v", cfg)
+	}
+}
+
+func TestReportJSONV2_EmptyRunMatchesSchema(t *testing.T) {
+	doc := reportV2(t, nil, RunInfo{})
+	if files := doc["files"].([]any); len(files) != 0 {
+		t.Errorf("files = %v, want []", files)
+	}
+}
+
+func TestReportJSONV2_Files(t *testing.T) {
+	doc := reportV2(t, v2Files(), RunInfo{})
+	files := doc["files"].([]any)
+	statuses := []string{}
+	for _, f := range files {
+		statuses = append(statuses, f.(map[string]any)["status"].(string))
+	}
+	if got := strings.Join(statuses, ","); got != "findings,clean,parse-error" {
+		t.Errorf("statuses = %s", got)
+	}
+	perr := files[2].(map[string]any)["parseErrors"].([]any)[0].(map[string]any)
+	if perr["line"] != float64(3) || perr["column"] != float64(7) || perr["message"] != "expected `fi`" {
+		t.Errorf("parse error = %v", perr)
+	}
+}
+
+func TestReportJSONV2_Findings(t *testing.T) {
+	var buf bytes.Buffer
+	if err := ReportJSONV2(&buf, v2Files(), RunInfo{}, testMeta); err != nil {
+		t.Fatal(err)
+	}
+	var doc v2Doc
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	findings := doc.Files[0].Findings
+	if len(findings) != 4 {
+		t.Fatalf("want 2 findings and 2 suppressed, got %d", len(findings))
+	}
+	f := findings[0]
+	if f.Title != "ZC1002 title" || f.EndColumn != 9 || f.Fix == nil || f.Fix.Edits[0].Replace != "$(date)" || !f.Fixable || f.Suppression != nil {
+		t.Errorf("finding = %+v", f)
+	}
+	if f := findings[1]; f.Related[0].File != "a.zsh" || f.Suggestions[0].Title != "Use print" || f.Fix != nil {
+		t.Errorf("finding = %+v", f)
+	}
+	if s := findings[2].Suppression; s == nil || s.Kind != SuppressedByNoka || s.Justification != "vendored" {
+		t.Errorf("noka suppression = %+v", s)
+	}
+	if s := findings[3].Suppression; s == nil || s.Kind != SuppressedByBaseline {
+		t.Errorf("baseline suppression = %+v", s)
+	}
+	want := v2Summary{
+		Files: 3, FilesWithFindings: 1, FilesWithParseErrors: 1, Findings: 2, Suppressed: 2, Fixable: 1,
+		BySeverity: map[katas.Severity]int{katas.SeverityError: 1, katas.SeverityWarning: 0, katas.SeverityInfo: 0, katas.SeverityStyle: 1},
+	}
+	got, _ := json.Marshal(doc.Summary)
+	wantJSON, _ := json.Marshal(want)
+	if !bytes.Equal(got, wantJSON) {
+		t.Errorf("summary = %s, want %s", got, wantJSON)
+	}
+}
+
+func TestReportJSONV2_WriterError(t *testing.T) {
+	if err := ReportJSONV2(&failWriter{}, v2Files(), RunInfo{}, nil); err == nil {
+		t.Error("want an error from a failing writer")
+	}
+}
+
+func TestValidateSchema_Rejects(t *testing.T) {
+	schema, err := decodeJSON(JSONV2Schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := ReportJSONV2(&buf, v2Files(), RunInfo{}, testMeta); err != nil {
+		t.Fatal(err)
+	}
+	for name, mutate := range map[string]func(doc map[string]any){
+		"missing summary":  func(doc map[string]any) { delete(doc, "summary") },
+		"unknown property": func(doc map[string]any) { doc["extra"] = true },
+		"bad severity": func(doc map[string]any) {
+			finding := doc["files"].([]any)[0].(map[string]any)["findings"].([]any)[0].(map[string]any)
+			finding["severity"] = "fatal"
+		},
+		"bad status": func(doc map[string]any) { doc["files"].([]any)[1].(map[string]any)["status"] = "fine" },
+		"negative count": func(doc map[string]any) {
+			doc["summary"].(map[string]any)["findings"] = float64(-1)
+		},
+	} {
+		doc, err := decodeJSON(buf.Bytes())
+		if err != nil {
+			t.Fatal(err)
+		}
+		mutate(doc.(map[string]any))
+		if validateSchema(schema, doc) == nil {
+			t.Errorf("%s: validated, want a schema error", name)
+		}
+	}
+}
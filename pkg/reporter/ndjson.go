@@ -0,0 +1,80 @@
+// SPDX-License-Identifier: MIT
+// Copyright the ZShellCheck contributors.
+package reporter
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// NDJSON record types, carried in every record's Type field.
+const (
+	ndjsonTypeFinding    = "finding"
+	ndjsonTypeParseError = "parse_error"
+	ndjsonTypeFileDone   = "file"
+)
+
+// NDJSONStream writes findings as newline-delimited JSON, one record per
+// line, as each file completes. Unlike the aggregate formats it holds
+// nothing back, so a scan of a large tree produces output from its first
+// file and in constant memory. With events on, it also records each
+// parse error and the end of each file.
+type NDJSONStream struct {
+	enc    *json.Encoder
+	events bool
+}
+
+// ndjsonFinding is a finding record: the `-format json` array element
+// fields plus its Type.
+type ndjsonFinding struct {
+	Type string `json:"Type"`
+	jsonFinding
+}
+
+type ndjsonParseError struct {
+	Type    string `json:"Type"`
+	File    string `json:"File"`
+	Message string `json:"Message"`
+}
+
+// ndjsonFileDone closes a file's records, counting what it produced.
+type ndjsonFileDone struct {
+	Type        string `json:"Type"`
+	File        string `json:"File"`
+	Findings    int    `json:"Findings"`
+	ParseErrors int    `json:"ParseErrors"`
+}
+
+// NewNDJSONStream returns a stream writing to w. events adds the
+// parse-error and file-done records to the finding records.
+func NewNDJSONStream(w io.Writer, events bool) *NDJSONStream {
+	return &NDJSONStream{enc: json.NewEncoder(w), events: events}
+}
+
+// File writes a record per finding of f, followed, with events on, by
+// its file-done record.
+func (s *NDJSONStream) File(f FileViolations) error {
+	for _, v := range f.Violations {
+		if err := s.enc.Encode(ndjsonFinding{Type: ndjsonTypeFinding, jsonFinding: jsonFindingOf(f, v)}); err != nil {
+			return err
+		}
+	}
+	if !s.events {
+		return nil
+	}
+	return s.enc.Encode(ndjsonFileDone{Type: ndjsonTypeFileDone, File: f.Filename, Findings: len(f.Violations)})
+}
+
+// ParseErrors records a file that failed to parse: with events on, a
+// record per error and then its file-done record; otherwise nothing.
+func (s *NDJSONStream) ParseErrors(filename string, errs []string) error {
+	if !s.events {
+		return nil
+	}
+	for _, msg := range errs {
+		if err := s.enc.Encode(ndjsonParseError{Type: ndjsonTypeParseError, File: filename, Message: msg}); err != nil {
+			return err
+		}
+	}
+	return s.enc.Encode(ndjsonFileDone{Type: ndjsonTypeFileDone, File: filename, ParseErrors: len(errs)})
+}
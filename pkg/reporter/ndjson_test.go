@@ -0,0 +1,106 @@
+// SPDX-License-Identifier: MIT
+// Copyright the ZShellCheck contributors.
+package reporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// ndjsonLines decodes every line of an NDJSON stream.
+func ndjsonLines(t *testing.T, out string) []map[string]any {
+	t.Helper()
+	var recs []map[string]any
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\n"), "\n") {
+		var rec map[string]any
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("line is not a JSON object: %q: %v", line, err)
+		}
+		recs = append(recs, rec)
+	}
+	return recs
+}
+
+func TestNDJSONStream_Findings(t *testing.T) {
+	var buf bytes.Buffer
+	s := NewNDJSONStream(&buf, false)
+	for _, f := range twoFiles() {
+		if err := s.File(f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.ParseErrors("c.zsh", []string{"unexpected token"}); err != nil {
+		t.Fatal(err)
+	}
+	recs := ndjsonLines(t, buf.String())
+	if len(recs) != 3 {
+		t.Fatalf("want one record per finding, got %d:\n%s", len(recs), buf.String())
+	}
+	for _, rec := range recs {
+		if rec["Type"] != "finding" {
+			t.Errorf("record type = %v, want finding", rec["Type"])
+		}
+	}
+	if recs[0]["File"] != "a.zsh" || recs[0]["KataID"] != "ZC1001" || recs[2]["Line"] != float64(7) {
+		t.Errorf("records do not carry the JSON finding fields: %v", recs)
+	}
+}
+
+func TestNDJSONStream_Fix(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewNDJSONStream(&buf, false).File(rdFiles()[0]); err != nil {
+		t.Fatal(err)
+	}
+	rec := ndjsonLines(t, buf.String())[0]
+	fix, ok := rec["Fix"].(map[string]any)
+	if rec["Fixable"] != true || !ok || fix["Safety"] != "safe" {
+		t.Errorf("record = %v, want the auto-fix", rec)
+	}
+}
+
+func TestNDJSONStream_Events(t *testing.T) {
+	var buf bytes.Buffer
+	s := NewNDJSONStream(&buf, true)
+	if err := s.File(FileViolations{Filename: "clean.zsh"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.File(twoFiles()[1]); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.ParseErrors("bad.zsh", []string{"first", "second"}); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		`{"Type":"file","File":"clean.zsh","Findings":0,"ParseErrors":0}`,
+		`"Type":"finding"`,
+		`"Type":"finding"`,
+		`{"Type":"file","File":"b.zsh","Findings":2,"ParseErrors":0}`,
+		`{"Type":"parse_error","File":"bad.zsh","Message":"first"}`,
+		`{"Type":"parse_error","File":"bad.zsh","Message":"second"}`,
+		`{"Type":"file","File":"bad.zsh","Findings":0,"ParseErrors":2}`,
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != len(want) {
+		t.Fatalf("got %d records, want %d:\n%s", len(lines), len(want), buf.String())
+	}
+	for i, w := range want {
+		if !strings.Contains(lines[i], w) {
+			t.Errorf("record %d = %s, want %s", i, lines[i], w)
+		}
+	}
+}
+
+func TestNDJSONStream_WriterError(t *testing.T) {
+	s := NewNDJSONStream(&failWriter{}, true)
+	if err := s.File(twoFiles()[0]); err == nil {
+		t.Error("want an error from a failing writer")
+	}
+	if err := s.File(FileViolations{Filename: "clean.zsh"}); err == nil {
+		t.Error("want an error writing the file record")
+	}
+	if err := s.ParseErrors("bad.zsh", []string{"x"}); err == nil {
+		t.Error("want an error writing the parse error record")
+	}
+}
@@ -0,0 +1,132 @@
+// SPDX-License-Identifier: MIT
+// Copyright the ZShellCheck contributors.
+package reporter
+
+import (
+	"encoding/json"
+	"io"
+	"path/filepath"
+
+	"github.com/afadesigns/zshellcheck/pkg/katas"
+)
+
+// Reviewdog Diagnostic Format shapes, as read by `reviewdog -f=rdjson`
+// and `-f=rdjsonl`. Positions are 1-based; a range's end is exclusive.
+type rdResult struct {
+	Source      rdSource       `json:"source"`
+	Diagnostics []rdDiagnostic `json:"diagnostics"`
+}
+
+type rdSource struct {
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+type rdDiagnostic struct {
+	Message     string         `json:"message"`
+	Location    rdLocation     `json:"location"`
+	Severity    string         `json:"severity"`
+	Source      *rdSource      `json:"source,omitempty"`
+	Code        rdCode         `json:"code"`
+	Suggestions []rdSuggestion `json:"suggestions,omitempty"`
+}
+
+type rdLocation struct {
+	Path  string  `json:"path"`
+	Range rdRange `json:"range"`
+}
+
+type rdRange struct {
+	Start rdPosition  `json:"start"`
+	End   *rdPosition `json:"end,omitempty"`
+}
+
+type rdPosition struct {
+	Line   int `json:"line"`
+	Column int `json:"column,omitempty"`
+}
+
+type rdCode struct {
+	Value string `json:"value"`
+	URL   string `json:"url,omitempty"`
+}
+
+type rdSuggestion struct {
+	Range rdRange `json:"range"`
+	Text  string  `json:"text"`
+}
+
+var rdToolSource = rdSource{Name: "zshellcheck", URL: "https://github.com/afadesigns/zshellcheck"}
+
+// ReportRDJSON writes every finding as one reviewdog rdjson document.
+// Each diagnostic carries its range, severity, and kata ID with the help
+// URL meta supplies; a finding with an auto-fix also carries one
+// suggestion per edit, which reviewdog posts as a suggested change.
+func ReportRDJSON(w io.Writer, files []FileViolations, meta func(string) RuleMeta) error {
+	doc := rdResult{Source: rdToolSource, Diagnostics: []rdDiagnostic{}}
+	for _, f := range files {
+		for _, v := range f.Violations {
+			doc.Diagnostics = append(doc.Diagnostics, rdDiagnosticOf(f, v, meta))
+		}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+// ReportRDJSONL writes every finding as a reviewdog rdjsonl diagnostic,
+// one JSON object per line, each naming zshellcheck as its source.
+func ReportRDJSONL(w io.Writer, files []FileViolations, meta func(string) RuleMeta) error {
+	enc := json.NewEncoder(w)
+	source := rdToolSource
+	for _, f := range files {
+		for _, v := range f.Violations {
+			d := rdDiagnosticOf(f, v, meta)
+			d.Source = &source
+			if err := enc.Encode(d); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// rdDiagnosticOf maps one finding onto a reviewdog diagnostic.
+func rdDiagnosticOf(f FileViolations, v katas.Violation, meta func(string) RuleMeta) rdDiagnostic {
+	region := buildSarifRegion(v)
+	r := rdRange{Start: rdPosition{Line: region.StartLine, Column: region.StartColumn}}
+	if region.EndLine != 0 {
+		r.End = &rdPosition{Line: region.EndLine, Column: region.EndColumn}
+	}
+	d := rdDiagnostic{
+		Message:  v.Message,
+		Location: rdLocation{Path: filepath.ToSlash(f.Filename), Range: r},
+		Severity: rdSeverity(v.Level),
+		Code:     rdCode{Value: v.KataID},
+	}
+	if meta != nil {
+		d.Code.URL = meta(v.KataID).HelpURI
+	}
+	for _, e := range v.Fix {
+		start := rdPosition{Line: atLeastOne(e.Line), Column: atLeastOne(e.Column)}
+		endLine, endCol := editEnd(f.Source, e)
+		d.Suggestions = append(d.Suggestions, rdSuggestion{
+			Range: rdRange{Start: start, End: &rdPosition{Line: endLine, Column: endCol}},
+			Text:  e.Replace,
+		})
+	}
+	return d
+}
+
+// rdSeverity maps a ZShellCheck severity onto reviewdog's scale; style
+// findings are informational.
+func rdSeverity(s katas.Severity) string {
+	switch s {
+	case katas.SeverityError:
+		return "ERROR"
+	case katas.SeverityWarning:
+		return "WARNING"
+	default:
+		return "INFO"
+	}
+}
@@ -0,0 +1,81 @@
+// SPDX-License-Identifier: MIT
+// Copyright the ZShellCheck contributors.
+package reporter
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/afadesigns/zshellcheck/pkg/katas"
+)
+
+func rdFiles() []FileViolations {
+	return []FileViolations{{Filename: "a.zsh", Source: []byte("x=`date`\nwhich git\n"), Violations: []katas.Violation{
+		{
+			KataID: "ZC1002", Message: "use $()", Line: 1, Column: 3, EndLine: 1, EndColumn: 9, Level: katas.SeverityStyle,
+			Fix: []katas.FixEdit{{Line: 1, Column: 3, Length: 6, Replace: "$(date)", Safety: katas.FixSafe}},
+		},
+		{KataID: "ZC1003", Message: "m", Line: 2, Column: 1, Level: katas.SeverityError},
+	}}}
+}
+
+func TestReportRDJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := ReportRDJSON(&buf, rdFiles(), testMeta); err != nil {
+		t.Fatal(err)
+	}
+	var doc rdResult
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if doc.Source.Name != "zshellcheck" || len(doc.Diagnostics) != 2 {
+		t.Fatalf("document = %+v", doc)
+	}
+	want := rdDiagnostic{
+		Message: "use $()",
+		Location: rdLocation{Path: "a.zsh", Range: rdRange{
+			Start: rdPosition{Line: 1, Column: 3}, End: &rdPosition{Line: 1, Column: 9},
+		}},
+		Severity: "INFO",
+		Code:     rdCode{Value: "ZC1002", URL: "https://example.test/ZC1002"},
+		Suggestions: []rdSuggestion{{
+			Range: rdRange{Start: rdPosition{Line: 1, Column: 3}, End: &rdPosition{Line: 1, Column: 9}},
+			Text:  "$(date)",
+		}},
+	}
+	if got := doc.Diagnostics[0]; !reflect.DeepEqual(got, want) {
+		t.Errorf("diagnostic = %+v, want %+v", got, want)
+	}
+	// A point finding has no end, and a finding without a fix no suggestions.
+	if got := doc.Diagnostics[1]; got.Severity != "ERROR" || got.Location.Range.End != nil || got.Suggestions != nil {
+		t.Errorf("diagnostic = %+v", got)
+	}
+}
+
+func TestReportRDJSONL(t *testing.T) {
+	var buf bytes.Buffer
+	if err := ReportRDJSONL(&buf, rdFiles(), nil); err != nil {
+		t.Fatal(err)
+	}
+	var lines int
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var d rdDiagnostic
+		if err := json.Unmarshal(scanner.Bytes(), &d); err != nil {
+			t.Fatalf("line %d is not a diagnostic: %v", lines+1, err)
+		}
+		if d.Source == nil || d.Source.Name != "zshellcheck" || d.Code.URL != "" {
+			t.Errorf("line %d = %+v", lines+1, d)
+		}
+		lines++
+	}
+	if lines != 2 {
+		t.Errorf("got %d lines, want 2", lines)
+	}
+	if err := ReportRDJSONL(&failWriter{}, rdFiles(), nil); err == nil {
+		t.Error("expected error from failing writer")
+	}
+}
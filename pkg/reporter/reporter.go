@@ -22,17 +22,38 @@ type TextReporter struct {
 	filename string
 	lines    []string
 	config   config.Config
-	// fixable, when set, reports whether a kata ID ships an auto-fix; a
-	// fixable finding is tagged with a trailing ` [*]` marker.
-	fixable func(string) bool
+	theme    theme
+	// fixable, when set, reports whether a finding's auto-fix would be
+	// applied; such a finding is tagged with a trailing ` [*]` marker.
+	fixable func(katas.Violation) bool
+	// describe, when set, supplies the kata title, description and help
+	// link printed under each finding in verbose mode.
+	describe func(string) RuleMeta
 }
 
-// MarkFixable sets the predicate used to tag findings whose kata ships an
-// auto-fix with a ` [*]` marker. Passing nil disables the marker.
-func (r *TextReporter) MarkFixable(fn func(string) bool) {
+// theme holds the resolved ANSI sequences the text report is drawn with;
+// every field is empty when colour is off.
+type theme struct {
+	error, warning, info, style string
+	id, title, message          string
+	line, column                string
+	bold, reset                 string
+}
+
+// MarkFixable sets the predicate used to tag findings whose auto-fix the
+// current command would apply with a ` [*]` marker. Passing nil disables
+// the marker.
+func (r *TextReporter) MarkFixable(fn func(katas.Violation) bool) {
 	r.fixable = fn
 }
 
+// DescribeWith sets the lookup used in verbose mode to print each
+// finding's kata title, description and where to read more. Passing nil
+// leaves verbose output with the explain hint only.
+func (r *TextReporter) DescribeWith(meta func(string) RuleMeta) {
+	r.describe = meta
+}
+
 // NewTextReporter creates a new TextReporter.
 func NewTextReporter(writer io.Writer, filename, source string, config config.Config) *TextReporter {
 	return &TextReporter{
@@ -40,76 +61,244 @@ func NewTextReporter(writer io.Writer, filename, source string, config config.Co
 		filename: filename,
 		lines:    strings.Split(source, "\n"),
 		config:   config,
+		theme:    newTheme(config),
 	}
 }
 
-func (r *TextReporter) getColor(code string) string {
-	if r.config.NoColor {
-		return ""
+// newTheme resolves cfg's colour settings. A setting that is unset or
+// fails to parse falls back to the default palette's colour for it.
+func newTheme(cfg config.Config) theme {
+	if cfg.NoColor {
+		return theme{}
+	}
+	def := config.DefaultConfig()
+	color := func(spec, fallback string) string {
+		if code, err := config.ColorCode(spec); err == nil && code != "" {
+			return code
+		}
+		return fallback
+	}
+	return theme{
+		error:   color(cfg.ErrorColor, def.ErrorColor),
+		warning: color(cfg.WarningColor, def.WarningColor),
+		info:    color(cfg.InfoColor, def.InfoColor),
+		style:   color(cfg.StyleColor, def.StyleColor),
+		id:      color(cfg.IDColor, def.IDColor),
+		title:   color(cfg.TitleColor, def.TitleColor),
+		message: color(cfg.MessageColor, def.MessageColor),
+		line:    color(cfg.LineColor, def.LineColor),
+		column:  color(cfg.ColumnColor, def.ColumnColor),
+		bold:    config.ColorBold,
+		reset:   config.ColorReset,
 	}
-	return code
 }
 
-// Report prints the violations to the writer.
-// levelColor returns the ANSI colour for a severity, honouring NoColor.
-func (r *TextReporter) levelColor(level katas.Severity) string {
+// levelColor returns the theme colour for a severity.
+func (t theme) levelColor(level katas.Severity) string {
 	switch level {
 	case katas.SeverityError:
-		return r.getColor("\033[31m") // Red
+		return t.error
 	case katas.SeverityWarning:
-		return r.getColor("\033[33m") // Yellow
+		return t.warning
 	case katas.SeverityInfo:
-		return r.getColor("\033[34m") // Blue
+		return t.info
 	case katas.SeverityStyle:
-		return r.getColor("\033[36m") // Cyan
+		return t.style
 	}
 	return ""
 }
 
+// Report prints the violations to the writer: a headline per finding,
+// followed, unless the compact style is configured, by the flagged
+// source line, related notes, suggestions and, in verbose mode, the
+// kata's description.
 func (r *TextReporter) Report(violations []katas.Violation) error {
 	for _, v := range violations {
-		color := r.levelColor(v.Level)
-		reset := r.getColor("\033[0m")
-		bold := r.getColor("\033[1m")
-
-		// Location: filename:line:col
-		if _, err := fmt.Fprintf(r.writer, "%s:%d:%d: ", r.filename, v.Line, v.Column); err != nil {
+		if err := r.headline(v); err != nil {
 			return err
 		}
+		if r.config.Compact {
+			continue
+		}
+		if err := r.details(v); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintln(r.writer); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-		// Severity, ID, Message, and a ` [*]` marker when the kata is
-		// auto-fixable. Example: warning: [ZC1001] Some message [*]
-		mark := ""
-		if r.fixable != nil && r.fixable(v.KataID) {
-			mark = " [*]"
+// headline prints a finding's one-line summary, with a ` [*]` marker when
+// its fix would be applied. Example:
+// script.zsh:3:5: warning: [ZC1001] Some message [*]
+func (r *TextReporter) headline(v katas.Violation) error {
+	t := r.theme
+	mark := ""
+	if r.fixable != nil && r.fixable(v) {
+		mark = " [*]"
+	}
+	_, err := fmt.Fprintf(r.writer, "%s:%s%d%s:%s%d%s: %s%s%s: [%s%s%s] %s%s%s%s\n",
+		r.filename, t.line, v.Line, t.reset, t.column, v.Column, t.reset,
+		t.levelColor(v.Level), v.Level, t.reset,
+		t.id, v.KataID, t.reset,
+		t.message, v.Message, t.reset, mark)
+	return err
+}
+
+// details prints what follows a finding's headline in the full style.
+func (r *TextReporter) details(v katas.Violation) error {
+	t := r.theme
+	if err := r.snippet(v); err != nil {
+		return err
+	}
+	for _, rel := range v.Related {
+		file := rel.File
+		if file == "" {
+			file = r.filename
+		}
+		if _, err := fmt.Fprintf(r.writer, "  %snote%s: %s:%d:%d: %s\n", t.bold, t.reset, file, rel.Line, rel.Column, rel.Message); err != nil {
+			return err
 		}
-		if _, err := fmt.Fprintf(r.writer, "%s%s%s: [%s] %s%s\n", color, v.Level, reset, v.KataID, v.Message, mark); err != nil {
+	}
+	for i, s := range v.Suggestions {
+		if _, err := fmt.Fprintf(r.writer, "  %ssuggestion %d%s: %s\n", t.bold, i+1, t.reset, s.Title); err != nil {
 			return err
 		}
+	}
+	if r.config.Verbose {
+		return r.description(v)
+	}
+	return nil
+}
 
-		// Code snippet
-		if v.Line > 0 && v.Line <= len(r.lines) {
-			lineContent := r.lines[v.Line-1]
-			// Replace tabs with spaces for correct alignment of caret (simple approach)
-			// Or keep it simple for now.
-			if _, err := fmt.Fprintf(r.writer, "  %s\n", lineContent); err != nil {
+// description prints a finding's kata title and description, and how to
+// read more about it.
+func (r *TextReporter) description(v katas.Violation) error {
+	t := r.theme
+	var meta RuleMeta
+	if r.describe != nil {
+		meta = r.describe(v.KataID)
+	}
+	if meta.Title != "" {
+		if _, err := fmt.Fprintf(r.writer, "  %s%s: %s%s\n", t.title, v.KataID, meta.Title, t.reset); err != nil {
+			return err
+		}
+	}
+	if desc := strings.TrimSpace(meta.Description); desc != "" {
+		for _, line := range strings.Split(desc, "\n") {
+			if _, err := fmt.Fprintf(r.writer, "    %s\n", line); err != nil {
 				return err
 			}
+		}
+	}
+	more := "zshellcheck -explain " + v.KataID
+	if meta.HelpURI != "" {
+		more += ", " + meta.HelpURI
+	}
+	_, err := fmt.Fprintf(r.writer, "  %smore%s: %s\n", t.bold, t.reset, more)
+	return err
+}
 
-			// Caret
-			padding := v.Column - 1
-			if padding < 0 {
-				padding = 0
-			}
-			// Use a simple space padding. Note: this might be slightly off if tabs are present,
-			// but it's a standard starting point.
-			if _, err := fmt.Fprintf(r.writer, "  %s%s↑%s\n", strings.Repeat(" ", padding), bold, reset); err != nil {
-				return err
+// maxRangeLines is the most lines of a multi-line range the snippet
+// shows in full; a longer range shows its first and last two lines with
+// an elision between.
+const maxRangeLines = 5
+
+// snippet prints the source around a finding under a line-number gutter,
+// with the configured lines of context either side. Each line of the
+// flagged range is underlined with `^^^^`, or a point finding marked with
+// a single `↑`; tabs are expanded and wide and combining characters
+// measured, so the markers sit under the right characters.
+func (r *TextReporter) snippet(v katas.Violation) error {
+	if v.Line < 1 || v.Line > len(r.lines) {
+		return nil
+	}
+	t := r.theme
+	first, last := v.Line, v.Line
+	if v.EndLine > v.Line {
+		last = v.EndLine
+		if v.EndColumn <= 1 {
+			// The range stops at the start of EndLine, so its last
+			// character is the newline ending the line before.
+			last--
+		}
+	}
+	available := len(r.lines)
+	if available > 1 && r.lines[available-1] == "" {
+		// The empty string after a trailing newline is not a line.
+		available--
+	}
+	last = min(last, len(r.lines))
+	context := max(r.config.Context, 0)
+	lo, hi := max(first-context, 1), max(min(last+context, available), last)
+	elide := last-first+1 > maxRangeLines
+	width := len(fmt.Sprint(hi))
+	gap := false
+	for n := lo; n <= hi; n++ {
+		if elide && n > first+1 && n < last-1 {
+			if !gap {
+				if _, err := fmt.Fprintf(r.writer, "  %s%*s%s |\n", t.line, width, "…", t.reset); err != nil {
+					return err
+				}
+				gap = true
 			}
+			continue
 		}
-		if _, err := fmt.Fprintln(r.writer); err != nil {
+		line := r.lines[n-1]
+		if _, err := fmt.Fprintf(r.writer, "  %s%*d%s | %s\n", t.line, width, n, t.reset, expandTabs(line, r.tabWidth())); err != nil {
+			return err
+		}
+		if n < first || n > last {
+			continue
+		}
+		pad, mark := r.marker(v, n, line)
+		if mark == "" {
+			continue
+		}
+		if _, err := fmt.Fprintf(r.writer, "  %*s | %s%s%s%s\n", width, "", strings.Repeat(" ", pad), t.bold, mark, t.reset); err != nil {
 			return err
 		}
 	}
 	return nil
 }
+
+// tabWidth returns the configured tab stop interval.
+func (r *TextReporter) tabWidth() int {
+	if r.config.TabWidth > 0 {
+		return r.config.TabWidth
+	}
+	return DefaultTabWidth
+}
+
+// marker returns the underline drawn beneath line n of a finding's range
+// and the display column it starts at: one `^` per cell of the range on
+// that line, a range continuing onto later lines running to the end of
+// the line and one carried over from earlier lines starting at its first
+// non-blank character. A finding with no range gets a `↑` at its column.
+// The mark is empty when line n holds none of the range.
+func (r *TextReporter) marker(v katas.Violation, n int, line string) (int, string) {
+	start, end := v.Column, 0
+	switch {
+	case n > v.Line:
+		start = len(line) - len(strings.TrimLeft(line, " \t")) + 1
+		end = len(line) + 1
+		if n == v.EndLine {
+			end = v.EndColumn
+		}
+		if end <= start {
+			return 0, ""
+		}
+	case v.EndLine == v.Line && v.EndColumn > v.Column:
+		end = v.EndColumn
+	case v.EndLine > v.Line:
+		end = len(line) + 1
+	}
+	tab := r.tabWidth()
+	from := displayColumn(line, start, tab)
+	if end <= start {
+		return from, "↑"
+	}
+	return from, strings.Repeat("^", max(displayColumn(line, end, tab)-from, 1))
+}
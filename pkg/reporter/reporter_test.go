@@ -5,6 +5,7 @@ package reporter
 import (
 	"bytes"
 	"fmt"
+	"regexp"
 	"strings"
 	"testing"
 
@@ -54,22 +55,22 @@ func TestTextReporter_Report(t *testing.T) {
 
 	// Check for code snippet and column pointer
 	// Format:
-	//   first line
-	//   ↑
-	expectedSnippet := "  first line\n"
-	if !bytes.Contains(buf.Bytes(), []byte(expectedSnippet)) {
+	//   1 | first line
+	//     | ↑
+	expectedSnippet := "1 | first line\n"
+	if !strings.Contains(stripANSI(buf.String()), expectedSnippet) {
 		t.Errorf("Report() output missing source code snippet.\nWant: %q\nGot:\n%q", expectedSnippet, buf.String())
 	}
 
 	// Check for column pointer with color (U+2191 upward arrow).
-	expectedCaret := "  " + config.ColorBold + "↑" + config.ColorReset
+	expectedCaret := "    | " + config.ColorBold + "↑" + config.ColorReset
 	if !bytes.Contains(buf.Bytes(), []byte(expectedCaret)) {
 		t.Errorf("Report() output missing caret.\nWant: %q\nGot:\n%q", expectedCaret, buf.String())
 	}
 
 	// Check for location
 	expectedLocation := "test.zsh:1:1:"
-	if !strings.Contains(buf.String(), expectedLocation) {
+	if !strings.Contains(stripANSI(buf.String()), expectedLocation) {
 		t.Errorf("Report() output missing location.\nWant: %q\nGot:\n%q", expectedLocation, buf.String())
 	}
 }
@@ -195,7 +196,7 @@ func TestTextReporter_MultiLineSource(t *testing.T) {
 	if !strings.Contains(output, "line two") {
 		t.Error("expected second line snippet in output")
 	}
-	if !strings.Contains(output, "script.zsh:2:3:") {
+	if !strings.Contains(stripANSI(output), "script.zsh:2:3:") {
 		t.Error("expected correct location in output")
 	}
 }
@@ -209,7 +210,7 @@ func TestTextReporter_FixableMark(t *testing.T) {
 	cfg := config.DefaultConfig()
 	cfg.NoColor = true
 	r := NewTextReporter(&buf, "test.zsh", "echo hello", cfg)
-	r.MarkFixable(func(id string) bool { return id == "ZC0001" })
+	r.MarkFixable(func(v katas.Violation) bool { return v.KataID == "ZC0001" })
 	if err := r.Report(violations); err != nil {
 		t.Fatalf("Report() error: %v", err)
 	}
@@ -222,6 +223,104 @@ func TestTextReporter_FixableMark(t *testing.T) {
 	}
 }
 
+// ansiEscape matches the SGR sequences the text reporter colours with.
+var ansiEscape = regexp.MustCompile("\033\\[[0-9;]*m")
+
+// stripANSI removes colour from reporter output.
+func stripANSI(s string) string {
+	return ansiEscape.ReplaceAllString(s, "")
+}
+
+func TestTextReporter_Palette(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.WarningColor = "#ff8800"
+	cfg.IDColor = "bold green"
+	cfg.LineColor = "208"
+	var buf bytes.Buffer
+	r := NewTextReporter(&buf, "test.zsh", "echo hello", cfg)
+	if err := r.Report([]katas.Violation{{KataID: "ZC0001", Message: "m", Level: katas.SeverityWarning, Line: 1, Column: 1}}); err != nil {
+		t.Fatalf("Report() error: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"\033[38;2;255;136;0mwarning",
+		"[\033[1;32mZC0001",
+		":\033[38;5;208m1",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%q", want, out)
+		}
+	}
+}
+
+func TestTextReporter_PaletteFallsBackOnBadColor(t *testing.T) {
+	var buf bytes.Buffer
+	cfg := config.DefaultConfig()
+	cfg.ErrorColor = "not-a-colour"
+	r := NewTextReporter(&buf, "test.zsh", "echo hello", cfg)
+	if err := r.Report([]katas.Violation{{KataID: "ZC0001", Message: "m", Level: katas.SeverityError, Line: 1, Column: 1}}); err != nil {
+		t.Fatalf("Report() error: %v", err)
+	}
+	if !strings.Contains(buf.String(), config.ColorRed+"error") {
+		t.Errorf("want default red for an unparsable error_color:\n%q", buf.String())
+	}
+}
+
+func TestTextReporter_Verbose(t *testing.T) {
+	var buf bytes.Buffer
+	cfg := config.DefaultConfig()
+	cfg.NoColor = true
+	cfg.Verbose = true
+	r := NewTextReporter(&buf, "test.zsh", "echo hello", cfg)
+	r.DescribeWith(func(id string) RuleMeta {
+		return RuleMeta{Title: "Prefer print", Description: "First line.\nSecond line.", HelpURI: "https://example.com/katas"}
+	})
+	if err := r.Report([]katas.Violation{{KataID: "ZC0001", Message: "m", Level: katas.SeverityStyle, Line: 1, Column: 1}}); err != nil {
+		t.Fatalf("Report() error: %v", err)
+	}
+	want := "  ZC0001: Prefer print\n    First line.\n    Second line.\n" +
+		"  more: zshellcheck -explain ZC0001, https://example.com/katas\n"
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("verbose output missing description.\nWant: %q\nGot:  %q", want, buf.String())
+	}
+}
+
+func TestTextReporter_NotVerbose(t *testing.T) {
+	var buf bytes.Buffer
+	cfg := config.DefaultConfig()
+	cfg.NoColor = true
+	r := NewTextReporter(&buf, "test.zsh", "echo hello", cfg)
+	r.DescribeWith(func(id string) RuleMeta { return RuleMeta{Title: "Prefer print"} })
+	if err := r.Report([]katas.Violation{{KataID: "ZC0001", Message: "m", Level: katas.SeverityStyle, Line: 1, Column: 1}}); err != nil {
+		t.Fatalf("Report() error: %v", err)
+	}
+	if strings.Contains(buf.String(), "Prefer print") || strings.Contains(buf.String(), "-explain") {
+		t.Errorf("description printed without verbose:\n%s", buf.String())
+	}
+}
+
+func TestTextReporter_Compact(t *testing.T) {
+	var buf bytes.Buffer
+	cfg := config.DefaultConfig()
+	cfg.NoColor = true
+	cfg.Compact = true
+	cfg.Verbose = true
+	r := NewTextReporter(&buf, "test.zsh", "echo hello\necho again", cfg)
+	violations := []katas.Violation{
+		{KataID: "ZC0001", Message: "first", Level: katas.SeverityWarning, Line: 1, Column: 1,
+			Suggestions: []katas.Suggestion{{Title: "do this"}}},
+		{KataID: "ZC0002", Message: "second", Level: katas.SeverityError, Line: 2, Column: 6},
+	}
+	if err := r.Report(violations); err != nil {
+		t.Fatalf("Report() error: %v", err)
+	}
+	want := "test.zsh:1:1: warning: [ZC0001] first\n" +
+		"test.zsh:2:6: error: [ZC0002] second\n"
+	if buf.String() != want {
+		t.Errorf("compact output.\nWant: %q\nGot:  %q", want, buf.String())
+	}
+}
+
 type failWriter struct{}
 
 func (w *failWriter) Write(p []byte) (n int, err error) {
@@ -240,3 +339,151 @@ func TestTextReporter_WriterError(t *testing.T) {
 		t.Error("expected error from failing writer")
 	}
 }
+
+func TestTextReporter_RangeUnderline(t *testing.T) {
+	cases := []struct {
+		name string
+		v    katas.Violation
+		want string
+	}{
+		{"single-line range", katas.Violation{Line: 1, Column: 4, EndLine: 1, EndColumn: 7}, "    |    ^^^\n"},
+		{"range continuing onto later lines", katas.Violation{Line: 1, Column: 4, EndLine: 3, EndColumn: 2},
+			"    |    ^^^^^^\n  2 | b\n    | ^\n  3 | c\n    | ^\n"},
+		{"range ending at a line start", katas.Violation{Line: 1, Column: 4, EndLine: 2, EndColumn: 1},
+			"    |    ^^^^^^\n\n"},
+		{"point finding", katas.Violation{Line: 1, Column: 4}, "    |    ↑\n"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			cfg := config.DefaultConfig()
+			cfg.NoColor = true
+			tc.v.KataID, tc.v.Level = "ZC0001", katas.SeverityWarning
+			r := NewTextReporter(&buf, "a.zsh", "rm -rf $x\nb\nc", cfg)
+			if err := r.Report([]katas.Violation{tc.v}); err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(buf.String(), "  1 | rm -rf $x\n"+tc.want) {
+				t.Errorf("missing marker %q:\n%s", tc.want, buf.String())
+			}
+		})
+	}
+}
+
+func TestTextReporter_CaretAlignment(t *testing.T) {
+	cases := []struct {
+		name, source string
+		v            katas.Violation
+		want         string
+	}{
+		{"tab", "\techo $x", katas.Violation{Line: 1, Column: 7, EndLine: 1, EndColumn: 9},
+			"  1 |         echo $x\n    |              ^^\n"},
+		{"wide runes", "print 日本 $x", katas.Violation{Line: 1, Column: 14, EndLine: 1, EndColumn: 16},
+			"  1 | print 日本 $x\n    |            ^^\n"},
+		{"wide range", "print 日本", katas.Violation{Line: 1, Column: 7, EndLine: 1, EndColumn: 13},
+			"  1 | print 日本\n    |       ^^^^\n"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			cfg := config.DefaultConfig()
+			cfg.NoColor = true
+			tc.v.KataID, tc.v.Level = "ZC0001", katas.SeverityWarning
+			r := NewTextReporter(&buf, "a.zsh", tc.source, cfg)
+			if err := r.Report([]katas.Violation{tc.v}); err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(buf.String(), tc.want) {
+				t.Errorf("want %q in:\n%s", tc.want, buf.String())
+			}
+		})
+	}
+}
+
+func TestTextReporter_TabWidth(t *testing.T) {
+	var buf bytes.Buffer
+	cfg := config.DefaultConfig()
+	cfg.NoColor = true
+	cfg.TabWidth = 2
+	r := NewTextReporter(&buf, "a.zsh", "\t\tx=1", cfg)
+	if err := r.Report([]katas.Violation{{KataID: "ZC0001", Level: katas.SeverityStyle, Line: 1, Column: 3}}); err != nil {
+		t.Fatal(err)
+	}
+	if want := "  1 |     x=1\n    |     ↑\n"; !strings.Contains(buf.String(), want) {
+		t.Errorf("want %q in:\n%s", want, buf.String())
+	}
+}
+
+func TestTextReporter_Context(t *testing.T) {
+	var buf bytes.Buffer
+	cfg := config.DefaultConfig()
+	cfg.NoColor = true
+	cfg.Context = 2
+	source := "one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\n"
+	r := NewTextReporter(&buf, "a.zsh", source, cfg)
+	if err := r.Report([]katas.Violation{{KataID: "ZC0001", Level: katas.SeverityStyle, Line: 9, Column: 1, EndLine: 9, EndColumn: 5}}); err != nil {
+		t.Fatal(err)
+	}
+	want := "   7 | seven\n   8 | eight\n   9 | nine\n     | ^^^^\n  10 | ten\n\n"
+	if !strings.HasSuffix(buf.String(), want) {
+		t.Errorf("context output.\nWant suffix: %q\nGot: %q", want, buf.String())
+	}
+}
+
+func TestTextReporter_LongRangeElided(t *testing.T) {
+	var buf bytes.Buffer
+	cfg := config.DefaultConfig()
+	cfg.NoColor = true
+	source := "f() {\n  a\n  b\n  c\n  d\n  e\n}"
+	r := NewTextReporter(&buf, "a.zsh", source, cfg)
+	if err := r.Report([]katas.Violation{{KataID: "ZC0001", Level: katas.SeverityStyle, Line: 1, Column: 1, EndLine: 7, EndColumn: 2}}); err != nil {
+		t.Fatal(err)
+	}
+	want := "  1 | f() {\n    | ^^^^^\n  2 |   a\n    |   ^\n  … |\n" +
+		"  6 |   e\n    |   ^\n  7 | }\n    | ^\n"
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("elided range.\nWant: %q\nGot:  %q", want, buf.String())
+	}
+}
+
+func TestTextReporter_RelatedNotes(t *testing.T) {
+	var buf bytes.Buffer
+	cfg := config.DefaultConfig()
+	cfg.NoColor = true
+	r := NewTextReporter(&buf, "a.zsh", "f() { :; }\nf() { :; }", cfg)
+	err := r.Report([]katas.Violation{{
+		KataID: "ZC2004", Message: "redefined", Line: 2, Column: 1, Level: katas.SeverityWarning,
+		Related: []katas.Location{
+			{Line: 1, Column: 1, Message: "first defined here"},
+			{File: "lib.zsh", Line: 7, Column: 3, Message: "also defined here"},
+		},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"  note: a.zsh:1:1: first defined here\n",
+		"  note: lib.zsh:7:3: also defined here\n",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestTextReporter_Suggestions(t *testing.T) {
+	var buf bytes.Buffer
+	cfg := config.DefaultConfig()
+	cfg.NoColor = true
+	r := NewTextReporter(&buf, "a.zsh", "echo -e x", cfg)
+	err := r.Report([]katas.Violation{{
+		KataID: "ZC1171", Message: "m", Line: 1, Column: 1, Level: katas.SeverityStyle,
+		Suggestions: []katas.Suggestion{{Title: "Use `print`"}, {Title: "Use `printf`"}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "  suggestion 1: Use `print`\n  suggestion 2: Use `printf`\n") {
+		t.Errorf("output missing numbered suggestions:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,172 @@
+// SPDX-License-Identifier: MIT
+// Copyright the ZShellCheck contributors.
+package reporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// validateSchema checks doc, decoded JSON, against schema, a JSON Schema
+// decoded the same way. It covers the keywords the published schemas
+// use — type, enum, const, pattern, minimum, required, properties,
+// additionalProperties, items, oneOf and local $ref — and fails on any
+// other, so a schema cannot quietly go unchecked.
+func validateSchema(schema, doc any) error {
+	root, ok := schema.(map[string]any)
+	if !ok {
+		return fmt.Errorf("schema is not an object")
+	}
+	return (&schemaValidator{root: root}).check(root, doc, "$")
+}
+
+type schemaValidator struct {
+	root map[string]any
+}
+
+// schemaAnnotations are the keywords that describe rather than constrain.
+var schemaAnnotations = map[string]bool{
+	"$schema": true, "$id": true, "$defs": true, "title": true, "description": true,
+}
+
+func (sv *schemaValidator) check(schema map[string]any, v any, path string) error {
+	keys := make([]string, 0, len(schema))
+	for k := range schema {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if err := sv.keyword(schema, k, v, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (sv *schemaValidator) keyword(schema map[string]any, k string, v any, path string) error {
+	obj, isObj := v.(map[string]any)
+	switch arg := schema[k]; k {
+	case "$ref":
+		ref, _ := arg.(string)
+		name, ok := strings.CutPrefix(ref, "#/$defs/")
+		def, found := sv.root["$defs"].(map[string]any)[name].(map[string]any)
+		if !ok || !found {
+			return fmt.Errorf("%s: unresolvable $ref %q", path, ref)
+		}
+		return sv.check(def, v, path)
+	case "type":
+		types, ok := arg.([]any)
+		if !ok {
+			types = []any{arg}
+		}
+		for _, t := range types {
+			if jsonType(v, t.(string)) {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s: %v is not of type %v", path, v, arg)
+	case "enum":
+		for _, e := range arg.([]any) {
+			if reflect.DeepEqual(e, v) {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s: %v is not one of %v", path, v, arg)
+	case "const":
+		if !reflect.DeepEqual(arg, v) {
+			return fmt.Errorf("%s: %v, want %v", path, v, arg)
+		}
+	case "pattern":
+		if s, ok := v.(string); ok && !regexp.MustCompile(arg.(string)).MatchString(s) {
+			return fmt.Errorf("%s: %q does not match %s", path, s, arg)
+		}
+	case "minimum":
+		if n, ok := v.(float64); ok && n < arg.(float64) {
+			return fmt.Errorf("%s: %v is below %v", path, n, arg)
+		}
+	case "required":
+		for _, r := range arg.([]any) {
+			if _, ok := obj[r.(string)]; isObj && !ok {
+				return fmt.Errorf("%s: missing required %q", path, r)
+			}
+		}
+	case "properties":
+		for name, sub := range arg.(map[string]any) {
+			if pv, ok := obj[name]; isObj && ok {
+				if err := sv.check(sub.(map[string]any), pv, path+"."+name); err != nil {
+					return err
+				}
+			}
+		}
+	case "additionalProperties":
+		if arg != false {
+			return fmt.Errorf("%s: only additionalProperties false is supported", path)
+		}
+		props, _ := schema["properties"].(map[string]any)
+		for name := range obj {
+			if _, ok := props[name]; !ok {
+				return fmt.Errorf("%s: unexpected property %q", path, name)
+			}
+		}
+	case "items":
+		arr, _ := v.([]any)
+		for i, item := range arr {
+			if err := sv.check(arg.(map[string]any), item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case "oneOf":
+		matched := 0
+		for _, sub := range arg.([]any) {
+			if sv.check(sub.(map[string]any), v, path) == nil {
+				matched++
+			}
+		}
+		if matched != 1 {
+			return fmt.Errorf("%s: %v matches %d of the oneOf schemas, want 1", path, v, matched)
+		}
+	default:
+		if !schemaAnnotations[k] {
+			return fmt.Errorf("%s: unsupported schema keyword %q", path, k)
+		}
+	}
+	return nil
+}
+
+// jsonType reports whether v, decoded JSON, is of JSON Schema type t.
+func jsonType(v any, t string) bool {
+	switch t {
+	case "null":
+		return v == nil
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "number":
+		_, ok := v.(float64)
+		return ok
+	case "integer":
+		n, ok := v.(float64)
+		return ok && n == float64(int64(n))
+	case "array":
+		_, ok := v.([]any)
+		return ok
+	case "object":
+		_, ok := v.(map[string]any)
+		return ok
+	}
+	return false
+}
+
+// decodeJSON decodes data into a generic value for validateSchema.
+func decodeJSON(data []byte) (any, error) {
+	var v any
+	err := json.Unmarshal(data, &v)
+	return v, err
+}
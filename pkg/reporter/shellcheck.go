@@ -0,0 +1,128 @@
+// SPDX-License-Identifier: MIT
+// Copyright the ZShellCheck contributors.
+package reporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/afadesigns/zshellcheck/pkg/katas"
+)
+
+// ShellCheck `-f json1` document shape, so editor plugins and tools that
+// parse ShellCheck output read ZShellCheck's unchanged.
+type shellcheckDoc struct {
+	Comments []shellcheckComment `json:"comments"`
+}
+
+type shellcheckComment struct {
+	File      string         `json:"file"`
+	Line      int            `json:"line"`
+	EndLine   int            `json:"endLine"`
+	Column    int            `json:"column"`
+	EndColumn int            `json:"endColumn"`
+	Level     katas.Severity `json:"level"`
+	Code      int            `json:"code"`
+	Message   string         `json:"message"`
+	Fix       *shellcheckFix `json:"fix"`
+}
+
+type shellcheckFix struct {
+	Replacements []shellcheckReplacement `json:"replacements"`
+}
+
+type shellcheckReplacement struct {
+	Line           int    `json:"line"`
+	EndLine        int    `json:"endLine"`
+	Column         int    `json:"column"`
+	EndColumn      int    `json:"endColumn"`
+	InsertionPoint string `json:"insertionPoint"`
+	Precedence     int    `json:"precedence"`
+	Replacement    string `json:"replacement"`
+}
+
+// ReportShellCheckJSON1 writes every finding in ShellCheck's json1
+// format. ShellCheck's levels are ZShellCheck's, so they pass through.
+// Consumers decode code as an integer, so it is the kata's number —
+// 1234 for ZC1234 — and the message names the full ID. A finding with
+// an auto-fix carries its edits as fix.replacements.
+func ReportShellCheckJSON1(w io.Writer, files []FileViolations) error {
+	doc := shellcheckDoc{Comments: []shellcheckComment{}}
+	for _, f := range files {
+		for _, v := range f.Violations {
+			region := buildSarifRegion(v)
+			c := shellcheckComment{
+				File:      f.Filename,
+				Line:      region.StartLine,
+				EndLine:   region.StartLine,
+				Column:    region.StartColumn,
+				EndColumn: region.StartColumn,
+				Level:     v.Level,
+				Code:      kataNumber(v.KataID),
+				Message:   fmt.Sprintf("%s [%s]", v.Message, v.KataID),
+			}
+			if region.EndLine != 0 {
+				c.EndLine, c.EndColumn = region.EndLine, region.EndColumn
+			}
+			if len(v.Fix) > 0 {
+				c.Fix = &shellcheckFix{}
+				for _, e := range v.Fix {
+					r := shellcheckReplacement{
+						Line:           atLeastOne(e.Line),
+						Column:         atLeastOne(e.Column),
+						InsertionPoint: "afterEnd",
+						Precedence:     1,
+						Replacement:    e.Replace,
+					}
+					r.EndLine, r.EndColumn = editEnd(f.Source, e)
+					c.Fix.Replacements = append(c.Fix.Replacements, r)
+				}
+			}
+			doc.Comments = append(doc.Comments, c)
+		}
+	}
+	return json.NewEncoder(w).Encode(doc)
+}
+
+// kataNumber returns the number of a kata ID such as ZC1234, or 0 when
+// the ID has no numeric part.
+func kataNumber(id string) int {
+	n, err := strconv.Atoi(strings.TrimLeft(id, "ABCDEFGHIJKLMNOPQRSTUVWXYZ"))
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// ReportGCC writes every finding as a GCC-style diagnostic line,
+//
+//	file:line:col: warning: message [ZC1234]
+//
+// the shape compiler problem matchers and ShellCheck's `-f gcc` use.
+// Info and style findings are notes.
+func ReportGCC(w io.Writer, files []FileViolations) error {
+	for _, f := range files {
+		for _, v := range f.Violations {
+			if _, err := fmt.Fprintf(w, "%s:%d:%d: %s: %s [%s]\n",
+				f.Filename, atLeastOne(v.Line), atLeastOne(v.Column), gccLevel(v.Level), v.Message, v.KataID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// gccLevel maps a ZShellCheck severity onto a GCC diagnostic kind.
+func gccLevel(s katas.Severity) string {
+	switch s {
+	case katas.SeverityError:
+		return "error"
+	case katas.SeverityWarning:
+		return "warning"
+	default:
+		return "note"
+	}
+}
@@ -0,0 +1,81 @@
+// SPDX-License-Identifier: MIT
+// Copyright the ZShellCheck contributors.
+package reporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/afadesigns/zshellcheck/pkg/katas"
+)
+
+func TestReportShellCheckJSON1(t *testing.T) {
+	files := []FileViolations{{Filename: "a.zsh", Source: []byte("x=`date`\necho\n"), Violations: []katas.Violation{
+		{
+			KataID: "ZC1002", Message: "use $()", Line: 1, Column: 3, EndLine: 1, EndColumn: 9, Level: katas.SeverityStyle,
+			Fix: []katas.FixEdit{{Line: 1, Column: 3, Length: 6, Replace: "$(date)"}},
+		},
+		{KataID: "ZC1003", Message: "m", Line: 2, Column: 1, Level: katas.SeverityError},
+	}}}
+	var buf bytes.Buffer
+	if err := ReportShellCheckJSON1(&buf, files); err != nil {
+		t.Fatal(err)
+	}
+	var doc shellcheckDoc
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	want := []shellcheckComment{
+		{
+			File: "a.zsh", Line: 1, EndLine: 1, Column: 3, EndColumn: 9, Level: katas.SeverityStyle,
+			Code: 1002, Message: "use $() [ZC1002]",
+			Fix: &shellcheckFix{Replacements: []shellcheckReplacement{{
+				Line: 1, EndLine: 1, Column: 3, EndColumn: 9, InsertionPoint: "afterEnd", Precedence: 1, Replacement: "$(date)",
+			}}},
+		},
+		// A point finding ends where it starts, and has a null fix.
+		{File: "a.zsh", Line: 2, EndLine: 2, Column: 1, EndColumn: 1, Level: katas.SeverityError, Code: 1003, Message: "m [ZC1003]"},
+	}
+	if !reflect.DeepEqual(doc.Comments, want) {
+		t.Errorf("comments = %+v, want %+v", doc.Comments, want)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte(`"fix":null`)) {
+		t.Errorf("finding without a fix should have a null fix:\n%s", buf.String())
+	}
+}
+
+func TestReportShellCheckJSON1_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := ReportShellCheckJSON1(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != "{\"comments\":[]}\n" {
+		t.Errorf("empty report = %q", got)
+	}
+}
+
+func TestKataNumber(t *testing.T) {
+	for id, want := range map[string]int{"ZC1234": 1234, "ZC0007": 7, "ZC": 0, "custom": 0} {
+		if got := kataNumber(id); got != want {
+			t.Errorf("kataNumber(%q) = %d, want %d", id, got, want)
+		}
+	}
+}
+
+func TestReportGCC(t *testing.T) {
+	var buf bytes.Buffer
+	if err := ReportGCC(&buf, twoFiles()); err != nil {
+		t.Fatal(err)
+	}
+	want := "a.zsh:1:1: error: msg a [ZC1001]\n" +
+		"b.zsh:5:10: warning: msg b [ZC1002]\n" +
+		"b.zsh:7:2: note: msg c [ZC1003]\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+	if err := ReportGCC(&failWriter{}, twoFiles()); err == nil {
+		t.Error("expected error from failing writer")
+	}
+}
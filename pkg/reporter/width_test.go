@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: MIT
+// Copyright the ZShellCheck contributors.
+package reporter
+
+import "testing"
+
+func TestRuneWidth(t *testing.T) {
+	tests := []struct {
+		r    rune
+		want int
+	}{
+		{'a', 1},
+		{'é', 1},
+		{'́', 0}, // combining acute accent
+		{'‍', 0}, // zero-width joiner
+		{'\x07', 0},
+		{'日', 2},
+		{'한', 2},
+		{'Ａ', 2}, // fullwidth A
+		{'🚀', 2},
+		{'→', 1},
+	}
+	for _, tt := range tests {
+		if got := runeWidth(tt.r); got != tt.want {
+			t.Errorf("runeWidth(%U) = %d, want %d", tt.r, got, tt.want)
+		}
+	}
+}
+
+func TestDisplayColumn(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    string
+		byteCol int
+		tab     int
+		want    int
+	}{
+		{"ascii", "echo hi", 6, 8, 5},
+		{"first column", "echo", 1, 8, 0},
+		{"leading tab", "\techo", 2, 8, 8},
+		{"tab to next stop", "ab\tc", 4, 4, 4},
+		{"tab width 2", "\t\tx", 3, 2, 4},
+		{"wide runes", "日本 x", 8, 8, 5},
+		{"combining mark", "é x", 5, 8, 2},
+		{"inside a rune", "日x", 2, 8, 0},
+		{"past the end", "ab", 5, 8, 4},
+		{"before the start", "ab", 0, 8, 0},
+	}
+	for _, tt := range tests {
+		if got := displayColumn(tt.line, tt.byteCol, tt.tab); got != tt.want {
+			t.Errorf("%s: displayColumn(%q, %d, %d) = %d, want %d", tt.name, tt.line, tt.byteCol, tt.tab, got, tt.want)
+		}
+	}
+}
+
+func TestExpandTabs(t *testing.T) {
+	tests := []struct {
+		line string
+		tab  int
+		want string
+	}{
+		{"no tabs", 8, "no tabs"},
+		{"\tx", 4, "    x"},
+		{"ab\tc", 4, "ab  c"},
+		{"日\tx", 4, "日  x"},
+	}
+	for _, tt := range tests {
+		if got := expandTabs(tt.line, tt.tab); got != tt.want {
+			t.Errorf("expandTabs(%q, %d) = %q, want %q", tt.line, tt.tab, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+// SPDX-License-Identifier: MIT
+// Copyright the ZShellCheck contributors.
+package reporter
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"github.com/afadesigns/zshellcheck/pkg/katas"
+)
+
+// Checkstyle XML document shape, as read by Jenkins' warnings plugin and
+// the many CI tools that accept Checkstyle results.
+type checkstyleDoc struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr"`
+	Column   int    `xml:"column,attr,omitempty"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+// ReportCheckstyle writes every scanned file as a Checkstyle `<file>`,
+// with one `<error>` per finding whose source is the kata ID. Files
+// without findings are listed empty, so a consumer sees what was checked.
+func ReportCheckstyle(w io.Writer, files []FileViolations) error {
+	doc := checkstyleDoc{Version: "4.3"}
+	for _, f := range files {
+		file := checkstyleFile{Name: f.Filename}
+		for _, v := range f.Violations {
+			file.Errors = append(file.Errors, checkstyleError{
+				Line:     atLeastOne(v.Line),
+				Column:   v.Column,
+				Severity: checkstyleSeverity(v.Level),
+				Message:  v.Message,
+				Source:   v.KataID,
+			})
+		}
+		doc.Files = append(doc.Files, file)
+	}
+	return writeXML(w, doc)
+}
+
+// checkstyleSeverity maps a ZShellCheck severity onto Checkstyle's
+// error/warning/info scale; style findings are informational.
+func checkstyleSeverity(s katas.Severity) string {
+	switch s {
+	case katas.SeverityError:
+		return "error"
+	case katas.SeverityWarning:
+		return "warning"
+	default:
+		return "info"
+	}
+}
+
+// JUnit XML document shape, in the common dialect GitLab, Jenkins and
+// most test-report viewers read.
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Errors   int         `xml:"errors,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// ReportJUnit writes one testsuite per scanned file. Each finding is a
+// failing testcase named after its kata and position; a clean file gets
+// a single passing testcase, so it still counts as tested.
+func ReportJUnit(w io.Writer, files []FileViolations) error {
+	doc := junitSuites{Name: "zshellcheck"}
+	for _, f := range files {
+		suite := junitSuite{Name: f.Filename}
+		for _, v := range f.Violations {
+			suite.Cases = append(suite.Cases, junitCase{
+				Name:      fmt.Sprintf("%s at %d:%d", v.KataID, v.Line, v.Column),
+				ClassName: f.Filename,
+				Failure: &junitFailure{
+					Message: v.Message,
+					Type:    string(v.Level),
+					Text:    fmt.Sprintf("%s:%d:%d: %s: [%s] %s", f.Filename, v.Line, v.Column, v.Level, v.KataID, v.Message),
+				},
+			})
+		}
+		suite.Failures = len(suite.Cases)
+		if suite.Failures == 0 {
+			suite.Cases = []junitCase{{Name: "zshellcheck", ClassName: f.Filename}}
+		}
+		suite.Tests = len(suite.Cases)
+		doc.Tests += suite.Tests
+		doc.Failures += suite.Failures
+		doc.Suites = append(doc.Suites, suite)
+	}
+	return writeXML(w, doc)
+}
+
+// writeXML writes doc as an indented XML document with its declaration.
+func writeXML(w io.Writer, doc any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,76 @@
+// SPDX-License-Identifier: MIT
+// Copyright the ZShellCheck contributors.
+package reporter
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func withCleanFile() []FileViolations {
+	return append(twoFiles(), FileViolations{Filename: "clean.zsh"})
+}
+
+func TestReportCheckstyle(t *testing.T) {
+	var buf bytes.Buffer
+	if err := ReportCheckstyle(&buf, withCleanFile()); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buf.String(), xml.Header) {
+		t.Errorf("missing XML declaration:\n%s", buf.String())
+	}
+	var doc checkstyleDoc
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid XML: %v\n%s", err, buf.String())
+	}
+	if len(doc.Files) != 3 || doc.Files[2].Name != "clean.zsh" || len(doc.Files[2].Errors) != 0 {
+		t.Fatalf("files = %+v, want a.zsh, b.zsh and an empty clean.zsh", doc.Files)
+	}
+	want := checkstyleError{Line: 5, Column: 10, Severity: "warning", Message: "msg b", Source: "ZC1002"}
+	if got := doc.Files[1].Errors[0]; got != want {
+		t.Errorf("error = %+v, want %+v", got, want)
+	}
+	// Style findings map onto Checkstyle's info.
+	if got := doc.Files[1].Errors[1].Severity; got != "info" {
+		t.Errorf("style severity = %q, want info", got)
+	}
+}
+
+func TestReportJUnit(t *testing.T) {
+	var buf bytes.Buffer
+	if err := ReportJUnit(&buf, withCleanFile()); err != nil {
+		t.Fatal(err)
+	}
+	var doc junitSuites
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid XML: %v\n%s", err, buf.String())
+	}
+	if doc.Tests != 4 || doc.Failures != 3 || len(doc.Suites) != 3 {
+		t.Fatalf("tests=%d failures=%d suites=%d, want 4, 3, 3", doc.Tests, doc.Failures, len(doc.Suites))
+	}
+	b := doc.Suites[1]
+	if b.Name != "b.zsh" || b.Tests != 2 || b.Failures != 2 {
+		t.Errorf("suite b.zsh = %+v", b)
+	}
+	c := b.Cases[0]
+	if c.Name != "ZC1002 at 5:10" || c.ClassName != "b.zsh" || c.Failure == nil ||
+		c.Failure.Message != "msg b" || c.Failure.Type != "warning" ||
+		c.Failure.Text != "b.zsh:5:10: warning: [ZC1002] msg b" {
+		t.Errorf("testcase = %+v %+v", c, c.Failure)
+	}
+	clean := doc.Suites[2]
+	if clean.Tests != 1 || clean.Failures != 0 || len(clean.Cases) != 1 || clean.Cases[0].Failure != nil {
+		t.Errorf("clean suite = %+v, want one passing testcase", clean)
+	}
+}
+
+func TestReportXML_WriterError(t *testing.T) {
+	if err := ReportCheckstyle(&failWriter{}, twoFiles()); err == nil {
+		t.Error("checkstyle: expected error from failing writer")
+	}
+	if err := ReportJUnit(&failWriter{}, twoFiles()); err == nil {
+		t.Error("junit: expected error from failing writer")
+	}
+}
@@ -15,7 +15,11 @@ type Token struct {
 	// bodies span lines; the parser's "same-line" arg-gathering
 	// checks should consult EndLine of the just-consumed token
 	// when comparing against peek.Line. Zero means "use Line".
-	EndLine           int
+	EndLine int
+	// EndColumn is the column just past the token's last byte on
+	// EndLine, so Column..EndColumn is a half-open range on a
+	// single-line token. Zero means the lexer did not record it.
+	EndColumn         int
 	HasPrecedingSpace bool
 	// HasPrecedingContinuation is set when the lexer consumed a
 	// `\<NL>` pair immediately before this token. The Line / Column